@@ -0,0 +1,242 @@
+package config
+
+import (
+	"Avocycle/models"
+	"Avocycle/utils"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// auditedEntities model yang setiap create / update / delete-nya dicatat ke audit_logs,
+// key = nama struct di schema GORM
+var auditedEntities = map[string]bool{
+	"Kebun":              true,
+	"Tanaman":            true,
+	"FaseBunga":          true,
+	"FaseBuah":           true,
+	"FasePanen":          true,
+	"Booking":            true,
+	"LogPenyakitTanaman": true,
+}
+
+// kolom yang tidak perlu masuk diff karena selalu berubah
+var auditIgnoredColumns = map[string]bool{
+	"updated_at": true,
+}
+
+const auditBeforeKey = "audit:before"
+
+// registerAuditCallbacks memasang callback audit di semua statement create / update / delete.
+// Callback jalan di dalam transaksi default GORM, jadi audit ikut rollback kalau query gagal.
+func registerAuditCallbacks(db *gorm.DB) error {
+	if err := db.Callback().Create().After("gorm:create").Register("audit:after_create", auditAfterCreate); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register("audit:before_update", auditSnapshotBefore); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().After("gorm:update").Register("audit:after_update", auditAfterUpdate); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().Before("gorm:delete").Register("audit:before_delete", auditSnapshotBefore); err != nil {
+		return err
+	}
+	return db.Callback().Delete().After("gorm:delete").Register("audit:after_delete", auditAfterDelete)
+}
+
+func audited(tx *gorm.DB) bool {
+	return tx.Statement.Schema != nil && auditedEntities[tx.Statement.Schema.Name]
+}
+
+func auditAfterCreate(tx *gorm.DB) {
+	if !audited(tx) || tx.Error != nil || tx.RowsAffected == 0 {
+		return
+	}
+	for _, id := range auditPrimaryKeys(tx) {
+		after := auditLoadRow(tx, id)
+		writeAuditLog(tx, "create", id, nil, after)
+	}
+}
+
+func auditSnapshotBefore(tx *gorm.DB) {
+	if !audited(tx) || tx.Error != nil {
+		return
+	}
+	before := map[uint]map[string]interface{}{}
+	for _, id := range auditTargetIDs(tx) {
+		if row := auditLoadRow(tx, id); row != nil {
+			before[id] = row
+		}
+	}
+	tx.Statement.Settings.Store(auditBeforeKey, before)
+}
+
+func auditAfterUpdate(tx *gorm.DB) {
+	if !audited(tx) || tx.Error != nil || tx.RowsAffected == 0 {
+		return
+	}
+	for id, before := range auditSnapshot(tx) {
+		after := auditLoadRow(tx, id)
+		if len(auditDiff(before, after)) == 0 {
+			continue
+		}
+		writeAuditLog(tx, "update", id, before, after)
+	}
+}
+
+func auditAfterDelete(tx *gorm.DB) {
+	if !audited(tx) || tx.Error != nil || tx.RowsAffected == 0 {
+		return
+	}
+	for id, before := range auditSnapshot(tx) {
+		writeAuditLog(tx, "delete", id, before, nil)
+	}
+}
+
+func auditSnapshot(tx *gorm.DB) map[uint]map[string]interface{} {
+	v, ok := tx.Statement.Settings.Load(auditBeforeKey)
+	if !ok {
+		return nil
+	}
+	before, _ := v.(map[uint]map[string]interface{})
+	return before
+}
+
+// auditTargetIDs baris yang akan kena update / delete. Statement massal (Model kosong + Where,
+// contoh rename kode_blok atau purge trash) tidak membawa ID, jadi dicari dulu dengan kondisi
+// WHERE yang sama supaya tetap tercatat per baris.
+func auditTargetIDs(tx *gorm.DB) []uint {
+	if ids := auditPrimaryKeys(tx); len(ids) > 0 {
+		return ids
+	}
+	c, ok := tx.Statement.Clauses["WHERE"]
+	if !ok {
+		return nil
+	}
+	where, ok := c.Expression.(clause.Where)
+	if !ok || len(where.Exprs) == 0 {
+		return nil
+	}
+
+	model := reflect.New(tx.Statement.Schema.ModelType).Interface()
+	q := tx.Session(&gorm.Session{NewDB: true}).Unscoped().Model(model).Clauses(where)
+	// soft delete / update biasa tidak menyentuh baris yang sudah di trash
+	if !tx.Statement.Unscoped && tx.Statement.Schema.LookUpField("DeletedAt") != nil {
+		q = q.Where("deleted_at IS NULL")
+	}
+	var ids []uint
+	if err := q.Pluck("id", &ids).Error; err != nil {
+		return nil
+	}
+	return ids
+}
+
+// auditPrimaryKeys ambil ID dari model yang sedang diproses (struct tunggal atau slice)
+func auditPrimaryKeys(tx *gorm.DB) []uint {
+	field := tx.Statement.Schema.PrioritizedPrimaryField
+	if field == nil {
+		return nil
+	}
+
+	var ids []uint
+	collect := func(rv reflect.Value) {
+		value, zero := field.ValueOf(tx.Statement.Context, rv)
+		if zero {
+			return
+		}
+		if id, ok := toUint(value); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	rv := reflect.Indirect(tx.Statement.ReflectValue)
+	switch rv.Kind() {
+	case reflect.Struct:
+		collect(rv)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			collect(reflect.Indirect(rv.Index(i)))
+		}
+	}
+	return ids
+}
+
+func toUint(v interface{}) (uint, bool) {
+	switch n := v.(type) {
+	case uint:
+		return n, true
+	case uint64:
+		return uint(n), true
+	case int:
+		return uint(n), true
+	case int64:
+		return uint(n), true
+	}
+	return 0, false
+}
+
+// auditLoadRow baca baris apa adanya (termasuk yang soft delete) sebagai map kolom -> nilai
+func auditLoadRow(tx *gorm.DB, id uint) map[string]interface{} {
+	row := map[string]interface{}{}
+	err := tx.Session(&gorm.Session{NewDB: true}).
+		Table(tx.Statement.Table).
+		Where("id = ?", id).
+		Take(&row).Error
+	if err != nil {
+		return nil
+	}
+	return row
+}
+
+// auditDiff kolom yang berubah: {"kolom": {"from": lama, "to": baru}}
+func auditDiff(before, after map[string]interface{}) map[string]interface{} {
+	changes := map[string]interface{}{}
+	for col, newVal := range after {
+		if auditIgnoredColumns[col] {
+			continue
+		}
+		oldVal := before[col]
+		if fmt.Sprint(oldVal) == fmt.Sprint(newVal) {
+			continue
+		}
+		changes[col] = map[string]interface{}{"from": oldVal, "to": newVal}
+	}
+	return changes
+}
+
+func writeAuditLog(tx *gorm.DB, action string, id uint, before, after map[string]interface{}) {
+	actor := utils.AuditActorFrom(tx.Statement.Context)
+
+	entry := models.AuditLog{
+		ActorID:   actor.UserID,
+		ActorRole: actor.Role,
+		Action:    action,
+		Entity:    tx.Statement.Schema.Name,
+		EntityID:  id,
+		Before:    auditJSON(before),
+		After:     auditJSON(after),
+		IP:        actor.IP,
+	}
+	if before != nil && after != nil {
+		entry.Changes = auditJSON(auditDiff(before, after))
+	}
+
+	if err := tx.Session(&gorm.Session{NewDB: true}).Create(&entry).Error; err != nil {
+		tx.AddError(fmt.Errorf("gagal menulis audit log: %w", err))
+	}
+}
+
+func auditJSON(v map[string]interface{}) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return b
+}
@@ -1,11 +0,0 @@
-package config
-
-import (
-	"gopkg.in/danilopolani/gocialite.v1"
-)
-
-var Gocial *gocialite.Dispatcher
-
-func InitGocial() {
-	Gocial = gocialite.NewDispatcher()
-}
\ No newline at end of file
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"Avocycle/models"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NormalizeKodeBlok kode blok disimpan trim + huruf besar supaya "a1 " dan "A1" jadi blok yang sama
+func NormalizeKodeBlok(kode string) string {
+	return strings.ToUpper(strings.TrimSpace(kode))
+}
+
+// migrateLegacyKodeBlok membuat Blok dari kode_blok teks milik tanaman yang belum punya blok_id,
+// lalu menautkan tanamannya. Aman dijalankan berulang.
+func migrateLegacyKodeBlok(db *gorm.DB) error {
+	var pending int64
+	if err := db.Unscoped().Model(&models.Tanaman{}).
+		Where("blok_id IS NULL AND TRIM(kode_blok) <> ''").Count(&pending).Error; err != nil || pending == 0 {
+		return err
+	}
+
+	tanamanTable, err := tableOf(db, &models.Tanaman{})
+	if err != nil {
+		return err
+	}
+	blokTable, err := tableOf(db, &models.Blok{})
+	if err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(
+			"INSERT INTO ? (kebun_id, kode, created_at, updated_at) "+
+				"SELECT DISTINCT kebun_id, UPPER(TRIM(kode_blok)), NOW(), NOW() FROM ? "+
+				"WHERE blok_id IS NULL AND TRIM(kode_blok) <> '' ON CONFLICT (kebun_id, kode) DO NOTHING",
+			clause.Table{Name: blokTable}, clause.Table{Name: tanamanTable},
+		).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(
+			"UPDATE ? AS t SET blok_id = b.id, kode_blok = b.kode FROM ? AS b "+
+				"WHERE b.kebun_id = t.kebun_id AND b.kode = UPPER(TRIM(t.kode_blok)) AND t.blok_id IS NULL",
+			clause.Table{Name: tanamanTable}, clause.Table{Name: blokTable},
+		).Error
+	})
+}
@@ -0,0 +1,39 @@
+package config
+
+import "gorm.io/gorm"
+
+// status booking (lihat models.Booking.Status)
+const (
+	BookingStatusOpen      = "open"      // menunggu panen, menahan tanaman
+	BookingStatusFulfilled = "fulfilled" // buah sudah diserahkan
+	BookingStatusCancelled = "cancelled"
+)
+
+// bookingStatusTransitions booking selesai / batal tidak bisa dibuka lagi
+var bookingStatusTransitions = map[string][]string{
+	BookingStatusOpen: {BookingStatusFulfilled, BookingStatusCancelled},
+}
+
+// BookingStatusCanChange cek transisi status booking
+func BookingStatusCanChange(from, to string) bool {
+	for _, s := range bookingStatusTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidBookingStatus status yang dikenal
+func ValidBookingStatus(status string) bool {
+	switch status {
+	case BookingStatusOpen, BookingStatusFulfilled, BookingStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// BookingOpen scope booking yang masih menahan tanaman (memblokir booking lain, hapus, dan Removed)
+func BookingOpen(db *gorm.DB) *gorm.DB {
+	return db.Where("bookings.status = ?", BookingStatusOpen)
+}
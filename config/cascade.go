@@ -0,0 +1,134 @@
+package config
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrCascadeBlocked masih ada record aktif yang melarang induknya dihapus (contoh: booking open)
+var ErrCascadeBlocked = errors.New("masih ada data aktif yang mencegah penghapusan")
+
+// CascadeReport ringkasan record aktif yang ikut terhapus saat induk dihapus
+type CascadeReport struct {
+	Entity   string           `json:"entity"`
+	ID       uint             `json:"id"`
+	Affected map[string]int64 `json:"affected"`
+	// Photos jumlah foto yang ikut ke trash, dihapus dari Cloudinary saat purge permanen
+	Photos   int64            `json:"photos"`
+	Blockers map[string]int64 `json:"blockers,omitempty"`
+	Blocked  bool             `json:"blocked"`
+}
+
+// PlanCascadeDelete menghitung dampak penghapusan tanpa mengubah data (dry run)
+func PlanCascadeDelete(db *gorm.DB, e *TrashEntity, id uint) (*CascadeReport, error) {
+	report := &CascadeReport{
+		Entity:   e.Name,
+		ID:       id,
+		Affected: map[string]int64{},
+		Blockers: map[string]int64{},
+	}
+	if err := planCascade(db, e, []uint{id}, report); err != nil {
+		return nil, err
+	}
+	report.Blocked = len(report.Blockers) > 0
+	return report, nil
+}
+
+func planCascade(db *gorm.DB, e *TrashEntity, ids []uint, report *CascadeReport) error {
+	report.Affected[e.Name] += int64(len(ids))
+
+	// foto utama lama sudah tercatat di galeri (lihat migrateLegacyMedia), cukup hitung media
+	photos, err := countOwnerMedia(db, e.Name, ids)
+	if err != nil {
+		return err
+	}
+	report.Photos += photos
+
+	for _, child := range trashChildren(e.Name) {
+		childIDs, err := activeChildIDs(db, child, ids)
+		if err != nil {
+			return err
+		}
+		if len(childIDs) == 0 {
+			continue
+		}
+		if child.BlocksCascade != nil {
+			var blocking int64
+			if err := db.Model(child.New()).Scopes(child.BlocksCascade).
+				Where("id IN ?", childIDs).Count(&blocking).Error; err != nil {
+				return err
+			}
+			if blocking > 0 {
+				report.Blockers[child.Name] += blocking
+			}
+		}
+		if err := planCascade(db, child, childIDs, report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func activeChildIDs(db *gorm.DB, child *TrashEntity, parentIDs []uint) ([]uint, error) {
+	var ids []uint
+	err := db.Model(child.New()).Where(child.ParentColumn+" IN ?", parentIDs).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// CascadeSoftDelete memindahkan record beserta seluruh turunannya ke trash dalam satu transaksi.
+// Induk dihapus lebih dulu supaya deleted_at anak >= induk, sehingga RestoreTrashed ikut
+// mengembalikan anak-anaknya. Jika diblokir, report tetap dikembalikan bersama ErrCascadeBlocked.
+func CascadeSoftDelete(db *gorm.DB, e *TrashEntity, id uint) (*CascadeReport, error) {
+	var report *CascadeReport
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		report, err = PlanCascadeDelete(tx, e, id)
+		if err != nil {
+			return err
+		}
+		if report.Blocked {
+			return ErrCascadeBlocked
+		}
+
+		record := e.New()
+		if err := tx.First(record, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(record).Error; err != nil {
+			return err
+		}
+		if err := softDeleteOwnerMedia(tx, e.Name, []uint{id}); err != nil {
+			return err
+		}
+		return cascadeChildren(tx, e, []uint{id})
+	})
+	return report, err
+}
+
+func cascadeChildren(tx *gorm.DB, e *TrashEntity, ids []uint) error {
+	for _, child := range trashChildren(e.Name) {
+		childIDs, err := activeChildIDs(tx, child, ids)
+		if err != nil {
+			return err
+		}
+		if len(childIDs) == 0 {
+			continue
+		}
+		// load dulu supaya callback audit mendapat ID tiap record
+		records := child.NewSlice()
+		if err := tx.Where("id IN ?", childIDs).Find(records).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(records).Error; err != nil {
+			return err
+		}
+		if err := softDeleteOwnerMedia(tx, child.Name, childIDs); err != nil {
+			return err
+		}
+		if err := cascadeChildren(tx, child, childIDs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -4,13 +4,28 @@ import (
 	"Avocycle/models"
 	"fmt"
 	"os"
+	"sync"
 
 	"github.com/joho/godotenv"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+var (
+	dbMu     sync.Mutex
+	sharedDB *gorm.DB
+)
+
+// DbConnect koneksi Postgres bersama (satu pool untuk seluruh proses). Koneksi dibuka dan
+// callback audit / riwayat fase didaftarkan sekali saat pemanggilan pertama; migrasi skema
+// tidak dijalankan di sini, lihat Migrate yang dipanggil sekali saat startup.
 func DbConnect() (*gorm.DB, error) {
+	dbMu.Lock()
+	defer dbMu.Unlock()
+	if sharedDB != nil {
+		return sharedDB, nil
+	}
+
 	err := godotenv.Load()
 	if err != nil {
 		fmt.Println("Warning: .env file not found, using system environment variables")
@@ -31,10 +46,27 @@ func DbConnect() (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-    // ini untuk migrate nanti
-    db.AutoMigrate(
+	// audit trail otomatis untuk create / update / delete (lihat audit.go)
+	if err := registerAuditCallbacks(db); err != nil {
+		return nil, fmt.Errorf("failed to register audit callbacks: %w", err)
+	}
+	if err := registerFaseHistoryCallbacks(db); err != nil {
+		return nil, fmt.Errorf("failed to register fase history callbacks: %w", err)
+	}
+
+	sharedDB = db
+	return db, nil
+}
+
+// Migrate AutoMigrate dan migrasi data lama. Dipanggil sekali dari main sebelum server jalan.
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(
 		&models.User{},
+		&models.Organization{},
+		&models.OrganizationMember{},
 		&models.Kebun{},
+		&models.Varietas{},
+		&models.Blok{},
 		&models.ProsesProduksi{},
 		&models.PerawatanPenyakit{},
 		&models.PenyakitTanaman{},
@@ -47,7 +79,56 @@ func DbConnect() (*gorm.DB, error) {
 		&models.FaseBunga{},
 		&models.FaseBuah{},
 		&models.FasePanen{},
-	)
+		&models.UserTwoFactor{},
+		&models.TwoFactorRecoveryCode{},
+		&models.SecurityPolicy{},
+		&models.LoginThrottle{},
+		&models.SecurityEvent{},
+		&models.KebunMember{},
+		&models.KebunInvitation{},
+		&models.AuditLog{},
+		&models.FaseRevision{},
+		&models.Media{},
+		&models.UploadSesi{},
+		&models.UploadChunk{},
+	); err != nil {
+		return fmt.Errorf("failed to auto migrate: %w", err)
+	}
 
-	return db, nil
+	// auth_provider sekarang bebas (Google + provider OIDC lain), buang CHECK lama
+	if db.Migrator().HasConstraint(&models.User{}, "chk_users_auth_provider") {
+		db.Migrator().DropConstraint(&models.User{}, "chk_users_auth_provider")
+	}
+
+	// varietas tanaman pindah dari CHECK ke tabel referensi
+	if err := migrateLegacyVarietas(db); err != nil {
+		return fmt.Errorf("failed to migrate varietas: %w", err)
+	}
+	if err := migrateKebunKetinggian(db); err != nil {
+		return fmt.Errorf("failed to migrate kebun ketinggian: %w", err)
+	}
+	if err := migrateLegacyKodeBlok(db); err != nil {
+		return fmt.Errorf("failed to migrate kode blok: %w", err)
+	}
+	// kode tanaman ganda dirapikan dulu, lalu unique index case-insensitive dibuat
+	if err := migrateKodeTanamanIndex(db); err != nil {
+		return fmt.Errorf("failed to migrate kode tanaman index: %w", err)
+	}
+	if err := migrateTanamanPosisiIndex(db); err != nil {
+		return fmt.Errorf("failed to migrate tanaman posisi index: %w", err)
+	}
+	if err := migrateLegacyMedia(db); err != nil {
+		return fmt.Errorf("failed to migrate legacy media: %w", err)
+	}
+	if err := migrateMediaKebun(db); err != nil {
+		return fmt.Errorf("failed to migrate media kebun: %w", err)
+	}
+	if err := migrateMediaPHashBands(db); err != nil {
+		return fmt.Errorf("failed to migrate media phash bands: %w", err)
+	}
+	if err := migrateKebunOwners(db); err != nil {
+		return fmt.Errorf("failed to backfill kebun owners: %w", err)
+	}
+
+	return nil
 }
\ No newline at end of file
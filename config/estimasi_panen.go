@@ -0,0 +1,273 @@
+package config
+
+import (
+	"Avocycle/models"
+	"math"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// sumber estimasi panen (models.FaseBuah.EstimasiSumber)
+const (
+	EstimasiSumberRiwayatVarietas = "riwayat_kebun_varietas" // interval cover-panen varietas yang sama di kebun ini
+	EstimasiSumberRiwayatKebun    = "riwayat_kebun"          // interval cover-panen semua varietas di kebun ini
+	EstimasiSumberVarietas        = "varietas"               // Varietas.HariCoverPanen dikoreksi ketinggian
+	EstimasiSumberMasaProduksi    = "masa_produksi"          // Tanaman.MasaProduksi
+	EstimasiSumberManual          = "manual"                 // diisi pengguna, tidak dihitung ulang
+)
+
+const (
+	// batas interval cover-panen yang dianggap wajar, panen di luar ini bukan milik cover tersebut
+	estimasiHariMin  = 60
+	estimasiHariMaks = 450
+	// bobot nilai acuan (varietas / masa produksi) setara jumlah sampel riwayat ini
+	estimasiBobotAcuan = 3
+	// buah berkembang lebih lambat di dataran lebih tinggi (suhu turun ~0,6°C per 100 m)
+	estimasiKoreksiPer100m = 0.03
+	// setengah lebar rentang minimal (hari)
+	estimasiRentangMin = 7
+)
+
+// EstimasiPanen perkiraan tanggal panen satu cover buah
+type EstimasiPanen struct {
+	Tanggal           time.Time `json:"tanggal"`
+	Awal              time.Time `json:"awal"`
+	Akhir             time.Time `json:"akhir"`
+	HariCoverPanen    int       `json:"hari_cover_panen"`
+	KoreksiKetinggian int       `json:"koreksi_ketinggian_hari"` // hari tambahan (negatif = lebih cepat) dari ketinggian kebun
+	Keyakinan         float64   `json:"keyakinan"`               // 0-1
+	Sumber            string    `json:"sumber"`
+	Sampel            int       `json:"sampel"` // jumlah interval riwayat yang dipakai
+}
+
+// Apply salin hasil estimasi ke fase buah dan lepas tanda estimasi manual
+func (e *EstimasiPanen) Apply(fb *models.FaseBuah) {
+	tanggal, awal, akhir, keyakinan := e.Tanggal, e.Awal, e.Akhir, e.Keyakinan
+	fb.EstimasiPanen = &tanggal
+	fb.EstimasiPanenAwal = &awal
+	fb.EstimasiPanenAkhir = &akhir
+	fb.EstimasiKeyakinan = &keyakinan
+	fb.EstimasiSumber = e.Sumber
+	fb.EstimasiManual = false
+}
+
+// EstimatorPanen menghitung estimasi panen. Riwayat interval per kebun / varietas disimpan selama
+// umur estimator, jadi satu estimator bisa dipakai untuk banyak fase buah sekaligus.
+type EstimatorPanen struct {
+	db      *gorm.DB
+	sebelum time.Time // hanya riwayat sebelum waktu ini (backtest), zero = semua
+	riwayat map[riwayatKey][]float64
+}
+
+type riwayatKey struct {
+	kebunID    uint
+	varietasID uint // 0 = semua varietas
+}
+
+type riwayatSumber struct {
+	key    riwayatKey
+	sumber string
+	bobot  float64
+}
+
+func NewEstimatorPanen(db *gorm.DB) *EstimatorPanen {
+	return &EstimatorPanen{db: db, riwayat: map[riwayatKey][]float64{}}
+}
+
+// NewEstimatorPanenSebelum estimator yang hanya melihat cover & panen sebelum t, seperti estimasi pada saat t
+func NewEstimatorPanenSebelum(db *gorm.DB, t time.Time) *EstimatorPanen {
+	return &EstimatorPanen{db: db, sebelum: t, riwayat: map[riwayatKey][]float64{}}
+}
+
+// HitungEstimasiPanen estimasi panen satu tanaman untuk tanggal cover tertentu
+func HitungEstimasiPanen(db *gorm.DB, tanaman *models.Tanaman, tanggalCover time.Time) (*EstimasiPanen, error) {
+	return NewEstimatorPanen(db).Hitung(tanaman, tanggalCover)
+}
+
+// Hitung estimasi dari nilai acuan (Varietas.HariCoverPanen dikoreksi ketinggian kebun, atau
+// Tanaman.MasaProduksi) yang digeser ke median interval cover-panen kebun sendiri. Makin banyak
+// dan makin seragam riwayatnya, makin besar bobot riwayat dan keyakinannya.
+// Tanaman.Varietas dan Tanaman.Kebun dimuat jika belum di-preload.
+func (e *EstimatorPanen) Hitung(tanaman *models.Tanaman, tanggalCover time.Time) (*EstimasiPanen, error) {
+	if tanaman.VarietasID != nil && tanaman.Varietas == nil {
+		var v models.Varietas
+		if err := e.db.First(&v, *tanaman.VarietasID).Error; err != nil && err != gorm.ErrRecordNotFound {
+			return nil, err
+		} else if err == nil {
+			tanaman.Varietas = &v
+		}
+	}
+	if tanaman.Kebun.ID == 0 {
+		if err := e.db.First(&tanaman.Kebun, tanaman.KebunID).Error; err != nil && err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+	}
+
+	est := &EstimasiPanen{}
+	acuan, keyakinanAcuan := float64(tanaman.MasaProduksi), 0.2
+	est.Sumber = EstimasiSumberMasaProduksi
+	if v := tanaman.Varietas; v != nil && v.HariCoverPanen != nil && *v.HariCoverPanen > 0 {
+		acuan, keyakinanAcuan = float64(*v.HariCoverPanen), 0.4
+		est.Sumber = EstimasiSumberVarietas
+		koreksi, diLuarRentang := koreksiKetinggian(v, tanaman.Kebun.Ketinggian, acuan)
+		acuan += koreksi
+		est.KoreksiKetinggian = int(math.Round(koreksi))
+		if diLuarRentang {
+			keyakinanAcuan = 0.3
+		}
+	}
+	rentangAcuan := math.Max(estimasiRentangMin, acuan*0.15)
+
+	hari, rentang, keyakinan := acuan, rentangAcuan, keyakinanAcuan
+
+	// riwayat varietas yang sama lebih dulu, baru semua varietas di kebun (bobot keyakinan lebih rendah)
+	var sources []riwayatSumber
+	if tanaman.VarietasID != nil {
+		sources = append(sources, riwayatSumber{riwayatKey{tanaman.KebunID, *tanaman.VarietasID}, EstimasiSumberRiwayatVarietas, 1})
+	}
+	sources = append(sources, riwayatSumber{riwayatKey{tanaman.KebunID, 0}, EstimasiSumberRiwayatKebun, 0.8})
+
+	for _, src := range sources {
+		samples, err := e.intervalRiwayat(src.key)
+		if err != nil {
+			return nil, err
+		}
+		if len(samples) == 0 {
+			continue
+		}
+
+		median, sebaran := medianMAD(samples)
+		n := float64(len(samples))
+		w := n / (n + estimasiBobotAcuan)
+
+		// interval yang seragam (koefisien variasi kecil) menaikkan keyakinan
+		konsistensi := math.Max(0.3, math.Min(1, 1-3*sebaran/median))
+		keyakinanRiwayat := 0.9 * konsistensi * src.bobot
+
+		hari = w*median + (1-w)*acuan
+		rentang = math.Max(estimasiRentangMin, w*1.28*sebaran+(1-w)*rentangAcuan)
+		keyakinan = w*keyakinanRiwayat + (1-w)*keyakinanAcuan
+		est.Sumber = src.sumber
+		est.Sampel = len(samples)
+		break
+	}
+
+	est.HariCoverPanen = int(math.Round(hari))
+	cover := time.Date(tanggalCover.Year(), tanggalCover.Month(), tanggalCover.Day(), 0, 0, 0, 0, tanggalCover.Location())
+	est.Tanggal = cover.AddDate(0, 0, est.HariCoverPanen)
+	est.Awal = cover.AddDate(0, 0, int(math.Round(hari-rentang)))
+	est.Akhir = cover.AddDate(0, 0, int(math.Round(hari+rentang)))
+	est.Keyakinan = math.Round(keyakinan*100) / 100
+	return est, nil
+}
+
+// koreksiKetinggian selisih hari dari ketinggian kebun terhadap titik tengah rentang ketinggian varietas.
+// Tanpa data ketinggian tidak ada koreksi.
+func koreksiKetinggian(v *models.Varietas, ketinggian *float64, hari float64) (float64, bool) {
+	if ketinggian == nil || v.KetinggianMin == nil || v.KetinggianMax == nil {
+		return 0, false
+	}
+	acuan := float64(*v.KetinggianMin+*v.KetinggianMax) / 2
+	diLuarRentang := *ketinggian < float64(*v.KetinggianMin) || *ketinggian > float64(*v.KetinggianMax)
+	return hari * estimasiKoreksiPer100m * (*ketinggian - acuan) / 100, diLuarRentang
+}
+
+// intervalRiwayat jumlah hari dari setiap cover ke panen aktual pertama tanaman yang sama setelahnya
+func (e *EstimatorPanen) intervalRiwayat(key riwayatKey) ([]float64, error) {
+	if samples, ok := e.riwayat[key]; ok {
+		return samples, nil
+	}
+
+	tanamanQuery := e.db.Model(&models.Tanaman{}).Select("id").Where("kebun_id = ?", key.kebunID)
+	if key.varietasID != 0 {
+		tanamanQuery = tanamanQuery.Where("varietas_id = ?", key.varietasID)
+	}
+
+	sebelum := e.sebelum
+	if sebelum.IsZero() {
+		sebelum = time.Now().AddDate(100, 0, 0)
+	}
+
+	var samples []float64
+	if err := e.db.Model(&models.FaseBuah{}).
+		Select("(panen.tanggal::date - fase_buahs.tanggal_cover::date)::float8").
+		Joins("JOIN LATERAL (SELECT MIN(p.tanggal_panen_aktual) AS tanggal FROM fase_panens p "+
+			"WHERE p.tanaman_id = fase_buahs.tanaman_id AND p.deleted_at IS NULL "+
+			"AND p.tanggal_panen_aktual >= fase_buahs.tanggal_cover + make_interval(days => ?) "+
+			"AND p.tanggal_panen_aktual <= fase_buahs.tanggal_cover + make_interval(days => ?) "+
+			"AND p.tanggal_panen_aktual < ?) AS panen ON panen.tanggal IS NOT NULL",
+			estimasiHariMin, estimasiHariMaks, sebelum).
+		Where("fase_buahs.tanaman_id IN (?)", tanamanQuery).
+		Scan(&samples).Error; err != nil {
+		return nil, err
+	}
+	e.riwayat[key] = samples
+	return samples, nil
+}
+
+// medianMAD median dan sebaran (median absolute deviation, diskalakan setara simpangan baku)
+func medianMAD(samples []float64) (float64, float64) {
+	median := medianOf(samples)
+	dev := make([]float64, len(samples))
+	for i, s := range samples {
+		dev[i] = math.Abs(s - median)
+	}
+	return median, 1.4826 * medianOf(dev)
+}
+
+func medianOf(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// HitungUlangEstimasiPanen perbarui estimasi fase buah yang belum dipanen di kebun, dipanggil setelah
+// panen aktual dicatat / diubah. Estimasi manual tidak disentuh. Mengembalikan jumlah yang berubah.
+func HitungUlangEstimasiPanen(db *gorm.DB, kebunID uint) (int, error) {
+	var open []models.FaseBuah
+	if err := db.Preload("Tanaman").Preload("Tanaman.Varietas").Preload("Tanaman.Kebun").
+		Where("estimasi_manual = ?", false).
+		Where("tanaman_id IN (?)", db.Model(&models.Tanaman{}).Select("id").Where("kebun_id = ?", kebunID)).
+		Scopes(faseBuahBelumDipanen(time.Now())).
+		Where("tanggal_cover >= ?", time.Now().AddDate(0, 0, -estimasiHariMaks)).
+		Find(&open).Error; err != nil {
+		return 0, err
+	}
+
+	estimator := NewEstimatorPanen(db)
+	changed := 0
+	for i := range open {
+		fb := &open[i]
+		if fb.TanggalCover == nil {
+			continue
+		}
+		est, err := estimator.Hitung(&fb.Tanaman, *fb.TanggalCover)
+		if err != nil {
+			return changed, err
+		}
+		if fb.EstimasiPanen != nil && fb.EstimasiPanen.Equal(est.Tanggal) &&
+			fb.EstimasiPanenAwal != nil && fb.EstimasiPanenAwal.Equal(est.Awal) &&
+			fb.EstimasiPanenAkhir != nil && fb.EstimasiPanenAkhir.Equal(est.Akhir) &&
+			fb.EstimasiKeyakinan != nil && *fb.EstimasiKeyakinan == est.Keyakinan &&
+			fb.EstimasiSumber == est.Sumber {
+			continue
+		}
+		if err := db.Model(&models.FaseBuah{Model: gorm.Model{ID: fb.ID}}).Updates(map[string]interface{}{
+			"estimasi_panen":       est.Tanggal,
+			"estimasi_panen_awal":  est.Awal,
+			"estimasi_panen_akhir": est.Akhir,
+			"estimasi_keyakinan":   est.Keyakinan,
+			"estimasi_sumber":      est.Sumber,
+		}).Error; err != nil {
+			return changed, err
+		}
+		changed++
+	}
+	return changed, nil
+}
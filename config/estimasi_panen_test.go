@@ -0,0 +1,170 @@
+package config
+
+import (
+	"Avocycle/models"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMedianMAD(t *testing.T) {
+	tests := []struct {
+		name        string
+		samples     []float64
+		wantMedian  float64
+		wantSebaran float64
+	}{
+		{"satu sampel", []float64{180}, 180, 0},
+		{"ganjil", []float64{190, 170, 180}, 180, 1.4826 * 10},
+		{"genap", []float64{170, 180, 190, 200}, 185, 1.4826 * 10},
+		{"semua sama", []float64{175, 175, 175, 175}, 175, 0},
+		// satu panen yang salah dicatat tidak menggeser median maupun sebaran
+		{"pencilan", []float64{180, 182, 184, 186, 420}, 184, 1.4826 * 2},
+	}
+	for _, tt := range tests {
+		median, sebaran := medianMAD(tt.samples)
+		if math.Abs(median-tt.wantMedian) > 1e-9 || math.Abs(sebaran-tt.wantSebaran) > 1e-9 {
+			t.Errorf("%s: medianMAD = %v, %v, want %v, %v", tt.name, median, sebaran, tt.wantMedian, tt.wantSebaran)
+		}
+	}
+
+	samples := []float64{190, 170, 180}
+	medianMAD(samples)
+	if samples[0] != 190 || samples[1] != 170 || samples[2] != 180 {
+		t.Errorf("medianMAD mengubah urutan sampel: %v", samples)
+	}
+}
+
+func TestKoreksiKetinggian(t *testing.T) {
+	intp := func(v int) *int { return &v }
+	floatp := func(v float64) *float64 { return &v }
+	varietas := &models.Varietas{KetinggianMin: intp(800), KetinggianMax: intp(1200)}
+
+	tests := []struct {
+		name          string
+		varietas      *models.Varietas
+		ketinggian    *float64
+		want          float64
+		wantDiLuarRtg bool
+	}{
+		{"ketinggian kebun kosong", varietas, nil, 0, false},
+		{"rentang varietas kosong", &models.Varietas{}, floatp(1000), 0, false},
+		{"hanya batas bawah", &models.Varietas{KetinggianMin: intp(800)}, floatp(1000), 0, false},
+		{"titik tengah", varietas, floatp(1000), 0, false},
+		{"100 m di atas titik tengah", varietas, floatp(1100), 200 * 0.03, false},
+		{"100 m di bawah titik tengah", varietas, floatp(900), -200 * 0.03, false},
+		{"tepat batas atas", varietas, floatp(1200), 2 * 200 * 0.03, false},
+		{"di atas rentang", varietas, floatp(1500), 5 * 200 * 0.03, true},
+		{"di bawah rentang", varietas, floatp(300), -7 * 200 * 0.03, true},
+	}
+	for _, tt := range tests {
+		got, diLuarRentang := koreksiKetinggian(tt.varietas, tt.ketinggian, 200)
+		if math.Abs(got-tt.want) > 1e-9 || diLuarRentang != tt.wantDiLuarRtg {
+			t.Errorf("%s: koreksiKetinggian = %v, %v, want %v, %v", tt.name, got, diLuarRentang, tt.want, tt.wantDiLuarRtg)
+		}
+	}
+}
+
+// estimator tanpa DB: varietas & kebun sudah di-preload dan riwayat sudah ada di cache
+func TestEstimatorPanenHitung(t *testing.T) {
+	intp := func(v int) *int { return &v }
+	floatp := func(v float64) *float64 { return &v }
+	varietasID := uint(3)
+	tanaman := func() *models.Tanaman {
+		kebun := models.Kebun{Ketinggian: floatp(1200)}
+		kebun.ID = 1
+		return &models.Tanaman{
+			KebunID:      1,
+			Kebun:        kebun,
+			VarietasID:   &varietasID,
+			Varietas:     &models.Varietas{HariCoverPanen: intp(200), KetinggianMin: intp(800), KetinggianMax: intp(1200)},
+			MasaProduksi: 150,
+		}
+	}
+	// jam dibuang, estimasi dihitung dari tanggal cover
+	cover := time.Date(2025, 1, 10, 15, 4, 0, 0, time.UTC)
+	day := func(n int) time.Time { return time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC).AddDate(0, 0, n) }
+
+	tests := []struct {
+		name          string
+		tanaman       *models.Tanaman
+		riwayat       map[riwayatKey][]float64
+		wantSumber    string
+		wantHari      int
+		wantKoreksi   int
+		wantSampel    int
+		wantKeyakinan float64
+		wantAwal      int
+		wantAkhir     int
+	}{
+		{
+			// 200 hari + 200 m di atas titik tengah rentang varietas = 200 + 200*0.03*2
+			name:          "varietas dikoreksi ketinggian",
+			tanaman:       tanaman(),
+			riwayat:       map[riwayatKey][]float64{{1, 3}: nil, {1, 0}: nil},
+			wantSumber:    EstimasiSumberVarietas,
+			wantHari:      212,
+			wantKoreksi:   12,
+			wantKeyakinan: 0.4,
+			wantAwal:      212 - 32, // rentang 15% dari 212 = 31,8
+			wantAkhir:     212 + 32,
+		},
+		{
+			name: "masa produksi tanpa varietas",
+			tanaman: func() *models.Tanaman {
+				t := tanaman()
+				t.VarietasID, t.Varietas = nil, nil
+				return t
+			}(),
+			riwayat:       map[riwayatKey][]float64{{1, 0}: nil},
+			wantSumber:    EstimasiSumberMasaProduksi,
+			wantHari:      150,
+			wantKeyakinan: 0.2,
+			wantAwal:      128, // round(150 - 22,5), rentang 15% dari 150
+			wantAkhir:     173, // round(150 + 22,5)
+		},
+		{
+			// median 184, w = 5/(5+3): 0,625*184 + 0,375*212 = 194,5. Pencilan 420 tidak
+			// menggeser median; konsistensi 1-3*2,9652/184 ≈ 0,952 -> keyakinan 0,625*0,857 + 0,375*0,4
+			name:          "riwayat varietas digeser dari acuan",
+			tanaman:       tanaman(),
+			riwayat:       map[riwayatKey][]float64{{1, 3}: {180, 182, 184, 186, 420}, {1, 0}: {100, 100, 100}},
+			wantSumber:    EstimasiSumberRiwayatVarietas,
+			wantHari:      195,
+			wantKoreksi:   12,
+			wantSampel:    5,
+			wantKeyakinan: 0.69,
+			wantAwal:      180, // rentang 0,625*1,28*2,9652 + 0,375*31,8 ≈ 14,3 dari 194,5
+			wantAkhir:     209,
+		},
+		{
+			name:          "riwayat kebun jika varietas belum punya riwayat",
+			tanaman:       tanaman(),
+			riwayat:       map[riwayatKey][]float64{{1, 3}: nil, {1, 0}: {200, 200, 200}},
+			wantSumber:    EstimasiSumberRiwayatKebun,
+			wantHari:      206, // 0,5*200 + 0,5*212
+			wantKoreksi:   12,
+			wantSampel:    3,
+			wantKeyakinan: 0.56,     // 0,5*0,9*0,8 + 0,5*0,4
+			wantAwal:      206 - 16, // 0,5*0 + 0,5*31,8 = 15,9
+			wantAkhir:     206 + 16,
+		},
+	}
+	for _, tt := range tests {
+		e := &EstimatorPanen{riwayat: tt.riwayat}
+		est, err := e.Hitung(tt.tanaman, cover)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if est.Sumber != tt.wantSumber || est.HariCoverPanen != tt.wantHari || est.KoreksiKetinggian != tt.wantKoreksi ||
+			est.Sampel != tt.wantSampel || est.Keyakinan != tt.wantKeyakinan {
+			t.Errorf("%s: sumber %s, hari %d, koreksi %d, sampel %d, keyakinan %v; want %s, %d, %d, %d, %v",
+				tt.name, est.Sumber, est.HariCoverPanen, est.KoreksiKetinggian, est.Sampel, est.Keyakinan,
+				tt.wantSumber, tt.wantHari, tt.wantKoreksi, tt.wantSampel, tt.wantKeyakinan)
+		}
+		if !est.Tanggal.Equal(day(tt.wantHari)) || !est.Awal.Equal(day(tt.wantAwal)) || !est.Akhir.Equal(day(tt.wantAkhir)) {
+			t.Errorf("%s: tanggal %v (%v - %v), want %v (%v - %v)", tt.name, est.Tanggal, est.Awal, est.Akhir,
+				day(tt.wantHari), day(tt.wantAwal), day(tt.wantAkhir))
+		}
+	}
+}
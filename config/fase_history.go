@@ -0,0 +1,154 @@
+package config
+
+import (
+	"Avocycle/models"
+	"Avocycle/utils"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// faseHistoryEntities record fase yang disimpan versinya (lihat models.FaseRevision).
+// Snapshot "before" dipinjam dari callback audit, jadi entitas ini juga harus ada di auditedEntities.
+var faseHistoryEntities = map[string]bool{
+	"FaseBunga": true,
+	"FaseBuah":  true,
+	"FasePanen": true,
+}
+
+func registerFaseHistoryCallbacks(db *gorm.DB) error {
+	if err := db.Callback().Create().After("audit:after_create").Register("fase_history:after_create", faseHistoryAfterCreate); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().After("audit:after_update").Register("fase_history:after_update", faseHistoryAfterUpdate); err != nil {
+		return err
+	}
+	return db.Callback().Delete().After("audit:after_delete").Register("fase_history:after_delete", faseHistoryAfterDelete)
+}
+
+func faseHistoryEnabled(tx *gorm.DB) bool {
+	return tx.Statement.Schema != nil && faseHistoryEntities[tx.Statement.Schema.Name] &&
+		tx.Error == nil && tx.RowsAffected > 0
+}
+
+func faseHistoryAfterCreate(tx *gorm.DB) {
+	if !faseHistoryEnabled(tx) {
+		return
+	}
+	now := time.Now()
+	for _, id := range auditPrimaryKeys(tx) {
+		if row := auditLoadRow(tx, id); row != nil {
+			writeFaseRevision(tx, "create", id, row, now, nil)
+		}
+	}
+}
+
+func faseHistoryAfterUpdate(tx *gorm.DB) {
+	if !faseHistoryEnabled(tx) {
+		return
+	}
+	now := time.Now()
+	for id, before := range auditSnapshot(tx) {
+		after := auditLoadRow(tx, id)
+		if after == nil || len(auditDiff(before, after)) == 0 {
+			continue
+		}
+		closeFaseRevision(tx, id, before, now)
+		writeFaseRevision(tx, "update", id, after, now, nil)
+	}
+}
+
+func faseHistoryAfterDelete(tx *gorm.DB) {
+	if !faseHistoryEnabled(tx) {
+		return
+	}
+	now := time.Now()
+	for id, before := range auditSnapshot(tx) {
+		closeFaseRevision(tx, id, before, now)
+		writeFaseRevision(tx, "delete", id, before, now, &now)
+	}
+}
+
+// lockFaseRecord kunci baris fase sampai transaksi selesai, supaya dua perubahan bersamaan
+// tidak membaca MAX(revision) yang sama. Record yang sudah dihapus permanen dilewati.
+func lockFaseRecord(tx *gorm.DB, id uint) bool {
+	var ids []uint
+	if err := tx.Session(&gorm.Session{NewDB: true}).Unscoped().Table(tx.Statement.Table).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).Pluck("id", &ids).Error; err != nil {
+		tx.AddError(fmt.Errorf("gagal mengunci record fase: %w", err))
+		return false
+	}
+	return true
+}
+
+// closeFaseRevision menutup versi aktif. Record lama (dibuat sebelum ada history) belum punya versi,
+// jadi dibuatkan versi awal dari snapshot sebelum perubahan, berlaku sejak created_at.
+func closeFaseRevision(tx *gorm.DB, id uint, before map[string]interface{}, at time.Time) {
+	if !lockFaseRecord(tx, id) {
+		return
+	}
+	session := tx.Session(&gorm.Session{NewDB: true})
+	entity := tx.Statement.Schema.Name
+
+	var count int64
+	if err := session.Model(&models.FaseRevision{}).Where("entity = ? AND record_id = ?", entity, id).Count(&count).Error; err != nil {
+		tx.AddError(fmt.Errorf("gagal membaca history fase: %w", err))
+		return
+	}
+	if count == 0 {
+		validFrom, ok := before["created_at"].(time.Time)
+		if !ok {
+			validFrom = at
+		}
+		writeFaseRevision(tx, "create", id, before, validFrom, &at)
+		return
+	}
+
+	if err := session.Model(&models.FaseRevision{}).
+		Where("entity = ? AND record_id = ? AND valid_to IS NULL", entity, id).
+		Update("valid_to", at).Error; err != nil {
+		tx.AddError(fmt.Errorf("gagal menutup versi fase: %w", err))
+	}
+}
+
+func writeFaseRevision(tx *gorm.DB, action string, id uint, row map[string]interface{}, validFrom time.Time, validTo *time.Time) {
+	if !lockFaseRecord(tx, id) {
+		return
+	}
+	session := tx.Session(&gorm.Session{NewDB: true})
+	entity := tx.Statement.Schema.Name
+
+	var last int
+	if err := session.Model(&models.FaseRevision{}).
+		Where("entity = ? AND record_id = ?", entity, id).
+		Select("COALESCE(MAX(revision), 0)").Scan(&last).Error; err != nil {
+		tx.AddError(fmt.Errorf("gagal membaca history fase: %w", err))
+		return
+	}
+
+	data, err := json.Marshal(row)
+	if err != nil {
+		tx.AddError(fmt.Errorf("gagal menyimpan history fase: %w", err))
+		return
+	}
+
+	tanamanID, _ := toUint(row["tanaman_id"])
+	revision := models.FaseRevision{
+		Entity:    entity,
+		RecordID:  id,
+		Revision:  last + 1,
+		Action:    action,
+		TanamanID: tanamanID,
+		Data:      data,
+		ValidFrom: validFrom,
+		ValidTo:   validTo,
+		ActorID:   utils.AuditActorFrom(tx.Statement.Context).UserID,
+	}
+	if err := session.Create(&revision).Error; err != nil {
+		tx.AddError(fmt.Errorf("gagal menyimpan history fase: %w", err))
+	}
+}
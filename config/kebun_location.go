@@ -0,0 +1,36 @@
+package config
+
+import (
+	"Avocycle/models"
+	"Avocycle/utils"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// migrateKebunKetinggian isi kolom numerik ketinggian dari teks mdpl lama.
+// Hanya menyentuh kebun yang ketinggiannya masih kosong. Nilai di luar rentang yang juga ditolak
+// saat create / update dilewati supaya tidak ikut ke koreksi ketinggian estimasi panen.
+func migrateKebunKetinggian(db *gorm.DB) error {
+	var rows []models.Kebun
+	if err := db.Unscoped().Select("id", "mdpl").
+		Where("ketinggian IS NULL AND mdpl <> ''").Find(&rows).Error; err != nil {
+		return err
+	}
+
+	for _, k := range rows {
+		v, ok := utils.ParseMDPL(k.MDPL)
+		if !ok {
+			continue
+		}
+		if !utils.ValidKetinggian(v) {
+			log.Printf("migrate: kebun %d mdpl %q di luar rentang %d-%d, ketinggian dibiarkan kosong",
+				k.ID, k.MDPL, utils.KetinggianMin, utils.KetinggianMaks)
+			continue
+		}
+		if err := db.Unscoped().Model(&models.Kebun{}).Where("id = ?", k.ID).Update("ketinggian", v).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package config
+
+import "gorm.io/gorm"
+
+// migrateKebunOwners isi owner untuk kebun lama yang belum punya anggota & organisasi.
+// Kebun tidak punya kolom pembuat, jadi owner diambil dari Petani pertama yang tercatat
+// di audit log kebun tersebut. Kebun yang tidak bisa ditelusuri tetap memakai fallback
+// kebun lama di controllers.kebunRoleOf sampai ada anggota ditambahkan.
+func migrateKebunOwners(db *gorm.DB) error {
+	return db.Exec(`
+		INSERT INTO kebun_members (created_at, updated_at, kebun_id, user_id, role)
+		SELECT NOW(), NOW(), k.id, a.actor_id, ?
+		FROM kebuns k
+		JOIN LATERAL (
+			SELECT al.actor_id
+			FROM audit_logs al
+			JOIN users u ON u.id = al.actor_id AND u.role = 'Petani' AND u.deleted_at IS NULL
+			WHERE al.entity = 'Kebun' AND al.entity_id = k.id AND al.deleted_at IS NULL
+			ORDER BY al.id
+			LIMIT 1
+		) a ON TRUE
+		WHERE k.organization_id IS NULL
+		  AND NOT EXISTS (SELECT 1 FROM kebun_members m WHERE m.kebun_id = k.id AND m.deleted_at IS NULL)
+	`, KebunRoleOwner).Error
+}
@@ -0,0 +1,80 @@
+package config
+
+// peran user di dalam satu kebun (lihat models.KebunMember)
+const (
+	KebunRoleOwner   = "owner"
+	KebunRoleManager = "manager"
+	KebunRoleWorker  = "worker"
+	KebunRoleViewer  = "viewer"
+)
+
+// KebunAction aksi yang dicek terhadap peran di kebun, di atas permission global
+type KebunAction string
+
+const (
+	KebunActView             KebunAction = "view"
+	KebunActUpdate           KebunAction = "update"
+	KebunActDelete           KebunAction = "delete"
+	KebunActTransfer         KebunAction = "organization:assign"
+	KebunActManageMembers    KebunAction = "members:manage"
+	KebunActTanamanWrite     KebunAction = "tanaman:write"
+	KebunActTanamanDelete    KebunAction = "tanaman:delete"
+	KebunActFaseWrite        KebunAction = "fase:write"
+	KebunActPenyakitClassify KebunAction = "penyakit:classify"
+)
+
+var kebunRoleActions = map[string][]KebunAction{
+	KebunRoleOwner: {
+		KebunActView, KebunActUpdate, KebunActDelete, KebunActTransfer, KebunActManageMembers,
+		KebunActTanamanWrite, KebunActTanamanDelete, KebunActFaseWrite, KebunActPenyakitClassify,
+	},
+	KebunRoleManager: {
+		KebunActView, KebunActUpdate, KebunActManageMembers,
+		KebunActTanamanWrite, KebunActTanamanDelete, KebunActFaseWrite, KebunActPenyakitClassify,
+	},
+	// pekerja lapangan: catat fase dan foto penyakit, tidak boleh ubah / hapus pohon
+	KebunRoleWorker: {
+		KebunActView, KebunActFaseWrite, KebunActPenyakitClassify,
+	},
+	KebunRoleViewer: {
+		KebunActView,
+	},
+}
+
+// kebunRoleRank dipakai supaya manager tidak bisa memberi peran setara / di atasnya
+var kebunRoleRank = map[string]int{
+	KebunRoleOwner:   4,
+	KebunRoleManager: 3,
+	KebunRoleWorker:  2,
+	KebunRoleViewer:  1,
+}
+
+// ValidKebunRole cek nama peran kebun
+func ValidKebunRole(role string) bool {
+	_, ok := kebunRoleRank[role]
+	return ok
+}
+
+// KebunRoleCan cek apakah peran kebun boleh melakukan aksi
+func KebunRoleCan(role string, action KebunAction) bool {
+	for _, a := range kebunRoleActions[role] {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// KebunRoleCanAssign owner boleh memberi peran apa saja, manager hanya worker / viewer
+func KebunRoleCanAssign(actorRole, targetRole string) bool {
+	if actorRole == KebunRoleOwner {
+		return ValidKebunRole(targetRole)
+	}
+	return KebunRoleCan(actorRole, KebunActManageMembers) && kebunRoleRank[targetRole] < kebunRoleRank[actorRole]
+}
+
+// peran user di organisasi (lihat models.OrganizationMember)
+const (
+	OrgRoleAdmin  = "admin"
+	OrgRoleMember = "member"
+)
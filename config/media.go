@@ -0,0 +1,392 @@
+package config
+
+import (
+	"Avocycle/models"
+	"Avocycle/utils"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MediaCover kolom foto tunggal lama yang diisi dengan foto pertama galeri
+type MediaCover struct {
+	URLColumn      string
+	PublicIDColumn string
+	ThumbColumn    string
+}
+
+// MediaOwnerTypes entitas yang boleh punya galeri foto (nama = nama entitas trash)
+var MediaOwnerTypes = map[string]*MediaCover{
+	"kebun":        nil,
+	"tanaman":      {URLColumn: "foto_tanaman", PublicIDColumn: "foto_tanaman_id", ThumbColumn: "foto_tanaman_thumb"},
+	"fase-bunga":   nil,
+	"fase-berbuah": nil,
+	"fase-panen":   {URLColumn: "foto_panen", PublicIDColumn: "foto_panen_id", ThumbColumn: "foto_panen_thumb"},
+	"log-penyakit": {URLColumn: "foto", PublicIDColumn: "foto_log_penyakit_id", ThumbColumn: "foto_thumb"},
+	"buah":         nil,
+}
+
+var ErrMediaOwnerType = errors.New("jenis pemilik media tidak dikenal")
+
+// MediaDuplicateMaxDistance selisih bit perceptual hash maksimal agar dua foto dianggap foto yang sama
+const MediaDuplicateMaxDistance = 5
+
+// mediaPHashBands potongan perceptual hash (geser, lebar bit) untuk kolom phash_bands. Jumlah potongan
+// MediaDuplicateMaxDistance+1: dua hash yang selisihnya <= 5 bit pasti punya minimal satu potongan yang sama,
+// jadi kandidat cukup dicari lewat index GIN, bukan menghitung jarak ke semua foto.
+var mediaPHashBands = [MediaDuplicateMaxDistance + 1][2]uint{{53, 11}, {42, 11}, {31, 11}, {20, 11}, {10, 10}, {0, 10}}
+
+// mediaPHashBandBits nomor potongan disimpan di atas nilainya supaya potongan berbeda tidak saling cocok
+const mediaPHashBandBits = 11
+
+// phashBands nilai phash_bands untuk satu hash, sama dengan kolom generated di migrateMediaPHashBands
+func phashBands(phash uint64) []string {
+	bands := make([]string, len(mediaPHashBands))
+	for i, b := range mediaPHashBands {
+		v := (phash>>b[0])&(1<<b[1]-1) | uint64(i)<<mediaPHashBandBits
+		bands[i] = fmt.Sprint(v)
+	}
+	return bands
+}
+
+// MediaOwnerEntity entitas trash pemilik galeri
+func MediaOwnerEntity(ownerType string) (*TrashEntity, error) {
+	if _, ok := MediaOwnerTypes[ownerType]; !ok {
+		return nil, ErrMediaOwnerType
+	}
+	e, ok := TrashEntityByName(ownerType)
+	if !ok {
+		return nil, ErrMediaOwnerType
+	}
+	return e, nil
+}
+
+// MediaOwnerKebunID kebun pemilik record, ditelusuri lewat ParentColumn entitas trash.
+// gorm.ErrRecordNotFound jika record (atau induknya) tidak ada / sudah di trash.
+func MediaOwnerKebunID(db *gorm.DB, ownerType string, ownerID uint) (uint, error) {
+	e, err := MediaOwnerEntity(ownerType)
+	if err != nil {
+		return 0, err
+	}
+	id := ownerID
+	for e.ParentEntity != "" {
+		var parentID uint
+		res := db.Model(e.New()).Select(e.ParentColumn).Where("id = ?", id).Scan(&parentID)
+		if res.Error != nil {
+			return 0, res.Error
+		}
+		if res.RowsAffected == 0 {
+			return 0, gorm.ErrRecordNotFound
+		}
+		id = parentID
+		e, _ = TrashEntityByName(e.ParentEntity)
+	}
+	if err := db.Select("id").First(e.New(), id).Error; err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// MediaOwnerTanamanID tanaman pemilik record: tanaman itu sendiri atau induk fase / log / buah.
+// false jika pemilik tidak berada di bawah tanaman (kebun) atau record tidak ditemukan.
+func MediaOwnerTanamanID(db *gorm.DB, ownerType string, ownerID uint) (uint, bool) {
+	e, err := MediaOwnerEntity(ownerType)
+	if err != nil {
+		return 0, false
+	}
+	id := ownerID
+	for e.Name != "tanaman" {
+		if e.ParentEntity == "" {
+			return 0, false
+		}
+		var parentID uint
+		res := db.Unscoped().Model(e.New()).Select(e.ParentColumn).Where("id = ?", id).Scan(&parentID)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return 0, false
+		}
+		id = parentID
+		e, _ = TrashEntityByName(e.ParentEntity)
+	}
+	return id, true
+}
+
+// FindDuplicateMedia foto lama paling mirip (perceptual hash) di kebun yang sama yang milik tanaman lain.
+// nil jika tidak ada atau pemilik baru tidak berada di bawah tanaman.
+func FindDuplicateMedia(db *gorm.DB, kebunID uint, ownerType string, ownerID uint, phash uint64) (*models.Media, error) {
+	tanamanID, ok := MediaOwnerTanamanID(db, ownerType, ownerID)
+	if !ok {
+		return nil, nil
+	}
+
+	// jumlah bit berbeda dihitung di Postgres: XOR lalu hitung angka 1 di representasi bit(64)
+	distance := "length(replace((phash # ?)::bit(64)::text, '0', ''))"
+	var candidates []models.Media
+	if err := db.Where("kebun_id = ?", kebunID).
+		Where("phash_bands && '{"+strings.Join(phashBands(phash), ",")+"}'::int[]").
+		Where(distance+" <= ?", int64(phash), MediaDuplicateMaxDistance).
+		Where("NOT (owner_type = ? AND owner_id = ?)", ownerType, ownerID).
+		Order(clause.Expr{SQL: distance + " ASC, id ASC", Vars: []interface{}{int64(phash)}}).
+		Limit(20).Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+	for i := range candidates {
+		other, ok := MediaOwnerTanamanID(db, candidates[i].OwnerType, candidates[i].OwnerID)
+		if ok && other != tanamanID {
+			return &candidates[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// NewMediaFromUpload record galeri dari hasil upload: ukuran, waktu & lokasi EXIF (waktu upload jika
+// tidak ada), hash, kebun pemilik, dan tanda duplikat jika foto yang sama sudah dipakai tanaman lain di kebun itu.
+// Belum disimpan; urutan, caption dan uploader diisi pemanggil.
+func NewMediaFromUpload(db *gorm.DB, ownerType string, ownerID uint, img *utils.UploadedImage) (*models.Media, error) {
+	kebunID, err := MediaOwnerKebunID(db, ownerType, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	phash := int64(img.PHash)
+	media := &models.Media{
+		OwnerType:     ownerType,
+		OwnerID:       ownerID,
+		KebunID:       &kebunID,
+		URL:           img.URL,
+		PublicID:      img.PublicID,
+		ThumbURL:      img.ThumbURL,
+		ThumbPublicID: img.ThumbPublicID,
+		Width:         img.Width,
+		Height:        img.Height,
+		PHash:         &phash,
+		ContentHash:   img.ContentHash,
+	}
+	if img.Exif != nil {
+		media.TakenAt = img.Exif.TakenAt
+		media.Latitude, media.Longitude = img.Exif.Latitude, img.Exif.Longitude
+	}
+	if media.TakenAt == nil {
+		now := time.Now()
+		media.TakenAt = &now
+	}
+
+	dup, err := FindDuplicateMedia(db, kebunID, ownerType, ownerID, img.PHash)
+	if err != nil {
+		return nil, err
+	}
+	if dup != nil {
+		media.DuplikatDariID = &dup.ID
+	}
+	return media, nil
+}
+
+// AttachCoverPhoto dipakai upload foto tunggal lama (foto_tanaman, foto_panen, foto log penyakit):
+// foto masuk galeri di urutan paling depan sehingga menjadi foto utama, foto lama tetap di galeri.
+// nil tanpa error jika img nil.
+func AttachCoverPhoto(db *gorm.DB, ownerType string, ownerID uint, img *utils.UploadedImage, uploadedBy *uint) (*models.Media, error) {
+	if img == nil {
+		return nil, nil
+	}
+	var first struct{ Urutan *int }
+	if err := db.Model(&models.Media{}).Select("MIN(urutan) AS urutan").
+		Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).Scan(&first).Error; err != nil {
+		return nil, err
+	}
+	media, err := NewMediaFromUpload(db, ownerType, ownerID, img)
+	if err != nil {
+		return nil, err
+	}
+	if first.Urutan != nil {
+		media.Urutan = *first.Urutan - 1
+	}
+	media.UploadedBy = uploadedBy
+	if err := db.Create(media).Error; err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+// SyncMediaCover samakan kolom foto tunggal lama dengan foto urutan pertama galeri (kosong jika galeri kosong)
+func SyncMediaCover(db *gorm.DB, ownerType string, ownerID uint) error {
+	cover := MediaOwnerTypes[ownerType]
+	if cover == nil {
+		return nil
+	}
+	e, err := MediaOwnerEntity(ownerType)
+	if err != nil {
+		return err
+	}
+
+	var first models.Media
+	if err := db.Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).
+		Order("urutan ASC, id ASC").Limit(1).Find(&first).Error; err != nil {
+		return err
+	}
+
+	// lewat Model(record) supaya callback audit mendapat ID
+	record := e.New()
+	if err := db.First(record, ownerID).Error; err != nil {
+		return err
+	}
+	return db.Model(record).Updates(map[string]interface{}{
+		cover.URLColumn:      first.URL,
+		cover.PublicIDColumn: first.PublicID,
+		cover.ThumbColumn:    first.ThumbURL,
+	}).Error
+}
+
+// --- dipakai trash / cascade: media ikut induknya ---
+
+func ownerMedia(db *gorm.DB, ownerType string, ids []uint) *gorm.DB {
+	return db.Model(&models.Media{}).Where("owner_type = ? AND owner_id IN ?", ownerType, ids)
+}
+
+func countOwnerMedia(db *gorm.DB, ownerType string, ids []uint) (int64, error) {
+	var count int64
+	err := ownerMedia(db, ownerType, ids).Count(&count).Error
+	return count, err
+}
+
+func softDeleteOwnerMedia(tx *gorm.DB, ownerType string, ids []uint) error {
+	return tx.Where("owner_type = ? AND owner_id IN ?", ownerType, ids).Delete(&models.Media{}).Error
+}
+
+func restoreOwnerMedia(tx *gorm.DB, ownerType string, id uint, since time.Time) error {
+	return tx.Unscoped().Model(&models.Media{}).
+		Where("owner_type = ? AND owner_id = ? AND deleted_at >= ?", ownerType, id, since).
+		Update("deleted_at", nil).Error
+}
+
+func collectOwnerMediaAssets(db *gorm.DB, ownerType string, ids []uint, assets *[]string) error {
+	for _, col := range []string{"public_id", "thumb_public_id"} {
+		var publicIDs []string
+		if err := ownerMedia(db.Unscoped(), ownerType, ids).Where(col+" <> ''").Pluck(col, &publicIDs).Error; err != nil {
+			return err
+		}
+		*assets = append(*assets, publicIDs...)
+	}
+	return nil
+}
+
+func purgeOwnerMedia(tx *gorm.DB, ownerType string, ids []uint) error {
+	return tx.Unscoped().Where("owner_type = ? AND owner_id IN ?", ownerType, ids).Delete(&models.Media{}).Error
+}
+
+// migrateLegacyMedia memasukkan foto tunggal lama ke galeri (sekali per foto, aman dijalankan berulang).
+// Media record yang sudah di trash ikut berstatus terhapus.
+func migrateLegacyMedia(db *gorm.DB) error {
+	mediaTable, err := tableOf(db, &models.Media{})
+	if err != nil {
+		return err
+	}
+	for ownerType, cover := range MediaOwnerTypes {
+		if cover == nil {
+			continue
+		}
+		e, err := MediaOwnerEntity(ownerType)
+		if err != nil {
+			return err
+		}
+		ownerTable, err := tableOf(db, e.New())
+		if err != nil {
+			return err
+		}
+		if err := db.Exec(
+			"INSERT INTO ? (created_at, updated_at, deleted_at, owner_type, owner_id, url, public_id, taken_at, urutan) "+
+				"SELECT o.created_at, NOW(), o.deleted_at, ?, o.id, o."+cover.URLColumn+", COALESCE(o."+cover.PublicIDColumn+", ''), o.updated_at, 0 "+
+				"FROM ? AS o WHERE o."+cover.URLColumn+" <> '' AND NOT EXISTS ("+
+				"SELECT 1 FROM ? AS m WHERE m.owner_type = ? AND m.owner_id = o.id AND m.url = o."+cover.URLColumn+")",
+			clause.Table{Name: mediaTable}, ownerType, clause.Table{Name: ownerTable},
+			clause.Table{Name: mediaTable}, ownerType,
+		).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateMediaPHashBands kolom generated phash_bands (lihat mediaPHashBands) beserta index GIN-nya.
+// AutoMigrate tidak bisa membuat kolom generated, jadi dibuat di sini sekali.
+func migrateMediaPHashBands(db *gorm.DB) error {
+	table, err := tableOf(db, &models.Media{})
+	if err != nil {
+		return err
+	}
+	if !db.Migrator().HasColumn(&models.Media{}, "phash_bands") {
+		exprs := make([]string, len(mediaPHashBands))
+		for i, b := range mediaPHashBands {
+			exprs[i] = fmt.Sprintf("(((phash >> %d) & %d) | %d)::int", b[0], 1<<b[1]-1, i<<mediaPHashBandBits)
+		}
+		if err := db.Exec(
+			"ALTER TABLE ? ADD COLUMN phash_bands int[] GENERATED ALWAYS AS (ARRAY["+strings.Join(exprs, ", ")+"]) STORED",
+			clause.Table{Name: table},
+		).Error; err != nil {
+			return err
+		}
+	}
+	return db.Exec("CREATE INDEX IF NOT EXISTS idx_media_phash_bands ON ? USING gin (phash_bands)", clause.Table{Name: table}).Error
+}
+
+// migrateMediaKebun isi kebun_id media lama dengan menelusuri induk pemiliknya sampai kebun, lalu buang
+// tanda duplikat yang menunjuk foto kebun lain (dulu pencarian foto mirip tidak dibatasi kebun).
+func migrateMediaKebun(db *gorm.DB) error {
+	mediaTable, err := tableOf(db, &models.Media{})
+	if err != nil {
+		return err
+	}
+	for ownerType := range MediaOwnerTypes {
+		e, err := MediaOwnerEntity(ownerType)
+		if err != nil {
+			return err
+		}
+		if e.ParentEntity == "" {
+			if err := db.Exec("UPDATE ? SET kebun_id = owner_id WHERE owner_type = ? AND kebun_id IS NULL",
+				clause.Table{Name: mediaTable}, ownerType).Error; err != nil {
+				return err
+			}
+			continue
+		}
+
+		// o0 = pemilik, o1 = induknya, ... sampai entitas yang induknya kebun
+		var joins []string
+		var tables []interface{}
+		alias := "o0"
+		for {
+			table, err := tableOf(db, e.New())
+			if err != nil {
+				return err
+			}
+			tables = append(tables, clause.Table{Name: table})
+			parent, ok := TrashEntityByName(e.ParentEntity)
+			if !ok {
+				return fmt.Errorf("induk %s tidak dikenal", e.Name)
+			}
+			if parent.ParentEntity == "" {
+				break
+			}
+			next := fmt.Sprintf("o%d", len(tables))
+			joins = append(joins, fmt.Sprintf(" JOIN ? AS %s ON %s.id = %s.%s", next, next, alias, e.ParentColumn))
+			alias, e = next, parent
+		}
+
+		vars := []interface{}{clause.Table{Name: mediaTable}}
+		vars = append(vars, tables...)
+		vars = append(vars, ownerType)
+		if err := db.Exec(
+			"UPDATE ? AS m SET kebun_id = "+alias+"."+e.ParentColumn+" FROM ? AS o0"+strings.Join(joins, "")+
+				" WHERE m.owner_type = ? AND m.owner_id = o0.id AND m.kebun_id IS NULL",
+			vars...,
+		).Error; err != nil {
+			return err
+		}
+	}
+
+	return db.Exec(
+		"UPDATE ? AS m SET duplikat_dari_id = NULL FROM ? AS d "+
+			"WHERE d.id = m.duplikat_dari_id AND d.kebun_id IS DISTINCT FROM m.kebun_id",
+		clause.Table{Name: mediaTable}, clause.Table{Name: mediaTable},
+	).Error
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"Avocycle/models"
+	"Avocycle/utils"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MediaGCPrefix prefix public id aset aplikasi, sama dengan folder upload (utils.CloudinaryFolder).
+// Aset di luar prefix tidak disentuh.
+func MediaGCPrefix() string {
+	return utils.CloudinaryFolder() + "/"
+}
+
+// MediaGCAutoDelete GC terjadwal ikut menghapus orphan hanya jika MEDIA_GC_AUTO_DELETE=true.
+// Default hanya melaporkan ke log; penghapusan manual lewat POST /admin/media/orphan/purge.
+func MediaGCAutoDelete() bool {
+	on, _ := strconv.ParseBool(os.Getenv("MEDIA_GC_AUTO_DELETE"))
+	return on
+}
+
+// MediaGCGrace umur minimal aset tanpa referensi sebelum dihapus (MEDIA_GC_GRACE_HOURS, default 72 jam).
+// Memberi waktu upload yang record DB-nya belum tersimpan.
+func MediaGCGrace() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("MEDIA_GC_GRACE_HOURS"))
+	if err != nil || hours <= 0 {
+		hours = 72
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// OrphanAsset aset di Cloudinary yang tidak dirujuk record mana pun
+type OrphanAsset struct {
+	utils.StoredAsset
+	SiapDihapus bool `json:"siap_dihapus"` // sudah melewati masa tenggang
+}
+
+// MediaGCReport hasil rekonsiliasi aset Cloudinary dengan database
+type MediaGCReport struct {
+	Prefix      string        `json:"prefix"`
+	TotalAset   int           `json:"total_aset"`
+	Dirujuk     int           `json:"dirujuk"`
+	Orphan      []OrphanAsset `json:"orphan"`
+	OrphanBytes int64         `json:"orphan_bytes"`
+	Dihapus     int           `json:"dihapus"`
+	GagalHapus  []string      `json:"gagal_hapus,omitempty"`
+}
+
+// ReferencedAssetIDs public id yang masih dirujuk: galeri (foto + thumbnail) dan kolom foto lama, termasuk
+// record di trash karena asetnya baru dihapus saat purge.
+func ReferencedAssetIDs(db *gorm.DB) (map[string]bool, error) {
+	refs := map[string]bool{}
+	pluck := func(model interface{}, column string) error {
+		var ids []string
+		if err := db.Unscoped().Model(model).Where(column+" <> ''").Distinct().Pluck(column, &ids).Error; err != nil {
+			return err
+		}
+		for _, id := range ids {
+			refs[id] = true
+		}
+		return nil
+	}
+
+	for _, col := range []string{"public_id", "thumb_public_id"} {
+		if err := pluck(&models.Media{}, col); err != nil {
+			return nil, err
+		}
+	}
+	for _, e := range TrashEntities {
+		for _, col := range e.AssetColumns {
+			if err := pluck(e.New(), col); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return refs, nil
+}
+
+// ReconcileMedia bandingkan aset Cloudinary di bawah MediaGCPrefix dengan referensi di database.
+// deleteExpired = true menghapus orphan yang sudah melewati MediaGCGrace.
+func ReconcileMedia(db *gorm.DB, deleteExpired bool) (*MediaGCReport, error) {
+	prefix := MediaGCPrefix()
+	// referensi diambil sebelum daftar aset: upload yang selesai di antara keduanya masih terlindungi masa tenggang
+	refs, err := ReferencedAssetIDs(db)
+	if err != nil {
+		return nil, err
+	}
+	assets, err := utils.ListCloudinaryAssets(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &MediaGCReport{Prefix: prefix, TotalAset: len(assets), Orphan: []OrphanAsset{}}
+	cutoff := time.Now().Add(-MediaGCGrace())
+	for _, a := range assets {
+		if refs[a.PublicID] {
+			report.Dirujuk++
+			continue
+		}
+		report.Orphan = append(report.Orphan, OrphanAsset{StoredAsset: a, SiapDihapus: a.CreatedAt.Before(cutoff)})
+		report.OrphanBytes += int64(a.Bytes)
+	}
+	sort.Slice(report.Orphan, func(i, j int) bool {
+		return report.Orphan[i].CreatedAt.Before(report.Orphan[j].CreatedAt)
+	})
+
+	if !deleteExpired {
+		return report, nil
+	}
+	for _, o := range report.Orphan {
+		if !o.SiapDihapus {
+			continue
+		}
+		if err := utils.DeleteCloudinaryAsset(o.PublicID); err != nil {
+			report.GagalHapus = append(report.GagalHapus, o.PublicID)
+			continue
+		}
+		report.Dihapus++
+	}
+	return report, nil
+}
+
+// StartMediaGC rekonsiliasi di background setiap MEDIA_GC_INTERVAL_HOURS (default 24 jam), orphan
+// hanya dihapus jika MediaGCAutoDelete. Tidak jalan tanpa CLOUDINARY_URL.
+func StartMediaGC() {
+	if os.Getenv("CLOUDINARY_URL") == "" {
+		return
+	}
+	hours, err := strconv.Atoi(os.Getenv("MEDIA_GC_INTERVAL_HOURS"))
+	if err != nil || hours <= 0 {
+		hours = 24
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Duration(hours) * time.Hour)
+		defer ticker.Stop()
+
+		for {
+			runMediaGC()
+			<-ticker.C
+		}
+	}()
+}
+
+func runMediaGC() {
+	db, err := DbConnect()
+	if err != nil {
+		log.Printf("media gc: gagal konek DB: %v", err)
+		return
+	}
+
+	report, err := ReconcileMedia(db, MediaGCAutoDelete())
+	if err != nil {
+		log.Printf("media gc: %v", err)
+		return
+	}
+	if len(report.Orphan) > 0 {
+		log.Printf("media gc: %d dari %d aset tanpa referensi (%d byte), %d dihapus, %d gagal",
+			len(report.Orphan), report.TotalAset, report.OrphanBytes, report.Dihapus, len(report.GagalHapus))
+	}
+}
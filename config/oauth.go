@@ -0,0 +1,209 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const googleIssuer = "https://accounts.google.com"
+
+// OAuthProvider menyimpan konfigurasi satu identity provider (Google atau issuer OIDC lain).
+// Endpoint diisi lewat OIDC discovery saat pertama kali dipakai.
+type OAuthProvider struct {
+	Name         string
+	DisplayName  string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+
+	mu          sync.Mutex
+	discovered  bool
+	AuthURL     string
+	TokenURL    string
+	UserInfoURL string
+	JWKSURL     string
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+var (
+	oauthProviders   = map[string]*OAuthProvider{}
+	oauthProvidersMu sync.RWMutex
+)
+
+// InitOAuthProviders membaca konfigurasi provider dari env.
+//
+// Google dikonfigurasi lewat CLIENT_ID_GOOGLE / CLIENT_SECRET_GOOGLE. Provider OIDC lain
+// didaftarkan lewat OAUTH_PROVIDERS="keycloak,mock" lalu OAUTH_<NAMA>_ISSUER,
+// OAUTH_<NAMA>_CLIENT_ID, OAUTH_<NAMA>_CLIENT_SECRET, OAUTH_<NAMA>_SCOPES (opsional)
+// dan OAUTH_<NAMA>_DISPLAY_NAME (opsional).
+func InitOAuthProviders() {
+	providers := map[string]*OAuthProvider{}
+
+	if clientID := os.Getenv("CLIENT_ID_GOOGLE"); clientID != "" {
+		providers["google"] = &OAuthProvider{
+			Name:         "google",
+			DisplayName:  "Google",
+			Issuer:       googleIssuer,
+			ClientID:     clientID,
+			ClientSecret: os.Getenv("CLIENT_SECRET_GOOGLE"),
+			RedirectURL:  oauthRedirectURL("google"),
+			Scopes:       []string{"openid", "email", "profile"},
+		}
+	}
+
+	for _, name := range strings.Split(os.Getenv("OAUTH_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || name == "google" {
+			continue
+		}
+
+		prefix := "OAUTH_" + strings.ToUpper(name) + "_"
+		issuer := strings.TrimRight(os.Getenv(prefix+"ISSUER"), "/")
+		clientID := os.Getenv(prefix + "CLIENT_ID")
+		if issuer == "" || clientID == "" {
+			fmt.Printf("Warning: provider OAuth %q dilewati, %sISSUER / %sCLIENT_ID kosong\n", name, prefix, prefix)
+			continue
+		}
+
+		scopes := []string{"openid", "email", "profile"}
+		if raw := os.Getenv(prefix + "SCOPES"); raw != "" {
+			scopes = strings.Fields(strings.ReplaceAll(raw, ",", " "))
+		}
+
+		displayName := os.Getenv(prefix + "DISPLAY_NAME")
+		if displayName == "" {
+			displayName = name
+		}
+
+		providers[name] = &OAuthProvider{
+			Name:         name,
+			DisplayName:  displayName,
+			Issuer:       issuer,
+			ClientID:     clientID,
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  oauthRedirectURL(name),
+			Scopes:       scopes,
+		}
+	}
+
+	oauthProvidersMu.Lock()
+	oauthProviders = providers
+	oauthProvidersMu.Unlock()
+}
+
+// AccountKey nilai users.auth_provider untuk akun dari provider ini. Dikunci ke nama provider di
+// OAUTH_PROVIDERS, bukan DISPLAY_NAME yang bisa diganti. Google tetap "Google" seperti alur lama.
+func (p *OAuthProvider) AccountKey() string {
+	if p.Name == "google" {
+		return "Google"
+	}
+	return p.Name
+}
+
+// GetOAuthProvider mengembalikan provider terdaftar berdasarkan nama di URL (auth/:provider).
+func GetOAuthProvider(name string) (*OAuthProvider, bool) {
+	oauthProvidersMu.RLock()
+	defer oauthProvidersMu.RUnlock()
+
+	p, ok := oauthProviders[strings.ToLower(name)]
+	return p, ok
+}
+
+// OAuthProviderNames dipakai untuk pesan error / listing provider di FE.
+func OAuthProviderNames() []string {
+	oauthProvidersMu.RLock()
+	defer oauthProvidersMu.RUnlock()
+
+	names := make([]string, 0, len(oauthProviders))
+	for name := range oauthProviders {
+		names = append(names, name)
+	}
+	return names
+}
+
+// redirect URL bisa dioverride per provider, default AUTH_REDIRECT_URL + "/<provider>/callback"
+func oauthRedirectURL(name string) string {
+	if override := os.Getenv("OAUTH_" + strings.ToUpper(name) + "_REDIRECT_URL"); override != "" {
+		return override
+	}
+	return strings.TrimRight(os.Getenv("AUTH_REDIRECT_URL"), "/") + "/" + name + "/callback"
+}
+
+// Discover mengambil endpoint dari <issuer>/.well-known/openid-configuration (sekali saja).
+func (p *OAuthProvider) Discover(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovered {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.Issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("oidc discovery %s gagal: %w", p.Name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("oidc discovery %s gagal: status %d", p.Name, resp.StatusCode)
+	}
+
+	var doc oidcDiscovery
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return fmt.Errorf("oidc discovery %s tidak valid: %w", p.Name, err)
+	}
+
+	if strings.TrimRight(doc.Issuer, "/") != p.Issuer {
+		return fmt.Errorf("issuer discovery %q tidak sama dengan konfigurasi %q", doc.Issuer, p.Issuer)
+	}
+
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JwksURI == "" {
+		return fmt.Errorf("oidc discovery %s tidak lengkap", p.Name)
+	}
+
+	p.AuthURL = doc.AuthorizationEndpoint
+	p.TokenURL = doc.TokenEndpoint
+	p.UserInfoURL = doc.UserinfoEndpoint
+	p.JWKSURL = doc.JwksURI
+	p.discovered = true
+	return nil
+}
+
+// OAuth2Config membangun oauth2.Config dari hasil discovery.
+func (p *OAuthProvider) OAuth2Config() *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     p.ClientID,
+		ClientSecret: p.ClientSecret,
+		RedirectURL:  p.RedirectURL,
+		Scopes:       p.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  p.AuthURL,
+			TokenURL: p.TokenURL,
+		},
+	}
+}
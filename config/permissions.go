@@ -0,0 +1,79 @@
+package config
+
+import "sort"
+
+// Permission nama hak akses dengan format <resource>:<aksi>.
+// Route memakai middleware.RequirePermission, bukan daftar role langsung.
+type Permission string
+
+const (
+	PermKebunWrite       Permission = "kebun:write"
+	PermTanamanWrite     Permission = "tanaman:write"
+	PermFaseWrite        Permission = "fase:write"
+	PermBuahRead         Permission = "buah:read"
+	PermBuahWrite        Permission = "buah:write"
+	PermStatistikRead    Permission = "statistik:read"
+	PermPenyakitClassify Permission = "penyakit:classify"
+	PermBookingRead      Permission = "booking:read"
+	PermBookingWrite     Permission = "booking:write"
+	PermBookingApprove   Permission = "booking:approve" // tandai booking pembeli fulfilled / cancelled
+	PermTwoFactorManage  Permission = "2fa:manage"
+	PermSecurityManage   Permission = "security:manage"
+	PermOrgCreate        Permission = "organization:create"
+	PermVarietasManage   Permission = "varietas:manage"
+)
+
+// rolePermissions satu-satunya tempat pemetaan role -> permission.
+// Baca data kebun / tanaman / fase cukup login, dibatasi organisasi & keanggotaan kebun.
+var rolePermissions = map[string][]Permission{
+	"Admin": {
+		PermKebunWrite,
+		PermTanamanWrite,
+		PermFaseWrite,
+		PermBuahRead,
+		PermBuahWrite,
+		PermStatistikRead,
+		PermPenyakitClassify,
+		PermBookingApprove,
+		PermTwoFactorManage,
+		PermSecurityManage,
+		PermOrgCreate,
+		PermVarietasManage,
+	},
+	"Petani": {
+		PermKebunWrite,
+		PermTanamanWrite,
+		PermFaseWrite,
+		PermBuahRead,
+		PermBuahWrite,
+		PermStatistikRead,
+		PermPenyakitClassify,
+		PermBookingApprove,
+		PermTwoFactorManage,
+		PermOrgCreate,
+	},
+	"Pembeli": {
+		PermBookingRead,
+		PermBookingWrite,
+	},
+}
+
+// HasPermission cek apakah role punya permission tertentu
+func HasPermission(role string, perm Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionsForRole daftar permission efektif sebuah role, terurut
+func PermissionsForRole(role string) []string {
+	perms := make([]string, 0, len(rolePermissions[role]))
+	for _, p := range rolePermissions[role] {
+		perms = append(perms, string(p))
+	}
+	sort.Strings(perms)
+	return perms
+}
@@ -0,0 +1,644 @@
+package config
+
+import (
+	"Avocycle/models"
+	"Avocycle/utils"
+	"math"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	PrakiraanMingguDefault = 12
+	PrakiraanMingguMaks    = 26
+	BacktestTitikDefault   = 4
+	BacktestTitikMaks      = 12
+
+	// nilai awal jika kebun belum punya riwayat untuk kalibrasi
+	prakiraanRasioBungaPentil = 0.05
+	prakiraanRasioPentilCover = 0.5
+	prakiraanRasioPanenCover  = 0.85
+	prakiraanBeratBuahKg      = 0.25
+
+	// rentang riwayat untuk kalibrasi rasio
+	prakiraanHariRiwayat = 730
+	// catatan bunga lebih tua dari ini dianggap sudah rontok atau sudah di-cover
+	prakiraanHariBungaAktif = 120
+	// perkiraan jeda bunga -> pentil dan pentil -> cover
+	prakiraanHariBungaPentil = 30
+	prakiraanHariPentilCover = 21
+	// ketidakpastian tambahan (hari) untuk buah yang masih berupa bunga / pentil
+	prakiraanRentangBunga = 14
+	// buah yang lewat estimasi tapi belum dipanen tetap dihitung di minggu pertama selama ini
+	prakiraanHariTerlambat = 28
+	// minimal buah dipanen sebelum berat rata-rata pohon sendiri dipakai
+	prakiraanSampelBeratPohon = 20
+	// jarak antar titik backtest (minggu)
+	prakiraanLangkahBacktest = 4
+)
+
+// PrakiraanScope cakupan prakiraan: satu kebun, bisa dipersempit ke satu blok atau satu tanaman
+type PrakiraanScope struct {
+	KebunID   uint
+	BlokID    *uint
+	TanamanID *uint
+}
+
+// tanaman pohon dalam cakupan. historis = pohon yang sudah ditanam pada asOf apa pun statusnya sekarang,
+// selain itu hanya pohon yang masih berdiri.
+func (s PrakiraanScope) tanaman(db *gorm.DB, asOf time.Time, historis bool) *gorm.DB {
+	q := db.Model(&models.Tanaman{}).Where("kebun_id = ?", s.KebunID)
+	if s.BlokID != nil {
+		q = q.Where("blok_id = ?", *s.BlokID)
+	}
+	if s.TanamanID != nil {
+		q = q.Where("id = ?", *s.TanamanID)
+	}
+	if historis {
+		return q.Where("tanggal_tanam < ?", asOf)
+	}
+	return q.Where("status IN ?", []string{TanamanStatusActive, TanamanStatusDormant})
+}
+
+// PrakiraanAsumsi rasio yang dipakai, dikalibrasi dari riwayat kebun sebelum tanggal prakiraan
+type PrakiraanAsumsi struct {
+	RasioBungaPentil float64 `json:"rasio_bunga_pentil"` // pentil per bunga
+	RasioPentilCover float64 `json:"rasio_pentil_cover"` // buah di-cover per pentil
+	RasioPanenCover  float64 `json:"rasio_panen_cover"`  // buah dipanen per buah di-cover
+	BeratBuahKg      float64 `json:"berat_buah_kg"`      // rata-rata kebun, pohon dengan riwayat memakai beratnya sendiri
+}
+
+// PrakiraanMinggu perkiraan hasil satu minggu (Senin - Minggu, waktu lokal kebun)
+type PrakiraanMinggu struct {
+	MingguMulai time.Time `json:"minggu_mulai"`
+	JumlahBuah  float64   `json:"jumlah_buah"`
+	BeratKg     float64   `json:"berat_kg"`
+	DariCover   float64   `json:"dari_cover"` // buah dari cover yang sudah tercatat
+	DariBunga   float64   `json:"dari_bunga"` // buah dari bunga / pentil yang belum di-cover
+}
+
+// PrakiraanTanaman total prakiraan satu pohon selama horizon
+type PrakiraanTanaman struct {
+	TanamanID   uint              `json:"tanaman_id"`
+	KodeTanaman string            `json:"kode_tanaman"`
+	BlokID      *uint             `json:"blok_id"`
+	KodeBlok    string            `json:"kode_blok"`
+	BeratBuahKg float64           `json:"berat_buah_kg"`
+	JumlahBuah  float64           `json:"jumlah_buah"`
+	BeratKg     float64           `json:"berat_kg"`
+	Mingguan    []PrakiraanMinggu `json:"-"`
+}
+
+// PrakiraanBlok total prakiraan satu blok selama horizon
+type PrakiraanBlok struct {
+	BlokID        *uint   `json:"blok_id"`
+	KodeBlok      string  `json:"kode_blok"`
+	JumlahTanaman int     `json:"jumlah_tanaman"`
+	JumlahBuah    float64 `json:"jumlah_buah"`
+	BeratKg       float64 `json:"berat_kg"`
+}
+
+// PrakiraanPanen prakiraan jumlah & berat buah per minggu untuk satu cakupan
+type PrakiraanPanen struct {
+	Mulai      time.Time          `json:"mulai"`
+	Minggu     int                `json:"minggu"`
+	JumlahBuah float64            `json:"jumlah_buah"`
+	BeratKg    float64            `json:"berat_kg"`
+	Mingguan   []PrakiraanMinggu  `json:"mingguan"`
+	Tanaman    []PrakiraanTanaman `json:"tanaman,omitempty"`
+	Blok       []PrakiraanBlok    `json:"blok,omitempty"`
+	Asumsi     PrakiraanAsumsi    `json:"asumsi"`
+}
+
+// RingkasPerBlok isi Blok dari total per pohon. Pohon tanpa blok dikumpulkan di entri blok_id null.
+func (p *PrakiraanPanen) RingkasPerBlok() {
+	index := map[uint]int{}
+	p.Blok = []PrakiraanBlok{}
+	for _, t := range p.Tanaman {
+		var key uint
+		if t.BlokID != nil {
+			key = *t.BlokID
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(p.Blok)
+			index[key] = i
+			p.Blok = append(p.Blok, PrakiraanBlok{BlokID: t.BlokID, KodeBlok: t.KodeBlok})
+		}
+		p.Blok[i].JumlahTanaman++
+		p.Blok[i].JumlahBuah = bulatkan(p.Blok[i].JumlahBuah+t.JumlahBuah, 1)
+		p.Blok[i].BeratKg = bulatkan(p.Blok[i].BeratKg+t.BeratKg, 2)
+	}
+	sort.Slice(p.Blok, func(i, j int) bool { return p.Blok[i].KodeBlok < p.Blok[j].KodeBlok })
+}
+
+// MingguMulai Senin 00:00 di zona loc dari minggu yang memuat t
+func MingguMulai(t time.Time, loc *time.Location) time.Time {
+	d := tanggalLokal(t, loc)
+	return d.AddDate(0, 0, -((int(d.Weekday()) + 6) % 7))
+}
+
+// tanggalLokal 00:00 hari kalender t di zona loc
+func tanggalLokal(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// hariSejak selisih hari kalender dari mulai (00:00 lokal) sampai t di zona mulai
+func hariSejak(mulai, t time.Time) int {
+	return int(math.Round(tanggalLokal(t, mulai.Location()).Sub(mulai).Hours() / 24))
+}
+
+// zonaKebun zona waktu lokal kebun, batas minggu prakiraan & backtest mengikuti hari kalender di kebun
+func zonaKebun(db *gorm.DB, kebunID uint) (*time.Location, error) {
+	var kebun models.Kebun
+	if err := db.Select("id", "longitude").Where("id = ?", kebunID).Limit(1).Find(&kebun).Error; err != nil {
+		return nil, err
+	}
+	return utils.ZonaWaktuKebun(kebun.Longitude), nil
+}
+
+// HitungPrakiraanPanen prakiraan per minggu mulai minggu ini dari tiga tahap buah: cover yang belum dipanen
+// (estimasi panen fase buah), bunga / pentil terakhir yang belum di-cover, dan berat buah dari panen sebelumnya.
+func HitungPrakiraanPanen(db *gorm.DB, scope PrakiraanScope, minggu int) (*PrakiraanPanen, error) {
+	loc, err := zonaKebun(db, scope.KebunID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return hitungPrakiraan(db, scope, now, MingguMulai(now, loc), minggu, false)
+}
+
+// hitungPrakiraan hanya memakai data yang tercatat sebelum asOf. historis (backtest) menghitung ulang
+// estimasi panen dengan riwayat sebelum asOf, bukan estimasi yang tersimpan sekarang.
+func hitungPrakiraan(db *gorm.DB, scope PrakiraanScope, asOf, mulai time.Time, minggu int, historis bool) (*PrakiraanPanen, error) {
+	k, err := kalibrasiPrakiraan(db, scope.KebunID, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &PrakiraanPanen{Mulai: mulai, Minggu: minggu, Mingguan: mingguKosong(mulai, minggu), Asumsi: k.PrakiraanAsumsi, Tanaman: []PrakiraanTanaman{}}
+
+	var tanaman []models.Tanaman
+	if err := scope.tanaman(db, asOf, historis).Preload("Varietas").Preload("Kebun").
+		Order("kode_tanaman ASC").Find(&tanaman).Error; err != nil {
+		return nil, err
+	}
+	if len(tanaman) == 0 {
+		return p, nil
+	}
+	ids := scope.tanaman(db, asOf, historis).Select("id")
+
+	var covers []models.FaseBuah
+	if err := db.Where("tanaman_id IN (?)", ids).
+		Where("tanggal_catat < ? AND tanggal_cover < ? AND tanggal_cover >= ?", asOf, asOf, asOf.AddDate(0, 0, -estimasiHariMaks)).
+		Scopes(faseBuahBelumDipanen(asOf)).
+		Find(&covers).Error; err != nil {
+		return nil, err
+	}
+
+	var coverTerakhir []struct {
+		TanamanID uint
+		Terakhir  time.Time
+	}
+	if err := db.Model(&models.FaseBuah{}).Select("tanaman_id, MAX(tanggal_cover) AS terakhir").
+		Where("tanaman_id IN (?) AND tanggal_catat < ?", ids, asOf).
+		Group("tanaman_id").Scan(&coverTerakhir).Error; err != nil {
+		return nil, err
+	}
+	terakhir := map[uint]time.Time{}
+	for _, r := range coverTerakhir {
+		terakhir[r.TanamanID] = r.Terakhir
+	}
+
+	var bungas []models.FaseBunga
+	if err := db.Where("tanaman_id IN (?)", ids).
+		Where("tanggal_catat >= ? AND tanggal_catat < ?", asOf.AddDate(0, 0, -prakiraanHariBungaAktif), asOf).
+		Order("tanggal_catat DESC, id DESC").Find(&bungas).Error; err != nil {
+		return nil, err
+	}
+
+	estimator := NewEstimatorPanen(db)
+	if historis {
+		estimator = NewEstimatorPanenSebelum(db, asOf)
+	}
+
+	pohon := make(map[uint]*PrakiraanTanaman, len(tanaman))
+	dataPohon := make(map[uint]*models.Tanaman, len(tanaman))
+	p.Tanaman = make([]PrakiraanTanaman, len(tanaman))
+	for i := range tanaman {
+		t := &tanaman[i]
+		p.Tanaman[i] = PrakiraanTanaman{
+			TanamanID:   t.ID,
+			KodeTanaman: t.KodeTanaman,
+			BlokID:      t.BlokID,
+			KodeBlok:    t.KodeBlok,
+			BeratBuahKg: k.beratBuah(t),
+			Mingguan:    mingguKosong(mulai, minggu),
+		}
+		pohon[t.ID] = &p.Tanaman[i]
+		dataPohon[t.ID] = t
+	}
+
+	// buah yang sudah di-cover
+	for _, fb := range covers {
+		pt, t := pohon[fb.TanamanID], dataPohon[fb.TanamanID]
+		if pt == nil || fb.TanggalCover == nil {
+			continue
+		}
+		var awal, akhir time.Time
+		switch {
+		case !historis && fb.EstimasiManual && fb.EstimasiPanen != nil:
+			awal, akhir = fb.EstimasiPanen.AddDate(0, 0, -estimasiRentangMin), fb.EstimasiPanen.AddDate(0, 0, estimasiRentangMin)
+		case !historis && fb.EstimasiPanenAwal != nil && fb.EstimasiPanenAkhir != nil:
+			awal, akhir = *fb.EstimasiPanenAwal, *fb.EstimasiPanenAkhir
+		default:
+			est, err := estimator.Hitung(t, *fb.TanggalCover)
+			if err != nil {
+				return nil, err
+			}
+			awal, akhir = est.Awal, est.Akhir
+		}
+		sebarMingguan(pt.Mingguan, mulai, awal, akhir, float64(fb.JumlahCover)*k.RasioPanenCover, true)
+	}
+
+	// bunga / pentil dari catatan terakhir setelah cover terakhir pohon
+	sudah := map[uint]bool{}
+	for _, b := range bungas {
+		if sudah[b.TanamanID] || b.TanggalCatat == nil {
+			continue
+		}
+		sudah[b.TanamanID] = true
+		pt, t := pohon[b.TanamanID], dataPohon[b.TanamanID]
+		if pt == nil || !b.TanggalCatat.After(terakhir[b.TanamanID]) {
+			continue
+		}
+
+		pentil, jeda := float64(b.PentilMuncul), prakiraanHariPentilCover
+		if pentil == 0 {
+			pentil, jeda = float64(b.JumlahBunga)*k.RasioBungaPentil, jeda+prakiraanHariBungaPentil
+		}
+		if pentil <= 0 {
+			continue
+		}
+		est, err := estimator.Hitung(t, b.TanggalCatat.AddDate(0, 0, jeda))
+		if err != nil {
+			return nil, err
+		}
+		sebarMingguan(pt.Mingguan, mulai,
+			est.Awal.AddDate(0, 0, -prakiraanRentangBunga), est.Akhir.AddDate(0, 0, prakiraanRentangBunga),
+			pentil*k.RasioPentilCover*k.RasioPanenCover, false)
+	}
+
+	for i := range p.Tanaman {
+		pt := &p.Tanaman[i]
+		for w := range pt.Mingguan {
+			m := &pt.Mingguan[w]
+			m.BeratKg = m.JumlahBuah * pt.BeratBuahKg
+
+			total := &p.Mingguan[w]
+			total.JumlahBuah += m.JumlahBuah
+			total.BeratKg += m.BeratKg
+			total.DariCover += m.DariCover
+			total.DariBunga += m.DariBunga
+			pt.JumlahBuah += m.JumlahBuah
+			pt.BeratKg += m.BeratKg
+		}
+		bulatkanMingguan(pt.Mingguan)
+		pt.JumlahBuah = bulatkan(pt.JumlahBuah, 1)
+		pt.BeratKg = bulatkan(pt.BeratKg, 2)
+		pt.BeratBuahKg = bulatkan(pt.BeratBuahKg, 3)
+	}
+	for _, m := range p.Mingguan {
+		p.JumlahBuah += m.JumlahBuah
+		p.BeratKg += m.BeratKg
+	}
+	bulatkanMingguan(p.Mingguan)
+	p.JumlahBuah = bulatkan(p.JumlahBuah, 1)
+	p.BeratKg = bulatkan(p.BeratKg, 2)
+	return p, nil
+}
+
+// faseBuahBelumDipanen fase buah yang belum diikuti panen aktual di pohon yang sama sebelum asOf.
+// Panen kurang dari estimasiHariMin setelah cover dianggap milik buah sebelumnya.
+func faseBuahBelumDipanen(asOf time.Time) func(*gorm.DB) *gorm.DB {
+	return func(q *gorm.DB) *gorm.DB {
+		return q.Where("NOT EXISTS (SELECT 1 FROM fase_panens p WHERE p.tanaman_id = fase_buahs.tanaman_id "+
+			"AND p.deleted_at IS NULL AND p.tanggal_panen_aktual >= fase_buahs.tanggal_cover + make_interval(days => ?) "+
+			"AND p.tanggal_panen_aktual < ?)", estimasiHariMin, asOf)
+	}
+}
+
+func mingguKosong(mulai time.Time, minggu int) []PrakiraanMinggu {
+	out := make([]PrakiraanMinggu, minggu)
+	for i := range out {
+		out[i].MingguMulai = mulai.AddDate(0, 0, 7*i)
+	}
+	return out
+}
+
+// sebarMingguan bagi buah merata ke setiap hari rentang [awal, akhir] lalu dijumlah per minggu. Hari yang
+// sudah lewat masuk minggu pertama selama belum lebih dari prakiraanHariTerlambat, di luar horizon dibuang.
+// Hari dihitung menurut kalender di zona mulai (zona kebun).
+func sebarMingguan(mingguan []PrakiraanMinggu, mulai, awal, akhir time.Time, buah float64, dariCover bool) {
+	if buah <= 0 || len(mingguan) == 0 {
+		return
+	}
+	awal = tanggalLokal(awal, mulai.Location())
+	akhir = tanggalLokal(akhir, mulai.Location())
+	if akhir.Before(awal) {
+		akhir = awal
+	}
+
+	hari := hariSejak(awal, akhir) + 1
+	perHari := buah / float64(hari)
+	for d := 0; d < hari; d++ {
+		h := hariSejak(mulai, awal.AddDate(0, 0, d))
+		idx := 0
+		if h >= 0 {
+			idx = h / 7
+		} else if h < -prakiraanHariTerlambat {
+			continue
+		}
+		if idx >= len(mingguan) {
+			break
+		}
+		mingguan[idx].JumlahBuah += perHari
+		if dariCover {
+			mingguan[idx].DariCover += perHari
+		} else {
+			mingguan[idx].DariBunga += perHari
+		}
+	}
+}
+
+func bulatkanMingguan(mingguan []PrakiraanMinggu) {
+	for i := range mingguan {
+		m := &mingguan[i]
+		m.JumlahBuah = bulatkan(m.JumlahBuah, 1)
+		m.BeratKg = bulatkan(m.BeratKg, 2)
+		m.DariCover = bulatkan(m.DariCover, 1)
+		m.DariBunga = bulatkan(m.DariBunga, 1)
+	}
+}
+
+func bulatkan(v float64, desimal int) float64 {
+	pow := math.Pow(10, float64(desimal))
+	return math.Round(v*pow) / pow
+}
+
+type kalibrasi struct {
+	PrakiraanAsumsi
+	beratPohon    map[uint]float64 // kg per buah dari panen pohon sendiri
+	beratVarietas map[uint]float64 // kg per buah varietas di kebun ini
+}
+
+// beratBuah berat per buah pohon: riwayat pohon sendiri, lalu varietas yang sama di kebun,
+// lalu Varietas.BeratBuahGram, terakhir rata-rata kebun
+func (k *kalibrasi) beratBuah(t *models.Tanaman) float64 {
+	if b, ok := k.beratPohon[t.ID]; ok {
+		return b
+	}
+	if t.VarietasID != nil {
+		if b, ok := k.beratVarietas[*t.VarietasID]; ok {
+			return b
+		}
+		if t.Varietas != nil && t.Varietas.BeratBuahGram != nil && *t.Varietas.BeratBuahGram > 0 {
+			return *t.Varietas.BeratBuahGram / 1000
+		}
+	}
+	return k.BeratBuahKg
+}
+
+// riwayatKalibrasi jumlah dari riwayat kebun yang dipakai kalibrasi
+type riwayatKalibrasi struct {
+	Bunga        float64 // bunga tercatat
+	Pentil       float64 // pentil tercatat
+	Cover        float64 // buah di-cover
+	CoverDipanen float64 // buah di-cover yang sudah diikuti panen
+	Panen        float64 // buah dipanen yang didahului cover
+	Berat        []beratPanen
+}
+
+// beratPanen total berat & jumlah buah panen satu pohon
+type beratPanen struct {
+	TanamanID  uint
+	VarietasID *uint
+	Berat      float64
+	Jumlah     float64
+}
+
+// kalibrasiPrakiraan rasio antar tahap dan berat buah dari riwayat kebun sebelum asOf
+func kalibrasiPrakiraan(db *gorm.DB, kebunID uint, asOf time.Time) (*kalibrasi, error) {
+	kebunTanaman := db.Model(&models.Tanaman{}).Select("id").Where("kebun_id = ?", kebunID)
+	dari := asOf.AddDate(0, 0, -prakiraanHariRiwayat)
+	var r riwayatKalibrasi
+
+	var bunga struct {
+		Bunga  float64
+		Pentil float64
+	}
+	if err := db.Model(&models.FaseBunga{}).
+		Select("COALESCE(SUM(jumlah_bunga), 0) AS bunga, COALESCE(SUM(pentil_muncul), 0) AS pentil").
+		Where("tanaman_id IN (?) AND tanggal_catat >= ? AND tanggal_catat < ?", kebunTanaman, dari, asOf).
+		Scan(&bunga).Error; err != nil {
+		return nil, err
+	}
+	r.Bunga, r.Pentil = bunga.Bunga, bunga.Pentil
+
+	if err := db.Model(&models.FaseBuah{}).Select("COALESCE(SUM(jumlah_cover), 0)").
+		Where("tanaman_id IN (?) AND tanggal_cover >= ? AND tanggal_cover < ?", kebunTanaman, dari, asOf).
+		Scan(&r.Cover).Error; err != nil {
+		return nil, err
+	}
+
+	// cover yang sudah dipanen dibanding panen yang didahului cover, di rentang riwayat yang sama
+	if err := db.Model(&models.FaseBuah{}).Select("COALESCE(SUM(fase_buahs.jumlah_cover), 0)").
+		Joins("JOIN LATERAL (SELECT MIN(p.tanggal_panen_aktual) AS tanggal FROM fase_panens p "+
+			"WHERE p.tanaman_id = fase_buahs.tanaman_id AND p.deleted_at IS NULL "+
+			"AND p.tanggal_panen_aktual >= fase_buahs.tanggal_cover + make_interval(days => ?) "+
+			"AND p.tanggal_panen_aktual <= fase_buahs.tanggal_cover + make_interval(days => ?) "+
+			"AND p.tanggal_panen_aktual < ?) AS panen ON panen.tanggal IS NOT NULL",
+			estimasiHariMin, estimasiHariMaks, asOf).
+		Where("fase_buahs.tanaman_id IN (?) AND panen.tanggal >= ?", kebunTanaman, dari).
+		Scan(&r.CoverDipanen).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Model(&models.FasePanen{}).Select("COALESCE(SUM(jumlah_panen), 0)").
+		Where("tanaman_id IN (?) AND tanggal_panen_aktual >= ? AND tanggal_panen_aktual < ?", kebunTanaman, dari, asOf).
+		Where("EXISTS (SELECT 1 FROM fase_buahs b WHERE b.tanaman_id = fase_panens.tanaman_id AND b.deleted_at IS NULL "+
+			"AND b.tanggal_cover <= fase_panens.tanggal_panen_aktual - make_interval(days => ?) "+
+			"AND b.tanggal_cover >= fase_panens.tanggal_panen_aktual - make_interval(days => ?))", estimasiHariMin, estimasiHariMaks).
+		Scan(&r.Panen).Error; err != nil {
+		return nil, err
+	}
+
+	if err := db.Model(&models.FasePanen{}).
+		Select("fase_panens.tanaman_id, t.varietas_id, SUM(fase_panens.berat_total) AS berat, SUM(fase_panens.jumlah_panen) AS jumlah").
+		Joins("JOIN (?) AS t ON t.id = fase_panens.tanaman_id", db.Model(&models.Tanaman{}).Select("id", "varietas_id").Where("kebun_id = ?", kebunID)).
+		Where("fase_panens.jumlah_panen > 0 AND fase_panens.berat_total > 0 AND fase_panens.tanggal_panen_aktual < ?", asOf).
+		Group("fase_panens.tanaman_id, t.varietas_id").
+		Scan(&r.Berat).Error; err != nil {
+		return nil, err
+	}
+	return r.kalibrasi(), nil
+}
+
+// kalibrasi rasio dibatasi ke rentang wajar, berat per buah pohon / varietas / kebun hanya dipakai
+// jika sampelnya minimal prakiraanSampelBeratPohon buah
+func (h riwayatKalibrasi) kalibrasi() *kalibrasi {
+	k := &kalibrasi{
+		PrakiraanAsumsi: PrakiraanAsumsi{
+			RasioBungaPentil: rasioAtauDefault(h.Pentil, h.Bunga, 0.005, 1, prakiraanRasioBungaPentil),
+			RasioPentilCover: rasioAtauDefault(h.Cover, h.Pentil, 0.05, 1, prakiraanRasioPentilCover),
+			RasioPanenCover:  rasioAtauDefault(h.Panen, h.CoverDipanen, 0.1, 1.5, prakiraanRasioPanenCover),
+		},
+		beratPohon:    map[uint]float64{},
+		beratVarietas: map[uint]float64{},
+	}
+
+	var totalBerat, totalJumlah float64
+	varBerat, varJumlah := map[uint]float64{}, map[uint]float64{}
+	for _, r := range h.Berat {
+		totalBerat += r.Berat
+		totalJumlah += r.Jumlah
+		if r.VarietasID != nil {
+			varBerat[*r.VarietasID] += r.Berat
+			varJumlah[*r.VarietasID] += r.Jumlah
+		}
+		if r.Jumlah >= prakiraanSampelBeratPohon {
+			k.beratPohon[r.TanamanID] = r.Berat / r.Jumlah
+		}
+	}
+	for id, j := range varJumlah {
+		if j >= prakiraanSampelBeratPohon {
+			k.beratVarietas[id] = varBerat[id] / j
+		}
+	}
+	k.BeratBuahKg = prakiraanBeratBuahKg
+	if totalJumlah >= prakiraanSampelBeratPohon {
+		k.BeratBuahKg = totalBerat / totalJumlah
+	}
+	k.RasioBungaPentil = bulatkan(k.RasioBungaPentil, 3)
+	k.RasioPentilCover = bulatkan(k.RasioPentilCover, 3)
+	k.RasioPanenCover = bulatkan(k.RasioPanenCover, 3)
+	k.BeratBuahKg = bulatkan(k.BeratBuahKg, 3)
+	return k
+}
+
+// rasioAtauDefault pembilang / penyebut dibatasi [min, max], default jika data belum ada
+func rasioAtauDefault(pembilang, penyebut, min, max, def float64) float64 {
+	if pembilang <= 0 || penyebut <= 0 {
+		return def
+	}
+	return math.Max(min, math.Min(max, pembilang/penyebut))
+}
+
+// BacktestMinggu prediksi vs panen tercatat satu minggu
+type BacktestMinggu struct {
+	MingguMulai  time.Time `json:"minggu_mulai"`
+	PrediksiBuah float64   `json:"prediksi_buah"`
+	AktualBuah   float64   `json:"aktual_buah"`
+	PrediksiKg   float64   `json:"prediksi_kg"`
+	AktualKg     float64   `json:"aktual_kg"`
+}
+
+// BacktestTitik prakiraan yang dibuat seolah-olah pada tanggal Mulai, dibandingkan dengan panen sesudahnya
+type BacktestTitik struct {
+	Mulai        time.Time        `json:"mulai"`
+	PrediksiBuah float64          `json:"prediksi_buah"`
+	AktualBuah   float64          `json:"aktual_buah"`
+	PrediksiKg   float64          `json:"prediksi_kg"`
+	AktualKg     float64          `json:"aktual_kg"`
+	GalatKg      *float64         `json:"galat_kg_persen"` // (prediksi - aktual) / aktual, null jika belum ada panen
+	WAPEKg       *float64         `json:"wape_kg_persen"`  // galat absolut mingguan / total aktual
+	Mingguan     []BacktestMinggu `json:"mingguan"`
+}
+
+// BacktestPrakiraan akurasi prakiraan terhadap panen yang sudah tercatat
+type BacktestPrakiraan struct {
+	Minggu int             `json:"minggu"`
+	Titik  []BacktestTitik `json:"titik"`
+	WAPEKg *float64        `json:"wape_kg_persen"` // gabungan semua titik
+	BiasKg *float64        `json:"bias_kg_persen"` // positif = prakiraan cenderung lebih tinggi
+}
+
+// BacktestPrakiraanPanen ulangi prakiraan pada beberapa titik di masa lalu (terbaru tepat satu horizon
+// sebelum minggu ini, mundur per prakiraanLangkahBacktest minggu) memakai data yang tercatat sebelum titik
+// itu saja, lalu bandingkan dengan panen aktual selama horizon.
+func BacktestPrakiraanPanen(db *gorm.DB, scope PrakiraanScope, minggu, titik int) (*BacktestPrakiraan, error) {
+	loc, err := zonaKebun(db, scope.KebunID)
+	if err != nil {
+		return nil, err
+	}
+	out := &BacktestPrakiraan{Minggu: minggu, Titik: []BacktestTitik{}}
+	terbaru := MingguMulai(time.Now(), loc).AddDate(0, 0, -7*minggu)
+
+	var sumAbs, sumPrediksi, sumAktual float64
+	for i := titik - 1; i >= 0; i-- {
+		mulai := terbaru.AddDate(0, 0, -7*prakiraanLangkahBacktest*i)
+		p, err := hitungPrakiraan(db, scope, mulai, mulai, minggu, true)
+		if err != nil {
+			return nil, err
+		}
+
+		var aktual []models.FasePanen
+		if err := db.Select("tanggal_panen_aktual", "jumlah_panen", "berat_total").
+			Where("tanaman_id IN (?)", scope.tanaman(db, mulai, true).Select("id")).
+			Where("tanggal_panen_aktual >= ? AND tanggal_panen_aktual < ?", mulai, mulai.AddDate(0, 0, 7*minggu)).
+			Find(&aktual).Error; err != nil {
+			return nil, err
+		}
+
+		t, abs := bandingkanBacktest(p, aktual)
+		sumAbs += abs
+		sumPrediksi += t.PrediksiKg
+		sumAktual += t.AktualKg
+		out.Titik = append(out.Titik, t)
+	}
+
+	if sumAktual > 0 {
+		wape := bulatkan(sumAbs/sumAktual*100, 1)
+		bias := bulatkan((sumPrediksi-sumAktual)/sumAktual*100, 1)
+		out.WAPEKg, out.BiasKg = &wape, &bias
+	}
+	return out, nil
+}
+
+// bandingkanBacktest prakiraan p dengan panen aktual per minggu, panen masuk minggu menurut tanggal
+// kalender di zona kebun (zona p.Mulai). Mengembalikan titik backtest dan total galat absolut mingguan (kg).
+func bandingkanBacktest(p *PrakiraanPanen, aktual []models.FasePanen) (BacktestTitik, float64) {
+	t := BacktestTitik{Mulai: p.Mulai, PrediksiBuah: p.JumlahBuah, PrediksiKg: p.BeratKg, Mingguan: make([]BacktestMinggu, len(p.Mingguan))}
+	for w, m := range p.Mingguan {
+		t.Mingguan[w] = BacktestMinggu{MingguMulai: m.MingguMulai, PrediksiBuah: m.JumlahBuah, PrediksiKg: m.BeratKg}
+	}
+	for _, a := range aktual {
+		if a.TanggalPanenAktual == nil {
+			continue
+		}
+		h := hariSejak(p.Mulai, *a.TanggalPanenAktual)
+		if h < 0 || h/7 >= len(t.Mingguan) {
+			continue
+		}
+		t.Mingguan[h/7].AktualBuah += float64(a.JumlahPanen)
+		t.Mingguan[h/7].AktualKg += a.BeratTotal
+	}
+
+	var abs float64
+	for w := range t.Mingguan {
+		m := &t.Mingguan[w]
+		m.AktualKg = bulatkan(m.AktualKg, 2)
+		t.AktualBuah += m.AktualBuah
+		t.AktualKg += m.AktualKg
+		abs += math.Abs(m.PrediksiKg - m.AktualKg)
+	}
+	t.AktualKg = bulatkan(t.AktualKg, 2)
+	if t.AktualKg > 0 {
+		galat := bulatkan((t.PrediksiKg-t.AktualKg)/t.AktualKg*100, 1)
+		wape := bulatkan(abs/t.AktualKg*100, 1)
+		t.GalatKg, t.WAPEKg = &galat, &wape
+	}
+	return t, abs
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"Avocycle/models"
+	"Avocycle/utils"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMingguMulai(t *testing.T) {
+	tests := []struct {
+		name string
+		t    time.Time
+		loc  *time.Location
+		want time.Time
+	}{
+		// Minggu malam UTC sudah Senin di WIB: minggu baru, bukan minggu sebelumnya
+		{"Minggu 18:00 UTC", time.Date(2025, 3, 9, 18, 0, 0, 0, time.UTC), utils.ZonaWIB, time.Date(2025, 3, 10, 0, 0, 0, 0, utils.ZonaWIB)},
+		{"Rabu siang", time.Date(2025, 3, 12, 10, 0, 0, 0, utils.ZonaWIB), utils.ZonaWIB, time.Date(2025, 3, 10, 0, 0, 0, 0, utils.ZonaWIB)},
+		{"tepat Senin 00:00", time.Date(2025, 3, 10, 0, 0, 0, 0, utils.ZonaWIB), utils.ZonaWIB, time.Date(2025, 3, 10, 0, 0, 0, 0, utils.ZonaWIB)},
+		{"Minggu 23:59 WIB", time.Date(2025, 3, 16, 23, 59, 0, 0, utils.ZonaWIB), utils.ZonaWIB, time.Date(2025, 3, 10, 0, 0, 0, 0, utils.ZonaWIB)},
+		{"WIT", time.Date(2025, 3, 9, 15, 30, 0, 0, time.UTC), utils.ZonaWIT, time.Date(2025, 3, 10, 0, 0, 0, 0, utils.ZonaWIT)},
+	}
+	for _, tt := range tests {
+		got := MingguMulai(tt.t, tt.loc)
+		if !got.Equal(tt.want) || got.Location() != tt.loc {
+			t.Errorf("%s: MingguMulai = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSebarMingguan(t *testing.T) {
+	mulai := time.Date(2025, 3, 10, 0, 0, 0, 0, utils.ZonaWIB)
+	// tanggal tersimpan sebagai 00:00 UTC, dibaca sebagai hari kalender yang sama di WIB
+	tgl := func(d int) time.Time { return time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name        string
+		awal, akhir time.Time
+		buah        float64
+		dariCover   bool
+		want        []float64
+	}{
+		{"dua minggu penuh", tgl(10), tgl(23), 14, true, []float64{7, 7, 0}},
+		{"panen Minggu malam UTC = Senin WIB", time.Date(2025, 3, 16, 18, 0, 0, 0, time.UTC), time.Date(2025, 3, 16, 18, 0, 0, 0, time.UTC), 5, true, []float64{0, 5, 0}},
+		{"terlambat masih masuk minggu pertama", tgl(1), tgl(1), 3, true, []float64{3, 0, 0}},
+		{"terlambat lebih dari 28 hari dibuang", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), 3, true, []float64{0, 0, 0}},
+		{"sebagian di luar horizon", tgl(27), time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC), 8, false, []float64{0, 0, 4}},
+		{"akhir sebelum awal dianggap satu hari", tgl(20), tgl(12), 4, false, []float64{0, 4, 0}},
+		{"tanpa buah", tgl(10), tgl(23), 0, true, []float64{0, 0, 0}},
+	}
+	for _, tt := range tests {
+		mingguan := mingguKosong(mulai, len(tt.want))
+		sebarMingguan(mingguan, mulai, tt.awal, tt.akhir, tt.buah, tt.dariCover)
+		for w, m := range mingguan {
+			dari := m.DariBunga
+			if tt.dariCover {
+				dari = m.DariCover
+			}
+			if math.Abs(m.JumlahBuah-tt.want[w]) > 1e-9 || math.Abs(dari-tt.want[w]) > 1e-9 {
+				t.Errorf("%s: minggu %d = %v (cover %v, bunga %v), want %v", tt.name, w, m.JumlahBuah, m.DariCover, m.DariBunga, tt.want[w])
+			}
+		}
+	}
+}
+
+func TestBandingkanBacktest(t *testing.T) {
+	mulai := time.Date(2025, 3, 10, 0, 0, 0, 0, utils.ZonaWIB)
+	p := &PrakiraanPanen{Mulai: mulai, Minggu: 2, Mingguan: mingguKosong(mulai, 2), JumlahBuah: 120, BeratKg: 30}
+	p.Mingguan[0].JumlahBuah, p.Mingguan[0].BeratKg = 40, 10
+	p.Mingguan[1].JumlahBuah, p.Mingguan[1].BeratKg = 80, 20
+
+	at := func(t time.Time) *time.Time { return &t }
+	aktual := []models.FasePanen{
+		// Senin 10 Maret 06:00 WIB
+		{TanggalPanenAktual: at(time.Date(2025, 3, 9, 23, 0, 0, 0, time.UTC)), JumlahPanen: 48, BeratTotal: 12},
+		// Senin 17 Maret 03:00 WIB, minggu kedua walau di UTC masih Minggu
+		{TanggalPanenAktual: at(time.Date(2025, 3, 16, 20, 0, 0, 0, time.UTC)), JumlahPanen: 40, BeratTotal: 8},
+		{TanggalPanenAktual: at(time.Date(2025, 3, 24, 0, 0, 0, 0, time.UTC)), JumlahPanen: 10, BeratTotal: 3},
+		{JumlahPanen: 10, BeratTotal: 3},
+	}
+
+	got, abs := bandingkanBacktest(p, aktual)
+	if got.Mingguan[0].AktualKg != 12 || got.Mingguan[1].AktualKg != 8 || got.AktualBuah != 88 || got.AktualKg != 20 {
+		t.Errorf("aktual = %+v, want minggu 12 kg & 8 kg, total 88 buah 20 kg", got.Mingguan)
+	}
+	if !got.Mingguan[1].MingguMulai.Equal(mulai.AddDate(0, 0, 7)) || got.Mingguan[1].PrediksiKg != 20 {
+		t.Errorf("minggu kedua = %+v", got.Mingguan[1])
+	}
+	// |10 - 12| + |20 - 8| = 14 kg dari aktual 20 kg, prediksi 30 kg
+	if abs != 14 || got.GalatKg == nil || *got.GalatKg != 50 || got.WAPEKg == nil || *got.WAPEKg != 70 {
+		t.Errorf("abs = %v, galat = %v, wape = %v; want 14, 50, 70", abs, got.GalatKg, got.WAPEKg)
+	}
+
+	kosong, _ := bandingkanBacktest(p, nil)
+	if kosong.GalatKg != nil || kosong.WAPEKg != nil {
+		t.Errorf("tanpa panen aktual: galat = %v, wape = %v, want nil", kosong.GalatKg, kosong.WAPEKg)
+	}
+}
+
+func TestKalibrasiRasio(t *testing.T) {
+	tests := []struct {
+		name    string
+		riwayat riwayatKalibrasi
+		want    PrakiraanAsumsi
+	}{
+		{"tanpa riwayat", riwayatKalibrasi{}, PrakiraanAsumsi{prakiraanRasioBungaPentil, prakiraanRasioPentilCover, prakiraanRasioPanenCover, prakiraanBeratBuahKg}},
+		{"dari riwayat", riwayatKalibrasi{Bunga: 2000, Pentil: 100, Cover: 60, CoverDipanen: 50, Panen: 45}, PrakiraanAsumsi{0.05, 0.6, 0.9, prakiraanBeratBuahKg}},
+		{"dibatasi maksimum", riwayatKalibrasi{Bunga: 10, Pentil: 100, Cover: 300, CoverDipanen: 10, Panen: 100}, PrakiraanAsumsi{1, 1, 1.5, prakiraanBeratBuahKg}},
+		{"dibatasi minimum", riwayatKalibrasi{Bunga: 100000, Pentil: 100, Cover: 1, CoverDipanen: 100, Panen: 1}, PrakiraanAsumsi{0.005, 0.05, 0.1, prakiraanBeratBuahKg}},
+		{"pentil belum tercatat", riwayatKalibrasi{Bunga: 500, Cover: 60, CoverDipanen: 50, Panen: 45}, PrakiraanAsumsi{prakiraanRasioBungaPentil, prakiraanRasioPentilCover, 0.9, prakiraanBeratBuahKg}},
+	}
+	for _, tt := range tests {
+		if got := tt.riwayat.kalibrasi().PrakiraanAsumsi; got != tt.want {
+			t.Errorf("%s: asumsi = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestKalibrasiBeratBuah(t *testing.T) {
+	varietas, varietasLain := uint(3), uint(7)
+	k := riwayatKalibrasi{Berat: []beratPanen{
+		{TanamanID: 1, VarietasID: &varietas, Berat: 7.5, Jumlah: 25},
+		{TanamanID: 2, VarietasID: &varietas, Berat: 2, Jumlah: 10},
+		{TanamanID: 3, Berat: 0.5, Jumlah: 5},
+	}}.kalibrasi()
+
+	if k.BeratBuahKg != 0.25 {
+		t.Errorf("berat rata-rata kebun = %v, want 0.25", k.BeratBuahKg)
+	}
+
+	gram := 280.0
+	pohon := func(id uint, varietasID *uint, v *models.Varietas) *models.Tanaman {
+		t := &models.Tanaman{VarietasID: varietasID, Varietas: v}
+		t.ID = id
+		return t
+	}
+	tests := []struct {
+		name    string
+		tanaman *models.Tanaman
+		want    float64
+	}{
+		{"riwayat pohon sendiri (25 buah)", pohon(1, &varietas, nil), 0.3},
+		{"sampel pohon kurang, pakai varietas di kebun", pohon(2, &varietas, nil), 9.5 / 35},
+		{"varietas tanpa riwayat, pakai berat varietas", pohon(9, &varietasLain, &models.Varietas{BeratBuahGram: &gram}), 0.28},
+		{"varietas tanpa data berat", pohon(10, &varietasLain, &models.Varietas{}), 0.25},
+		{"tanpa varietas", pohon(3, nil, nil), 0.25},
+	}
+	for _, tt := range tests {
+		if got := k.beratBuah(tt.tanaman); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: beratBuah = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"Avocycle/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// tanamanKodeIndex unique index kode tanaman per kebun, case-insensitive (A-01 dan a-01 dianggap sama)
+const tanamanKodeIndex = "idx_tanaman_kode_ci"
+
+// dedupeKodeTanaman dijalankan sebelum unique index tanamanKodeIndex dibuat.
+// Kode ganda (tanpa beda huruf besar/kecil) di kebun yang sama diberi akhiran -<id>
+// (yang paling lama tetap), sekali saja.
+func dedupeKodeTanaman(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.Tanaman{}) || db.Migrator().HasIndex(&models.Tanaman{}, tanamanKodeIndex) {
+		return nil
+	}
+
+	table, err := tableOf(db, &models.Tanaman{})
+	if err != nil {
+		return err
+	}
+
+	return db.Exec(
+		"UPDATE ? AS t SET kode_tanaman = LEFT(t.kode_tanaman, 38) || '-' || t.id FROM ("+
+			"SELECT id, ROW_NUMBER() OVER (PARTITION BY kebun_id, LOWER(kode_tanaman) ORDER BY id) AS rn "+
+			"FROM ? WHERE deleted_at IS NULL) AS d WHERE d.id = t.id AND d.rn > 1",
+		clause.Table{Name: table}, clause.Table{Name: table},
+	).Error
+}
+
+// migrateKodeTanamanIndex ganti unique index lama (kebun_id, kode_tanaman) yang masih membedakan
+// huruf besar/kecil dengan index di LOWER(kode_tanaman). AutoMigrate tidak bisa membuat index ekspresi.
+func migrateKodeTanamanIndex(db *gorm.DB) error {
+	if err := dedupeKodeTanaman(db); err != nil {
+		return err
+	}
+	table, err := tableOf(db, &models.Tanaman{})
+	if err != nil {
+		return err
+	}
+	if err := db.Exec(
+		"CREATE UNIQUE INDEX IF NOT EXISTS "+tanamanKodeIndex+" ON ? (kebun_id, LOWER(kode_tanaman)) WHERE deleted_at IS NULL",
+		clause.Table{Name: table},
+	).Error; err != nil {
+		return err
+	}
+	if db.Migrator().HasIndex(&models.Tanaman{}, "idx_tanaman_kode") {
+		return db.Migrator().DropIndex(&models.Tanaman{}, "idx_tanaman_kode")
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"Avocycle/models"
+
+	"gorm.io/gorm"
+)
+
+// status siklus hidup tanaman (lihat models.Tanaman.Status)
+const (
+	TanamanStatusActive    = "Active"
+	TanamanStatusDormant   = "Dormant"
+	TanamanStatusRemoved   = "Removed"
+	TanamanStatusReplanted = "Replanted" // sudah diganti tanaman penerus di posisi yang sama
+)
+
+// tanamanStatusTransitions perubahan status yang boleh lewat endpoint status.
+// Replanted hanya lewat operasi tanam ulang, Removed dan Replanted tidak bisa kembali aktif.
+var tanamanStatusTransitions = map[string][]string{
+	TanamanStatusActive:  {TanamanStatusDormant, TanamanStatusRemoved},
+	TanamanStatusDormant: {TanamanStatusActive, TanamanStatusRemoved},
+}
+
+// TanamanStatusCanChange cek transisi status manual
+func TanamanStatusCanChange(from, to string) bool {
+	for _, s := range tanamanStatusTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TanamanStatusRecordable fase / penyakit hanya dicatat untuk tanaman yang masih berdiri
+func TanamanStatusRecordable(status string) bool {
+	return status == TanamanStatusActive || status == TanamanStatusDormant
+}
+
+// TanamanStatusReplantable tanaman yang boleh diganti penerus
+func TanamanStatusReplantable(status string) bool {
+	return status != TanamanStatusReplanted
+}
+
+// migrateTanamanPosisiIndex index posisi lama belum memperhitungkan status, sehingga penerus hasil
+// tanam ulang tidak bisa menempati posisi pendahulunya. AutoMigrate membuat idx_tanaman_posisi_aktif.
+func migrateTanamanPosisiIndex(db *gorm.DB) error {
+	if !db.Migrator().HasIndex(&models.Tanaman{}, "idx_tanaman_posisi") {
+		return nil
+	}
+	return db.Migrator().DropIndex(&models.Tanaman{}, "idx_tanaman_posisi")
+}
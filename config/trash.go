@@ -0,0 +1,404 @@
+package config
+
+import (
+	"Avocycle/models"
+	"Avocycle/utils"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// TrashEntity entitas yang bisa dilihat di trash, di-restore, dan di-purge permanen.
+// Record anak (ParentEntity) ikut di-restore / di-purge bersama induknya.
+type TrashEntity struct {
+	Name         string // segmen URL, contoh "fase-bunga"
+	Label        string
+	ParentEntity string   // "" untuk kebun
+	ParentColumn string   // kolom foreign key ke induk
+	AssetColumns []string // kolom public id Cloudinary, dihapus saat purge
+	Dependents   []trashDependent
+	// BlocksCascade scope record jenis ini yang membatalkan cascade delete induknya (lihat cascade.go),
+	// contoh booking open. Record yang tidak memenuhi scope ikut ke trash bersama induknya.
+	BlocksCascade func(*gorm.DB) *gorm.DB
+	New           func() interface{}
+	NewSlice      func() interface{}
+}
+
+// trashDependent tabel pendukung (tidak punya trash sendiri) yang ikut dihapus permanen saat purge
+type trashDependent struct {
+	New    func() interface{}
+	Column string
+}
+
+// TrashEntities urutan induk -> anak. Blok sebelum tanaman: purge memproses anak dari belakang,
+// jadi tanaman (yang merujuk blok_id) terhapus lebih dulu dan restore mengembalikan blok lebih dulu.
+var TrashEntities = []TrashEntity{
+	{Name: "kebun", Label: "Kebun",
+		Dependents: []trashDependent{
+			{New: func() interface{} { return &models.KebunMember{} }, Column: "kebun_id"},
+			{New: func() interface{} { return &models.KebunInvitation{} }, Column: "kebun_id"},
+		},
+		New: func() interface{} { return &models.Kebun{} }, NewSlice: func() interface{} { return &[]models.Kebun{} }},
+	{Name: "blok", Label: "Blok", ParentEntity: "kebun", ParentColumn: "kebun_id",
+		New: func() interface{} { return &models.Blok{} }, NewSlice: func() interface{} { return &[]models.Blok{} }},
+	{Name: "tanaman", Label: "Tanaman", ParentEntity: "kebun", ParentColumn: "kebun_id",
+		AssetColumns: []string{"foto_tanaman_id"},
+		New:          func() interface{} { return &models.Tanaman{} }, NewSlice: func() interface{} { return &[]models.Tanaman{} }},
+	{Name: "fase-bunga", Label: "Fase bunga", ParentEntity: "tanaman", ParentColumn: "tanaman_id",
+		New: func() interface{} { return &models.FaseBunga{} }, NewSlice: func() interface{} { return &[]models.FaseBunga{} }},
+	{Name: "fase-berbuah", Label: "Fase buah", ParentEntity: "tanaman", ParentColumn: "tanaman_id",
+		New: func() interface{} { return &models.FaseBuah{} }, NewSlice: func() interface{} { return &[]models.FaseBuah{} }},
+	{Name: "fase-panen", Label: "Fase panen", ParentEntity: "tanaman", ParentColumn: "tanaman_id",
+		AssetColumns: []string{"foto_panen_id"},
+		New:          func() interface{} { return &models.FasePanen{} }, NewSlice: func() interface{} { return &[]models.FasePanen{} }},
+	{Name: "log-penyakit", Label: "Log penyakit", ParentEntity: "tanaman", ParentColumn: "tanaman_id",
+		AssetColumns: []string{"foto_log_penyakit_id"},
+		Dependents:   []trashDependent{{New: func() interface{} { return &models.PerawatanPenyakit{} }, Column: "log_penyakit_tanaman_id"}},
+		New:          func() interface{} { return &models.LogPenyakitTanaman{} }, NewSlice: func() interface{} { return &[]models.LogPenyakitTanaman{} }},
+	{Name: "buah", Label: "Buah", ParentEntity: "tanaman", ParentColumn: "tanaman_id",
+		Dependents: []trashDependent{{New: func() interface{} { return &models.LogProsesProduksi{} }, Column: "buah_id"}},
+		New:        func() interface{} { return &models.Buah{} }, NewSlice: func() interface{} { return &[]models.Buah{} }},
+	{Name: "booking", Label: "Booking", ParentEntity: "tanaman", ParentColumn: "tanaman_id",
+		BlocksCascade: BookingOpen,
+		New:           func() interface{} { return &models.Booking{} }, NewSlice: func() interface{} { return &[]models.Booking{} }},
+}
+
+var (
+	ErrTrashNotFound      = errors.New("record tidak ada di trash")
+	ErrTrashParentDeleted = errors.New("induk record masih di trash, restore induknya dulu")
+	// ErrTrashChildrenRemain induk punya anak yang masih aktif atau di-trash terpisah sebelumnya
+	ErrTrashChildrenRemain = errors.New("masih ada data turunan yang tidak ikut di-trash bersama record ini")
+	// ErrTrashConflict restore bentrok dengan nilai unik record aktif (contoh kode_tanaman dipakai lagi)
+	ErrTrashConflict = errors.New("data aktif lain sudah memakai nilai unik record ini")
+)
+
+// TrashEntityByName cari entitas trash dari segmen URL
+func TrashEntityByName(name string) (*TrashEntity, bool) {
+	for i := range TrashEntities {
+		if TrashEntities[i].Name == name {
+			return &TrashEntities[i], true
+		}
+	}
+	return nil, false
+}
+
+func trashChildren(name string) []*TrashEntity {
+	var children []*TrashEntity
+	for i := range TrashEntities {
+		if TrashEntities[i].ParentEntity == name {
+			children = append(children, &TrashEntities[i])
+		}
+	}
+	return children
+}
+
+// trashDeletedAt waktu soft delete record, nil jika record masih aktif
+func trashDeletedAt(tx *gorm.DB, e *TrashEntity, id uint) (*time.Time, error) {
+	var row struct{ DeletedAt *time.Time }
+	res := tx.Unscoped().Model(e.New()).Select("deleted_at").Where("id = ?", id).Scan(&row)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, ErrTrashNotFound
+	}
+	return row.DeletedAt, nil
+}
+
+// RestoreTrashed mengembalikan record dari trash beserta anak-anaknya yang terhapus
+// bersamaan atau sesudahnya. Anak yang sudah dihapus lebih dulu tetap di trash.
+func RestoreTrashed(db *gorm.DB, e *TrashEntity, id uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		deletedAt, err := trashDeletedAt(tx, e, id)
+		if err != nil {
+			return err
+		}
+		if deletedAt == nil {
+			return ErrTrashNotFound
+		}
+
+		if e.ParentEntity != "" {
+			parent, _ := TrashEntityByName(e.ParentEntity)
+			var parentID uint
+			if err := tx.Unscoped().Model(e.New()).Select(e.ParentColumn).Where("id = ?", id).Scan(&parentID).Error; err != nil {
+				return err
+			}
+			parentDeletedAt, err := trashDeletedAt(tx, parent, parentID)
+			if err != nil && err != ErrTrashNotFound {
+				return err
+			}
+			if err == ErrTrashNotFound || parentDeletedAt != nil {
+				return ErrTrashParentDeleted
+			}
+		}
+
+		return restoreTree(tx, e, id, *deletedAt)
+	})
+}
+
+func restoreTree(tx *gorm.DB, e *TrashEntity, id uint, since time.Time) error {
+	record := e.New()
+	if err := tx.Unscoped().First(record, id).Error; err != nil {
+		return err
+	}
+	if err := restoreConflict(tx, e, id); err != nil {
+		return err
+	}
+	// lewat Model(record) supaya callback audit / history fase ikut mencatat restore
+	if err := tx.Unscoped().Model(record).Update("deleted_at", nil).Error; err != nil {
+		// unique index parsial lain (contoh posisi baris / kolom) yang sudah ditempati record aktif
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return fmt.Errorf("%w: %s (%s #%d)", ErrTrashConflict, pgErr.ConstraintName, e.Label, id)
+		}
+		return err
+	}
+	if err := restoreOwnerMedia(tx, e.Name, id, since); err != nil {
+		return err
+	}
+
+	for _, child := range trashChildren(e.Name) {
+		var ids []uint
+		if err := tx.Unscoped().Model(child.New()).
+			Where(child.ParentColumn+" = ? AND deleted_at >= ?", id, since).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		for _, childID := range ids {
+			if err := restoreTree(tx, child, childID, since); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// restoreConflict kode tanaman hanya unik di antara tanaman aktif, jadi selama di trash kodenya
+// bisa dipakai tanaman baru. Restore ditolak dengan pesan jelas alih-alih error unique index.
+func restoreConflict(tx *gorm.DB, e *TrashEntity, id uint) error {
+	if e.Name != "tanaman" {
+		return nil
+	}
+	var t models.Tanaman
+	if err := tx.Unscoped().Select("id", "kebun_id", "kode_tanaman").First(&t, id).Error; err != nil {
+		return err
+	}
+	var count int64
+	if err := tx.Model(&models.Tanaman{}).
+		Where("kebun_id = ? AND LOWER(kode_tanaman) = LOWER(?) AND id <> ?", t.KebunID, t.KodeTanaman, t.ID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: kode_tanaman %s sudah dipakai tanaman lain di kebun ini", ErrTrashConflict, t.KodeTanaman)
+	}
+	return nil
+}
+
+// purgeBlocked blok masih dirujuk tanaman (aktif atau di trash) yang tidak ikut di-purge. Tanaman
+// anak kebun, bukan anak blok, jadi tidak tertangkap pengecekan anak di purgeTree.
+func purgeBlocked(tx *gorm.DB, e *TrashEntity, ids []uint) error {
+	if e.Name != "blok" {
+		return nil
+	}
+	var count int64
+	if err := tx.Unscoped().Model(&models.Tanaman{}).Where("blok_id IN ?", ids).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: %d Tanaman", ErrTrashChildrenRemain, count)
+	}
+	return nil
+}
+
+// PurgeTrashed menghapus permanen record di trash beserta anak yang ikut di-trash bersamanya.
+// Anak yang masih aktif atau di-trash lebih dulu (punya masa retensi sendiri) tidak ikut dihapus;
+// selama masih ada, purge ditolak dengan ErrTrashChildrenRemain. Foto di Cloudinary baru dihapus
+// setelah transaksi commit, yang gagal tertinggal sebagai aset yatim dan dibersihkan media GC.
+func PurgeTrashed(db *gorm.DB, e *TrashEntity, id uint) error {
+	var assets []string
+	err := db.Transaction(func(tx *gorm.DB) error {
+		deletedAt, err := trashDeletedAt(tx, e, id)
+		if err != nil {
+			return err
+		}
+		if deletedAt == nil {
+			return ErrTrashNotFound
+		}
+		if err := collectTrashAssets(tx, e, []uint{id}, *deletedAt, &assets); err != nil {
+			return err
+		}
+		return purgeTree(tx, e, []uint{id}, *deletedAt)
+	})
+	if err != nil {
+		return err
+	}
+
+	// foto utama lama juga ada di galeri, hapus sekali saja
+	seen := map[string]bool{}
+	for _, publicID := range assets {
+		if seen[publicID] {
+			continue
+		}
+		seen[publicID] = true
+		if err := utils.DeleteCloudinaryAsset(publicID); err != nil {
+			log.Printf("trash: gagal hapus aset %s setelah purge %s #%d: %v", publicID, e.Name, id, err)
+		}
+	}
+	return nil
+}
+
+// trashedChildIDs anak record yang ikut di-trash bersama induknya (deleted_at >= waktu trash induk)
+func trashedChildIDs(tx *gorm.DB, child *TrashEntity, parentIDs []uint, since time.Time) ([]uint, error) {
+	var ids []uint
+	err := tx.Unscoped().Model(child.New()).
+		Where(child.ParentColumn+" IN ? AND deleted_at >= ?", parentIDs, since).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func collectTrashAssets(tx *gorm.DB, e *TrashEntity, ids []uint, since time.Time, assets *[]string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	for _, col := range e.AssetColumns {
+		var publicIDs []string
+		if err := tx.Unscoped().Model(e.New()).Where("id IN ? AND "+col+" <> ''", ids).Pluck(col, &publicIDs).Error; err != nil {
+			return err
+		}
+		*assets = append(*assets, publicIDs...)
+	}
+	if err := collectOwnerMediaAssets(tx, e.Name, ids, assets); err != nil {
+		return err
+	}
+	for _, child := range trashChildren(e.Name) {
+		childIDs, err := trashedChildIDs(tx, child, ids, since)
+		if err != nil {
+			return err
+		}
+		if err := collectTrashAssets(tx, child, childIDs, since, assets); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// purgeTree hapus anak dulu baru induk supaya foreign key tidak dilanggar. Antar anak diproses
+// dari belakang (tanaman sebelum blok yang dirujuknya).
+func purgeTree(tx *gorm.DB, e *TrashEntity, ids []uint, since time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	children := trashChildren(e.Name)
+	for i := len(children) - 1; i >= 0; i-- {
+		child := children[i]
+		var remaining int64
+		if err := tx.Unscoped().Model(child.New()).
+			Where(child.ParentColumn+" IN ? AND (deleted_at IS NULL OR deleted_at < ?)", ids, since).
+			Count(&remaining).Error; err != nil {
+			return err
+		}
+		if remaining > 0 {
+			return fmt.Errorf("%w: %d %s", ErrTrashChildrenRemain, remaining, child.Label)
+		}
+
+		childIDs, err := trashedChildIDs(tx, child, ids, since)
+		if err != nil {
+			return err
+		}
+		if err := purgeTree(tx, child, childIDs, since); err != nil {
+			return err
+		}
+	}
+	if err := purgeBlocked(tx, e, ids); err != nil {
+		return err
+	}
+	for _, dep := range e.Dependents {
+		if err := tx.Unscoped().Where(dep.Column+" IN ?", ids).Delete(dep.New()).Error; err != nil {
+			return err
+		}
+	}
+	if err := purgeOwnerMedia(tx, e.Name, ids); err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("id IN ?", ids).Delete(e.New()).Error
+}
+
+// PurgeExpiredTrash purge semua record yang sudah di trash lebih lama dari retention.
+// Mengembalikan jumlah record induk yang berhasil di-purge.
+func PurgeExpiredTrash(db *gorm.DB, retention time.Duration) (int, error) {
+	cutoff := time.Now().Add(-retention)
+	purged := 0
+	var errs []error
+
+	// anak dulu: anak yang di-trash sendiri lebih awal sudah kedaluwarsa lebih dulu dan
+	// harus hilang sebelum induknya bisa di-purge (lihat ErrTrashChildrenRemain)
+	for i := len(TrashEntities) - 1; i >= 0; i-- {
+		e := &TrashEntities[i]
+		var ids []uint
+		if err := db.Unscoped().Model(e.New()).Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).Pluck("id", &ids).Error; err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, id := range ids {
+			// bisa saja sudah ikut terhapus bersama induknya di iterasi sebelumnya
+			if err := PurgeTrashed(db, e, id); err != nil {
+				if err != ErrTrashNotFound {
+					errs = append(errs, fmt.Errorf("%s #%d: %w", e.Name, id, err))
+				}
+				continue
+			}
+			purged++
+		}
+	}
+	return purged, errors.Join(errs...)
+}
+
+// TrashRetention lama data disimpan di trash sebelum purge otomatis (TRASH_RETENTION_DAYS, default 30 hari)
+func TrashRetention() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS"))
+	if err != nil || days <= 0 {
+		days = 30
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// StartTrashPurger menjalankan purge otomatis di background setiap TRASH_PURGE_INTERVAL_HOURS (default 24 jam)
+func StartTrashPurger() {
+	hours, err := strconv.Atoi(os.Getenv("TRASH_PURGE_INTERVAL_HOURS"))
+	if err != nil || hours <= 0 {
+		hours = 24
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Duration(hours) * time.Hour)
+		defer ticker.Stop()
+
+		for {
+			runTrashPurge()
+			<-ticker.C
+		}
+	}()
+}
+
+func runTrashPurge() {
+	db, err := DbConnect()
+	if err != nil {
+		log.Printf("trash purge: gagal konek DB: %v", err)
+		return
+	}
+
+	purged, err := PurgeExpiredTrash(db, TrashRetention())
+	if err != nil {
+		log.Printf("trash purge: %v", err)
+	}
+	if purged > 0 {
+		log.Printf("trash purge: %d record dihapus permanen", purged)
+	}
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"Avocycle/models"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UploadTempDir direktori file sementara multipart yang melebihi UploadMaxMemory. Package mime/multipart
+// selalu menulis ke os.TempDir(), jadi direktorinya diatur lewat TMPDIR proses (ENV di Dockerfile / systemd).
+// TMPDIR kosong = bawaan sistem, yang tidak dibersihkan StartUploadCleaner karena dipakai proses lain.
+func UploadTempDir() string {
+	return strings.TrimSpace(os.Getenv("TMPDIR"))
+}
+
+// InitUploadTempDir buat direktori TMPDIR jika belum ada, tanpanya setiap upload besar gagal
+func InitUploadTempDir() error {
+	dir := UploadTempDir()
+	if dir == "" {
+		return nil
+	}
+	return os.MkdirAll(dir, 0o700)
+}
+
+// UploadMaxMemory batas isi multipart yang disimpan di memori sebelum tumpah ke UploadTempDir
+// (UPLOAD_MAX_MEMORY_MB, default 32 MB seperti bawaan gin)
+func UploadMaxMemory() int64 {
+	mb, err := strconv.Atoi(os.Getenv("UPLOAD_MAX_MEMORY_MB"))
+	if err != nil || mb <= 0 {
+		mb = 32
+	}
+	return int64(mb) << 20
+}
+
+// UploadSesiTTL umur sesi upload bertahap sejak potongan terakhir (UPLOAD_SESSION_TTL_HOURS, default 24 jam)
+func UploadSesiTTL() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("UPLOAD_SESSION_TTL_HOURS"))
+	if err != nil || hours <= 0 {
+		hours = 24
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// UploadSesiMaxPerUser jumlah sesi upload bertahap yang boleh terbuka bersamaan per user
+// (UPLOAD_SESSION_MAX_PER_USER, default 5). Potongan disimpan di database, jadi dibatasi.
+func UploadSesiMaxPerUser() int64 {
+	n, err := strconv.Atoi(os.Getenv("UPLOAD_SESSION_MAX_PER_USER"))
+	if err != nil || n <= 0 {
+		n = 5
+	}
+	return int64(n)
+}
+
+// StartUploadCleaner hapus sesi upload kedaluwarsa dan file sementara multipart yang tertinggal
+// (misalnya proses mati di tengah request) setiap jam
+func StartUploadCleaner() {
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+
+		for {
+			runUploadCleanup()
+			<-ticker.C
+		}
+	}()
+}
+
+func runUploadCleanup() {
+	db, err := DbConnect()
+	if err != nil {
+		log.Printf("upload cleanup: gagal konek DB: %v", err)
+		return
+	}
+
+	expired, err := PurgeExpiredUploadSesi(db, time.Now())
+	if err != nil {
+		log.Printf("upload cleanup: %v", err)
+	}
+	if expired > 0 {
+		log.Printf("upload cleanup: %d sesi upload kedaluwarsa dihapus", expired)
+	}
+
+	removed := removeStaleUploadFiles(UploadSesiTTL())
+	if removed > 0 {
+		log.Printf("upload cleanup: %d file sementara dihapus", removed)
+	}
+}
+
+// PurgeExpiredUploadSesi hapus sesi yang lewat ExpiresAt, potongannya ikut terhapus (ON DELETE CASCADE)
+func PurgeExpiredUploadSesi(db *gorm.DB, now time.Time) (int64, error) {
+	res := db.Where("expires_at < ?", now).Delete(&models.UploadSesi{})
+	return res.RowsAffected, res.Error
+}
+
+// removeStaleUploadFiles file multipart-* lama di TMPDIR. Direktori bawaan sistem tidak
+// disentuh karena dipakai proses lain.
+func removeStaleUploadFiles(olderThan time.Duration) int {
+	dir := UploadTempDir()
+	if dir == "" {
+		return 0
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, "multipart-*"))
+	if err != nil {
+		return 0
+	}
+	removed := 0
+	cutoff := time.Now().Add(-olderThan)
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() || info.ModTime().After(cutoff) {
+			continue
+		}
+		if os.Remove(path) == nil {
+			removed++
+		}
+	}
+	return removed
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"Avocycle/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// tableOf nama tabel model menurut naming strategy GORM (contoh: Tanaman -> tanamen)
+func tableOf(db *gorm.DB, model interface{}) (string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return "", err
+	}
+	return stmt.Schema.Table, nil
+}
+
+// migrateLegacyVarietas memindahkan kolom teks tanaman.varietas (dulu CHECK Var1/Var2/Var3)
+// ke tabel Varietas + foreign key varietas_id, lalu membuang kolom lama. Aman dijalankan berulang.
+func migrateLegacyVarietas(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&models.Tanaman{}, "varietas") {
+		return nil
+	}
+
+	tanamanTable, err := tableOf(db, &models.Tanaman{})
+	if err != nil {
+		return err
+	}
+	varietasTable, err := tableOf(db, &models.Varietas{})
+	if err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(
+			"INSERT INTO ? (nama, created_at, updated_at) SELECT DISTINCT varietas, NOW(), NOW() FROM ? "+
+				"WHERE varietas IS NOT NULL AND varietas <> '' ON CONFLICT (nama) DO NOTHING",
+			clause.Table{Name: varietasTable}, clause.Table{Name: tanamanTable},
+		).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(
+			"UPDATE ? AS t SET varietas_id = v.id FROM ? AS v WHERE v.nama = t.varietas AND t.varietas_id IS NULL",
+			clause.Table{Name: tanamanTable}, clause.Table{Name: varietasTable},
+		).Error; err != nil {
+			return err
+		}
+
+		// ikut membuang CHECK lama di kolom tersebut
+		return tx.Migrator().DropColumn(&models.Tanaman{}, "varietas")
+	})
+}
@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"Avocycle/config"
+	"Avocycle/models"
+	"Avocycle/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// requestDB koneksi DB yang membawa context request, supaya callback audit
+// tahu actor (user, role, IP) yang melakukan perubahan
+func requestDB(c *gin.Context) (*gorm.DB, error) {
+	db, err := config.DbConnect()
+	if err != nil {
+		return nil, err
+	}
+	return db.WithContext(c.Request.Context()), nil
+}
+
+// parseAuditTime terima format 2006-01-02 atau RFC3339
+func parseAuditTime(raw string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", raw, time.Local)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
+
+// GetAuditLogs godoc
+// @Summary Audit log perubahan data
+// @Description Riwayat create / update / delete kebun, tanaman, fase, booking, dan log penyakit
+// @Description beserta actor, IP, dan diff sebelum / sesudah
+// @Tags Admin Security
+// @Security Bearer
+// @Produce json
+// @Param entity query string false "Kebun / Tanaman / FaseBunga / FaseBuah / FasePanen / Booking / LogPenyakitTanaman"
+// @Param entity_id query int false "ID entitas"
+// @Param actor_id query int false "ID user pelaku"
+// @Param action query string false "create / update / delete"
+// @Param from query string false "Mulai tanggal (YYYY-MM-DD atau RFC3339)"
+// @Param to query string false "Sampai tanggal (YYYY-MM-DD atau RFC3339)"
+// @Param page query int false "Halaman"
+// @Param per_page query int false "Jumlah data per halaman"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /admin/audit-logs [get]
+func GetAuditLogs(c *gin.Context) {
+	page, perPage := utils.GetPagination(c)
+	offset := utils.GetOffset(page, perPage)
+
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	query := db.Model(&models.AuditLog{})
+	if entity := c.Query("entity"); entity != "" {
+		query = query.Where("entity = ?", entity)
+	}
+	if action := c.Query("action"); action != "" {
+		query = query.Where("action = ?", action)
+	}
+	for param, column := range map[string]string{"entity_id": "entity_id", "actor_id": "actor_id"} {
+		raw := c.Query(param)
+		if raw == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, param+" tidak valid", raw)
+			return
+		}
+		query = query.Where(column+" = ?", id)
+	}
+	if raw := c.Query("from"); raw != "" {
+		from, err := parseAuditTime(raw, false)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Format from tidak valid", raw)
+			return
+		}
+		query = query.Where("created_at >= ?", from)
+	}
+	if raw := c.Query("to"); raw != "" {
+		to, err := parseAuditTime(raw, true)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Format to tidak valid", raw)
+			return
+		}
+		query = query.Where("created_at <= ?", to)
+	}
+
+	var totalRows int64
+	if err := query.Count(&totalRows).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menghitung audit log", err.Error())
+		return
+	}
+
+	pagination := utils.CalculatePagination(page, perPage, totalRows)
+
+	var logs []models.AuditLog
+	if err := query.Order("created_at DESC").Limit(perPage).Offset(offset).Find(&logs).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal mengambil audit log", err.Error())
+		return
+	}
+
+	utils.SuccessResponseWithMeta(c, http.StatusOK, "Audit log berhasil diambil", logs, pagination)
+}
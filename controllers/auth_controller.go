@@ -26,6 +26,16 @@ type LoginRequest struct {
     Password string `json:"password" example:"secret123"`
 }
 
+// currentClaims mengambil claims JWT yang disimpan RoleMiddleware di context
+func currentClaims(c *gin.Context) *utils.Claims {
+    if v, ok := c.Get("claims"); ok {
+        if claims, ok := v.(*utils.Claims); ok {
+            return claims
+        }
+    }
+    return nil
+}
+
 // ManualRegisterPetani godoc
 // @Summary Register Petani
 // @Description Register petani menggunakan credential lokal
@@ -269,9 +279,10 @@ func ManualRegisterPembeli(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param user body LoginRequest true "Login credentials"
-// @Success 200 {object} map[string]interface{} "Login success with JWT"
+// @Success 200 {object} map[string]interface{} "Login success with JWT, atau two_factor_required + challenge_token jika 2FA aktif"
 // @Failure 400 {object} map[string]interface{} "Invalid input"
 // @Failure 401 {object} map[string]interface{} "Wrong credentials"
+// @Failure 429 {object} map[string]interface{} "Akun atau IP terkunci sementara"
 // @Router /login [post]
 func ManualLogin(c *gin.Context) {
 	var requestBody struct {
@@ -296,11 +307,19 @@ func ManualLogin(c *gin.Context) {
         return
     }
 
+	// tolak lebih awal jika akun / IP sedang terkunci
+	clientIP := c.ClientIP()
+	if remaining, locked := checkLoginLock(db, requestBody.Email, clientIP); locked {
+		respondLoginLocked(c, remaining)
+		return
+	}
+
 	var user models.User
 
 	// Find user by email
     if err := db.Where("email = ?", requestBody.Email).First(&user).Error; err != nil {
         if err == gorm.ErrRecordNotFound {
+            recordLoginFailure(db, requestBody.Email, clientIP, nil)
             c.JSON(http.StatusUnauthorized, gin.H{
                 "success": false,
                 "error":   "Invalid email or password",
@@ -322,10 +341,37 @@ func ManualLogin(c *gin.Context) {
 
 	// compare password
 	if err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(requestBody.Password)) ; err != nil {
+		recordLoginFailure(db, requestBody.Email, clientIP, &user.ID)
 		c.JSON(http.StatusUnauthorized, gin.H{"error" : "Invalid Username Or Password"})
 		return 
 	}
 
+	// 2FA aktif → jangan kasih JWT dulu, kirim challenge untuk ditukar di /login/2fa
+	var twoFactor models.UserTwoFactor
+	if err := db.Where("user_id = ? AND enabled = ?", user.ID, true).First(&twoFactor).Error; err == nil {
+		challenge, err := utils.GenerateTwoFactorChallenge(&user)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error" : "Failed to Generate 2FA challenge"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"message": "Two-factor authentication required",
+			"data": gin.H{
+				"two_factor_required": true,
+				"challenge_token":     challenge,
+			},
+		})
+		return
+	} else if err != gorm.ErrRecordNotFound {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Database error",
+		})
+		return
+	}
+
 	// generate jwt token
 	token, err := utils.GenerateJWT(&user)
 	if err != nil {
@@ -333,6 +379,8 @@ func ManualLogin(c *gin.Context) {
 		return
 	}
 
+	resetLoginFailures(db, user.Email)
+
 	c.JSON(http.StatusOK, gin.H{
         "success": true,
         "message": "Login successful",
@@ -11,87 +11,6 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// Dipanggil dari CallbackHandlerPembeli / CallbackHandlerPetani
-// defaultRole hanya untuk info ke FE (preselect), role final ditentukan di completeGoogleWithRole.
-func handleGoogleCallback(c *gin.Context, defaultRole string) {
-	state := c.Query("state")
-	code := c.Query("code")
-	provider := "google"
-
-	googleUser, _, err := config.Gocial.Handle(state, code)
-	if err != nil {
-		redirectFrontendWithError(c, "google_handle_error")
-		return
-	}
-
-	db, err := config.DbConnect()
-	if err != nil {
-		redirectFrontendWithError(c, "db_connect_failed")
-		return
-	}
-
-	// cek user lama (auth_provider = "Google")
-	var user models.User
-	db.Where("auth_provider = ? AND provider_id = ?", "Google", googleUser.ID).
-		First(&user)
-
-	if user.ID != 0 {
-		// ========== USER LAMA → langsung kirim JWT ke FE ==========
-		jwtToken, err := utils.GenerateJWT(&user)
-		if err != nil {
-			redirectFrontendWithError(c, "jwt_generate_failed")
-			return
-		}
-
-		frontendCallback := os.Getenv("FRONTEND_GOOGLE_CALLBACK_URL")
-		if frontendCallback == "" {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":    "FRONTEND_GOOGLE_CALLBACK_URL not set",
-				"jwtToken": jwtToken,
-				"user":     user,
-			})
-			return
-		}
-
-		u, _ := url.Parse(frontendCallback)
-		q := u.Query()
-		q.Set("token", jwtToken)
-		u.RawQuery = q.Encode()
-
-		c.Redirect(http.StatusTemporaryRedirect, u.String())
-		return
-	}
-
-	// ========== USER BARU → kirim tempToken ke FE untuk pilih role ==========
-	tempToken, err := utils.GenerateTempGoogleToken(
-		provider,
-		googleUser.ID,
-		googleUser.Email,
-		googleUser.FullName,
-	)
-	if err != nil {
-		redirectFrontendWithError(c, "temp_token_failed")
-		return
-	}
-
-	chooseRoleURL := os.Getenv("FRONTEND_CHOOSE_ROLE_URL")
-	if chooseRoleURL == "" {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":     "FRONTEND_CHOOSE_ROLE_URL not set",
-			"tempToken": tempToken,
-		})
-		return
-	}
-
-	u, _ := url.Parse(chooseRoleURL)
-	q := u.Query()
-	q.Set("tempToken", tempToken)
-	q.Set("defaultRole", defaultRole) // optional untuk preselect di FE
-	u.RawQuery = q.Encode()
-
-	c.Redirect(http.StatusTemporaryRedirect, u.String())
-}
-
 // Body dari FE: { "tempToken": "..." }
 type CompleteGoogleReq struct {
 	TempToken string `json:"tempToken" binding:"required"`
@@ -136,6 +55,20 @@ func completeGoogleWithRole(c *gin.Context, role string) {
 		}
 	}
 
+	challenge, err := twoFactorChallengeFor(db, &user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "two_factor_challenge_failed"})
+		return
+	}
+	if challenge != "" {
+		c.JSON(http.StatusOK, gin.H{
+			"success":             true,
+			"two_factor_required": true,
+			"challenge_token":     challenge,
+		})
+		return
+	}
+
 	jwtToken, err := utils.GenerateJWT(&user)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "jwt_generate_failed"})
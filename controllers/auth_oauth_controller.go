@@ -0,0 +1,315 @@
+package controllers
+
+import (
+	"Avocycle/config"
+	"Avocycle/models"
+	"Avocycle/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+const oauthStateCookie = "avocycle_oauth_state"
+
+// role yang boleh dipilih lewat login OAuth (Admin tidak bisa daftar sendiri)
+func normalizeOAuthRole(role string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(role)) {
+	case "petani":
+		return "Petani", true
+	case "pembeli":
+		return "Pembeli", true
+	default:
+		return "", false
+	}
+}
+
+// RedirectHandler godoc
+// @Summary Login via OAuth / OIDC provider
+// @Description Redirect browser ke halaman login provider (google atau provider OIDC yang dikonfigurasi lewat OAUTH_PROVIDERS).
+// @Description Role dibawa di parameter state yang ditandatangani, lalu diverifikasi di callback.
+// @Description
+// @Description ⚠ Cannot be tested directly via Swagger or Postman, buka di browser biasa.
+// @Tags Auth OAuth
+// @Produce json
+// @Param provider path string true "Nama provider, contoh: google"
+// @Param role query string true "petani / pembeli"
+// @Success 302 {string} string "Redirect ke provider"
+// @Failure 400 {object} utils.Response
+// @Router /auth/{provider} [get]
+func RedirectHandler(c *gin.Context) {
+	redirectToProvider(c, c.Param("provider"), c.Query("role"))
+}
+
+func redirectToProvider(c *gin.Context, providerName, roleParam string) {
+	role, ok := normalizeOAuthRole(roleParam)
+	if !ok {
+		utils.ErrorResponse(c, http.StatusBadRequest, "role tidak valid, gunakan petani atau pembeli", roleParam)
+		return
+	}
+
+	provider, ok := config.GetOAuthProvider(providerName)
+	if !ok {
+		utils.ErrorResponse(c, http.StatusNotFound, "Provider OAuth tidak dikenal", gin.H{
+			"provider":  providerName,
+			"available": config.OAuthProviderNames(),
+		})
+		return
+	}
+
+	if err := provider.Discover(c.Request.Context()); err != nil {
+		utils.ErrorResponse(c, http.StatusBadGateway, "Gagal memuat konfigurasi provider", err.Error())
+		return
+	}
+
+	stateID, err := utils.RandomToken(16)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal membuat state", err.Error())
+		return
+	}
+
+	nonce, err := utils.RandomToken(16)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal membuat nonce", err.Error())
+		return
+	}
+
+	state, err := utils.GenerateOAuthState(provider.Name, role, nonce, stateID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal membuat state", err.Error())
+		return
+	}
+
+	// stateID disimpan di cookie supaya state tidak bisa dipakai dari browser lain (CSRF login)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, stateID, int((10 * time.Minute).Seconds()), "/", "", isSecureRequest(c), true)
+
+	authURL := provider.OAuth2Config().AuthCodeURL(state, oauth2.SetAuthURLParam("nonce", nonce))
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// CallbackHandler godoc
+// @Summary OAuth / OIDC callback
+// @Description Callback dari provider. State dan nonce diverifikasi, id_token dicek ke JWKS provider,
+// @Description lalu user dibuat (jika baru) dengan role dari state dan browser diarahkan ke FRONTEND_GOOGLE_CALLBACK_URL?token=...
+// @Description Jika 2FA aktif, redirect membawa two_factor_required=true&challenge_token=... untuk ditukar di /login/2fa.
+// @Tags Auth OAuth
+// @Produce json
+// @Param provider path string true "Nama provider, contoh: google"
+// @Param state query string true "State dari provider"
+// @Param code query string true "Authorization code"
+// @Success 307 {string} string "Redirect ke frontend dengan token"
+// @Router /auth/{provider}/callback [get]
+func CallbackHandler(c *gin.Context) {
+	providerName := strings.ToLower(c.Param("provider"))
+
+	if providerErr := c.Query("error"); providerErr != "" {
+		redirectFrontendWithError(c, "provider_denied")
+		return
+	}
+
+	stateClaims, err := utils.ParseOAuthState(c.Query("state"))
+	if err != nil {
+		redirectFrontendWithError(c, "invalid_state")
+		return
+	}
+
+	stateID, err := c.Cookie(oauthStateCookie)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, "", -1, "/", "", isSecureRequest(c), true)
+	if err != nil || stateID == "" || stateID != stateClaims.ID || stateClaims.Provider != providerName {
+		redirectFrontendWithError(c, "state_mismatch")
+		return
+	}
+
+	provider, ok := config.GetOAuthProvider(providerName)
+	if !ok {
+		redirectFrontendWithError(c, "unknown_provider")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	if err := provider.Discover(ctx); err != nil {
+		redirectFrontendWithError(c, "provider_discovery_failed")
+		return
+	}
+
+	token, err := provider.OAuth2Config().Exchange(ctx, c.Query("code"))
+	if err != nil {
+		redirectFrontendWithError(c, "code_exchange_failed")
+		return
+	}
+
+	rawIDToken, _ := token.Extra("id_token").(string)
+	if rawIDToken == "" {
+		redirectFrontendWithError(c, "missing_id_token")
+		return
+	}
+
+	idClaims, err := utils.VerifyIDToken(ctx, rawIDToken, provider.JWKSURL, provider.Issuer, provider.ClientID, stateClaims.Nonce)
+	if err != nil {
+		redirectFrontendWithError(c, "invalid_id_token")
+		return
+	}
+
+	// beberapa provider tidak menaruh email/nama di id_token, ambil dari userinfo
+	if (idClaims.Email == "" || idClaims.Name == "") && provider.UserInfoURL != "" {
+		if info, err := fetchOIDCUserInfo(ctx, provider, token); err == nil {
+			if idClaims.Email == "" {
+				idClaims.Email = info.Email
+				idClaims.EmailVerified = info.EmailVerified
+			}
+			if idClaims.Name == "" {
+				idClaims.Name = info.Name
+			}
+		}
+	}
+
+	if idClaims.Subject == "" || idClaims.Email == "" {
+		redirectFrontendWithError(c, "missing_identity")
+		return
+	}
+
+	// provider yang menyatakan email belum diverifikasi ditolak, email dipakai sebagai identitas akun
+	if verified, present := utils.ParseEmailVerified(idClaims.EmailVerified); present && !verified {
+		redirectFrontendWithError(c, "email_not_verified")
+		return
+	}
+
+	db, err := config.DbConnect()
+	if err != nil {
+		redirectFrontendWithError(c, "db_connect_failed")
+		return
+	}
+
+	user, errCode := findOrCreateOAuthUser(db, provider, idClaims, stateClaims.Role)
+	if errCode != "" {
+		redirectFrontendWithError(c, errCode)
+		return
+	}
+
+	// 2FA aktif: FE menerima challenge_token dan menukarnya di /login/2fa, sama seperti login password
+	challenge, err := twoFactorChallengeFor(db, user)
+	if err != nil {
+		redirectFrontendWithError(c, "two_factor_challenge_failed")
+		return
+	}
+
+	jwtToken := ""
+	if challenge == "" {
+		jwtToken, err = utils.GenerateJWT(user)
+		if err != nil {
+			redirectFrontendWithError(c, "jwt_generate_failed")
+			return
+		}
+	}
+
+	frontendCallback := os.Getenv("FRONTEND_GOOGLE_CALLBACK_URL")
+	if frontendCallback == "" {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FRONTEND_GOOGLE_CALLBACK_URL not set"})
+		return
+	}
+
+	u, _ := url.Parse(frontendCallback)
+	q := u.Query()
+	if challenge != "" {
+		q.Set("two_factor_required", "true")
+		q.Set("challenge_token", challenge)
+	} else {
+		q.Set("token", jwtToken)
+	}
+	u.RawQuery = q.Encode()
+
+	c.Redirect(http.StatusTemporaryRedirect, u.String())
+}
+
+// findOrCreateOAuthUser cari akun dari (provider, subject) atau buat baru. Email yang sudah dipakai akun lain
+// (login lokal atau provider lain) tidak digabung otomatis, user diminta login dengan cara lamanya.
+// Mengembalikan kode error untuk FE jika gagal.
+func findOrCreateOAuthUser(db *gorm.DB, provider *config.OAuthProvider, idClaims *utils.IDTokenClaims, role string) (*models.User, string) {
+	var user models.User
+	err := db.Where("auth_provider = ? AND provider_id = ?", provider.AccountKey(), idClaims.Subject).First(&user).Error
+	if err == nil {
+		return &user, ""
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, "db_error"
+	}
+
+	// akun lama disimpan dengan display name provider, pindahkan ke kunci yang stabil
+	if provider.DisplayName != provider.AccountKey() {
+		err := db.Where("auth_provider = ? AND provider_id = ?", provider.DisplayName, idClaims.Subject).First(&user).Error
+		if err == nil {
+			if err := db.Model(&user).Update("auth_provider", provider.AccountKey()).Error; err != nil {
+				return nil, "db_error"
+			}
+			return &user, ""
+		}
+		if err != gorm.ErrRecordNotFound {
+			return nil, "db_error"
+		}
+	}
+
+	email := strings.ToLower(strings.TrimSpace(idClaims.Email))
+	var count int64
+	if err := db.Model(&models.User{}).Where("LOWER(email) = ?", email).Count(&count).Error; err != nil {
+		return nil, "db_error"
+	}
+	if count > 0 {
+		return nil, "email_already_registered"
+	}
+
+	fullName := strings.TrimSpace(idClaims.Name)
+	if fullName == "" {
+		fullName = strings.Split(email, "@")[0]
+	}
+	user = models.User{
+		FullName:     fullName,
+		Email:        email,
+		AuthProvider: provider.AccountKey(),
+		ProviderID:   idClaims.Subject,
+		Role:         role,
+	}
+	if err := db.Create(&user).Error; err != nil {
+		return nil, "create_user_failed"
+	}
+	return &user, ""
+}
+
+type oidcUserInfo struct {
+	Email         string      `json:"email"`
+	EmailVerified interface{} `json:"email_verified"`
+	Name          string      `json:"name"`
+}
+
+func fetchOIDCUserInfo(ctx context.Context, provider *config.OAuthProvider, token *oauth2.Token) (*oidcUserInfo, error) {
+	resp, err := provider.OAuth2Config().Client(ctx, token).Get(provider.UserInfoURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("userinfo status %d", resp.StatusCode)
+	}
+
+	var info oidcUserInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+func isSecureRequest(c *gin.Context) bool {
+	return c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+}
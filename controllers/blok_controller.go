@@ -0,0 +1,477 @@
+package controllers
+
+import (
+	"Avocycle/config"
+	"Avocycle/models"
+	"Avocycle/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// BlokRequest body create / update blok. Untuk update, field null tidak diubah.
+type BlokRequest struct {
+	Kode        *string  `json:"kode" example:"A1"`
+	Nama        *string  `json:"nama" example:"Blok lereng timur"`
+	LuasHektar  *float64 `json:"luas_hektar" example:"0.75"`
+	JumlahBaris *int     `json:"jumlah_baris" example:"10"`
+	JumlahKolom *int     `json:"jumlah_kolom" example:"12"`
+	ZonaIrigasi *string  `json:"zona_irigasi" example:"Zona 1"`
+}
+
+func (r *BlokRequest) apply(b *models.Blok) *string {
+	fail := func(msg string) *string { return &msg }
+
+	if r.Kode != nil {
+		kode := config.NormalizeKodeBlok(*r.Kode)
+		if kode == "" || len(kode) > 25 {
+			return fail("kode wajib diisi, maksimal 25 karakter")
+		}
+		b.Kode = kode
+	}
+	if r.Nama != nil {
+		b.Nama = strings.TrimSpace(*r.Nama)
+	}
+	if r.LuasHektar != nil {
+		if *r.LuasHektar <= 0 {
+			return fail("luas_hektar harus > 0")
+		}
+		b.LuasHektar = r.LuasHektar
+	}
+	if r.JumlahBaris != nil {
+		if *r.JumlahBaris <= 0 {
+			return fail("jumlah_baris harus > 0")
+		}
+		b.JumlahBaris = r.JumlahBaris
+	}
+	if r.JumlahKolom != nil {
+		if *r.JumlahKolom <= 0 {
+			return fail("jumlah_kolom harus > 0")
+		}
+		b.JumlahKolom = r.JumlahKolom
+	}
+	if r.ZonaIrigasi != nil {
+		b.ZonaIrigasi = strings.TrimSpace(*r.ZonaIrigasi)
+	}
+	return nil
+}
+
+// resolveBlok cari blok di kebun dari blok_id, atau dari kode blok (case-insensitive)
+func resolveBlok(db *gorm.DB, kebunID uint, idStr, kode string) (*models.Blok, *string) {
+	var blok models.Blok
+	var err error
+	switch {
+	case idStr != "":
+		id, convErr := strconv.Atoi(idStr)
+		if convErr != nil || id <= 0 {
+			msg := "blok_id tidak valid"
+			return nil, &msg
+		}
+		err = db.Where("kebun_id = ?", kebunID).First(&blok, id).Error
+	case config.NormalizeKodeBlok(kode) != "":
+		err = db.Where("kebun_id = ? AND kode = ?", kebunID, config.NormalizeKodeBlok(kode)).First(&blok).Error
+	default:
+		msg := "blok_id atau kode_blok wajib diisi"
+		return nil, &msg
+	}
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			msg := "blok tidak ditemukan di kebun ini, buat dulu lewat /kebun/{id}/blok"
+			return nil, &msg
+		}
+		msg := "gagal cek blok"
+		return nil, &msg
+	}
+	return &blok, nil
+}
+
+// blokFromParam ambil blok dari :id lalu cek aksi di kebunnya, false jika response error sudah dikirim
+func blokFromParam(c *gin.Context, db *gorm.DB, action config.KebunAction) (*models.Blok, bool) {
+	var blok models.Blok
+	if err := db.First(&blok, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "Blok tidak ditemukan", nil)
+			return nil, false
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil blok", err.Error())
+		return nil, false
+	}
+	if !authorizeKebun(c, db, blok.KebunID, action) {
+		return nil, false
+	}
+	return &blok, true
+}
+
+func blokKodeTaken(db *gorm.DB, kebunID uint, kode string, exceptID uint) bool {
+	var count int64
+	db.Unscoped().Model(&models.Blok{}).Where("kebun_id = ? AND kode = ? AND id <> ?", kebunID, kode, exceptID).Count(&count)
+	return count > 0
+}
+
+// GetKebunBlok godoc
+// @Summary Daftar blok kebun
+// @Description Blok tanam di kebun beserta jumlah tanaman per blok
+// @Tags Blok
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Kebun"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /kebun/{id}/blok [get]
+func GetKebunBlok(c *gin.Context) {
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	kebun, ok := kebunFromParam(c, db)
+	if !ok || !authorizeKebun(c, db, kebun.ID, config.KebunActView) {
+		return
+	}
+
+	var bloks []models.Blok
+	if err := db.Where("kebun_id = ?", kebun.ID).Order("kode ASC").Find(&bloks).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal mengambil blok", err.Error())
+		return
+	}
+
+	var counts []struct {
+		BlokID uint
+		Total  int64
+	}
+	if err := db.Model(&models.Tanaman{}).Select("blok_id, COUNT(*) AS total").
+		Where("kebun_id = ? AND blok_id IS NOT NULL", kebun.ID).Scopes(tanamanAktif).Group("blok_id").Scan(&counts).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menghitung tanaman per blok", err.Error())
+		return
+	}
+	totalByBlok := make(map[uint]int64, len(counts))
+	for _, row := range counts {
+		totalByBlok[row.BlokID] = row.Total
+	}
+
+	result := make([]gin.H, 0, len(bloks))
+	for _, b := range bloks {
+		result = append(result, gin.H{"blok": b, "jumlah_tanaman": totalByBlok[b.ID]})
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Data blok kebun", result)
+}
+
+// CreateBlok godoc
+// @Summary Tambah blok
+// @Description Kode blok unik per kebun (disimpan huruf besar). Hanya owner / manager kebun.
+// @Tags Blok
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "ID Kebun"
+// @Param request body controllers.BlokRequest true "Blok"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /kebun/{id}/blok [post]
+func CreateBlok(c *gin.Context) {
+	var input BlokRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
+		return
+	}
+	if input.Kode == nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "kode wajib diisi", nil)
+		return
+	}
+
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	kebun, ok := kebunFromParam(c, db)
+	if !ok || !authorizeKebun(c, db, kebun.ID, config.KebunActUpdate) {
+		return
+	}
+
+	blok := models.Blok{KebunID: kebun.ID}
+	if msg := input.apply(&blok); msg != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, *msg, nil)
+		return
+	}
+
+	if blokKodeTaken(db, kebun.ID, blok.Kode, 0) {
+		utils.ErrorResponse(c, http.StatusConflict, "Kode blok sudah dipakai di kebun ini", blok.Kode)
+		return
+	}
+
+	if err := db.Create(&blok).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menyimpan blok", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Blok berhasil dibuat", blok)
+}
+
+// GetBlokByID godoc
+// @Summary Detail blok
+// @Tags Blok
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Blok"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /blok/{id} [get]
+func GetBlokByID(c *gin.Context) {
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	blok, ok := blokFromParam(c, db, config.KebunActView)
+	if !ok {
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Detail blok", blok)
+}
+
+// UpdateBlok godoc
+// @Summary Ubah blok
+// @Description Ganti kode blok ikut memperbarui kode_blok semua tanaman di blok tersebut
+// @Tags Blok
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "ID Blok"
+// @Param request body controllers.BlokRequest true "Field yang diubah"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /blok/{id} [put]
+func UpdateBlok(c *gin.Context) {
+	var input BlokRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
+		return
+	}
+
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	blok, ok := blokFromParam(c, db, config.KebunActUpdate)
+	if !ok {
+		return
+	}
+
+	oldKode := blok.Kode
+	if msg := input.apply(blok); msg != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, *msg, nil)
+		return
+	}
+
+	if blok.Kode != oldKode && blokKodeTaken(db, blok.KebunID, blok.Kode, blok.ID) {
+		utils.ErrorResponse(c, http.StatusConflict, "Kode blok sudah dipakai di kebun ini", blok.Kode)
+		return
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(blok).Error; err != nil {
+			return err
+		}
+		if blok.Kode == oldKode {
+			return nil
+		}
+		// kode_blok di tanaman hanya salinan, termasuk tanaman di trash
+		return tx.Unscoped().Model(&models.Tanaman{}).Where("blok_id = ?", blok.ID).Update("kode_blok", blok.Kode).Error
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal update blok", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Blok berhasil diperbarui", blok)
+}
+
+// DeleteBlok godoc
+// @Summary Hapus blok
+// @Description Ditolak jika masih ada tanaman (termasuk di trash) di blok ini
+// @Tags Blok
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Blok"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /blok/{id} [delete]
+func DeleteBlok(c *gin.Context) {
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	blok, ok := blokFromParam(c, db, config.KebunActUpdate)
+	if !ok {
+		return
+	}
+
+	var tanamanCount int64
+	if err := db.Unscoped().Model(&models.Tanaman{}).Where("blok_id = ?", blok.ID).Count(&tanamanCount).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal cek tanaman di blok", err.Error())
+		return
+	}
+	if tanamanCount > 0 {
+		utils.ErrorResponse(c, http.StatusConflict, "Blok masih berisi tanaman", gin.H{"tanaman_count": tanamanCount})
+		return
+	}
+
+	// hard delete supaya kode bisa dipakai lagi (unique per kebun)
+	if err := db.Unscoped().Delete(blok).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal hapus blok", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Blok berhasil dihapus", utils.EmptyObj{})
+}
+
+// GetBlokTanaman godoc
+// @Summary Tanaman dalam blok
+// @Tags Blok
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Blok"
+// @Param page query int false "Halaman"
+// @Param per_page query int false "Jumlah per halaman"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /blok/{id}/tanaman [get]
+func GetBlokTanaman(c *gin.Context) {
+	page, perPage := utils.GetPagination(c)
+	offset := utils.GetOffset(page, perPage)
+
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	blok, ok := blokFromParam(c, db, config.KebunActView)
+	if !ok {
+		return
+	}
+
+	query := db.Model(&models.Tanaman{}).Where("blok_id = ?", blok.ID)
+
+	var totalRows int64
+	if err := query.Count(&totalRows).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menghitung tanaman", err.Error())
+		return
+	}
+
+	pagination := utils.CalculatePagination(page, perPage, totalRows)
+
+	var tanaman []models.Tanaman
+	if err := query.Preload("Varietas").Order("kode_tanaman ASC").Limit(perPage).Offset(offset).Find(&tanaman).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal mengambil tanaman", err.Error())
+		return
+	}
+
+	utils.SuccessResponseWithMeta(c, http.StatusOK, "Tanaman di blok "+blok.Kode, tanaman, pagination)
+}
+
+// GetBlokStatistik godoc
+// @Summary Statistik blok
+// @Description Jumlah tanaman, tanaman sakit (log penyakit terbaru), siap panen (fase buah terbaru),
+// @Description dan total hasil panen di satu blok
+// @Tags Blok
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Blok"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /blok/{id}/statistik [get]
+func GetBlokStatistik(c *gin.Context) {
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	blok, ok := blokFromParam(c, db, config.KebunActView)
+	if !ok {
+		return
+	}
+
+	// hasil panen dihitung dari semua generasi tanaman, hitungan pohon hanya yang Active
+	tanamanIDs := db.Model(&models.Tanaman{}).Select("id").Where("blok_id = ?", blok.ID)
+	aktifIDs := db.Model(&models.Tanaman{}).Select("id").Where("blok_id = ?", blok.ID).Scopes(tanamanAktif)
+
+	var jumlahTanaman int64
+	if err := db.Model(&models.Tanaman{}).Where("blok_id = ?", blok.ID).Scopes(tanamanAktif).Count(&jumlahTanaman).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menghitung tanaman", err.Error())
+		return
+	}
+
+	// kondisi dari log penyakit terbaru per tanaman, sama seperti CountTanamanDiseased
+	latestLog := db.Model(&models.LogPenyakitTanaman{}).
+		Select("tanaman_id, MAX(created_at) AS latest_created_at").
+		Where("tanaman_id IN (?)", aktifIDs).
+		Group("tanaman_id")
+	var tanamanSakit int64
+	if err := db.Table("(?) AS logs", db.Model(&models.LogPenyakitTanaman{})).
+		Joins("JOIN (?) AS latest ON logs.tanaman_id = latest.tanaman_id AND logs.created_at = latest.latest_created_at", latestLog).
+		Where("logs.kondisi IN ?", []string{"Parah", "Sedang", "Ringan"}).
+		Count(&tanamanSakit).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menghitung tanaman sakit", err.Error())
+		return
+	}
+
+	latestBuah := db.Model(&models.FaseBuah{}).
+		Select("tanaman_id, MAX(created_at) AS latest_created_at").
+		Where("tanaman_id IN (?)", aktifIDs).
+		Group("tanaman_id")
+	var siapPanen int64
+	if err := db.Model(&models.FaseBuah{}).
+		Joins("INNER JOIN (?) AS latest ON fase_buahs.tanaman_id = latest.tanaman_id AND fase_buahs.created_at = latest.latest_created_at", latestBuah).
+		Where("fase_buahs.estimasi_panen <= ?", time.Now()).
+		Count(&siapPanen).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menghitung tanaman siap panen", err.Error())
+		return
+	}
+
+	var panen struct {
+		JumlahPanen int64
+		BeratTotal  float64
+	}
+	if err := db.Model(&models.FasePanen{}).
+		Select("COALESCE(SUM(jumlah_panen), 0) AS jumlah_panen, COALESCE(SUM(berat_total), 0) AS berat_total").
+		Where("tanaman_id IN (?)", tanamanIDs).
+		Scan(&panen).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menghitung hasil panen", err.Error())
+		return
+	}
+
+	stat := gin.H{
+		"blok":           blok,
+		"jumlah_tanaman": jumlahTanaman,
+		"tanaman_sakit":  tanamanSakit,
+		"siap_panen":     siapPanen,
+		"jumlah_panen":   panen.JumlahPanen,
+		"berat_panen_kg": panen.BeratTotal,
+	}
+	if blok.LuasHektar != nil && *blok.LuasHektar > 0 {
+		stat["kepadatan_per_hektar"] = float64(jumlahTanaman) / *blok.LuasHektar
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Statistik blok "+blok.Kode, stat)
+}
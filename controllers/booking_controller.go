@@ -20,9 +20,64 @@ type BookingRequest struct {
     TanamanID uint `json:"tanaman_id" example:"10"`
 }
 
+// BookingStatusRequest body ubah status booking
+type BookingStatusRequest struct {
+    Status string `json:"status" example:"fulfilled"`
+}
+
 // --- mutex global untuk menghindari race condition ---
 var bookingMutex sync.Mutex
 
+// ownBooking scope booking milik user login; Admin melihat semua
+func ownBooking(c *gin.Context) func(*gorm.DB) *gorm.DB {
+	return func(q *gorm.DB) *gorm.DB {
+		if claims := currentClaims(c); claims != nil && claims.Role != "Admin" {
+			return q.Where("bookings.user_id = ?", claims.UserID)
+		}
+		return q
+	}
+}
+
+// bookingUserAllowed pembeli hanya boleh memakai user_id miliknya sendiri
+func bookingUserAllowed(c *gin.Context, userID uint) bool {
+	claims := currentClaims(c)
+	if claims != nil && (claims.Role == "Admin" || claims.UserID == userID) {
+		return true
+	}
+	utils.ErrorResponse(c, http.StatusForbidden, "Booking hanya boleh untuk akun Anda sendiri", userID)
+	return false
+}
+
+// bookableTanaman tanaman harus Active dan belum punya booking open lain. excludeID booking yang sedang diubah.
+// false jika response error sudah dikirim.
+func bookableTanaman(c *gin.Context, db *gorm.DB, tanamanID, excludeID uint) bool {
+	var tanaman models.Tanaman
+	if err := db.Select("id", "status").First(&tanaman, tanamanID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Tanaman tidak ditemukan", tanamanID)
+			return false
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal cek tanaman", err.Error())
+		return false
+	}
+	if tanaman.Status != config.TanamanStatusActive {
+		utils.ErrorResponse(c, http.StatusConflict, "Hanya tanaman berstatus Active yang bisa dibooking", gin.H{"tanaman_id": tanamanID, "status": tanaman.Status})
+		return false
+	}
+
+	var existing int64
+	if err := db.Model(&models.Booking{}).Scopes(config.BookingOpen).
+		Where("tanaman_id = ? AND id <> ?", tanamanID, excludeID).Count(&existing).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal cek booking tanaman", err.Error())
+		return false
+	}
+	if existing > 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Tanaman ini sudah dibooking", tanamanID)
+		return false
+	}
+	return true
+}
+
 // GetAllBooking godoc
 // @Summary Get all booking with pagination
 // @Description Retrieve paginated list of booking (hanya untuk role Pembeli)
@@ -31,6 +86,7 @@ var bookingMutex sync.Mutex
 // @Produce json
 // @Param page query int false "Page number"
 // @Param per_page query int false "Items per page"
+// @Param status query string false "Filter status: open, fulfilled, cancelled"
 // @Success 200 {object} utils.Response{data=[]models.SwaggerBooking,meta=utils.Pagination}
 // @Failure 400 {object} utils.Response
 // @Failure 500 {object} utils.Response
@@ -39,14 +95,24 @@ func GetAllBooking(c *gin.Context) {
 	page, perPage := utils.GetPagination(c)
 	offset := utils.GetOffset(page, perPage)
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
 	}
 
+	// pembeli hanya melihat booking miliknya sendiri
+	query := db.Model(&models.Booking{}).Scopes(ownBooking(c))
+	if status := c.Query("status"); status != "" {
+		if !config.ValidBookingStatus(status) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "status tidak valid (open, fulfilled, cancelled)", status)
+			return
+		}
+		query = query.Where("status = ?", status)
+	}
+
 	var totalRows int64
-	if err := db.Model(&models.Booking{}).Count(&totalRows).Error; err != nil {
+	if err := query.Count(&totalRows).Error; err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menghitung total data booking", err.Error())
 		return
 	}
@@ -60,7 +126,7 @@ func GetAllBooking(c *gin.Context) {
 	}
 
 	var bookingList []models.Booking
-	if err := db.Preload("User").Preload("Tanaman.Kebun").
+	if err := query.Preload("User").Preload("Tanaman.Kebun").
 		Limit(perPage).
 		Offset(offset).
 		Find(&bookingList).Error; err != nil {
@@ -78,7 +144,7 @@ func GetAllBooking(c *gin.Context) {
 
 // GetBookingByID godoc
 // @Summary Get booking by ID
-// @Description Retrieve detail booking by ID (role Pembeli)
+// @Description Retrieve detail booking by ID (role Pembeli, hanya booking milik sendiri)
 // @Tags Booking
 // @Security Bearer
 // @Produce json
@@ -90,14 +156,14 @@ func GetAllBooking(c *gin.Context) {
 func GetBookingByID(c *gin.Context) {
 	id := c.Param("id")
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
 	}
 
 	var booking models.Booking
-	if err := db.Preload("User").Preload("Tanaman.Kebun").First(&booking, id).Error; err != nil {
+	if err := db.Scopes(ownBooking(c)).Preload("User").Preload("Tanaman.Kebun").First(&booking, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utils.ErrorResponse(c, http.StatusNotFound, "Booking tidak ditemukan", nil)
 			return
@@ -111,7 +177,7 @@ func GetBookingByID(c *gin.Context) {
 
 // CreateBooking godoc
 // @Summary Create a new booking
-// @Description Booking action by pembeli
+// @Description Booking action by pembeli. Tanaman harus berstatus Active dan belum punya booking open.
 // @Tags Booking
 // @Security Bearer
 // @Accept json
@@ -119,13 +185,14 @@ func GetBookingByID(c *gin.Context) {
 // @Param booking body controllers.BookingRequest true "Booking input"
 // @Success 201 {object} utils.Response{data=models.SwaggerBooking}
 // @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /pembeli/booking [post]
 func CreateBooking(c *gin.Context) {
 	bookingMutex.Lock()         // --- mulai critical section ---
 	defer bookingMutex.Unlock() // --- akhiri critical section ---
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
@@ -140,6 +207,9 @@ func CreateBooking(c *gin.Context) {
 		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
 		return
 	}
+	if !bookingUserAllowed(c, input.UserID) {
+		return
+	}
 
 	// Validasi user
 	var user models.User
@@ -158,27 +228,15 @@ func CreateBooking(c *gin.Context) {
 		return
 	}
 
-	// Validasi tanaman
-	var tanaman models.Tanaman
-	if err := db.First(&tanaman, input.TanamanID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			utils.ErrorResponse(c, http.StatusBadRequest, "Tanaman tidak ditemukan", input.TanamanID)
-			return
-		}
-		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal cek tanaman", err.Error())
-		return
-	}
-
-	// Cegah double booking: tanaman hanya boleh dibooking satu kali
-	var existingBooking models.Booking
-	if err := db.Where("tanaman_id = ?", input.TanamanID).First(&existingBooking).Error; err == nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Tanaman ini sudah dibooking", input.TanamanID)
+	// Validasi tanaman & cegah double booking: satu booking open per tanaman
+	if !bookableTanaman(c, db, input.TanamanID, 0) {
 		return
 	}
 
 	newBooking := models.Booking{
 		UserID:    input.UserID,
 		TanamanID: input.TanamanID,
+		Status:    config.BookingStatusOpen,
 	}
 
 	if err := db.Create(&newBooking).Error; err != nil {
@@ -199,7 +257,8 @@ func CreateBooking(c *gin.Context) {
 
 // UpdateBooking godoc
 // @Summary Update existing booking
-// @Description Update booking by ID (role Pembeli)
+// @Description Update booking by ID (role Pembeli). Hanya booking open yang bisa diubah, status lewat
+// @Description PUT /pembeli/booking/{id}/status.
 // @Tags Booking
 // @Security Bearer
 // @Accept json
@@ -209,19 +268,20 @@ func CreateBooking(c *gin.Context) {
 // @Success 200 {object} utils.Response{data=models.SwaggerBooking}
 // @Failure 400 {object} utils.Response
 // @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /pembeli/booking/{id} [put]
 func UpdateBooking(c *gin.Context) {
 	id := c.Param("id")
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
 	}
 
 	var booking models.Booking
-	if err := db.First(&booking, id).Error; err != nil {
+	if err := db.Scopes(ownBooking(c)).First(&booking, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utils.ErrorResponse(c, http.StatusNotFound, "Booking tidak ditemukan", nil)
 			return
@@ -230,6 +290,11 @@ func UpdateBooking(c *gin.Context) {
 		return
 	}
 
+	if booking.Status != config.BookingStatusOpen {
+		utils.ErrorResponse(c, http.StatusConflict, "Booking yang sudah "+booking.Status+" tidak bisa diubah", nil)
+		return
+	}
+
 	var input struct {
 		UserID    *uint `json:"user_id"`
 		TanamanID *uint `json:"tanaman_id"`
@@ -245,6 +310,9 @@ func UpdateBooking(c *gin.Context) {
 	defer bookingMutex.Unlock()
 
 	if input.UserID != nil {
+		if !bookingUserAllowed(c, *input.UserID) {
+			return
+		}
 		var user models.User
 		if err := db.First(&user, *input.UserID).Error; err != nil {
 			if err == gorm.ErrRecordNotFound {
@@ -257,21 +325,9 @@ func UpdateBooking(c *gin.Context) {
 		booking.UserID = *input.UserID
 	}
 
-	if input.TanamanID != nil {
-		var tanaman models.Tanaman
-		if err := db.First(&tanaman, *input.TanamanID).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				utils.ErrorResponse(c, http.StatusBadRequest, "Tanaman tidak ditemukan", *input.TanamanID)
-				return
-			}
-			utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal cek tanaman", err.Error())
-			return
-		}
-
+	if input.TanamanID != nil && *input.TanamanID != booking.TanamanID {
 		// Cegah double booking
-		var existing models.Booking
-		if err := db.Where("tanaman_id = ? AND id != ?", *input.TanamanID, id).First(&existing).Error; err == nil {
-			utils.ErrorResponse(c, http.StatusBadRequest, "Tanaman sudah dibooking oleh pengguna lain", *input.TanamanID)
+		if !bookableTanaman(c, db, *input.TanamanID, booking.ID) {
 			return
 		}
 		booking.TanamanID = *input.TanamanID
@@ -305,14 +361,14 @@ func UpdateBooking(c *gin.Context) {
 func DeleteBooking(c *gin.Context) {
 	id := c.Param("id")
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
 	}
 
 	var booking models.Booking
-	if err := db.Preload("User").Preload("Tanaman").First(&booking, id).Error; err != nil {
+	if err := db.Scopes(ownBooking(c)).Preload("User").Preload("Tanaman").First(&booking, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utils.ErrorResponse(c, http.StatusNotFound, "Booking tidak ditemukan", nil)
 			return
@@ -331,7 +387,7 @@ func DeleteBooking(c *gin.Context) {
 
 // GetBookingByUserID godoc
 // @Summary Get booking list by user ID
-// @Description Retrieve user's booking list with pagination
+// @Description Retrieve user's booking list with pagination. Pembeli hanya boleh melihat user_id miliknya sendiri.
 // @Tags Booking
 // @Security Bearer
 // @Produce json
@@ -340,6 +396,7 @@ func DeleteBooking(c *gin.Context) {
 // @Param per_page query int false "Items per page"
 // @Success 200 {object} utils.Response{data=[]models.SwaggerBooking,meta=utils.Pagination}
 // @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /pembeli/booking/user/{user_id} [get]
 func GetBookingByUserID(c *gin.Context) {
@@ -349,8 +406,11 @@ func GetBookingByUserID(c *gin.Context) {
 		utils.ErrorResponse(c, http.StatusBadRequest, "user_id tidak valid", userID)
 		return
 	}
+	if !bookingUserAllowed(c, uint(uid)) {
+		return
+	}
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
@@ -385,3 +445,121 @@ func GetBookingByUserID(c *gin.Context) {
 
 	utils.SuccessResponseWithMeta(c, http.StatusOK, "Data booking user berhasil diambil", userBookings, pagination)
 }
+
+// GetTanamanBooking godoc
+// @Summary Booking sebuah tanaman
+// @Description Riwayat booking tanaman untuk anggota kebun, booking open paling atas
+// @Tags Booking
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Tanaman"
+// @Success 200 {object} utils.Response{data=[]models.SwaggerBooking}
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tanaman/{id}/booking [get]
+func GetTanamanBooking(c *gin.Context) {
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	tanamanID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "id tidak valid", c.Param("id"))
+		return
+	}
+	if !authorizeTanaman(c, db, uint(tanamanID), config.KebunActView) {
+		return
+	}
+
+	var bookings []models.Booking
+	// kontak pemesan saja, bukan seluruh kolom user
+	contact := func(q *gorm.DB) *gorm.DB { return q.Select("id", "full_name", "phone", "email") }
+	if err := db.Preload("User", contact).Where("tanaman_id = ?", tanamanID).
+		Order("status = 'open' DESC, created_at DESC").Find(&bookings).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal mengambil data booking", err.Error())
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "Data booking tanaman berhasil diambil", bookings)
+}
+
+// UpdateBookingStatus godoc
+// @Summary Ubah status booking
+// @Description Pembeli hanya bisa membatalkan booking miliknya (cancelled). Pengguna dengan permission booking:approve
+// @Description yang punya hak tulis tanaman di kebunnya menandai booking fulfilled / cancelled. Booking yang sudah selesai / batal tidak bisa dibuka lagi.
+// @Tags Booking
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Booking ID"
+// @Param body body controllers.BookingStatusRequest true "Status baru"
+// @Success 200 {object} utils.Response{data=models.SwaggerBooking}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /pembeli/booking/{id}/status [put]
+// @Router /booking/{id}/status [put]
+func UpdateBookingStatus(c *gin.Context) {
+	var input struct {
+		Status string `json:"status" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
+		return
+	}
+	if !config.ValidBookingStatus(input.Status) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "status tidak valid (fulfilled, cancelled)", input.Status)
+		return
+	}
+
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	var booking models.Booking
+	if err := db.First(&booking, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "Booking tidak ditemukan", nil)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil data booking", err.Error())
+		return
+	}
+
+	// pemesan boleh membatalkan bookingnya sendiri, selebihnya urusan pengelola kebun yang punya booking:approve
+	claims := currentClaims(c)
+	if !(claims.UserID == booking.UserID && input.Status == config.BookingStatusCancelled) {
+		if !config.HasPermission(claims.Role, config.PermBookingApprove) {
+			utils.ErrorResponse(c, http.StatusForbidden, "Peran Anda tidak bisa mengubah status booking ini", nil)
+			return
+		}
+		if !authorizeTanaman(c, db, booking.TanamanID, config.KebunActTanamanWrite) {
+			return
+		}
+	}
+
+	if !config.BookingStatusCanChange(booking.Status, input.Status) {
+		utils.ErrorResponse(c, http.StatusConflict, fmt.Sprintf("Status booking tidak bisa diubah dari %s ke %s", booking.Status, input.Status), nil)
+		return
+	}
+
+	res := db.Model(&booking).Where("status = ?", booking.Status).Update("status", input.Status)
+	if res.Error != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ubah status booking", res.Error.Error())
+		return
+	}
+	if res.RowsAffected == 0 {
+		utils.ErrorResponse(c, http.StatusConflict, "Status booking sudah berubah, muat ulang", nil)
+		return
+	}
+
+	if err := db.Preload("User").Preload("Tanaman.Kebun").First(&booking, booking.ID).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil data booking terbaru", err.Error())
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "Status booking menjadi "+booking.Status, booking)
+}
@@ -21,6 +21,7 @@ import (
 // @Success 200 {object} utils.Response
 // @Failure 400 {object} utils.Response
 // @Failure 500 {object} utils.Response
+// @Param organization_id query int false "Filter satu organisasi"
 // @Router /petani/buah [get]
 func GetAllBuah(c *gin.Context){
 	// get pagination parameters
@@ -34,9 +35,14 @@ func GetAllBuah(c *gin.Context){
 		return
 	}
 
+	tenant, ok := resolveTenant(c, db)
+	if !ok {
+		return
+	}
+
 	// count total rows
 	var totalRows int64
-	if err := db.Model(&models.Buah{}).Count(&totalRows).Error; err != nil {
+	if err := db.Model(&models.Buah{}).Scopes(tenant.ByTanaman("tanaman_id")).Count(&totalRows).Error; err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to count buah data", err.Error())
 		return
 	}
@@ -56,7 +62,7 @@ func GetAllBuah(c *gin.Context){
 
 	// get paginated data
 	var buahList []models.Buah
-	if err := db.Preload("Tanaman.Kebun").
+	if err := db.Scopes(tenant.ByTanaman("tanaman_id")).Preload("Tanaman.Kebun").
 		Limit(perPage).
 		Offset(offset).
 		Find(&buahList).Error; err != nil {
@@ -94,8 +100,13 @@ func GetBuahByID(c *gin.Context) {
         return
     }
 
+    tenant, ok := resolveTenant(c, db)
+    if !ok {
+        return
+    }
+
     var buah models.Buah
-    if err := db.Preload("Tanaman.Kebun").First(&buah, id).Error; err != nil {
+    if err := db.Scopes(tenant.ByTanaman("tanaman_id")).Preload("Tanaman.Kebun").First(&buah, id).Error; err != nil {
         utils.ErrorResponse(c, http.StatusNotFound, "Buah not found", err.Error())
         return
     }
@@ -281,10 +292,15 @@ func GetBuahByKebun(c *gin.Context) {
         return
     }
 
+    tenant, ok := resolveTenant(c, db)
+    if !ok {
+        return
+    }
+
     // 1. Cari semua ID Tanaman yang terkait dengan Kebun ID ini
     var tanamanIDs []uint
     // Menggunakan Pluck untuk mendapatkan daftar ID tanaman
-    if err := db.Model(&models.Tanaman{}).
+    if err := db.Model(&models.Tanaman{}).Scopes(tenant.ByKebun("kebun_id")).
         Where("kebun_id = ?", idKebun).
         Pluck("id", &tanamanIDs).Error; err != nil {
         utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve related Tanaman IDs", err.Error())
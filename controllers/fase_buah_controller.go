@@ -2,6 +2,7 @@ package controllers
 
 import (
     "fmt"
+    "mime/multipart"
     "net/http"
     "strconv"
     "time"
@@ -24,6 +25,19 @@ type CreateFaseBuahInput struct {
     TanamanID     uint   `json:"tanaman_id" example:"5"`
 }
 
+// UpdateFaseBuahInput semua field opsional. Estimasi manual dipertahankan saat cover / tanaman berubah
+// kecuali hitung_ulang_estimasi = true.
+type UpdateFaseBuahInput struct {
+    MingguKe            *int    `json:"minggu_ke" example:"3"`
+    TanggalCatat        *string `json:"tanggal_catat" example:"2025-11-29"`
+    TanggalCover        *string `json:"tanggal_cover" example:"2025-11-30"`
+    JumlahCover         *int    `json:"jumlah_cover" example:"15"`
+    WarnaLabel          *string `json:"warna_label" example:"Hijau"`
+    EstimasiPanen       *string `json:"estimasi_panen" example:"2026-04-20"` // estimasi manual
+    HitungUlangEstimasi bool    `json:"hitung_ulang_estimasi" example:"false"`
+    TanamanID           *uint   `json:"tanaman_id" example:"5"`
+}
+
 // FaseBuah model example
 // @Description Data fase berbuah
 type FaseBuah struct {
@@ -34,6 +48,11 @@ type FaseBuah struct {
     JumlahCover    int        `json:"jumlah_cover" example:"10"`
     WarnaLabel     string     `json:"warna_label,omitempty" example:"Hijau"`
     EstimasiPanen  *time.Time `json:"estimasi_panen"`
+    EstimasiPanenAwal  *time.Time `json:"estimasi_panen_awal,omitempty"`
+    EstimasiPanenAkhir *time.Time `json:"estimasi_panen_akhir,omitempty"`
+    EstimasiKeyakinan  *float64   `json:"estimasi_keyakinan,omitempty" example:"0.65"`
+    EstimasiSumber     string     `json:"estimasi_sumber,omitempty" example:"riwayat_kebun_varietas"`
+    EstimasiManual     bool       `json:"estimasi_manual" example:"false"`
     TanamanID      uint       `json:"tanaman_id" example:"5"`
 }
 
@@ -69,19 +88,25 @@ func parseAndValidateEstimasiPanen(dateStr string) (time.Time, error) {
 // @Param per_page query int false "Jumlah item per halaman" default(10)
 // @Success 200 {object} utils.Response
 // @Failure 500 {object} utils.Response
+// @Param organization_id query int false "Filter satu organisasi"
 // @Router /petani/fase-berbuah [get]
 func GetAllFaseBuah(c *gin.Context) {
 	page, perPage := utils.GetPagination(c)
 	offset := utils.GetOffset(page, perPage)
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
 	}
 
+	tenant, ok := resolveTenant(c, db)
+	if !ok {
+		return
+	}
+
 	var totalRows int64
-	if err := db.Model(&models.FaseBuah{}).Count(&totalRows).Error; err != nil {
+	if err := db.Model(&models.FaseBuah{}).Scopes(tenant.ByTanaman("tanaman_id")).Count(&totalRows).Error; err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal hitung data fase berbuah", err.Error())
 		return
 	}
@@ -97,7 +122,7 @@ func GetAllFaseBuah(c *gin.Context) {
 	}
 
 	var faseBuahList []models.FaseBuah
-	if err := db.Preload("Tanaman").
+	if err := db.Scopes(tenant.ByTanaman("tanaman_id")).Preload("Tanaman").
 		Limit(perPage).
 		Offset(offset).
 		Find(&faseBuahList).Error; err != nil {
@@ -126,14 +151,19 @@ func GetAllFaseBuah(c *gin.Context) {
 func GetFaseBuahByID(c *gin.Context) {
 	id := c.Param("id")
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
 	}
 
+	tenant, ok := resolveTenant(c, db)
+	if !ok {
+		return
+	}
+
 	var faseBuah models.FaseBuah
-	if err := db.Preload("Tanaman").First(&faseBuah, id).Error; err != nil {
+	if err := db.Scopes(tenant.ByTanaman("tanaman_id")).Preload("Tanaman").First(&faseBuah, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utils.ErrorResponse(c, http.StatusNotFound, "Fase berbuah tidak ditemukan", nil)
 			return
@@ -145,34 +175,83 @@ func GetFaseBuahByID(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Detail fase berbuah", faseBuah)
 }
 
+// GetFaseBuahEstimasi godoc
+// @Summary Rincian estimasi panen fase berbuah
+// @Description Hitung estimasi panen dengan data terkini: varietas, ketinggian kebun, tanggal cover dan
+// @Description riwayat interval cover-panen kebun. Tidak mengubah estimasi yang tersimpan.
+// @Tags Fase Berbuah
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Fase Buah"
+// @Success 200 {object} utils.Response{data=config.EstimasiPanen}
+// @Failure 404 {object} utils.Response
+// @Router /fase-berbuah/{id}/estimasi [get]
+func GetFaseBuahEstimasi(c *gin.Context) {
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	tenant, ok := resolveTenant(c, db)
+	if !ok {
+		return
+	}
+
+	var faseBuah models.FaseBuah
+	if err := db.Scopes(tenant.ByTanaman("tanaman_id")).Preload("Tanaman").First(&faseBuah, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "Fase berbuah tidak ditemukan", nil)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil fase berbuah", err.Error())
+		return
+	}
+
+	estimasi, err := config.HitungEstimasiPanen(db, &faseBuah.Tanaman, *faseBuah.TanggalCover)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal hitung estimasi panen", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Estimasi panen", estimasi)
+}
+
 // POST /fase-berbuah
 // @Summary Create fase berbuah
-// @Description Menambahkan data fase berbuah baru untuk tanaman
+// @Description Menambahkan data fase berbuah baru untuk tanaman. Bisa juga dikirim sebagai multipart/form-data dengan
+// @Description field yang sama ditambah foto (opsional, masuk galeri); tanggal_catat kosong memakai tanggal EXIF foto.
 // @Tags Fase Berbuah
 // @Accept json
+// @Accept mpfd
 // @Produce json
 // @Security Bearer
 // @Param request body CreateFaseBuahInput true "Fase Buah Data"
 // @Success 201 {object} utils.Response
 // @Failure 400 {object} utils.Response
 // @Failure 500 {object} utils.Response
+// @Router /fase-berbuah [post]
 // @Router /petani/fase-berbuah [post]
 func CreateFaseBuah(c *gin.Context) {
 	var input struct {
-		MingguKe      int    `json:"minggu_ke" binding:"required"`
-		TanggalCatat  string `json:"tanggal_catat" binding:"required"` // YYYY-MM-DD
-		TanggalCover  string `json:"tanggal_cover" binding:"required"` // YYYY-MM-DD
-		JumlahCover   int    `json:"jumlah_cover" binding:"required"`
-		WarnaLabel    string `json:"warna_label,omitempty"`
-		TanamanID     uint   `json:"tanaman_id" binding:"required"`
+		MingguKe      int                   `json:"minggu_ke" form:"minggu_ke" binding:"required"`
+		TanggalCatat  string                `json:"tanggal_catat" form:"tanggal_catat"` // YYYY-MM-DD, default tanggal EXIF foto
+		TanggalCover  string                `json:"tanggal_cover" form:"tanggal_cover" binding:"required"` // YYYY-MM-DD
+		JumlahCover   int                   `json:"jumlah_cover" form:"jumlah_cover" binding:"required"`
+		WarnaLabel    string                `json:"warna_label,omitempty" form:"warna_label"`
+		TanamanID     uint                  `json:"tanaman_id" form:"tanaman_id" binding:"required"`
+		Foto          *multipart.FileHeader `json:"-" form:"foto"`
 	}
 
-	if err := c.ShouldBindJSON(&input); err != nil {
+	if err := c.ShouldBind(&input); err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
 		return
 	}
+	if !defaultTanggalDariFoto(c, &input.TanggalCatat, input.Foto, "tanggal_catat") {
+		return
+	}
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
@@ -210,14 +289,24 @@ func CreateFaseBuah(c *gin.Context) {
 		return
 	}
 
+	if !authorizeTanaman(c, db, input.TanamanID, config.KebunActFaseWrite) {
+		return
+	}
+	if !ensureTanamanRecordable(c, db, input.TanamanID) {
+		return
+	}
+
 	var tanaman models.Tanaman
 	if err := db.First(&tanaman, input.TanamanID).Error; err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil data tanaman", err.Error())
 		return
 	}
 
-	estimasiPanen := parsedTanggalCover.AddDate(0, 0, tanaman.MasaProduksi)
-
+	estimasi, err := config.HitungEstimasiPanen(db, &tanaman, parsedTanggalCover)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal hitung estimasi panen", err.Error())
+		return
+	}
 
 	// Buat FaseBuah
 	faseBuah := models.FaseBuah{
@@ -226,11 +315,27 @@ func CreateFaseBuah(c *gin.Context) {
 		TanggalCover:  &parsedTanggalCover,
 		JumlahCover:   input.JumlahCover,
 		WarnaLabel:    input.WarnaLabel,
-		EstimasiPanen: &estimasiPanen,
 		TanamanID:     input.TanamanID,
 	}
+	estimasi.Apply(&faseBuah)
 
-	if err := db.Create(&faseBuah).Error; err != nil {
+	uploaded, ok := uploadFotoFase(c, input.Foto, "fase-berbuah")
+	if !ok {
+		return
+	}
+
+	// fase berbuah & entri galeri satu transaksi
+	var cover *models.Media
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&faseBuah).Error; err != nil {
+			return err
+		}
+		var err error
+		cover, err = config.AttachCoverPhoto(tx, "fase-berbuah", faseBuah.ID, uploaded, currentUserID(c))
+		return err
+	})
+	if err != nil {
+		utils.DeleteUploadedImage(uploaded)
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal simpan fase berbuah", err.Error())
 		return
 	}
@@ -238,26 +343,29 @@ func CreateFaseBuah(c *gin.Context) {
 	// Preload Tanaman
 	db.Preload("Tanaman").First(&faseBuah, faseBuah.ID)
 
-	utils.SuccessResponse(c, http.StatusCreated, "Fase berbuah berhasil dibuat", faseBuah)
+	warnings := fotoWarnings(db, tanamanKebunLokasi(db, faseBuah.TanamanID), "foto fase berbuah", cover)
+	utils.SuccessResponseWithWarnings(c, http.StatusCreated, "Fase berbuah berhasil dibuat", faseBuah, warnings)
 }
 
 // PUT /fase-berbuah/:id
 // @Summary Update fase berbuah
-// @Description Mengupdate data fase berbuah berdasarkan ID
+// @Description Mengupdate data fase berbuah berdasarkan ID. Estimasi panen dihitung ulang jika cover / tanaman
+// @Description berubah, kecuali estimasinya diisi manual; kirim hitung_ulang_estimasi = true untuk menggantinya.
 // @Tags Fase Berbuah
 // @Security Bearer
 // @Accept json
 // @Produce json
 // @Param id path int true "ID Fase Buah"
-// @Param request body CreateFaseBuahInput true "Fase Buah Data"
+// @Param request body UpdateFaseBuahInput true "Fase Buah Data"
 // @Success 200 {object} utils.Response
 // @Failure 400 {object} utils.Response
 // @Failure 404 {object} utils.Response
+// @Router /fase-berbuah/{id} [put]
 // @Router /petani/fase-berbuah/{id} [put]
 func UpdateFaseBuah(c *gin.Context) {
 	id := c.Param("id")
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
@@ -273,20 +381,31 @@ func UpdateFaseBuah(c *gin.Context) {
 		return
 	}
 
+	if !authorizeTanaman(c, db, faseBuah.TanamanID, config.KebunActFaseWrite) {
+		return
+	}
+
 	var input struct {
-		MingguKe      *int    `json:"minggu_ke"`
-		TanggalCatat  *string `json:"tanggal_catat"` // YYYY-MM-DD
-		TanggalCover  *string `json:"tanggal_cover"` // YYYY-MM-DD
-		JumlahCover   *int    `json:"jumlah_cover"`
-		WarnaLabel    *string `json:"warna_label"`
-		EstimasiPanen *string `json:"estimasi_panen"` // YYYY-MM-DD
-		TanamanID     *uint   `json:"tanaman_id"`
+		MingguKe            *int    `json:"minggu_ke"`
+		TanggalCatat        *string `json:"tanggal_catat"` // YYYY-MM-DD
+		TanggalCover        *string `json:"tanggal_cover"` // YYYY-MM-DD
+		JumlahCover         *int    `json:"jumlah_cover"`
+		WarnaLabel          *string `json:"warna_label"`
+		EstimasiPanen       *string `json:"estimasi_panen"` // YYYY-MM-DD
+		HitungUlangEstimasi bool    `json:"hitung_ulang_estimasi"`
+		TanamanID           *uint   `json:"tanaman_id"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
 		return
 	}
+	if input.EstimasiPanen != nil && input.HitungUlangEstimasi {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Pilih salah satu: estimasi_panen manual atau hitung_ulang_estimasi", nil)
+		return
+	}
+	// dibaca sebelum estimasi_panen baru diterapkan
+	estimasiManual := faseBuah.EstimasiManual
 
 	// Apply updates with validation
 	if input.MingguKe != nil {
@@ -315,6 +434,7 @@ func UpdateFaseBuah(c *gin.Context) {
 		faseBuah.TanggalCover = &parsed
 	}
 
+	// estimasi manual tidak ikut dihitung ulang saat panen baru dicatat
 	if input.EstimasiPanen != nil {
 		parsed, err := parseAndValidateEstimasiPanen(*input.EstimasiPanen)
 		if err != nil {
@@ -322,6 +442,11 @@ func UpdateFaseBuah(c *gin.Context) {
 			return
 		}
 		faseBuah.EstimasiPanen = &parsed
+		faseBuah.EstimasiPanenAwal = nil
+		faseBuah.EstimasiPanenAkhir = nil
+		faseBuah.EstimasiKeyakinan = nil
+		faseBuah.EstimasiSumber = config.EstimasiSumberManual
+		faseBuah.EstimasiManual = true
 	}
 
 	if input.JumlahCover != nil {
@@ -341,6 +466,12 @@ func UpdateFaseBuah(c *gin.Context) {
 			utils.ErrorResponse(c, http.StatusBadRequest, *msg, input.TanamanID)
 			return
 		}
+		if !authorizeTanaman(c, db, *input.TanamanID, config.KebunActFaseWrite) {
+			return
+		}
+		if !ensureTanamanRecordable(c, db, *input.TanamanID) {
+			return
+		}
 
 		// Update nilai FK di struct
 		faseBuah.TanamanID = *input.TanamanID
@@ -353,6 +484,28 @@ func UpdateFaseBuah(c *gin.Context) {
 		// ----------------------------
 	}
 
+	// cover / tanaman berubah: estimasi hitungan lama tidak berlaku lagi. Estimasi manual (estimasi_manual)
+	// hanya diganti jika klien meminta lewat hitung_ulang_estimasi.
+	var warnings []string
+	coverBerubah := input.TanggalCover != nil || input.TanamanID != nil
+	hitungUlang := input.EstimasiPanen == nil && (input.HitungUlangEstimasi || (coverBerubah && !estimasiManual))
+	if input.EstimasiPanen == nil && coverBerubah && estimasiManual && !input.HitungUlangEstimasi {
+		warnings = append(warnings, "Estimasi panen manual dipertahankan, kirim hitung_ulang_estimasi = true untuk menghitung ulang")
+	}
+	if hitungUlang && faseBuah.TanggalCover != nil {
+		var tanaman models.Tanaman
+		if err := db.First(&tanaman, faseBuah.TanamanID).Error; err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil data tanaman", err.Error())
+			return
+		}
+		estimasi, err := config.HitungEstimasiPanen(db, &tanaman, *faseBuah.TanggalCover)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal hitung estimasi panen", err.Error())
+			return
+		}
+		estimasi.Apply(&faseBuah)
+	}
+
 	// Save to DB (Ini akan menyimpan semua perubahan termasuk tanaman_id baru)
 	if err := db.Save(&faseBuah).Error; err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal update fase berbuah", err.Error())
@@ -372,7 +525,7 @@ func UpdateFaseBuah(c *gin.Context) {
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Fase berbuah berhasil diperbarui", faseBuah)
+	utils.SuccessResponseWithWarnings(c, http.StatusOK, "Fase berbuah berhasil diperbarui", faseBuah, warnings)
 }
 
 // DELETE /fase-berbuah/:id
@@ -384,11 +537,12 @@ func UpdateFaseBuah(c *gin.Context) {
 // @Param id path int true "ID Fase Buah"
 // @Success 200 {object} utils.Response
 // @Failure 404 {object} utils.Response
+// @Router /fase-berbuah/{id} [delete]
 // @Router /petani/fase-berbuah/{id} [delete]
 func DeleteFaseBuah(c *gin.Context) {
 	id := c.Param("id")
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
@@ -404,6 +558,10 @@ func DeleteFaseBuah(c *gin.Context) {
 		return
 	}
 
+	if !authorizeTanaman(c, db, faseBuah.TanamanID, config.KebunActFaseWrite) {
+		return
+	}
+
 	if err := db.Delete(&faseBuah).Error; err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal hapus fase berbuah", err.Error())
 		return
@@ -435,12 +593,17 @@ func GetFaseBuahByTanaman(c *gin.Context) {
 	page, perPage := utils.GetPagination(c)
 	offset := utils.GetOffset(page, perPage)
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
 	}
 
+	tenant, ok := resolveTenant(c, db)
+	if !ok {
+		return
+	}
+
 	// Pastikan Tanaman ada
 	if msg := ensureTanamanExists(db, uint(tanamanID)); msg != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, *msg, tanamanID)
@@ -448,7 +611,7 @@ func GetFaseBuahByTanaman(c *gin.Context) {
 	}
 
 	var totalRows int64
-	if err := db.Model(&models.FaseBuah{}).Where("tanaman_id = ?", tanamanID).Count(&totalRows).Error; err != nil {
+	if err := db.Model(&models.FaseBuah{}).Scopes(tenant.ByTanaman("tanaman_id")).Where("tanaman_id = ?", tanamanID).Count(&totalRows).Error; err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal hitung data fase berbuah", err.Error())
 		return
 	}
@@ -464,7 +627,7 @@ func GetFaseBuahByTanaman(c *gin.Context) {
 	}
 
 	var faseBuahList []models.FaseBuah
-	if err := db.Where("tanaman_id = ?", tanamanID).
+	if err := db.Scopes(tenant.ByTanaman("tanaman_id")).Where("tanaman_id = ?", tanamanID).
 		Preload("Tanaman").
 		Limit(perPage).
 		Offset(offset).
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"fmt"
+	"mime/multipart"
 	"net/http"
 	"strconv"
 	"time"
@@ -37,6 +38,33 @@ func isValidMingguKe(mingguKe int) bool {
 	return mingguKe > 0
 }
 
+// defaultTanggalDariFoto tanggal kosong diisi tanggal foto diambil (EXIF), false jika response error sudah dikirim
+func defaultTanggalDariFoto(c *gin.Context, tanggal *string, foto *multipart.FileHeader, field string) bool {
+	if *tanggal != "" {
+		return true
+	}
+	if exif := utils.ReadExifFile(foto); exif != nil && exif.TakenAt != nil {
+		*tanggal = exif.TakenAt.Format("2006-01-02")
+		return true
+	}
+	utils.ErrorResponse(c, http.StatusBadRequest, field+" wajib diisi (tidak ada foto bertanggal EXIF)", nil)
+	return false
+}
+
+// uploadFotoFase upload foto opsional fase bunga / buah ke galeri, nil jika tidak ada foto.
+// false jika response error sudah dikirim.
+func uploadFotoFase(c *gin.Context, foto *multipart.FileHeader, ownerType string) (*utils.UploadedImage, bool) {
+	if foto == nil {
+		return nil, true
+	}
+	uploaded, err := utils.UploadImage(foto, "galeri/"+ownerType)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Upload foto gagal", err.Error())
+		return nil, false
+	}
+	return uploaded, true
+}
+
 // Helper function to parse and validate TanggalCatat (YYYY-MM-DD, not in future)
 func parseAndValidateTanggalCatat(dateStr string) (time.Time, error) {
 	parsed, err := time.Parse("2006-01-02", dateStr)
@@ -77,19 +105,25 @@ func ensureTanamanExists(db *gorm.DB, tanamanID uint) *string {
 // @Param per_page query int false "Jumlah data per halaman"
 // @Success 200 {object} utils.Response
 // @Failure 500 {object} utils.Response
+// @Param organization_id query int false "Filter satu organisasi"
 // @Router /petani/fase-bunga [get]
 func GetAllFaseBunga(c *gin.Context) {
 	page, perPage := utils.GetPagination(c)
 	offset := utils.GetOffset(page, perPage)
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
 	}
 
+	tenant, ok := resolveTenant(c, db)
+	if !ok {
+		return
+	}
+
 	var totalRows int64
-	if err := db.Model(&models.FaseBunga{}).Count(&totalRows).Error; err != nil {
+	if err := db.Model(&models.FaseBunga{}).Scopes(tenant.ByTanaman("tanaman_id")).Count(&totalRows).Error; err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal hitung data fase bunga", err.Error())
 		return
 	}
@@ -105,7 +139,7 @@ func GetAllFaseBunga(c *gin.Context) {
 	}
 
 	var faseBungaList []models.FaseBunga
-	if err := db.Preload("Tanaman").
+	if err := db.Scopes(tenant.ByTanaman("tanaman_id")).Preload("Tanaman").
 		Limit(perPage).
 		Offset(offset).
 		Find(&faseBungaList).Error; err != nil {
@@ -134,14 +168,19 @@ func GetAllFaseBunga(c *gin.Context) {
 func GetFaseBungaByID(c *gin.Context) {
 	id := c.Param("id")
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
 	}
 
+	tenant, ok := resolveTenant(c, db)
+	if !ok {
+		return
+	}
+
 	var faseBunga models.FaseBunga
-	if err := db.Preload("Tanaman").First(&faseBunga, id).Error; err != nil {
+	if err := db.Scopes(tenant.ByTanaman("tanaman_id")).Preload("Tanaman").First(&faseBunga, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utils.ErrorResponse(c, http.StatusNotFound, "Fase bunga tidak ditemukan", nil)
 			return
@@ -154,32 +193,39 @@ func GetFaseBungaByID(c *gin.Context) {
 }
 
 // @Summary Create fase bunga
-// @Description Menambahkan fase bunga baru untuk tanaman
+// @Description Menambahkan fase bunga baru untuk tanaman. Bisa juga dikirim sebagai multipart/form-data dengan
+// @Description field yang sama ditambah foto (opsional, masuk galeri); tanggal_catat kosong memakai tanggal EXIF foto.
 // @Tags Fase Bunga
 // @Security Bearer
 // @Accept json
+// @Accept mpfd
 // @Produce json
 // @Param request body controllers.CreateFaseBungaInput true "Fase Bunga Data"
 // @Success 201 {object} utils.Response
 // @Failure 400 {object} utils.Response
 // @Failure 500 {object} utils.Response
+// @Router /fase-bunga [post]
 // @Router /petani/fase-bunga [post]
 func CreateFaseBunga(c *gin.Context) {
 	var input struct {
-		MingguKe     int    `json:"minggu_ke" binding:"required"`
-		TanggalCatat string `json:"tanggal_catat" binding:"required"` // YYYY-MM-DD
-		JumlahBunga  int    `json:"jumlah_bunga"`
-		BungaPecah   int    `json:"bunga_pecah"`
-		PentilMuncul int    `json:"pentil_muncul"`
-		TanamanID    uint   `json:"tanaman_id" binding:"required"`
+		MingguKe     int                   `json:"minggu_ke" form:"minggu_ke" binding:"required"`
+		TanggalCatat string                `json:"tanggal_catat" form:"tanggal_catat"` // YYYY-MM-DD, default tanggal EXIF foto
+		JumlahBunga  int                   `json:"jumlah_bunga" form:"jumlah_bunga"`
+		BungaPecah   int                   `json:"bunga_pecah" form:"bunga_pecah"`
+		PentilMuncul int                   `json:"pentil_muncul" form:"pentil_muncul"`
+		TanamanID    uint                  `json:"tanaman_id" form:"tanaman_id" binding:"required"`
+		Foto         *multipart.FileHeader `json:"-" form:"foto"`
 	}
 
-	if err := c.ShouldBindJSON(&input); err != nil {
+	if err := c.ShouldBind(&input); err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
 		return
 	}
+	if !defaultTanggalDariFoto(c, &input.TanggalCatat, input.Foto, "tanggal_catat") {
+		return
+	}
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
@@ -210,6 +256,13 @@ func CreateFaseBunga(c *gin.Context) {
 		return
 	}
 
+	if !authorizeTanaman(c, db, input.TanamanID, config.KebunActFaseWrite) {
+		return
+	}
+	if !ensureTanamanRecordable(c, db, input.TanamanID) {
+		return
+	}
+
 	// Buat FaseBunga
 	faseBunga := models.FaseBunga{
 		MingguKe:     input.MingguKe,
@@ -220,7 +273,23 @@ func CreateFaseBunga(c *gin.Context) {
 		TanamanID:    input.TanamanID,
 	}
 
-	if err := db.Create(&faseBunga).Error; err != nil {
+	uploaded, ok := uploadFotoFase(c, input.Foto, "fase-bunga")
+	if !ok {
+		return
+	}
+
+	// fase bunga & entri galeri satu transaksi
+	var cover *models.Media
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&faseBunga).Error; err != nil {
+			return err
+		}
+		var err error
+		cover, err = config.AttachCoverPhoto(tx, "fase-bunga", faseBunga.ID, uploaded, currentUserID(c))
+		return err
+	})
+	if err != nil {
+		utils.DeleteUploadedImage(uploaded)
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal simpan fase bunga", err.Error())
 		return
 	}
@@ -228,7 +297,8 @@ func CreateFaseBunga(c *gin.Context) {
 	// Preload Tanaman
 	db.Preload("Tanaman").First(&faseBunga, faseBunga.ID)
 
-	utils.SuccessResponse(c, http.StatusCreated, "Fase bunga berhasil dibuat", faseBunga)
+	warnings := fotoWarnings(db, tanamanKebunLokasi(db, faseBunga.TanamanID), "foto fase bunga", cover)
+	utils.SuccessResponseWithWarnings(c, http.StatusCreated, "Fase bunga berhasil dibuat", faseBunga, warnings)
 }
 
 // @Summary Update fase bunga
@@ -242,11 +312,12 @@ func CreateFaseBunga(c *gin.Context) {
 // @Success 200 {object} utils.Response
 // @Failure 400 {object} utils.Response
 // @Failure 404 {object} utils.Response
+// @Router /fase-bunga/{id} [put]
 // @Router /petani/fase-bunga/{id} [put]
 func UpdateFaseBunga(c *gin.Context) {
 	id := c.Param("id")
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
@@ -263,6 +334,10 @@ func UpdateFaseBunga(c *gin.Context) {
 		return
 	}
 
+	if !authorizeTanaman(c, db, faseBunga.TanamanID, config.KebunActFaseWrite) {
+		return
+	}
+
 	var input struct {
 		MingguKe      *int    `json:"minggu_ke"`
 		TanggalCatat  *string `json:"tanggal_catat"` // YYYY-MM-DD
@@ -328,6 +403,12 @@ func UpdateFaseBunga(c *gin.Context) {
 			utils.ErrorResponse(c, http.StatusBadRequest, *msg, *input.TanamanID)
 			return
 		}
+		if !authorizeTanaman(c, db, *input.TanamanID, config.KebunActFaseWrite) {
+			return
+		}
+		if !ensureTanamanRecordable(c, db, *input.TanamanID) {
+			return
+		}
 		
 		// Update Foreign Key di struct
 		faseBunga.TanamanID = *input.TanamanID
@@ -365,11 +446,12 @@ func UpdateFaseBunga(c *gin.Context) {
 // @Param id path int true "ID Fase Bunga"
 // @Success 200 {object} utils.Response
 // @Failure 404 {object} utils.Response
+// @Router /fase-bunga/{id} [delete]
 // @Router /petani/fase-bunga/{id} [delete]
 func DeleteFaseBunga(c *gin.Context) {
 	id := c.Param("id")
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
@@ -385,6 +467,10 @@ func DeleteFaseBunga(c *gin.Context) {
 		return
 	}
 
+	if !authorizeTanaman(c, db, faseBunga.TanamanID, config.KebunActFaseWrite) {
+		return
+	}
+
 	if err := db.Delete(&faseBunga).Error; err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal hapus fase bunga", err.Error())
 		return
@@ -412,12 +498,17 @@ func GetFaseBungaByTanaman(c *gin.Context) {
 	page, perPage := utils.GetPagination(c)
 	offset := utils.GetOffset(page, perPage)
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
 	}
 
+	tenant, ok := resolveTenant(c, db)
+	if !ok {
+		return
+	}
+
 	// Pastikan Tanaman ada
 	if msg := ensureTanamanExists(db, uint(tanamanID)); msg != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, *msg, tanamanID)
@@ -425,7 +516,7 @@ func GetFaseBungaByTanaman(c *gin.Context) {
 	}
 
 	var totalRows int64
-	if err := db.Model(&models.FaseBunga{}).Where("tanaman_id = ?", tanamanID).Count(&totalRows).Error; err != nil {
+	if err := db.Model(&models.FaseBunga{}).Scopes(tenant.ByTanaman("tanaman_id")).Where("tanaman_id = ?", tanamanID).Count(&totalRows).Error; err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal hitung data fase bunga", err.Error())
 		return
 	}
@@ -441,7 +532,7 @@ func GetFaseBungaByTanaman(c *gin.Context) {
 	}
 
 	var faseBungaList []models.FaseBunga
-	if err := db.Where("tanaman_id = ?", tanamanID).
+	if err := db.Scopes(tenant.ByTanaman("tanaman_id")).Where("tanaman_id = ?", tanamanID).
 		Preload("Tanaman").
 		Limit(perPage).
 		Offset(offset).
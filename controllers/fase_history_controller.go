@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"Avocycle/models"
+	"Avocycle/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// respondFaseRevisions daftar versi satu record fase. Record yang sudah dihapus tetap bisa dilihat historinya.
+func respondFaseRevisions(c *gin.Context, entity string, model interface{}, label string) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ID tidak valid", c.Param("id"))
+		return
+	}
+
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	tenant, ok := resolveTenant(c, db)
+	if !ok {
+		return
+	}
+
+	var count int64
+	if err := db.Unscoped().Model(model).Scopes(tenant.ByTanaman("tanaman_id")).Where("id = ?", id).Count(&count).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil "+label, err.Error())
+		return
+	}
+	if count == 0 {
+		utils.ErrorResponse(c, http.StatusNotFound, label+" tidak ditemukan", nil)
+		return
+	}
+
+	var revisions []models.FaseRevision
+	if err := db.Where("entity = ? AND record_id = ?", entity, id).Order("revision ASC").Find(&revisions).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil riwayat "+label, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Riwayat "+label, revisions)
+}
+
+// GetFaseBungaRevisions godoc
+// @Summary Riwayat versi fase bunga
+// @Description Semua versi record fase bunga (create / update / delete) beserta masa berlakunya
+// @Tags Fase Bunga
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Fase Bunga"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /fase-bunga/{id}/revisions [get]
+func GetFaseBungaRevisions(c *gin.Context) {
+	respondFaseRevisions(c, "FaseBunga", &models.FaseBunga{}, "Fase bunga")
+}
+
+// GetFaseBuahRevisions godoc
+// @Summary Riwayat versi fase buah
+// @Description Semua versi record fase buah (create / update / delete) beserta masa berlakunya
+// @Tags Fase Buah
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Fase Buah"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /fase-berbuah/{id}/revisions [get]
+func GetFaseBuahRevisions(c *gin.Context) {
+	respondFaseRevisions(c, "FaseBuah", &models.FaseBuah{}, "Fase buah")
+}
+
+// GetFasePanenRevisions godoc
+// @Summary Riwayat versi fase panen
+// @Description Semua versi record fase panen (create / update / delete) beserta masa berlakunya
+// @Tags Fase Panen
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Fase Panen"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /fase-panen/{id}/revisions [get]
+func GetFasePanenRevisions(c *gin.Context) {
+	respondFaseRevisions(c, "FasePanen", &models.FasePanen{}, "Fase panen")
+}
+
+// faseAsOf data fase satu tanaman seperti pada waktu `at`: versi yang berlaku saat itu,
+// ditambah record lama yang belum pernah punya versi (dibuat sebelum history aktif)
+func faseAsOf(db *gorm.DB, entity string, model interface{}, tanamanID uint, at time.Time) ([]map[string]interface{}, error) {
+	var revisions []models.FaseRevision
+	if err := db.Where("entity = ? AND tanaman_id = ? AND valid_from <= ? AND (valid_to IS NULL OR valid_to > ?)", entity, tanamanID, at, at).
+		Order("record_id ASC").Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+
+	rows := make([]map[string]interface{}, 0, len(revisions))
+	for _, rev := range revisions {
+		row := map[string]interface{}{}
+		if err := json.Unmarshal(rev.Data, &row); err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+
+	var legacy []map[string]interface{}
+	if err := db.Unscoped().Model(model).
+		Where("tanaman_id = ? AND created_at <= ? AND (deleted_at IS NULL OR deleted_at > ?)", tanamanID, at, at).
+		Where("id NOT IN (?)", db.Model(&models.FaseRevision{}).Select("record_id").Where("entity = ?", entity)).
+		Order("id ASC").Find(&legacy).Error; err != nil {
+		return nil, err
+	}
+
+	return append(rows, legacy...), nil
+}
+
+// GetTanamanFaseAsOf godoc
+// @Summary Data fase tanaman per tanggal
+// @Description Fase bunga, buah, dan panen sebuah tanaman sesuai kondisi data pada waktu as_of
+// @Description (sebelum koreksi / penghapusan setelah waktu tersebut)
+// @Tags Tanaman
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Tanaman"
+// @Param as_of query string true "Tanggal (YYYY-MM-DD, dihitung akhir hari) atau RFC3339"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tanaman/{id}/fase-history [get]
+func GetTanamanFaseAsOf(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ID tidak valid", c.Param("id"))
+		return
+	}
+
+	raw := c.Query("as_of")
+	if raw == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "as_of wajib diisi", nil)
+		return
+	}
+	at, err := parseAuditTime(raw, true)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Format as_of tidak valid", raw)
+		return
+	}
+
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	tenant, ok := resolveTenant(c, db)
+	if !ok {
+		return
+	}
+
+	var tanaman models.Tanaman
+	if err := db.Unscoped().Scopes(tenant.ByKebun("kebun_id")).First(&tanaman, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "Tanaman tidak ditemukan", nil)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil tanaman", err.Error())
+		return
+	}
+
+	result := gin.H{"tanaman_id": tanaman.ID, "as_of": at}
+	for key, src := range map[string]struct {
+		entity string
+		model  interface{}
+	}{
+		"fase_bunga": {"FaseBunga", &models.FaseBunga{}},
+		"fase_buah":  {"FaseBuah", &models.FaseBuah{}},
+		"fase_panen": {"FasePanen", &models.FasePanen{}},
+	} {
+		rows, err := faseAsOf(db, src.entity, src.model, tanaman.ID, at)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil data fase", err.Error())
+			return
+		}
+		result[key] = rows
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Data fase tanaman per tanggal", result)
+}
@@ -59,19 +59,25 @@ func parseAndValidateTanggalPanenAktual(dateStr string) (time.Time, error) {
 // @Param page query int false "Nomor halaman"
 // @Param per_page query int false "Jumlah data per halaman"
 // @Success 200 {object} utils.Response
+// @Param organization_id query int false "Filter satu organisasi"
 // @Router /petani/fase-panen [get]
 func GetAllFasePanen(c *gin.Context) {
     page, perPage := utils.GetPagination(c)
     offset := utils.GetOffset(page, perPage)
 
-    db, err := config.DbConnect()
+    db, err := requestDB(c)
     if err != nil {
         utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
         return
     }
 
+    tenant, ok := resolveTenant(c, db)
+    if !ok {
+        return
+    }
+
     var totalRows int64
-    if err := db.Model(&models.FasePanen{}).Count(&totalRows).Error; err != nil {
+    if err := db.Model(&models.FasePanen{}).Scopes(tenant.ByTanaman("tanaman_id")).Count(&totalRows).Error; err != nil {
         utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal hitung data fase panen", err.Error())
         return
     }
@@ -87,7 +93,7 @@ func GetAllFasePanen(c *gin.Context) {
     }
 
     var list []models.FasePanen
-    if err := db.Preload("Tanaman").
+    if err := db.Scopes(tenant.ByTanaman("tanaman_id")).Preload("Tanaman").
         Limit(perPage).
         Offset(offset).
         Find(&list).Error; err != nil {
@@ -117,14 +123,19 @@ func GetAllFasePanen(c *gin.Context) {
 func GetFasePanenByID(c *gin.Context) {
     id := c.Param("id")
 
-    db, err := config.DbConnect()
+    db, err := requestDB(c)
     if err != nil {
         utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
         return
     }
 
+    tenant, ok := resolveTenant(c, db)
+    if !ok {
+        return
+    }
+
     var rec models.FasePanen
-    if err := db.Preload("Tanaman").First(&rec, id).Error; err != nil {
+    if err := db.Scopes(tenant.ByTanaman("tanaman_id")).Preload("Tanaman").First(&rec, id).Error; err != nil {
         if err == gorm.ErrRecordNotFound {
             utils.ErrorResponse(c, http.StatusNotFound, "Fase panen tidak ditemukan", nil)
             return
@@ -143,7 +154,7 @@ func GetFasePanenByID(c *gin.Context) {
 // @Security Bearer
 // @Accept multipart/form-data
 // @Produce json
-// @Param tanggal_panen_aktual formData string true "Tanggal Panen Aktual (YYYY-MM-DD)"
+// @Param tanggal_panen_aktual formData string false "Tanggal Panen Aktual (YYYY-MM-DD), default tanggal EXIF foto_panen"
 // @Param jumlah_panen formData int false "Jumlah Panen"
 // @Param jumlah_sampel formData int false "Jumlah Sampel"
 // @Param berat_total formData number false "Berat Total (Kg)"
@@ -151,16 +162,17 @@ func GetFasePanenByID(c *gin.Context) {
 // @Param foto_panen formData file false "Foto Panen"
 // @Param tanaman_id formData int true "ID Tanaman"
 // @Success 201 {object} utils.Response
+// @Router /fase-panen [post]
 // @Router /petani/fase-panen [post]
 func CreateFasePanen(c *gin.Context) {
-    db, err := config.DbConnect()
+    db, err := requestDB(c)
     if err != nil {
         utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
         return
     }
 
     var input struct {
-        TanggalPanenAktual string `form:"tanggal_panen_aktual" binding:"required"`
+        TanggalPanenAktual string `form:"tanggal_panen_aktual"`
         JumlahPanen        int    `form:"jumlah_panen"`
         JumlahSampel       int    `form:"jumlah_sampel"`
         BeratTotal         float64`form:"berat_total"`
@@ -174,6 +186,16 @@ func CreateFasePanen(c *gin.Context) {
         return
     }
 
+    // tanggal kosong: pakai waktu foto diambil (EXIF)
+    if input.TanggalPanenAktual == "" {
+        if exif := utils.ReadExifFile(input.FotoPanen); exif != nil && exif.TakenAt != nil {
+            input.TanggalPanenAktual = exif.TakenAt.Format("2006-01-02")
+        } else {
+            utils.ErrorResponse(c, http.StatusBadRequest, "tanggal_panen_aktual wajib diisi (foto_panen tidak memuat tanggal foto)", nil)
+            return
+        }
+    }
+
     parsedTanggal, err := parseAndValidateTanggalPanenAktual(input.TanggalPanenAktual)
     if err != nil {
         utils.ErrorResponse(c, http.StatusBadRequest, "tanggal_panen_aktual tidak valid", err.Error())
@@ -185,6 +207,13 @@ func CreateFasePanen(c *gin.Context) {
         return
     }
 
+    if !authorizeTanaman(c, db, input.TanamanID, config.KebunActFaseWrite) {
+        return
+    }
+    if !ensureTanamanRecordable(c, db, input.TanamanID) {
+        return
+    }
+
     rec := models.FasePanen{
         TanggalPanenAktual: &parsedTanggal,
         JumlahPanen:        input.JumlahPanen,
@@ -195,17 +224,31 @@ func CreateFasePanen(c *gin.Context) {
     }
 
     // Upload foto jika ada
+    var uploaded *utils.UploadedImage
+    var errUpload error
     if input.FotoPanen != nil {
-        url, publicID, errUpload := utils.AsyncUploadOptionalImage(input.FotoPanen, "panen")
+        uploaded, errUpload = utils.UploadImage(input.FotoPanen, "panen")
         if errUpload != nil {
             utils.ErrorResponse(c, http.StatusBadRequest, "Upload foto gagal", errUpload.Error())
             return
         }
-        rec.FotoPanen = url
-        rec.FotoPanenID = publicID
+        rec.FotoPanen = uploaded.URL
+        rec.FotoPanenID = uploaded.PublicID
+        rec.FotoPanenThumb = uploaded.ThumbURL
     }
 
-    if err := db.Create(&rec).Error; err != nil {
+    // fase panen & entri galeri satu transaksi
+    var cover *models.Media
+    err = db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Create(&rec).Error; err != nil {
+            return err
+        }
+        var err error
+        cover, err = config.AttachCoverPhoto(tx, "fase-panen", rec.ID, uploaded, currentUserID(c))
+        return err
+    })
+    if err != nil {
+		utils.DeleteUploadedImage(uploaded)
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal simpan data fase panen", err.Error())
 		return
 	}
@@ -218,7 +261,25 @@ func CreateFasePanen(c *gin.Context) {
 		return
 	}
 
-    utils.SuccessResponse(c, http.StatusCreated, "Fase panen berhasil dibuat", createdRec)
+    warnings := fotoWarnings(db, tanamanKebunLokasi(db, rec.TanamanID), "foto panen", cover)
+    warnings = append(warnings, hitungUlangEstimasiPanen(db, rec.TanamanID)...)
+
+    utils.SuccessResponseWithWarnings(c, http.StatusCreated, "Fase panen berhasil dibuat", createdRec, warnings)
+}
+
+// hitungUlangEstimasiPanen riwayat panen kebun berubah, perbarui estimasi fase buah yang belum dipanen.
+// Panen sudah tersimpan, jadi kegagalan cukup dilaporkan sebagai peringatan.
+func hitungUlangEstimasiPanen(db *gorm.DB, tanamanIDs ...uint) []string {
+    var kebunIDs []uint
+    if err := db.Model(&models.Tanaman{}).Where("id IN ?", tanamanIDs).Distinct().Pluck("kebun_id", &kebunIDs).Error; err != nil {
+        return []string{"Estimasi panen belum diperbarui: " + err.Error()}
+    }
+    for _, kebunID := range kebunIDs {
+        if _, err := config.HitungUlangEstimasiPanen(db, kebunID); err != nil {
+            return []string{"Estimasi panen belum diperbarui: " + err.Error()}
+        }
+    }
+    return nil
 }
 
 // PUT /fase-panen/:id
@@ -237,11 +298,12 @@ func CreateFasePanen(c *gin.Context) {
 // @Param foto_panen formData file false "Foto Panen"
 // @Param tanaman_id formData int false "ID Tanaman"
 // @Success 200 {object} utils.Response
+// @Router /fase-panen/{id} [put]
 // @Router /petani/fase-panen/{id} [put]
 func UpdateFasePanen(c *gin.Context) {
     id := c.Param("id")
 
-    db, err := config.DbConnect()
+    db, err := requestDB(c)
     if err != nil {
         utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
         return
@@ -253,6 +315,11 @@ func UpdateFasePanen(c *gin.Context) {
         return
     }
 
+    if !authorizeTanaman(c, db, rec.TanamanID, config.KebunActFaseWrite) {
+        return
+    }
+    tanamanLamaID := rec.TanamanID
+
     var input struct {
         TanggalPanenAktual *string `form:"tanggal_panen_aktual"`
         JumlahPanen        *int    `form:"jumlah_panen"`
@@ -298,21 +365,41 @@ func UpdateFasePanen(c *gin.Context) {
             utils.ErrorResponse(c, http.StatusBadRequest, *msg, *input.TanamanID)
             return
         }
+        if !authorizeTanaman(c, db, *input.TanamanID, config.KebunActFaseWrite) {
+            return
+        }
+        if !ensureTanamanRecordable(c, db, *input.TanamanID) {
+            return
+        }
         rec.TanamanID = *input.TanamanID
     }
 
     // Ganti foto jika ada upload baru
+    var uploaded *utils.UploadedImage
+    var errUpload error
     if input.FotoPanen != nil {
-        url, publicID, errUpload := utils.AsyncUploadOptionalImage(input.FotoPanen, "panen")
+        uploaded, errUpload = utils.UploadImage(input.FotoPanen, "panen")
         if errUpload != nil {
             utils.ErrorResponse(c, http.StatusBadRequest, "Upload foto gagal", errUpload.Error())
             return
         }
-        rec.FotoPanen = url
-        rec.FotoPanenID = publicID
+        rec.FotoPanen = uploaded.URL
+        rec.FotoPanenID = uploaded.PublicID
+        rec.FotoPanenThumb = uploaded.ThumbURL
     }
 
-    if err := db.Save(&rec).Error; err != nil {
+    // foto lama tetap di galeri, foto baru menjadi foto utama
+    var cover *models.Media
+    err = db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Save(&rec).Error; err != nil {
+            return err
+        }
+        var err error
+        cover, err = config.AttachCoverPhoto(tx, "fase-panen", rec.ID, uploaded, currentUserID(c))
+        return err
+    })
+    if err != nil {
+		utils.DeleteUploadedImage(uploaded)
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal update", err.Error())
 		return
 	}
@@ -326,7 +413,10 @@ func UpdateFasePanen(c *gin.Context) {
 		return
 	}
 
-    utils.SuccessResponse(c, http.StatusOK, "Fase panen diperbarui", updatedRec)
+    warnings := fotoWarnings(db, tanamanKebunLokasi(db, rec.TanamanID), "foto panen", cover)
+    warnings = append(warnings, hitungUlangEstimasiPanen(db, tanamanLamaID, rec.TanamanID)...)
+
+    utils.SuccessResponseWithWarnings(c, http.StatusOK, "Fase panen diperbarui", updatedRec, warnings)
 }
 
 // DELETE /fase-panen/:id
@@ -337,11 +427,12 @@ func UpdateFasePanen(c *gin.Context) {
 // @Param id path int true "ID Fase Panen"
 // @Success 200 {object} utils.Response
 // @Failure 404 {object} utils.Response
+// @Router /fase-panen/{id} [delete]
 // @Router /petani/fase-panen/{id} [delete]
 func DeleteFasePanen(c *gin.Context) {
     id := c.Param("id")
 
-    db, err := config.DbConnect()
+    db, err := requestDB(c)
     if err != nil {
         utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
         return
@@ -357,12 +448,17 @@ func DeleteFasePanen(c *gin.Context) {
         return
     }
 
+    if !authorizeTanaman(c, db, rec.TanamanID, config.KebunActFaseWrite) {
+        return
+    }
+
     if err := db.Delete(&rec).Error; err != nil {
         utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal hapus fase panen", err.Error())
         return
     }
 
-    utils.SuccessResponse(c, http.StatusOK, "Fase panen berhasil dihapus", utils.EmptyObj{})
+    warnings := hitungUlangEstimasiPanen(db, rec.TanamanID)
+    utils.SuccessResponseWithWarnings(c, http.StatusOK, "Fase panen berhasil dihapus", utils.EmptyObj{}, warnings)
 }
 
 // GET /fase-panen/tanaman/:tanaman_id (paginated)
@@ -390,12 +486,17 @@ func GetFasePanenByTanaman(c *gin.Context) {
     page, perPage := utils.GetPagination(c)
     offset := utils.GetOffset(page, perPage)
 
-    db, err := config.DbConnect()
+    db, err := requestDB(c)
     if err != nil {
         utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
         return
     }
 
+    tenant, ok := resolveTenant(c, db)
+    if !ok {
+        return
+    }
+
     // Pastikan Tanaman ada
     if msg := ensureTanamanExists(db, tanamanID); msg != nil {
         utils.ErrorResponse(c, http.StatusBadRequest, *msg, tanamanID)
@@ -403,7 +504,7 @@ func GetFasePanenByTanaman(c *gin.Context) {
     }
 
     var totalRows int64
-    if err := db.Model(&models.FasePanen{}).Where("tanaman_id = ?", tanamanID).Count(&totalRows).Error; err != nil {
+    if err := db.Model(&models.FasePanen{}).Scopes(tenant.ByTanaman("tanaman_id")).Where("tanaman_id = ?", tanamanID).Count(&totalRows).Error; err != nil {
         utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal hitung data fase panen", err.Error())
         return
     }
@@ -419,7 +520,7 @@ func GetFasePanenByTanaman(c *gin.Context) {
     }
 
     var list []models.FasePanen
-    if err := db.Where("tanaman_id = ?", tanamanID).
+    if err := db.Scopes(tenant.ByTanaman("tanaman_id")).Where("tanaman_id = ?", tanamanID).
         Preload("Tanaman").
         Limit(perPage).
         Offset(offset).
@@ -1,12 +1,8 @@
 package controllers
 
 import (
-	"Avocycle/config"
 	// "Avocycle/models"
 	// "Avocycle/utils"
-	"net/http"
-	// "net/url"
-	"os"
 
 	// "github.com/danilopolani/gocialite/structs"
 	"github.com/gin-gonic/gin"
@@ -16,6 +12,7 @@ import (
 // RedirectHandlerPembeli godoc
 // @Summary Login via Google OAuth (Pembeli)
 // @Description This endpoint will redirect users to Google Sign-in page in browser.
+// @Description Alias dari /auth/google?role=pembeli.
 // @Description 
 // @Description ⚠ Cannot be tested directly via Swagger or Postman.
 // @Description 
@@ -27,39 +24,7 @@ import (
 // @Success 302 {string} string "Redirect to Google OAuth"
 // @Router /auth/google/pembeli [get]
 func RedirectHandlerPembeli(c *gin.Context) {
-    // provider := c.Param("provider")
-    provider := "google"
-
-    providerSecrets := map[string]map[string]string{
-        "google": {
-            "clientID":     os.Getenv("CLIENT_ID_GOOGLE"),
-            "clientSecret": os.Getenv("CLIENT_SECRET_GOOGLE"),
-            "redirectURL":  os.Getenv("AUTH_REDIRECT_URL") + "/google/callback/pembeli",
-        },
-    }
-
-    providerScopes := map[string][]string{
-        "google": {},
-    }
-
-    providerData := providerSecrets[provider]
-    actualScopes := providerScopes[provider]
-
-    authURL, err := config.Gocial.New().
-        Driver(provider).
-        Scopes(actualScopes).
-        Redirect(
-            providerData["clientID"],
-            providerData["clientSecret"],
-            providerData["redirectURL"],
-        )
-
-    if err != nil {
-        c.Writer.Write([]byte("Error: " + err.Error()))
-        return
-    }
-
-    c.Redirect(http.StatusFound, authURL)
+    redirectToProvider(c, "google", "pembeli")
 }
 
 
@@ -98,7 +63,7 @@ func RedirectHandlerPembeli(c *gin.Context) {
 // @Description    .    }
 // @Description     }
 func CallbackHandlerPembeli(c *gin.Context) {
-    handleGoogleCallback(c, "Pembeli")
+    CallbackHandler(c)
 }
 
 // func getOrRegisterUserPembeli(provider string, user *structs.User) models.User {
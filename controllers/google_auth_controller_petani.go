@@ -1,11 +1,8 @@
 package controllers
 
 import (
-	"Avocycle/config"
 	// "Avocycle/models"
 	// "Avocycle/utils"
-	"net/http"
-	"os"
 
 	// "github.com/danilopolani/gocialite/structs"
 	"github.com/gin-gonic/gin"
@@ -16,6 +13,7 @@ import (
 // RedirectHandlerPetani godoc
 // @Summary Login via Google OAuth (Petani)
 // @Description This endpoint will redirect users to Google Sign-in page in browser.
+// @Description Alias dari /auth/google?role=petani.
 // @Description 
 // @Description ⚠ Cannot be tested directly via Swagger or Postman.
 // @Description 
@@ -27,39 +25,7 @@ import (
 // @Success 302 {string} string "Redirect to Google OAuth"
 // @Router /auth/google/petani [get]
 func RedirectHandlerPetani(c *gin.Context) {
-    // provider := c.Param("provider")
-    provider := "google"
-
-    providerSecrets := map[string]map[string]string{
-        "google": {
-            "clientID":     os.Getenv("CLIENT_ID_GOOGLE"),
-            "clientSecret": os.Getenv("CLIENT_SECRET_GOOGLE"),
-            "redirectURL":  os.Getenv("AUTH_REDIRECT_URL") + "/google/callback/petani",
-        },
-    }
-
-    providerScopes := map[string][]string{
-        "google": {},
-    }
-
-    providerData := providerSecrets[provider]
-    actualScopes := providerScopes[provider]
-
-    authURL, err := config.Gocial.New().
-        Driver(provider).
-        Scopes(actualScopes).
-        Redirect(
-            providerData["clientID"],
-            providerData["clientSecret"],
-            providerData["redirectURL"],
-        )
-
-    if err != nil {
-        c.Writer.Write([]byte("Error: " + err.Error()))
-        return
-    }
-
-    c.Redirect(http.StatusFound, authURL)
+    redirectToProvider(c, "google", "petani")
 }
 
 
@@ -98,9 +64,8 @@ func RedirectHandlerPetani(c *gin.Context) {
 // @Description    . 	"expires_in": 3599
 // @Description    .    }
 // @Description     }
-// RedirectHandlerPetani tetap sama seperti sekarang
 func CallbackHandlerPetani(c *gin.Context) {
-    handleGoogleCallback(c, "Petani")
+    CallbackHandler(c)
 }
 
 // func getOrRegisterUser(provider string, user *structs.User) models.User {
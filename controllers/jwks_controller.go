@@ -0,0 +1,21 @@
+package controllers
+
+import (
+	"Avocycle/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetJWKS godoc
+// @Summary Public key JWT (JWKS)
+// @Description Daftar public key (RS256 / EdDSA) untuk memverifikasi token Avocycle dari service lain.
+// @Description Key lama yang sudah dirotasi tetap ada di sini sampai token lamanya expired.
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} utils.JWKS
+// @Router /.well-known/jwks.json [get]
+func GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, utils.PublicJWKS())
+}
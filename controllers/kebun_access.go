@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"Avocycle/config"
+	"Avocycle/models"
+	"Avocycle/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// authorizeKebun memastikan user login boleh melakukan action di kebun sesuai perannya
+// (models.KebunMember). Admin selalu boleh, admin organisasi pemilik kebun setara owner,
+// anggota organisasi hanya boleh melihat. Kebun lama tanpa anggota & tanpa organisasi
+// masih bisa dikelola pemegang kebun:write. Mengembalikan false jika response error sudah dikirim.
+func authorizeKebun(c *gin.Context, db *gorm.DB, kebunID uint, action config.KebunAction) bool {
+	claims := currentClaims(c)
+	if claims == nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Token tidak ditemukan", nil)
+		return false
+	}
+
+	role, err := kebunRoleOf(db, claims, kebunID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal cek keanggotaan kebun", err.Error())
+		return false
+	}
+
+	if role == "" {
+		utils.ErrorResponse(c, http.StatusForbidden, "Anda bukan anggota kebun ini", gin.H{"kebun_id": kebunID})
+		return false
+	}
+
+	if !config.KebunRoleCan(role, action) {
+		utils.ErrorResponse(c, http.StatusForbidden, "Peran Anda di kebun ini tidak mengizinkan aksi ini", gin.H{
+			"kebun_id": kebunID,
+			"role":     role,
+			"action":   action,
+		})
+		return false
+	}
+
+	return true
+}
+
+// kebunRoleOf peran efektif user di kebun: Admin & admin organisasi = owner,
+// anggota organisasi = viewer, selain itu dari KebunMember. "" berarti tidak punya akses.
+func kebunRoleOf(db *gorm.DB, claims *utils.Claims, kebunID uint) (string, error) {
+	if claims.Role == "Admin" {
+		return config.KebunRoleOwner, nil
+	}
+
+	var member models.KebunMember
+	err := db.Where("kebun_id = ? AND user_id = ?", kebunID, claims.UserID).First(&member).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return "", err
+	}
+
+	// Unscoped supaya peran tetap terbaca untuk kebun di trash (restore)
+	var kebun models.Kebun
+	if err := db.Unscoped().Select("id", "organization_id").First(&kebun, kebunID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+
+	if kebun.OrganizationID != nil {
+		if orgRole := organizationRoleOf(db, *kebun.OrganizationID, claims.UserID); orgRole == config.OrgRoleAdmin {
+			return config.KebunRoleOwner, nil
+		} else if orgRole == config.OrgRoleMember && member.ID == 0 {
+			return config.KebunRoleViewer, nil
+		}
+	} else if member.ID == 0 && config.HasPermission(claims.Role, config.PermKebunWrite) {
+		// kebun lama yang pemiliknya tidak tertelusuri (config.migrateKebunOwners) masih dikelola
+		// seperti sebelum ada keanggotaan, sampai owner / Admin menambahkan anggota pertama
+		var memberCount int64
+		if err := db.Model(&models.KebunMember{}).Where("kebun_id = ?", kebunID).Count(&memberCount).Error; err != nil {
+			return "", err
+		}
+		if memberCount == 0 {
+			return config.KebunRoleOwner, nil
+		}
+	}
+
+	return member.Role, nil
+}
+
+// authorizeTanaman sama seperti authorizeKebun lewat kebun milik tanaman
+func authorizeTanaman(c *gin.Context, db *gorm.DB, tanamanID uint, action config.KebunAction) bool {
+	var tanaman models.Tanaman
+	if err := db.Select("id", "kebun_id").First(&tanaman, tanamanID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "Tanaman tidak ditemukan", tanamanID)
+			return false
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil tanaman", err.Error())
+		return false
+	}
+
+	return authorizeKebun(c, db, tanaman.KebunID, action)
+}
+
+// kebunActorRole peran efektif user login di kebun untuk urusan keanggotaan
+func kebunActorRole(db *gorm.DB, claims *utils.Claims, kebunID uint) string {
+	role, _ := kebunRoleOf(db, claims, kebunID)
+	return role
+}
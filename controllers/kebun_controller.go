@@ -1,8 +1,11 @@
 package controllers
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -13,9 +16,105 @@ import (
 	"Avocycle/utils"
 )
 
+// KebunLokasiRequest lokasi & luas kebun, dipakai create dan update (field null tidak diubah)
+type KebunLokasiRequest struct {
+	Ketinggian *float64        `json:"ketinggian" example:"1200"`
+	Latitude   *float64        `json:"latitude" example:"-7.2575"`
+	Longitude  *float64        `json:"longitude" example:"112.7521"`
+	Batas      json.RawMessage `json:"batas" swaggertype:"object"` // GeoJSON Polygon, "null" untuk menghapus
+	LuasHektar *float64        `json:"luas_hektar" example:"2.5"`
+	Alamat     *string         `json:"alamat"`
+	Desa       *string         `json:"desa"`
+	Kecamatan  *string         `json:"kecamatan"`
+	Kabupaten  *string         `json:"kabupaten"`
+	Provinsi   *string         `json:"provinsi"`
+	KodePos    *string         `json:"kode_pos"`
+}
+
 type UpdateKebunRequest struct {
 	NamaKebun *string `json:"nama_kebun"`
 	MDPL      *string `json:"mdpl"`
+	KebunLokasiRequest
+}
+
+// applyKebunLokasi validasi lalu isi field lokasi kebun. mdpl teks dipakai sebagai ketinggian
+// jika ketinggian tidak dikirim. Batas tanpa luas / koordinat mengisi keduanya dari polygon.
+func applyKebunLokasi(k *models.Kebun, mdpl *string, r *KebunLokasiRequest) *string {
+	fail := func(msg string) *string { return &msg }
+
+	if mdpl != nil {
+		k.MDPL = strings.TrimSpace(*mdpl)
+		if r.Ketinggian == nil {
+			// teks yang tidak bisa dibaca mengosongkan ketinggian, supaya nilai lama tidak tertinggal
+			k.Ketinggian = nil
+			if v, ok := utils.ParseMDPL(k.MDPL); ok && utils.ValidKetinggian(v) {
+				k.Ketinggian = &v
+			}
+		}
+	}
+	if r.Ketinggian != nil {
+		if !utils.ValidKetinggian(*r.Ketinggian) {
+			return fail("ketinggian harus di antara -500 dan 6000 mdpl")
+		}
+		k.Ketinggian = r.Ketinggian
+		if mdpl == nil {
+			k.MDPL = strconv.FormatFloat(*r.Ketinggian, 'f', -1, 64)
+		}
+	}
+
+	if (r.Latitude == nil) != (r.Longitude == nil) {
+		return fail("latitude dan longitude harus dikirim bersamaan")
+	}
+	if r.Latitude != nil {
+		if err := utils.ValidateLatLng(*r.Latitude, *r.Longitude); err != nil {
+			return fail(err.Error())
+		}
+		k.Latitude, k.Longitude = r.Latitude, r.Longitude
+	}
+
+	if r.LuasHektar != nil {
+		if *r.LuasHektar <= 0 {
+			return fail("luas_hektar harus > 0")
+		}
+		k.LuasHektar = r.LuasHektar
+	}
+
+	switch {
+	case len(r.Batas) == 0:
+	case string(r.Batas) == "null":
+		k.Batas = nil
+	default:
+		poly, err := utils.ParseGeoJSONPolygon(r.Batas)
+		if err != nil {
+			return fail(err.Error())
+		}
+		k.Batas = r.Batas
+		if r.LuasHektar == nil {
+			luas := math.Round(poly.AreaHectares()*10000) / 10000
+			k.LuasHektar = &luas
+		}
+		if r.Latitude == nil {
+			lat, lng := poly.Centroid()
+			k.Latitude, k.Longitude = &lat, &lng
+		}
+	}
+
+	for dst, src := range map[*string]*string{
+		&k.Alamat: r.Alamat, &k.Desa: r.Desa, &k.Kecamatan: r.Kecamatan,
+		&k.Kabupaten: r.Kabupaten, &k.Provinsi: r.Provinsi, &k.KodePos: r.KodePos,
+	} {
+		if src != nil {
+			*dst = strings.TrimSpace(*src)
+		}
+	}
+	return nil
+}
+
+type CreateKebunRequest struct {
+	NamaKebun      string  `json:"nama_kebun" binding:"required"`
+	MDPL           *string `json:"mdpl"`
+	OrganizationID *uint   `json:"organization_id"`
+	KebunLokasiRequest
 }
 
 // --- CONTROLLERS ---
@@ -23,25 +122,33 @@ type UpdateKebunRequest struct {
 // GET /kebun
 // GetAllKebun godoc
 // @Summary      Ambil semua kebun
-// @Description  Mengambil daftar kebun dengan pagination (menggunakan meta pagination sesuai utils Pagination)
+// @Description  Mengambil daftar kebun dengan pagination (menggunakan meta pagination sesuai utils Pagination).
+// @Description  Petani melihat kebun organisasi / keanggotaannya, pembeli melihat semua kebun (read-only).
 // @Tags         Kebun
 // @Param        page      query   int     false  "Halaman"
 // @Param        per_page  query   int     false  "Jumlah data per halaman"
 // @Success      200  {object}  utils.Response  "Data kebun berhasil diambil"
 // @Failure      500  {object}  utils.Response
+// @Security Bearer
+// @Param organization_id query int false "Filter satu organisasi"
 // @Router       /kebun [get]
 func GetAllKebun(c *gin.Context) {
 	page, perPage := utils.GetPagination(c)
 	offset := utils.GetOffset(page, perPage)
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
 	}
 
+	tenant, ok := resolveCatalog(c, db)
+	if !ok {
+		return
+	}
+
 	var totalRows int64
-	if err := db.Model(&models.Kebun{}).Count(&totalRows).Error; err != nil {
+	if err := db.Model(&models.Kebun{}).Scopes(tenant.ByKebun("id")).Count(&totalRows).Error; err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menghitung data kebun", err.Error())
 		return
 	}
@@ -59,7 +166,7 @@ func GetAllKebun(c *gin.Context) {
 	}
 
 	var kebunList []models.Kebun
-	if err := db.Limit(perPage).Offset(offset).Find(&kebunList).Error; err != nil {
+	if err := db.Scopes(tenant.ByKebun("id")).Limit(perPage).Offset(offset).Find(&kebunList).Error; err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal mengambil data kebun", err.Error())
 		return
 	}
@@ -79,19 +186,26 @@ func GetAllKebun(c *gin.Context) {
 // @Param       id   path   int   true  "ID Kebun"
 // @Success     200  {object} utils.Response
 // @Failure     404  {object} utils.Response
+// @Security Bearer
 // @Router      /kebun/{id} [get]
 // GET /kebun/:id
 func GetKebunByID(c *gin.Context) {
 	id := c.Param("id")
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
 	}
 
+	tenant, ok := resolveCatalog(c, db)
+	if !ok {
+		return
+	}
+
+	// kebun di luar organisasi user dianggap tidak ada
 	var kebun models.Kebun
-	if err := db.First(&kebun, id).Error; err != nil {
+	if err := db.Scopes(tenant.ByKebun("id")).Preload("Organization").First(&kebun, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utils.ErrorResponse(c, http.StatusNotFound, "Kebun tidak ditemukan", nil)
 			return
@@ -106,26 +220,23 @@ func GetKebunByID(c *gin.Context) {
 // POST /kebun
 // CreateKebun godoc
 // @Summary     Membuat kebun baru
-// @Description Membuat data kebun baru (response mengikuti utils.Response)
+// @Description Membuat data kebun baru (response mengikuti utils.Response). Pembuat otomatis menjadi owner kebun.
 // @Tags        Kebun
 // @Accept      json
 // @Produce     json
-// @Param       request  body   object{nama_kebun=string,mdpl=string}  true  "Input kebun"
+// @Param       request  body   controllers.CreateKebunRequest  true  "Input kebun"
 // @Security 	Bearer
 // @Success     201  {object} utils.Response
 // @Failure     400  {object} utils.Response
 // @Router      /kebun [post]
 func CreateKebun(c *gin.Context) {
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
 	}
 
-	var input struct {
-		NamaKebun string `json:"nama_kebun" binding:"required"`
-		MDPL      string `json:"mdpl" binding:"required"`
-	}
+	var input CreateKebunRequest
 
 	if err := c.ShouldBindJSON(&input); err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
@@ -138,12 +249,40 @@ func CreateKebun(c *gin.Context) {
 		return
 	}
 
+	// kebun hanya boleh dibuat di organisasi tempat user menjadi anggota
+	if input.OrganizationID != nil {
+		claims := currentClaims(c)
+		if claims.Role != "Admin" && organizationRoleOf(db, *input.OrganizationID, claims.UserID) == "" {
+			utils.ErrorResponse(c, http.StatusForbidden, "Anda bukan anggota organisasi ini", gin.H{"organization_id": *input.OrganizationID})
+			return
+		}
+	}
+
+	if input.MDPL == nil && input.Ketinggian == nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ketinggian (atau mdpl) wajib diisi", nil)
+		return
+	}
+
 	kebun := models.Kebun{
-		NamaKebun: nama,
-		MDPL:      input.MDPL,
+		NamaKebun:      nama,
+		OrganizationID: input.OrganizationID,
+	}
+	if msg := applyKebunLokasi(&kebun, input.MDPL, &input.KebunLokasiRequest); msg != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, *msg, nil)
+		return
 	}
 
-	if err := db.Create(&kebun).Error; err != nil {
+	// pembuat kebun otomatis menjadi owner
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&kebun).Error; err != nil {
+			return err
+		}
+		if claims := currentClaims(c); claims != nil {
+			return tx.Create(&models.KebunMember{KebunID: kebun.ID, UserID: claims.UserID, Role: config.KebunRoleOwner}).Error
+		}
+		return nil
+	})
+	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal membuat kebun", err.Error())
 		return
 	}
@@ -154,7 +293,7 @@ func CreateKebun(c *gin.Context) {
 // PUT /kebun/:id
 // UpdateKebun godoc
 // @Summary     Update kebun
-// @Description Mengupdate data kebun berdasarkan ID (partial update)
+// @Description Mengupdate data kebun berdasarkan ID (partial update). Hanya owner / manager kebun.
 // @Tags        Kebun
 // @Accept      json
 // @Produce     json
@@ -162,12 +301,13 @@ func CreateKebun(c *gin.Context) {
 // @Param request body controllers.UpdateKebunRequest true "Input update"
 // @Security 	Bearer
 // @Success     200  {object} utils.Response
+// @Failure     403  {object} utils.Response
 // @Failure     404  {object} utils.Response
 // @Router      /kebun/{id} [put]
 func UpdateKebun(c *gin.Context) {
 	id := c.Param("id")
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
@@ -183,6 +323,10 @@ func UpdateKebun(c *gin.Context) {
 		return
 	}
 
+	if !authorizeKebun(c, db, kebun.ID, config.KebunActUpdate) {
+		return
+	}
+
 	var input UpdateKebunRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
@@ -198,8 +342,9 @@ func UpdateKebun(c *gin.Context) {
 		kebun.NamaKebun = nama
 	}
 
-	if input.MDPL != nil {
-		kebun.MDPL = *input.MDPL
+	if msg := applyKebunLokasi(&kebun, input.MDPL, &input.KebunLokasiRequest); msg != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, *msg, nil)
+		return
 	}
 
 	if err := db.Save(&kebun).Error; err != nil {
@@ -213,17 +358,21 @@ func UpdateKebun(c *gin.Context) {
 // DELETE /kebun/:id
 // DeleteKebun godoc
 // @Summary     Hapus kebun
-// @Description Menghapus data kebun berdasarkan ID
+// @Description Memindahkan kebun beserta tanaman, fase, buah, dan log penyakitnya ke trash dalam satu transaksi.
+// @Description Ditolak (409) jika masih ada booking aktif. Hanya owner kebun.
 // @Tags        Kebun
 // @Param       id   path   int  true  "ID Kebun"
+// @Param       dry_run query bool false "true = hanya tampilkan data yang akan terhapus"
 // @Security 	Bearer
 // @Success     200  {object} utils.Response
+// @Failure     403  {object} utils.Response
 // @Failure     404  {object} utils.Response
+// @Failure     409  {object} utils.Response
 // @Router      /kebun/{id} [delete]
 func DeleteKebun(c *gin.Context) {
 	id := c.Param("id")
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
@@ -239,10 +388,110 @@ func DeleteKebun(c *gin.Context) {
 		return
 	}
 
-	if err := db.Delete(&kebun).Error; err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal hapus kebun", err.Error())
+	if !authorizeKebun(c, db, kebun.ID, config.KebunActDelete) {
+		return
+	}
+
+	respondCascadeDelete(c, db, "kebun", kebun.ID)
+}
+
+// NearbyKebun data publik kebun untuk pencarian sekitar (tanpa batas & data organisasi)
+type NearbyKebun struct {
+	ID         uint     `json:"id"`
+	NamaKebun  string   `json:"nama_kebun"`
+	Ketinggian *float64 `json:"ketinggian"`
+	Latitude   float64  `json:"latitude"`
+	Longitude  float64  `json:"longitude"`
+	LuasHektar *float64 `json:"luas_hektar"`
+	Desa       string   `json:"desa,omitempty"`
+	Kecamatan  string   `json:"kecamatan,omitempty"`
+	Kabupaten  string   `json:"kabupaten,omitempty"`
+	Provinsi   string   `json:"provinsi,omitempty"`
+	JarakKm    float64  `json:"jarak_km"`
+}
+
+// GET /kebun/nearby
+// GetNearbyKebun godoc
+// @Summary     Kebun terdekat
+// @Description Mencari kebun dalam radius tertentu dari sebuah titik, urut dari yang terdekat.
+// @Description Terbuka untuk semua user login, hanya kebun yang boleh dilihat pengguna (organisasi / keanggotaan,
+// @Description katalog untuk Pembeli) dan hanya data publik kebun yang dikembalikan.
+// @Tags        Kebun
+// @Produce     json
+// @Param       lat       query  number  true   "Latitude titik asal"
+// @Param       lng       query  number  true   "Longitude titik asal"
+// @Param       radius_km query  number  false  "Radius dalam km (default 25, maks 200)"
+// @Param       limit     query  int     false  "Jumlah maksimal hasil (default 20, maks 100)"
+// @Security    Bearer
+// @Success     200  {object} utils.Response
+// @Failure     400  {object} utils.Response
+// @Router      /kebun/nearby [get]
+func GetNearbyKebun(c *gin.Context) {
+	lat, errLat := strconv.ParseFloat(c.Query("lat"), 64)
+	lng, errLng := strconv.ParseFloat(c.Query("lng"), 64)
+	if errLat != nil || errLng != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "lat dan lng wajib berupa angka", nil)
+		return
+	}
+	if err := utils.ValidateLatLng(lat, lng); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	radius := 25.0
+	if raw := c.Query("radius_km"); raw != "" {
+		r, err := strconv.ParseFloat(raw, 64)
+		if err != nil || r <= 0 || r > 200 {
+			utils.ErrorResponse(c, http.StatusBadRequest, "radius_km harus di antara 0 dan 200", raw)
+			return
+		}
+		radius = r
+	}
+
+	limit := 20
+	if raw := c.Query("limit"); raw != "" {
+		l, err := strconv.Atoi(raw)
+		if err != nil || l <= 0 || l > 100 {
+			utils.ErrorResponse(c, http.StatusBadRequest, "limit harus di antara 1 dan 100", raw)
+			return
+		}
+		limit = l
+	}
+
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	// hanya kebun yang boleh dilihat pengguna, sama seperti GetAllKebun
+	tenant, ok := resolveCatalog(c, db)
+	if !ok {
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Kebun berhasil dihapus", utils.EmptyObj{})
+	// bounding box dulu supaya index lokasi terpakai, baru haversine untuk jarak sebenarnya
+	dLat := radius / 111.045
+	dLng := radius / (111.045 * math.Max(math.Cos(lat*math.Pi/180), 0.01))
+	distance := "(2 * 6371 * ASIN(SQRT(POWER(SIN(RADIANS(latitude - ?) / 2), 2) + " +
+		"COS(RADIANS(?)) * COS(RADIANS(latitude)) * POWER(SIN(RADIANS(longitude - ?) / 2), 2))))"
+
+	var result []NearbyKebun
+	if err := db.Model(&models.Kebun{}).
+		Select("id, nama_kebun, ketinggian, latitude, longitude, luas_hektar, desa, kecamatan, kabupaten, provinsi, "+distance+" AS jarak_km", lat, lat, lng).
+		Where("latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?", lat-dLat, lat+dLat, lng-dLng, lng+dLng).
+		Where(distance+" <= ?", lat, lat, lng, radius).
+		Scopes(tenant.ByKebun("id")).
+		Order("jarak_km ASC").
+		Limit(limit).
+		Scan(&result).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal mencari kebun terdekat", err.Error())
+		return
+	}
+
+	for i := range result {
+		result[i].JarakKm = math.Round(result[i].JarakKm*100) / 100
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Kebun terdekat", result)
 }
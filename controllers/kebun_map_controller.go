@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"Avocycle/config"
+	"Avocycle/models"
+	"Avocycle/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetKebunGeoJSON godoc
+// @Summary Peta tanaman kebun (GeoJSON)
+// @Description FeatureCollection untuk web map. Feature pertama batas kebun (jika ada, properties.jenis = "kebun"),
+// @Description sisanya satu feature Point per tanaman (properties.jenis = "tanaman") berisi fase terakhir,
+// @Description kondisi penyakit terbaru, dan status booking. Tanaman tanpa GPS tetap dikirim dengan geometry null
+// @Description beserta baris / kolom di blok, kecuali ?hanya_berkoordinat=true.
+// @Tags Kebun
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Kebun"
+// @Param blok_id query int false "Hanya tanaman di blok ini"
+// @Param fase query string false "Filter fase: belum-ada, bunga, buah, panen"
+// @Param status query string false "Filter status tanaman: Active, Dormant, Removed, Replanted (boleh dipisah koma)"
+// @Param hanya_berkoordinat query bool false "true = lewati tanaman tanpa latitude / longitude"
+// @Success 200 {object} utils.GeoJSONFeatureCollection
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /kebun/{id}/geojson [get]
+func GetKebunGeoJSON(c *gin.Context) {
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	kebun, ok := kebunFromParam(c, db)
+	if !ok {
+		return
+	}
+	if !authorizeKebun(c, db, kebun.ID, config.KebunActView) {
+		return
+	}
+
+	query := db.Model(&models.Tanaman{}).Where("kebun_id = ?", kebun.ID)
+	if raw := c.Query("blok_id"); raw != "" {
+		blokID, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || blokID == 0 {
+			utils.ErrorResponse(c, http.StatusBadRequest, "blok_id tidak valid", raw)
+			return
+		}
+		query = query.Where("blok_id = ?", blokID)
+	}
+	if c.Query("hanya_berkoordinat") == "true" {
+		query = query.Where("latitude IS NOT NULL AND longitude IS NOT NULL")
+	}
+	byStatus, ok := tanamanStatusFilter(c)
+	if !ok {
+		return
+	}
+	query = query.Scopes(byStatus)
+	filterFase := c.Query("fase")
+	switch filterFase {
+	case "", "belum-ada", "bunga", "buah", "panen":
+	default:
+		utils.ErrorResponse(c, http.StatusBadRequest, "fase harus belum-ada, bunga, buah, atau panen", filterFase)
+		return
+	}
+
+	var tanaman []models.Tanaman
+	if err := query.Preload("Varietas").Order("kode_tanaman ASC").Find(&tanaman).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal mengambil tanaman", err.Error())
+		return
+	}
+	ids := make([]uint, len(tanaman))
+	for i, t := range tanaman {
+		ids[i] = t.ID
+	}
+
+	fase, err := faseSaatIniBulk(db, ids)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil fase tanaman", err.Error())
+		return
+	}
+
+	// kondisi dari log penyakit terbaru per tanaman, sama seperti statistik
+	var logs []models.LogPenyakitTanaman
+	if err := db.Select("DISTINCT ON (tanaman_id) *").Where("tanaman_id IN ?", ids).
+		Preload("Penyakit").Order("tanaman_id, created_at DESC").Find(&logs).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil log penyakit", err.Error())
+		return
+	}
+	kondisi := make(map[uint]*models.LogPenyakitTanaman, len(logs))
+	for i := range logs {
+		kondisi[logs[i].TanamanID] = &logs[i]
+	}
+
+	var bookings []struct {
+		TanamanID uint
+		Jumlah    int64
+	}
+	if err := db.Model(&models.Booking{}).Scopes(config.BookingOpen).Select("tanaman_id, COUNT(*) AS jumlah").
+		Where("tanaman_id IN ?", ids).Group("tanaman_id").Scan(&bookings).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil booking", err.Error())
+		return
+	}
+	booked := make(map[uint]int64, len(bookings))
+	for _, b := range bookings {
+		booked[b.TanamanID] = b.Jumlah
+	}
+
+	collection := utils.NewGeoJSONFeatureCollection()
+	if len(kebun.Batas) > 0 {
+		collection.Features = append(collection.Features, utils.GeoJSONFeature{
+			Type:     "Feature",
+			ID:       "kebun-" + strconv.FormatUint(uint64(kebun.ID), 10),
+			Geometry: json.RawMessage(kebun.Batas),
+			Properties: map[string]any{
+				"jenis":       "kebun",
+				"kebun_id":    kebun.ID,
+				"nama_kebun":  kebun.NamaKebun,
+				"luas_hektar": kebun.LuasHektar,
+			},
+		})
+	}
+
+	for _, t := range tanaman {
+		f, ok := fase[t.ID]
+		if !ok {
+			f = TanamanFase{Fase: "belum-ada"}
+		}
+		if filterFase != "" && f.Fase != filterFase {
+			continue
+		}
+
+		props := map[string]any{
+			"jenis":          "tanaman",
+			"tanaman_id":     t.ID,
+			"kode_tanaman":   t.KodeTanaman,
+			"nama_tanaman":   t.NamaTanaman,
+			"status":         t.Status,
+			"blok_id":        t.BlokID,
+			"kode_blok":      t.KodeBlok,
+			"baris":          t.Baris,
+			"kolom":          t.Kolom,
+			"fase":           f.Fase,
+			"siap_panen":     f.SiapPanen,
+			"estimasi_panen": f.EstimasiPanen,
+			"kondisi":        nil,
+			"booking_status": "tersedia",
+			"jumlah_booking": booked[t.ID],
+		}
+		if t.Varietas != nil {
+			props["varietas"] = t.Varietas.Nama
+		}
+		if logPenyakit, ok := kondisi[t.ID]; ok {
+			props["kondisi"] = logPenyakit.Kondisi
+			props["penyakit"] = logPenyakit.Penyakit.NamaPenyakit
+			props["kondisi_dicatat"] = logPenyakit.CreatedAt.Format(time.RFC3339)
+		}
+		if booked[t.ID] > 0 {
+			props["booking_status"] = "dibooking"
+		}
+
+		feature := utils.GeoJSONFeature{Type: "Feature", ID: t.ID, Properties: props}
+		if t.Latitude != nil && t.Longitude != nil {
+			feature.Geometry = utils.NewGeoJSONPoint(*t.Latitude, *t.Longitude)
+		}
+		collection.Features = append(collection.Features, feature)
+	}
+
+	// dikirim apa adanya (tanpa bungkus utils.Response) supaya bisa langsung dipakai library peta
+	c.Header("Content-Type", "application/geo+json")
+	c.JSON(http.StatusOK, collection)
+}
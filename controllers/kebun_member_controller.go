@@ -0,0 +1,578 @@
+package controllers
+
+import (
+	"Avocycle/config"
+	"Avocycle/models"
+	"Avocycle/utils"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const kebunInvitationTTL = 7 * 24 * time.Hour
+
+// InviteKebunMemberRequest body undangan anggota kebun
+type InviteKebunMemberRequest struct {
+	Email string `json:"email" example:"pekerja@example.com"`
+	Role  string `json:"role" example:"worker"`
+}
+
+// UpdateKebunMemberRequest body ubah peran anggota
+type UpdateKebunMemberRequest struct {
+	Role string `json:"role" example:"manager"`
+}
+
+// AcceptKebunInvitationRequest body menerima undangan
+type AcceptKebunInvitationRequest struct {
+	Token string `json:"token" example:"q1w2e3r4..."`
+}
+
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(token)))
+	return hex.EncodeToString(sum[:])
+}
+
+// kebunFromParam ambil kebun dari path :id, kirim 404 jika tidak ada
+func kebunFromParam(c *gin.Context, db *gorm.DB) (*models.Kebun, bool) {
+	var kebun models.Kebun
+	if err := db.First(&kebun, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "Kebun tidak ditemukan", nil)
+			return nil, false
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil data kebun", err.Error())
+		return nil, false
+	}
+	return &kebun, true
+}
+
+// countKebunOwners dipakai supaya kebun tidak pernah kehilangan owner terakhir
+func countKebunOwners(db *gorm.DB, kebunID uint) int64 {
+	var owners int64
+	db.Model(&models.KebunMember{}).
+		Where("kebun_id = ? AND role = ?", kebunID, config.KebunRoleOwner).
+		Count(&owners)
+	return owners
+}
+
+// GetKebunMembers godoc
+// @Summary Daftar anggota kebun
+// @Description Anggota kebun beserta perannya (owner, manager, worker, viewer)
+// @Tags Kebun Member
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Kebun"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /kebun/{id}/members [get]
+func GetKebunMembers(c *gin.Context) {
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	kebun, ok := kebunFromParam(c, db)
+	if !ok || !authorizeKebun(c, db, kebun.ID, config.KebunActView) {
+		return
+	}
+
+	var members []models.KebunMember
+	if err := db.Preload("User").Where("kebun_id = ?", kebun.ID).Order("id ASC").Find(&members).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal mengambil anggota kebun", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Anggota kebun berhasil diambil", members)
+}
+
+// UpdateKebunMember godoc
+// @Summary Ubah peran anggota kebun
+// @Description Owner bisa memberi peran apa saja, manager hanya worker / viewer. Owner terakhir tidak bisa diturunkan.
+// @Tags Kebun Member
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "ID Kebun"
+// @Param user_id path int true "User ID anggota"
+// @Param request body controllers.UpdateKebunMemberRequest true "Peran baru"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /kebun/{id}/members/{user_id} [put]
+func UpdateKebunMember(c *gin.Context) {
+	claims := currentClaims(c)
+
+	var input struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
+		return
+	}
+	if !config.ValidKebunRole(input.Role) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "role tidak valid (owner, manager, worker, viewer)", input.Role)
+		return
+	}
+
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	kebun, ok := kebunFromParam(c, db)
+	if !ok || !authorizeKebun(c, db, kebun.ID, config.KebunActManageMembers) {
+		return
+	}
+
+	var member models.KebunMember
+	if err := db.Where("kebun_id = ? AND user_id = ?", kebun.ID, c.Param("user_id")).First(&member).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "Anggota tidak ditemukan", nil)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil anggota", err.Error())
+		return
+	}
+
+	actorRole := kebunActorRole(db, claims, kebun.ID)
+	if !config.KebunRoleCanAssign(actorRole, input.Role) || !config.KebunRoleCanAssign(actorRole, member.Role) {
+		utils.ErrorResponse(c, http.StatusForbidden, "Peran Anda tidak bisa mengubah peran ini", gin.H{"your_role": actorRole})
+		return
+	}
+
+	if member.Role == config.KebunRoleOwner && input.Role != config.KebunRoleOwner && countKebunOwners(db, kebun.ID) <= 1 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Kebun harus punya minimal satu owner", nil)
+		return
+	}
+
+	if input.Role != config.KebunRoleViewer {
+		var user models.User
+		if err := db.First(&user, member.UserID).Error; err == nil && !config.HasPermission(user.Role, config.PermFaseWrite) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Akun ini hanya bisa menjadi viewer", gin.H{"account_role": user.Role})
+			return
+		}
+	}
+
+	if err := db.Model(&member).Update("role", input.Role).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal update peran anggota", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Peran anggota berhasil diperbarui", member)
+}
+
+// RemoveKebunMember godoc
+// @Summary Keluarkan anggota kebun
+// @Description Owner / manager bisa mengeluarkan anggota dengan peran di bawahnya; anggota juga bisa keluar sendiri.
+// @Tags Kebun Member
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Kebun"
+// @Param user_id path int true "User ID anggota"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /kebun/{id}/members/{user_id} [delete]
+func RemoveKebunMember(c *gin.Context) {
+	claims := currentClaims(c)
+
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	kebun, ok := kebunFromParam(c, db)
+	if !ok {
+		return
+	}
+
+	var member models.KebunMember
+	if err := db.Where("kebun_id = ? AND user_id = ?", kebun.ID, c.Param("user_id")).First(&member).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "Anggota tidak ditemukan", nil)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil anggota", err.Error())
+		return
+	}
+
+	// keluar sendiri tidak butuh hak kelola anggota
+	if member.UserID != claims.UserID {
+		if !authorizeKebun(c, db, kebun.ID, config.KebunActManageMembers) {
+			return
+		}
+		if actorRole := kebunActorRole(db, claims, kebun.ID); !config.KebunRoleCanAssign(actorRole, member.Role) {
+			utils.ErrorResponse(c, http.StatusForbidden, "Peran Anda tidak bisa mengeluarkan anggota ini", gin.H{"your_role": actorRole})
+			return
+		}
+	}
+
+	if member.Role == config.KebunRoleOwner && countKebunOwners(db, kebun.ID) <= 1 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Kebun harus punya minimal satu owner", nil)
+		return
+	}
+
+	// hard delete supaya user bisa diundang lagi (unique kebun_id + user_id)
+	if err := db.Unscoped().Delete(&member).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal mengeluarkan anggota", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Anggota berhasil dikeluarkan", utils.EmptyObj{})
+}
+
+// InviteKebunMember godoc
+// @Summary Undang anggota kebun
+// @Description Membuat undangan lewat email. Token hanya dikembalikan sekali di response ini untuk dikirim ke calon anggota,
+// @Description lalu diterima lewat POST /kebun-invitations/accept. Calon anggota yang login dengan email undangan
+// @Description juga bisa menerima lewat POST /me/kebun-invitations/{invitation_id}/accept tanpa token.
+// @Description Peran selain viewer butuh akun Petani.
+// @Tags Kebun Member
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "ID Kebun"
+// @Param request body controllers.InviteKebunMemberRequest true "Undangan"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /kebun/{id}/invitations [post]
+func InviteKebunMember(c *gin.Context) {
+	claims := currentClaims(c)
+
+	var input struct {
+		Email string `json:"email" binding:"required,email"`
+		Role  string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
+		return
+	}
+	if !config.ValidKebunRole(input.Role) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "role tidak valid (owner, manager, worker, viewer)", input.Role)
+		return
+	}
+	email := strings.ToLower(strings.TrimSpace(input.Email))
+
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	kebun, ok := kebunFromParam(c, db)
+	if !ok || !authorizeKebun(c, db, kebun.ID, config.KebunActManageMembers) {
+		return
+	}
+
+	if actorRole := kebunActorRole(db, claims, kebun.ID); !config.KebunRoleCanAssign(actorRole, input.Role) {
+		utils.ErrorResponse(c, http.StatusForbidden, "Peran Anda tidak bisa mengundang dengan peran ini", gin.H{"your_role": actorRole})
+		return
+	}
+
+	var existing int64
+	db.Model(&models.KebunMember{}).
+		Joins("JOIN users ON users.id = kebun_members.user_id").
+		Where("kebun_members.kebun_id = ? AND LOWER(users.email) = ?", kebun.ID, email).
+		Count(&existing)
+	if existing > 0 {
+		utils.ErrorResponse(c, http.StatusConflict, "User sudah menjadi anggota kebun ini", email)
+		return
+	}
+
+	token, err := utils.RandomToken(32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal membuat token undangan", err.Error())
+		return
+	}
+
+	invitation := models.KebunInvitation{
+		KebunID:     kebun.ID,
+		Email:       email,
+		Role:        input.Role,
+		TokenHash:   hashInvitationToken(token),
+		InvitedByID: claims.UserID,
+		ExpiresAt:   time.Now().Add(kebunInvitationTTL),
+	}
+
+	// undangan lama yang belum diterima untuk email yang sama diganti
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("kebun_id = ? AND email = ? AND accepted_at IS NULL", kebun.ID, email).
+			Delete(&models.KebunInvitation{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&invitation).Error
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal membuat undangan", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Undangan berhasil dibuat", gin.H{
+		"invitation": invitation,
+		"token":      token,
+	})
+}
+
+// GetKebunInvitations godoc
+// @Summary Daftar undangan kebun
+// @Description Undangan yang belum diterima dan belum kedaluwarsa
+// @Tags Kebun Member
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Kebun"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /kebun/{id}/invitations [get]
+func GetKebunInvitations(c *gin.Context) {
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	kebun, ok := kebunFromParam(c, db)
+	if !ok || !authorizeKebun(c, db, kebun.ID, config.KebunActManageMembers) {
+		return
+	}
+
+	var invitations []models.KebunInvitation
+	if err := db.Where("kebun_id = ? AND accepted_at IS NULL AND expires_at > ?", kebun.ID, time.Now()).
+		Order("created_at DESC").
+		Find(&invitations).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal mengambil undangan", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Undangan kebun berhasil diambil", invitations)
+}
+
+// RevokeKebunInvitation godoc
+// @Summary Batalkan undangan kebun
+// @Tags Kebun Member
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Kebun"
+// @Param invitation_id path int true "ID Undangan"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /kebun/{id}/invitations/{invitation_id} [delete]
+func RevokeKebunInvitation(c *gin.Context) {
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	kebun, ok := kebunFromParam(c, db)
+	if !ok || !authorizeKebun(c, db, kebun.ID, config.KebunActManageMembers) {
+		return
+	}
+
+	invitationID, err := strconv.Atoi(c.Param("invitation_id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invitation_id tidak valid", c.Param("invitation_id"))
+		return
+	}
+
+	res := db.Where("id = ? AND kebun_id = ? AND accepted_at IS NULL", invitationID, kebun.ID).
+		Delete(&models.KebunInvitation{})
+	if res.Error != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal membatalkan undangan", res.Error.Error())
+		return
+	}
+	if res.RowsAffected == 0 {
+		utils.ErrorResponse(c, http.StatusNotFound, "Undangan tidak ditemukan", nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Undangan berhasil dibatalkan", utils.EmptyObj{})
+}
+
+// GetMyKebunInvitations godoc
+// @Summary Undangan kebun untuk saya
+// @Description Undangan aktif yang ditujukan ke email user login
+// @Tags Kebun Member
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /me/kebun-invitations [get]
+func GetMyKebunInvitations(c *gin.Context) {
+	claims := currentClaims(c)
+
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	var user models.User
+	if err := db.First(&user, claims.UserID).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "User tidak ditemukan", nil)
+		return
+	}
+
+	var invitations []models.KebunInvitation
+	if err := db.Preload("Kebun").
+		Where("email = ? AND accepted_at IS NULL AND expires_at > ?", strings.ToLower(user.Email), time.Now()).
+		Order("created_at DESC").
+		Find(&invitations).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal mengambil undangan", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Undangan kebun berhasil diambil", invitations)
+}
+
+// AcceptKebunInvitation godoc
+// @Summary Terima undangan kebun
+// @Description Token dari pengundang. Email akun yang login harus sama dengan email undangan.
+// @Tags Kebun Member
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body controllers.AcceptKebunInvitationRequest true "Token undangan"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /kebun-invitations/accept [post]
+func AcceptKebunInvitation(c *gin.Context) {
+	var input struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
+		return
+	}
+
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	var invitation models.KebunInvitation
+	if err := db.Where("token_hash = ? AND accepted_at IS NULL AND expires_at > ?", hashInvitationToken(input.Token), time.Now()).
+		First(&invitation).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Undangan tidak valid atau sudah kedaluwarsa", nil)
+		return
+	}
+
+	acceptInvitation(c, db, &invitation, false)
+}
+
+// AcceptMyKebunInvitation godoc
+// @Summary Terima undangan kebun tanpa token
+// @Description Menerima undangan dari GET /me/kebun-invitations berdasarkan ID. Cukup login dengan akun
+// @Description yang emailnya sama dengan email undangan, token tidak perlu diteruskan oleh pengundang.
+// @Description Email akun lokal tidak diverifikasi, jadi akun yang dibuat setelah undangan tetap wajib pakai token.
+// @Tags Kebun Member
+// @Security Bearer
+// @Produce json
+// @Param invitation_id path int true "ID Undangan"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /me/kebun-invitations/{invitation_id}/accept [post]
+func AcceptMyKebunInvitation(c *gin.Context) {
+	invitationID, err := strconv.Atoi(c.Param("invitation_id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invitation_id tidak valid", c.Param("invitation_id"))
+		return
+	}
+
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	var invitation models.KebunInvitation
+	if err := db.Where("id = ? AND accepted_at IS NULL AND expires_at > ?", invitationID, time.Now()).
+		First(&invitation).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Undangan tidak valid atau sudah kedaluwarsa", nil)
+		return
+	}
+
+	acceptInvitation(c, db, &invitation, true)
+}
+
+// acceptInvitation menjadikan user login anggota kebun sesuai undangan; email akun harus cocok.
+// Tanpa token (byID) akun harus sudah ada sebelum undangan dibuat, supaya email orang lain
+// yang belum terdaftar tidak bisa didaftarkan duluan lalu dipakai mengambil undangannya.
+func acceptInvitation(c *gin.Context, db *gorm.DB, invitation *models.KebunInvitation, byID bool) {
+	claims := currentClaims(c)
+
+	var user models.User
+	if err := db.First(&user, claims.UserID).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "User tidak ditemukan", nil)
+		return
+	}
+
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		utils.ErrorResponse(c, http.StatusForbidden, "Undangan ini bukan untuk akun Anda", nil)
+		return
+	}
+	if byID && !user.CreatedAt.Before(invitation.CreatedAt) {
+		utils.ErrorResponse(c, http.StatusForbidden, "Akun dibuat setelah undangan, terima undangan memakai token dari pengundang", nil)
+		return
+	}
+
+	if invitation.Role != config.KebunRoleViewer && !config.HasPermission(user.Role, config.PermFaseWrite) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Peran ini butuh akun Petani", gin.H{"role": invitation.Role})
+		return
+	}
+
+	member := models.KebunMember{KebunID: invitation.KebunID, UserID: user.ID, Role: invitation.Role}
+	errAlreadyMember := errors.New("sudah anggota")
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var existing int64
+		tx.Model(&models.KebunMember{}).Where("kebun_id = ? AND user_id = ?", invitation.KebunID, user.ID).Count(&existing)
+		if existing > 0 {
+			return errAlreadyMember
+		}
+
+		now := time.Now()
+		res := tx.Model(&models.KebunInvitation{}).
+			Where("id = ? AND accepted_at IS NULL", invitation.ID).
+			Update("accepted_at", &now)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Create(&member).Error
+	})
+	if err != nil {
+		switch err {
+		case errAlreadyMember:
+			utils.ErrorResponse(c, http.StatusConflict, "Anda sudah menjadi anggota kebun ini", nil)
+		case gorm.ErrRecordNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound, "Undangan tidak valid atau sudah kedaluwarsa", nil)
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menerima undangan", err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Undangan diterima", member)
+}
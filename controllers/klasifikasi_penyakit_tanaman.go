@@ -9,7 +9,6 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -21,6 +20,7 @@ import (
 	"github.com/joho/godotenv"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/api/option"
+	"gorm.io/gorm"
 )
 
 // @Definitions
@@ -69,7 +69,7 @@ type ErrorResponseWrapper struct {
 }
 
 // @Summary Klasifikasi Penyakit Tanaman Alpukat
-// @Description Menerima foto tanaman alpukat, mengklasifikasikan penyakit menggunakan Gemini AI, mengunggah foto, dan menyimpan log ke database.
+// @Description Menerima foto tanaman alpukat, mengklasifikasikan penyakit menggunakan Gemini AI, mengunggah foto, dan menyimpan log ke database. Foto yang identik dengan foto klasifikasi sebelumnya memakai diagnosis lama tanpa memanggil Gemini (dari_cache = true).
 // @Tags Petani & Admin (Deteksi)
 // @Accept multipart/form-data
 // @Produce json
@@ -85,7 +85,7 @@ func ClassifyPenyakit(c *gin.Context) {
 	idTanaman := c.Param("id_tanaman")
 
 	// connect to database
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
     if err != nil {
         utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
         return
@@ -98,6 +98,14 @@ func ClassifyPenyakit(c *gin.Context) {
         return
 	}
 
+	// worker ke atas di kebun tanaman ini
+	if !authorizeTanaman(c, db, uint(tanamanId), config.KebunActPenyakitClassify) {
+		return
+	}
+	if !ensureTanamanRecordable(c, db, uint(tanamanId)) {
+		return
+	}
+
 	// load env file
 	err = godotenv.Load()
 	if err != nil {
@@ -128,32 +136,28 @@ func ClassifyPenyakit(c *gin.Context) {
         return
     }
 
-	// Tentukan MIME type berdasarkan ekstensi file (abaikan header dari client)
-	ext := strings.ToLower(filepath.Ext(file.Filename))
-	var contentType string
-	switch ext {
-	case ".jpg", ".jpeg":
-		contentType = "image/jpeg"
-	case ".png":
-		contentType = "image/png"
-	case ".webp":
-		contentType = "image/webp"
-	case ".gif":
-		contentType = "image/gif"
-	default:
-		// fallback aman ke JPEG
-		contentType = "image/jpeg"
+	// Validasi isi file dan perkecil sebelum dikirim ke Gemini (bukan berdasarkan ekstensi / header client)
+	classifierImage, err := utils.PrepareClassifierImage(imageBytes)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "File bukan gambar yang valid", err.Error())
+		return
 	}
 
+	// foto identik yang sudah pernah diklasifikasi: pakai diagnosis sebelumnya, Gemini tidak dipanggil lagi
+	cached := cachedKlasifikasi(db, uint(tanamanId), utils.ContentHash(imageBytes))
+
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-    client, err := genai.NewClient(ctx, option.WithAPIKey(os.Getenv("GEMINI_API")))
-    if err != nil {
-        utils.ErrorResponse(c, http.StatusInternalServerError, "Inisialisasi Gemini gagal", err.Error())
-        return
-    }
-    defer client.Close()
+	var client *genai.Client
+	if cached == nil {
+		client, err = genai.NewClient(ctx, option.WithAPIKey(os.Getenv("GEMINI_API")))
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Inisialisasi Gemini gagal", err.Error())
+			return
+		}
+		defer client.Close()
+	}
 
 	var(
 		classifyResult struct {
@@ -162,65 +166,69 @@ func ClassifyPenyakit(c *gin.Context) {
             Kondisi         string `json:"kondisi"`
             SaranPerawatan  string `json:"saran_perawatan"`
         }
-        uploadURL string
-        uploadID  string
+        uploaded *utils.UploadedImage
 	)
 	g, gctx := errgroup.WithContext(ctx)
 
-	g.Go(
-		func() error {
-			model := client.GenerativeModel("gemini-2.5-flash")
-
-			part := genai.Blob{
-				MIMEType: contentType,
-				Data: imageBytes,
-			}
-
-			resp, err := model.GenerateContent(gctx,
-			part,
-			genai.Text(`Analisis foto tanaman alpukat ini dan hasilkan JSON dengan format persis:
+	if cached != nil {
+		classifyResult.NamaPenyakit = cached.Penyakit.NamaPenyakit
+		classifyResult.Deskripsi = cached.Penyakit.Deskripsi
+		classifyResult.Kondisi = cached.Kondisi
+		classifyResult.SaranPerawatan = cached.SaranPerawatan
+	} else {
+		g.Go(
+			func() error {
+				model := client.GenerativeModel("gemini-2.5-flash")
+
+				part := genai.Blob{
+					MIMEType: classifierImage.MIME,
+					Data: classifierImage.Data,
+				}
+
+				resp, err := model.GenerateContent(gctx,
+				part,
+				genai.Text(`Analisis foto tanaman alpukat ini dan hasilkan JSON dengan format persis:
 {
   "nama_penyakit": "<nama penyakit atau 'Tidak terdeteksi'>",
   "deskripsi": "<deskripsi penyakit>",
   "kondisi": "<Parah|Sedang|Ringan|Sembuh>",
   "saran_perawatan": "<saran singkat>"
 }`),
-		)
-		if err != nil {
-			return err
-		}
+			)
+			if err != nil {
+				return err
+			}
 
-		text := cleanupGeminiJSON(extractText(resp))
+			text := cleanupGeminiJSON(extractText(resp))
 
-		// --- LOGGING TAMBAHAN UNTUK DEBUGGING ---
-		fmt.Printf("DEBUG: Gemini Raw Text Result: \n%s\n", text)
-		// --- END LOGGING ---
+			// --- LOGGING TAMBAHAN UNTUK DEBUGGING ---
+			fmt.Printf("DEBUG: Gemini Raw Text Result: \n%s\n", text)
+			// --- END LOGGING ---
 
-		// Cek apakah hasil yang dibersihkan terlihat seperti JSON
-		if !strings.HasPrefix(text, "{") {
-			// Jika tidak diawali kurung kurawal, ini adalah kegagalan format.
-			// Kembalikan error dengan hasil teks mentah dari Gemini.
-			return fmt.Errorf("Gemini tidak mengembalikan JSON yang valid. Output: %s", text)
-		}
+			// Cek apakah hasil yang dibersihkan terlihat seperti JSON
+			if !strings.HasPrefix(text, "{") {
+				// Jika tidak diawali kurung kurawal, ini adalah kegagalan format.
+				// Kembalikan error dengan hasil teks mentah dari Gemini.
+				return fmt.Errorf("Gemini tidak mengembalikan JSON yang valid. Output: %s", text)
+			}
 
-		if err := json.Unmarshal([]byte(text), &classifyResult); err != nil {
-			return fmt.Errorf("Gagal parsing hasil Gemini: %w", err)
-		}
-		return nil
-	})
+			if err := json.Unmarshal([]byte(text), &classifyResult); err != nil {
+				return fmt.Errorf("Gagal parsing hasil Gemini: %w", err)
+			}
+			return nil
+		})
+	}
 
 	g.Go(
 		func() error {
-			url, publicID, err := utils.AsyncUploadOptionalImage(file, "logpenyakit")
-			if err != nil {
-				return err
-			}
-			uploadURL = url
-			uploadID = publicID
-			return nil
+			var err error
+			uploaded, err = utils.UploadImage(file, "logpenyakit")
+			return err
 		})
 
 	if err := g.Wait(); err != nil {
+		// klasifikasi gagal tapi upload mungkin sudah selesai
+		utils.DeleteUploadedImage(uploaded)
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Proses klasifikasi atau upload gagal", err.Error())
 		return
 	}
@@ -231,6 +239,7 @@ func ClassifyPenyakit(c *gin.Context) {
 	}
 
 	if err := db.FirstOrCreate(&penyakit, models.PenyakitTanaman{NamaPenyakit: classifyResult.NamaPenyakit}).Error; err != nil {
+		utils.DeleteUploadedImage(uploaded)
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menyimpan penyakit", err.Error())
         return
 	}
@@ -240,12 +249,24 @@ func ClassifyPenyakit(c *gin.Context) {
 		PenyakitID: penyakit.ID,
 		Kondisi: classifyResult.Kondisi,
 		SaranPerawatan: classifyResult.SaranPerawatan,
-		Foto: uploadURL,
-		FotoLogPenyakitID: uploadID,
+		Foto: uploaded.URL,
+		FotoLogPenyakitID: uploaded.PublicID,
+		FotoThumb: uploaded.ThumbURL,
 	}
-	if err := db.Create(&logPenyakit).Error; err != nil {
+	// log penyakit & entri galeri satu transaksi
+	var cover *models.Media
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&logPenyakit).Error; err != nil {
+			return err
+		}
+		var err error
+		cover, err = config.AttachCoverPhoto(tx, "log-penyakit", logPenyakit.ID, uploaded, currentUserID(c))
+		return err
+	})
+	if err != nil {
+		utils.DeleteUploadedImage(uploaded)
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menyimpan log penyakit", err.Error())
-        return
+		return
 	}
 
 	// ===============================================
@@ -262,13 +283,16 @@ func ClassifyPenyakit(c *gin.Context) {
 			// Lanjutkan tanpa return error, agar response sukses tetap terkirim
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Klasifikasi berhasil", gin.H{
+	warnings := fotoWarnings(db, tanamanKebunLokasi(db, logPenyakit.TanamanID), "foto tanaman", cover)
+
+	utils.SuccessResponseWithWarnings(c, http.StatusOK, "Klasifikasi berhasil", gin.H{
 		"nama_penyakit":    classifyResult.NamaPenyakit,
 		"deskripsi":		classifyResult.Deskripsi,
         "kondisi":          classifyResult.Kondisi,
         "saran_perawatan":  classifyResult.SaranPerawatan,
         "log":              logPenyakit,	
-	})
+        "dari_cache":       cached != nil,
+	}, warnings)
 }
 
 func cleanupGeminiJSON(raw string) string {
@@ -289,4 +313,19 @@ func extractText(resp *genai.GenerateContentResponse) string {
         }
     }
     return b.String()
-}
\ No newline at end of file
+}
+
+// cachedKlasifikasi log penyakit terbaru di kebun yang sama yang fotonya identik (SHA-256 file sama),
+// nil jika belum pernah. Diagnosis kebun lain tidak dipakai ulang.
+func cachedKlasifikasi(db *gorm.DB, tanamanID uint, contentHash string) *models.LogPenyakitTanaman {
+	var logPenyakit models.LogPenyakitTanaman
+	err := db.Preload("Penyakit").
+		Where("id IN (?)", db.Model(&models.Media{}).Select("owner_id").
+			Where("owner_type = ? AND content_hash = ?", "log-penyakit", contentHash).
+			Where("kebun_id = (?)", db.Model(&models.Tanaman{}).Select("kebun_id").Where("id = ?", tanamanID))).
+		Order("id DESC").First(&logPenyakit).Error
+	if err != nil {
+		return nil
+	}
+	return &logPenyakit
+}
@@ -1,7 +1,6 @@
 package controllers
 
 import (
-	"Avocycle/config"
 	"Avocycle/models"
 	"Avocycle/utils"
 	"fmt"
@@ -46,6 +45,8 @@ type SwaggerLogPenyakitTanaman struct {
 // @Param per_page query int false "Items per page" default(10)
 // @Success 200 {object} utils.Response{data=[]SwaggerLogPenyakitTanaman,meta=utils.Pagination} "Berhasil mengambil data"
 // @Failure 500 {object} utils.Response "Gagal koneksi atau query database"
+// @Security Bearer
+// @Param organization_id query int false "Filter satu organisasi"
 // @Router /Log-Penyakit-Tanaman [get]
 func GetAllLogPenyakit(c *gin.Context) {
 	// get pagination parameters
@@ -53,15 +54,20 @@ func GetAllLogPenyakit(c *gin.Context) {
 	offset := utils.GetOffset(page, perPage)
 
 	// connect to db
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
 	}
 
+	tenant, ok := resolveTenant(c, db)
+	if !ok {
+		return
+	}
+
 	// count total rows
 	var totalRows int64
-	if err := db.Model(&models.LogPenyakitTanaman{}).Count(&totalRows).Error; err != nil {
+	if err := db.Model(&models.LogPenyakitTanaman{}).Scopes(tenant.ByTanaman("tanaman_id")).Count(&totalRows).Error; err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to count log penyakit tanaman data", err.Error())
 		return
 	}
@@ -81,7 +87,7 @@ func GetAllLogPenyakit(c *gin.Context) {
 
 	// get paginated data
 	var logPenyakitList []models.LogPenyakitTanaman
-	if err := db.Preload("Tanaman").
+	if err := db.Scopes(tenant.ByTanaman("tanaman_id")).Preload("Tanaman").
 		Preload("Penyakit").
 		Limit(perPage).
 		Offset(offset).
@@ -111,18 +117,24 @@ func GetAllLogPenyakit(c *gin.Context) {
 // @Success 200 {object} utils.Response{data=SwaggerLogPenyakitTanaman} "Detail ditemukan"
 // @Failure 404 {object} utils.Response "Data tidak ditemukan"
 // @Failure 500 {object} utils.Response "Gagal retrieve data"
+// @Security Bearer
 // @Router /Log-Penyakit-Tanaman/{id} [get]
 func GetLogPenyakitById(c *gin.Context) {
 	id := c.Param("id")
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to connect to DB", err.Error())
 		return
 	}
 
+	tenant, ok := resolveTenant(c, db)
+	if !ok {
+		return
+	}
+
 	var logPenyakitTanaman models.LogPenyakitTanaman
-	if err := db.Preload("Tanaman").Preload("Penyakit").First(&logPenyakitTanaman, id).Error; err != nil {
+	if err := db.Scopes(tenant.ByTanaman("tanaman_id")).Preload("Tanaman").Preload("Penyakit").First(&logPenyakitTanaman, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utils.ErrorResponse(c, http.StatusNotFound, "Log penyakit tanaman tidak ditemukan", nil)
 			return
@@ -146,23 +158,29 @@ func GetLogPenyakitById(c *gin.Context) {
 // @Success 200 {object} utils.Response{data=[]SwaggerLogPenyakitTanaman,meta=utils.Pagination} "Berhasil mengambil data"
 // @Failure 404 {object} utils.Response "Data tidak ditemukan"
 // @Failure 500 {object} utils.Response "Gagal koneksi atau query database"
+// @Security Bearer
 // @Router /Log-Penyakit-Tanaman/Tanaman/{id_tanaman} [get]
 func GetLogPenyakitByTanamanId(c *gin.Context) {
 	idTanaman := c.Param("id_tanaman")
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to connect to DB", err.Error())
 		return
 	}
 
+	tenant, ok := resolveTenant(c, db)
+	if !ok {
+		return
+	}
+
 	// get pagination parameters
 	page, perPage := utils.GetPagination(c)
 	offset := utils.GetOffset(page, perPage)
 
 	// count total rows
 	var totalRows int64
-	if err := db.Model(&models.LogPenyakitTanaman{}).
+	if err := db.Model(&models.LogPenyakitTanaman{}).Scopes(tenant.ByTanaman("tanaman_id")).
 				Where("tanaman_id = ?", idTanaman).
 				Count(&totalRows).
 				Error; err != nil {
@@ -182,7 +200,7 @@ func GetLogPenyakitByTanamanId(c *gin.Context) {
     }
 
 	var logPenyakitTanamanList []models.LogPenyakitTanaman
-	if err := db.Model(&models.LogPenyakitTanaman{}).
+	if err := db.Model(&models.LogPenyakitTanaman{}).Scopes(tenant.ByTanaman("tanaman_id")).
 				Where("tanaman_id = ?", idTanaman).
 				Preload("Tanaman").
 				Preload("Penyakit").
@@ -0,0 +1,329 @@
+package controllers
+
+import (
+	"Avocycle/config"
+	"Avocycle/models"
+	"Avocycle/utils"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	accountMaxAttempts = 5                // gagal berturut-turut sebelum akun dikunci
+	ipMaxAttempts      = 20               // gagal dari satu IP (lintas akun) sebelum IP dikunci
+	loginFailureWindow = 15 * time.Minute // hitungan gagal direset setelah bersih selama ini (dihitung dari akhir lock)
+	lockoutBase        = time.Minute      // lock pertama, lalu dikali 2 tiap gagal berikutnya
+	lockoutMax         = time.Hour
+)
+
+func accountThrottleKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// unknownAccountThrottleKey untuk email yang tidak terdaftar: dipisah per IP supaya
+// satu penyerang tidak bisa mengunci email orang lain yang belum daftar, dan jumlah
+// barisnya ikut dibatasi lock IP (maks ipMaxAttempts email baru per periode lock)
+func unknownAccountThrottleKey(email, ip string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if len(email) > 200 {
+		email = email[:200]
+	}
+	return "account:" + email + "|" + ip
+}
+
+func ipThrottleKey(ip string) string {
+	return "ip:" + ip
+}
+
+// lockoutDuration backoff eksponensial: 1m, 2m, 4m, ... maksimal 1 jam
+func lockoutDuration(failedCount, maxAttempts int) time.Duration {
+	exp := failedCount - maxAttempts
+	if exp < 0 {
+		return 0
+	}
+	d := time.Duration(float64(lockoutBase) * math.Pow(2, float64(exp)))
+	if d > lockoutMax || d <= 0 {
+		return lockoutMax
+	}
+	return d
+}
+
+// checkLoginLock mengembalikan sisa waktu lock jika akun atau IP sedang terkunci
+func checkLoginLock(db *gorm.DB, email, ip string) (time.Duration, bool) {
+	var throttle models.LoginThrottle
+	keys := []string{accountThrottleKey(email), unknownAccountThrottleKey(email, ip), ipThrottleKey(ip)}
+	err := db.Where("key IN ? AND locked_until > ?", keys, time.Now()).
+		Order("locked_until DESC").
+		First(&throttle).Error
+	if err != nil {
+		return 0, false
+	}
+	return time.Until(*throttle.LockedUntil), true
+}
+
+// recordLoginFailure menaikkan hitungan gagal akun & IP secara atomik (aman lintas replika)
+// dan mengunci jika melewati batas. userID nil jika email tidak terdaftar.
+func recordLoginFailure(db *gorm.DB, email, ip string, userID *uint) {
+	accountKey := accountThrottleKey(email)
+	if userID == nil {
+		accountKey = unknownAccountThrottleKey(email, ip)
+	}
+	bumpThrottle(db, accountKey, "account", accountMaxAttempts, func(until time.Time, count int) {
+		db.Create(&models.SecurityEvent{
+			Event:  "account_locked",
+			UserID: userID,
+			Email:  strings.ToLower(strings.TrimSpace(email)),
+			IP:     ip,
+			Detail: fmt.Sprintf("%d login gagal, dikunci sampai %s", count, until.Format(time.RFC3339)),
+		})
+	})
+
+	bumpThrottle(db, ipThrottleKey(ip), "ip", ipMaxAttempts, func(until time.Time, count int) {
+		db.Create(&models.SecurityEvent{
+			Event:  "ip_locked",
+			IP:     ip,
+			Detail: fmt.Sprintf("%d login gagal dari IP ini, dikunci sampai %s", count, until.Format(time.RFC3339)),
+		})
+	})
+}
+
+// bumpThrottle hitungan tidak direset oleh lock itu sendiri: masa bersih baru dihitung
+// setelah lock berakhir, jadi gagal pertama sesudah lock langsung memperpanjang backoff
+func bumpThrottle(db *gorm.DB, key, scope string, maxAttempts int, onLock func(until time.Time, count int)) {
+	now := time.Now()
+
+	row := models.LoginThrottle{Key: key, Scope: scope, FailedCount: 1, LastFailedAt: &now}
+	err := db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"failed_count": gorm.Expr(
+				"CASE WHEN GREATEST(login_throttles.last_failed_at, login_throttles.locked_until) IS NULL "+
+					"OR GREATEST(login_throttles.last_failed_at, login_throttles.locked_until) < ? "+
+					"THEN 1 ELSE login_throttles.failed_count + 1 END",
+				now.Add(-loginFailureWindow),
+			),
+			"last_failed_at": now,
+			"updated_at":     now,
+			"deleted_at":     nil,
+		}),
+	}).Create(&row).Error
+	if err != nil {
+		fmt.Println("Warning: gagal mencatat login gagal:", err)
+		return
+	}
+
+	var current models.LoginThrottle
+	if err := db.Where("key = ?", key).First(&current).Error; err != nil {
+		return
+	}
+
+	if d := lockoutDuration(current.FailedCount, maxAttempts); d > 0 {
+		until := now.Add(d)
+		db.Model(&current).Update("locked_until", &until)
+		onLock(until, current.FailedCount)
+	}
+}
+
+// resetLoginFailures dipanggil setelah login berhasil
+func resetLoginFailures(db *gorm.DB, email string) {
+	db.Model(&models.LoginThrottle{}).
+		Where("key = ?", accountThrottleKey(email)).
+		Updates(map[string]interface{}{"failed_count": 0, "locked_until": nil})
+}
+
+// respondLoginLocked balasan 429 dengan header Retry-After
+func respondLoginLocked(c *gin.Context, remaining time.Duration) {
+	seconds := int(math.Ceil(remaining.Seconds()))
+	c.Header("Retry-After", fmt.Sprint(seconds))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"success":     false,
+		"error":       "Too many failed login attempts, try again later",
+		"retry_after": seconds,
+	})
+}
+
+// GetLoginLocks godoc
+// @Summary Daftar lockout login aktif
+// @Description Akun dan IP yang sedang terkunci karena terlalu banyak login gagal
+// @Tags Admin Security
+// @Security Bearer
+// @Produce json
+// @Param page query int false "Halaman"
+// @Param per_page query int false "Jumlah data per halaman"
+// @Success 200 {object} utils.Response
+// @Router /admin/login-locks [get]
+func GetLoginLocks(c *gin.Context) {
+	page, perPage := utils.GetPagination(c)
+	offset := utils.GetOffset(page, perPage)
+
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	query := db.Model(&models.LoginThrottle{}).Where("locked_until > ?", time.Now())
+
+	var totalRows int64
+	if err := query.Count(&totalRows).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menghitung data lock", err.Error())
+		return
+	}
+
+	pagination := utils.CalculatePagination(page, perPage, totalRows)
+
+	var locks []models.LoginThrottle
+	if err := query.Order("locked_until DESC").Limit(perPage).Offset(offset).Find(&locks).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal mengambil data lock", err.Error())
+		return
+	}
+
+	utils.SuccessResponseWithMeta(c, http.StatusOK, "Data lock login berhasil diambil", locks, pagination)
+}
+
+// UnlockLoginThrottle godoc
+// @Summary Buka lock akun / IP
+// @Description Menghapus lock dan mereset hitungan gagal berdasarkan ID lock
+// @Tags Admin Security
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID lock"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/login-locks/{id}/unlock [post]
+func UnlockLoginThrottle(c *gin.Context) {
+	id := c.Param("id")
+
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	var throttle models.LoginThrottle
+	if err := db.First(&throttle, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "Lock tidak ditemukan", nil)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil data lock", err.Error())
+		return
+	}
+
+	unlockThrottle(c, db, &throttle)
+}
+
+// UnlockUserLogin godoc
+// @Summary Buka lock akun user
+// @Description Mereset lock login akun berdasarkan user ID
+// @Tags Admin Security
+// @Security Bearer
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/users/{id}/unlock [post]
+func UnlockUserLogin(c *gin.Context) {
+	id := c.Param("id")
+
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	var user models.User
+	if err := db.First(&user, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "User tidak ditemukan", nil)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil user", err.Error())
+		return
+	}
+
+	var throttle models.LoginThrottle
+	if err := db.Where("key = ?", accountThrottleKey(user.Email)).First(&throttle).Error; err != nil {
+		utils.SuccessResponse(c, http.StatusOK, "Akun tidak sedang terkunci", utils.EmptyObj{})
+		return
+	}
+
+	unlockThrottle(c, db, &throttle)
+}
+
+func unlockThrottle(c *gin.Context, db *gorm.DB, throttle *models.LoginThrottle) {
+	if err := db.Model(throttle).Updates(map[string]interface{}{"failed_count": 0, "locked_until": nil}).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal membuka lock", err.Error())
+		return
+	}
+
+	event := models.SecurityEvent{Event: throttle.Scope + "_unlocked", Detail: throttle.Key}
+	if claims := currentClaims(c); claims != nil {
+		event.ActorID = &claims.UserID
+	}
+	if throttle.Scope == "account" {
+		event.Email = strings.TrimPrefix(throttle.Key, "account:")
+		if email, ip, ok := strings.Cut(event.Email, "|"); ok {
+			event.Email, event.IP = email, ip
+		}
+		var user models.User
+		if db.Where("email = ?", event.Email).First(&user).Error == nil {
+			event.UserID = &user.ID
+		}
+	} else {
+		event.IP = strings.TrimPrefix(throttle.Key, "ip:")
+	}
+	db.Create(&event)
+
+	utils.SuccessResponse(c, http.StatusOK, "Lock berhasil dibuka", throttle)
+}
+
+// GetSecurityEvents godoc
+// @Summary Riwayat kejadian keamanan
+// @Description Daftar lockout / unlock terbaru, bisa difilter event
+// @Tags Admin Security
+// @Security Bearer
+// @Produce json
+// @Param event query string false "account_locked / ip_locked / account_unlocked / ip_unlocked"
+// @Param page query int false "Halaman"
+// @Param per_page query int false "Jumlah data per halaman"
+// @Success 200 {object} utils.Response
+// @Router /admin/security-events [get]
+func GetSecurityEvents(c *gin.Context) {
+	page, perPage := utils.GetPagination(c)
+	offset := utils.GetOffset(page, perPage)
+
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	query := db.Model(&models.SecurityEvent{})
+	if event := c.Query("event"); event != "" {
+		query = query.Where("event = ?", event)
+	}
+
+	var totalRows int64
+	if err := query.Count(&totalRows).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menghitung security event", err.Error())
+		return
+	}
+
+	pagination := utils.CalculatePagination(page, perPage, totalRows)
+
+	var events []models.SecurityEvent
+	if err := query.Order("created_at DESC").Limit(perPage).Offset(offset).Find(&events).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal mengambil security event", err.Error())
+		return
+	}
+
+	utils.SuccessResponseWithMeta(c, http.StatusOK, "Security event berhasil diambil", events, pagination)
+}
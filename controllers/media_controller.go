@@ -0,0 +1,504 @@
+package controllers
+
+import (
+	"Avocycle/config"
+	"Avocycle/models"
+	"Avocycle/utils"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// batas jumlah foto per sekali upload
+const maxMediaPerUpload = 10
+
+// UpdateMediaRequest body ubah keterangan foto. Field null tidak diubah.
+type UpdateMediaRequest struct {
+	Caption *string `json:"caption" example:"Daun menguning di sisi timur"`
+	TakenAt *string `json:"taken_at" example:"2025-01-31T08:30:00+07:00"` // RFC3339 atau YYYY-MM-DD
+}
+
+// ReorderMediaRequest urutan baru galeri, foto pertama menjadi foto utama
+type ReorderMediaRequest struct {
+	IDs []uint `json:"ids" binding:"required,min=1"`
+}
+
+// mediaOwnerAction aksi kebun yang dibutuhkan untuk mengubah galeri jenis record ini
+func mediaOwnerAction(ownerType string) config.KebunAction {
+	if ownerType == "kebun" {
+		return config.KebunActUpdate
+	}
+	// pekerja lapangan boleh mendokumentasikan pohon dan catatannya
+	return config.KebunActFaseWrite
+}
+
+func currentUserID(c *gin.Context) *uint {
+	if claims := currentClaims(c); claims != nil {
+		id := claims.UserID
+		return &id
+	}
+	return nil
+}
+
+// mediaOwnerFromParam baca :owner_type / :owner_id lalu cek akses ke kebun pemiliknya
+func mediaOwnerFromParam(c *gin.Context, db *gorm.DB, action config.KebunAction) (string, uint, bool) {
+	ownerType := c.Param("owner_type")
+	ownerID, err := strconv.ParseUint(c.Param("owner_id"), 10, 64)
+	if err != nil || ownerID == 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "owner_id tidak valid", c.Param("owner_id"))
+		return "", 0, false
+	}
+	if !authorizeMediaOwner(c, db, ownerType, uint(ownerID), action) {
+		return "", 0, false
+	}
+	return ownerType, uint(ownerID), true
+}
+
+func authorizeMediaOwner(c *gin.Context, db *gorm.DB, ownerType string, ownerID uint, action config.KebunAction) bool {
+	kebunID, err := config.MediaOwnerKebunID(db, ownerType, ownerID)
+	if err != nil {
+		switch err {
+		case config.ErrMediaOwnerType:
+			utils.ErrorResponse(c, http.StatusBadRequest, "owner_type harus salah satu: kebun, tanaman, fase-bunga, fase-berbuah, fase-panen, log-penyakit, buah", ownerType)
+		case gorm.ErrRecordNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound, "Record pemilik foto tidak ditemukan", gin.H{"owner_type": ownerType, "owner_id": ownerID})
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal cek pemilik foto", err.Error())
+		}
+		return false
+	}
+	return authorizeKebun(c, db, kebunID, action)
+}
+
+func mediaFromParam(c *gin.Context, db *gorm.DB) (*models.Media, bool) {
+	var media models.Media
+	if err := db.First(&media, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "Foto tidak ditemukan", nil)
+			return nil, false
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil foto", err.Error())
+		return nil, false
+	}
+	if !authorizeMediaOwner(c, db, media.OwnerType, media.OwnerID, mediaOwnerAction(media.OwnerType)) {
+		return nil, false
+	}
+	return &media, true
+}
+
+// parseTakenAt waktu foto diambil: RFC3339 atau YYYY-MM-DD, tidak boleh di masa depan
+func parseTakenAt(raw string) (*time.Time, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		if t, err = time.Parse("2006-01-02", raw); err != nil {
+			return nil, fmt.Errorf("format harus RFC3339 atau YYYY-MM-DD")
+		}
+	}
+	if t.After(time.Now().Add(time.Minute)) {
+		return nil, fmt.Errorf("waktu foto tidak boleh di masa depan")
+	}
+	return &t, nil
+}
+
+// fotoJarakMaksKm jarak GPS foto dari titik kebun sebelum diberi peringatan (kebun tanpa batas polygon)
+const fotoJarakMaksKm = 1.0
+
+// kebunLokasi kebun untuk cek lokasi foto, nil jika gagal dimuat
+func kebunLokasi(db *gorm.DB, kebunID uint) *models.Kebun {
+	var kebun models.Kebun
+	if err := db.Select("id", "nama_kebun", "latitude", "longitude", "batas").First(&kebun, kebunID).Error; err != nil {
+		return nil
+	}
+	return &kebun
+}
+
+// tanamanKebunLokasi kebunLokasi dari kebun tempat tanaman berada
+func tanamanKebunLokasi(db *gorm.DB, tanamanID uint) *models.Kebun {
+	var kebunID uint
+	if err := db.Model(&models.Tanaman{}).Select("kebun_id").Where("id = ?", tanamanID).Scan(&kebunID).Error; err != nil || kebunID == 0 {
+		return nil
+	}
+	return kebunLokasi(db, kebunID)
+}
+
+// fotoWarnings peringatan untuk foto yang baru masuk galeri: lokasi jauh dari kebun dan foto yang sama
+// sudah dipakai tanaman lain. Foto tetap tersimpan. nil jika media nil (tidak ada upload).
+func fotoWarnings(db *gorm.DB, kebun *models.Kebun, label string, media *models.Media) []string {
+	if media == nil {
+		return nil
+	}
+	var warnings []string
+	if w := fotoLokasiWarning(kebun, label, media.Latitude, media.Longitude); w != "" {
+		warnings = append(warnings, w)
+	}
+	if w := fotoDuplikatWarning(db, label, media); w != "" {
+		warnings = append(warnings, w)
+	}
+	return warnings
+}
+
+// fotoDuplikatWarning peringatan jika media ditandai duplikat foto tanaman lain. Hanya foto di kebun yang sama
+// yang disebut, kode tanaman kebun lain tidak boleh terlihat.
+func fotoDuplikatWarning(db *gorm.DB, label string, media *models.Media) string {
+	if media.DuplikatDariID == nil || media.KebunID == nil {
+		return ""
+	}
+	var dup models.Media
+	if err := db.Where("kebun_id = ?", *media.KebunID).First(&dup, *media.DuplikatDariID).Error; err != nil {
+		return ""
+	}
+	milik := dup.OwnerType + " #" + strconv.FormatUint(uint64(dup.OwnerID), 10)
+	if tanamanID, ok := config.MediaOwnerTanamanID(db, dup.OwnerType, dup.OwnerID); ok {
+		var kode string
+		db.Unscoped().Model(&models.Tanaman{}).Select("kode_tanaman").Where("id = ?", tanamanID).Scan(&kode)
+		milik = "tanaman " + kode
+	}
+	return fmt.Sprintf("%s sangat mirip dengan foto #%d milik %s, pastikan bukan foto yang sama", label, dup.ID, milik)
+}
+
+// fotoLokasiWarning peringatan jika GPS foto di luar batas polygon kebun (berapa pun jaraknya), atau untuk kebun
+// tanpa batas lebih dari fotoJarakMaksKm dari titik kebun. Kosong jika foto tanpa GPS atau kebun belum punya lokasi.
+func fotoLokasiWarning(kebun *models.Kebun, label string, latitude, longitude *float64) string {
+	if kebun == nil || latitude == nil || longitude == nil {
+		return ""
+	}
+	lat, lng := *latitude, *longitude
+
+	if len(kebun.Batas) > 0 {
+		if poly, err := utils.ParseGeoJSONPolygon(kebun.Batas); err == nil {
+			if poly.Contains(lat, lng) {
+				return ""
+			}
+			refLat, refLng := poly.Centroid()
+			if kebun.Latitude != nil && kebun.Longitude != nil {
+				refLat, refLng = *kebun.Latitude, *kebun.Longitude
+			}
+			jarak := utils.HaversineKm(lat, lng, refLat, refLng)
+			return fmt.Sprintf("Lokasi %s (%.6f, %.6f) di luar batas kebun %s (%.1f km dari kebun)", label, lat, lng, kebun.NamaKebun, jarak)
+		}
+	}
+	if kebun.Latitude == nil || kebun.Longitude == nil {
+		return ""
+	}
+
+	jarak := utils.HaversineKm(lat, lng, *kebun.Latitude, *kebun.Longitude)
+	if jarak <= fotoJarakMaksKm {
+		return ""
+	}
+	return fmt.Sprintf("Lokasi %s (%.6f, %.6f) berjarak %.1f km dari kebun %s", label, lat, lng, jarak, kebun.NamaKebun)
+}
+
+// GetGaleri godoc
+// @Summary Galeri foto record
+// @Description Semua foto milik satu record, urut sesuai urutan galeri. Foto pertama = foto utama.
+// @Tags Galeri
+// @Security Bearer
+// @Produce json
+// @Param owner_type path string true "kebun, tanaman, fase-bunga, fase-berbuah, fase-panen, log-penyakit, buah"
+// @Param owner_id path int true "ID record"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /galeri/{owner_type}/{owner_id} [get]
+func GetGaleri(c *gin.Context) {
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	ownerType, ownerID, ok := mediaOwnerFromParam(c, db, config.KebunActView)
+	if !ok {
+		return
+	}
+
+	var media []models.Media
+	if err := db.Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).
+		Order("urutan ASC, id ASC").Find(&media).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil galeri", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Galeri foto", media)
+}
+
+// UploadGaleri godoc
+// @Summary Tambah foto ke galeri
+// @Description Upload satu atau beberapa foto (field foto diulang, maks 10). Foto ditambahkan di akhir galeri.
+// @Tags Galeri
+// @Security Bearer
+// @Accept multipart/form-data
+// @Produce json
+// @Param owner_type path string true "kebun, tanaman, fase-bunga, fase-berbuah, fase-panen, log-penyakit, buah"
+// @Param owner_id path int true "ID record"
+// @Param foto formData file true "Foto (boleh lebih dari satu)"
+// @Param caption formData string false "Keterangan, dipakai untuk semua foto yang diupload"
+// @Param taken_at formData string false "Waktu foto diambil (RFC3339 atau YYYY-MM-DD), default EXIF foto lalu waktu upload"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /galeri/{owner_type}/{owner_id} [post]
+func UploadGaleri(c *gin.Context) {
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	ownerType, ownerID, ok := mediaOwnerFromParam(c, db, mediaOwnerAction(c.Param("owner_type")))
+	if !ok {
+		return
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["foto"]) == 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "foto wajib diisi", nil)
+		return
+	}
+	files := form.File["foto"]
+	if len(files) > maxMediaPerUpload {
+		utils.ErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("maksimal %d foto per upload", maxMediaPerUpload), len(files))
+		return
+	}
+
+	takenAt, err := parseTakenAt(strings.TrimSpace(c.PostForm("taken_at")))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "taken_at tidak valid", err.Error())
+		return
+	}
+	caption := strings.TrimSpace(c.PostForm("caption"))
+
+	var last struct{ Urutan *int }
+	if err := db.Model(&models.Media{}).Select("MAX(urutan) AS urutan").
+		Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).Scan(&last).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil urutan galeri", err.Error())
+		return
+	}
+	urutan := 0
+	if last.Urutan != nil {
+		urutan = *last.Urutan + 1
+	}
+
+	var kebun *models.Kebun
+	if kebunID, err := config.MediaOwnerKebunID(db, ownerType, ownerID); err == nil {
+		kebun = kebunLokasi(db, kebunID)
+	}
+
+	uploadedBy := currentUserID(c)
+	created := make([]models.Media, 0, len(files))
+	var warnings []string
+	for i, file := range files {
+		uploaded, err := utils.UploadImage(file, "galeri/"+ownerType)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Upload foto gagal", gin.H{"file": utils.SanitizeFilename(file.Filename), "error": err.Error(), "berhasil": created})
+			return
+		}
+		media, err := config.NewMediaFromUpload(db, ownerType, ownerID, uploaded)
+		if err == nil {
+			media.Caption = caption
+			media.Urutan = urutan + i
+			media.UploadedBy = uploadedBy
+			if takenAt != nil {
+				media.TakenAt = takenAt
+			}
+			err = db.Create(media).Error
+		}
+		if err != nil {
+			utils.DeleteUploadedImage(uploaded)
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menyimpan foto", err.Error())
+			return
+		}
+		warnings = append(warnings, fotoWarnings(db, kebun, "foto "+utils.SanitizeFilename(file.Filename), media)...)
+		created = append(created, *media)
+	}
+
+	// galeri yang tadinya kosong: foto pertama menjadi foto utama
+	if urutan == 0 {
+		if err := config.SyncMediaCover(db, ownerType, ownerID); err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal memperbarui foto utama", err.Error())
+			return
+		}
+	}
+
+	utils.SuccessResponseWithWarnings(c, http.StatusCreated, fmt.Sprintf("%d foto ditambahkan", len(created)), created, warnings)
+}
+
+// ReorderGaleri godoc
+// @Summary Atur urutan galeri
+// @Description ids berisi semua foto galeri dengan urutan baru. Foto pertama menjadi foto utama record.
+// @Tags Galeri
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param owner_type path string true "kebun, tanaman, fase-bunga, fase-berbuah, fase-panen, log-penyakit, buah"
+// @Param owner_id path int true "ID record"
+// @Param body body ReorderMediaRequest true "Urutan id foto"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /galeri/{owner_type}/{owner_id}/urutan [put]
+func ReorderGaleri(c *gin.Context) {
+	var req ReorderMediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
+		return
+	}
+
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	ownerType, ownerID, ok := mediaOwnerFromParam(c, db, mediaOwnerAction(c.Param("owner_type")))
+	if !ok {
+		return
+	}
+
+	var existing []uint
+	if err := db.Model(&models.Media{}).Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).
+		Pluck("id", &existing).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil galeri", err.Error())
+		return
+	}
+	inGaleri := make(map[uint]bool, len(existing))
+	for _, id := range existing {
+		inGaleri[id] = true
+	}
+	seen := map[uint]bool{}
+	for _, id := range req.IDs {
+		if !inGaleri[id] || seen[id] {
+			utils.ErrorResponse(c, http.StatusBadRequest, "ids harus berisi foto galeri ini, masing-masing sekali", id)
+			return
+		}
+		seen[id] = true
+	}
+	if len(seen) != len(existing) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ids harus memuat semua foto galeri", gin.H{"jumlah_foto": len(existing)})
+		return
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for i, id := range req.IDs {
+			if err := tx.Model(&models.Media{}).Where("id = ?", id).Update("urutan", i).Error; err != nil {
+				return err
+			}
+		}
+		return config.SyncMediaCover(tx, ownerType, ownerID)
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menyimpan urutan", err.Error())
+		return
+	}
+
+	var media []models.Media
+	db.Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).Order("urutan ASC, id ASC").Find(&media)
+	utils.SuccessResponse(c, http.StatusOK, "Urutan galeri diperbarui", media)
+}
+
+// UpdateMedia godoc
+// @Summary Ubah keterangan foto
+// @Tags Galeri
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "ID Foto"
+// @Param body body UpdateMediaRequest true "Keterangan foto"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /media/{id} [put]
+func UpdateMedia(c *gin.Context) {
+	var req UpdateMediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
+		return
+	}
+
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	media, ok := mediaFromParam(c, db)
+	if !ok {
+		return
+	}
+
+	if req.Caption != nil {
+		media.Caption = strings.TrimSpace(*req.Caption)
+	}
+	if req.TakenAt != nil {
+		takenAt, err := parseTakenAt(strings.TrimSpace(*req.TakenAt))
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "taken_at tidak valid", err.Error())
+			return
+		}
+		media.TakenAt = takenAt
+	}
+
+	if err := db.Save(media).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menyimpan foto", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Foto berhasil diperbarui", media)
+}
+
+// DeleteMedia godoc
+// @Summary Hapus satu foto
+// @Description Menghapus foto dari galeri dan Cloudinary. Jika foto utama yang dihapus, foto berikutnya menjadi foto utama.
+// @Tags Galeri
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Foto"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /media/{id} [delete]
+func DeleteMedia(c *gin.Context) {
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	media, ok := mediaFromParam(c, db)
+	if !ok {
+		return
+	}
+
+	// aset dihapus dulu; kalau gagal, record tetap ada supaya bisa dicoba lagi
+	for _, publicID := range []string{media.PublicID, media.ThumbPublicID} {
+		if err := utils.DeleteCloudinaryAsset(publicID); err != nil {
+			utils.ErrorResponse(c, http.StatusBadGateway, "Gagal hapus foto di Cloudinary", err.Error())
+			return
+		}
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Delete(media).Error; err != nil {
+			return err
+		}
+		return config.SyncMediaCover(tx, media.OwnerType, media.OwnerID)
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal hapus foto", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Foto berhasil dihapus", utils.EmptyObj{})
+}
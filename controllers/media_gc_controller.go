@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"Avocycle/config"
+	"Avocycle/utils"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetOrphanMedia godoc
+// @Summary Laporan foto tanpa referensi
+// @Description Aset gambar di Cloudinary (di bawah folder CLOUDINARY_FOLDER) yang tidak dirujuk galeri maupun kolom foto record mana pun.
+// @Description siap_dihapus = sudah melewati masa tenggang MEDIA_GC_GRACE_HOURS. Tidak menghapus apa pun.
+// @Tags Admin Security
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=config.MediaGCReport}
+// @Failure 502 {object} utils.Response
+// @Router /admin/media/orphan [get]
+func GetOrphanMedia(c *gin.Context) {
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+	report, err := config.ReconcileMedia(db, false)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadGateway, "Gagal rekonsiliasi media", err.Error())
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, fmt.Sprintf("%d aset tanpa referensi", len(report.Orphan)), report)
+}
+
+// PurgeOrphanMedia godoc
+// @Summary Hapus foto tanpa referensi
+// @Description Jalankan garbage collector sekarang: hapus aset tanpa referensi yang sudah melewati masa tenggang.
+// @Description Aset yang lebih baru tetap dilaporkan tapi tidak dihapus.
+// @Tags Admin Security
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=config.MediaGCReport}
+// @Failure 502 {object} utils.Response
+// @Router /admin/media/orphan/purge [post]
+func PurgeOrphanMedia(c *gin.Context) {
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+	report, err := config.ReconcileMedia(db, true)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadGateway, "Gagal rekonsiliasi media", err.Error())
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, fmt.Sprintf("%d aset tanpa referensi dihapus", report.Dihapus), report)
+}
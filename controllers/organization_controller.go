@@ -0,0 +1,490 @@
+package controllers
+
+import (
+	"Avocycle/config"
+	"Avocycle/models"
+	"Avocycle/utils"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateOrganizationRequest body organisasi baru / update nama
+type CreateOrganizationRequest struct {
+	Name string `json:"name" example:"Koperasi Alpukat Sumberjaya"`
+}
+
+// AddOrganizationMemberRequest body tambah anggota organisasi
+type AddOrganizationMemberRequest struct {
+	Email string `json:"email" example:"petani@example.com"`
+	Role  string `json:"role" example:"member"`
+}
+
+// AssignKebunOrganizationRequest body pindah kebun ke organisasi (null = lepas)
+type AssignKebunOrganizationRequest struct {
+	OrganizationID *uint `json:"organization_id" example:"1"`
+}
+
+// organizationFromParam ambil organisasi dari path :id dan pastikan user minimal berperan minRole.
+// Admin platform selalu boleh. Mengembalikan false jika response error sudah dikirim.
+func organizationFromParam(c *gin.Context, db *gorm.DB, minRole string) (*models.Organization, bool) {
+	claims := currentClaims(c)
+
+	var org models.Organization
+	if err := db.First(&org, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "Organisasi tidak ditemukan", nil)
+			return nil, false
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil organisasi", err.Error())
+		return nil, false
+	}
+
+	if claims.Role == "Admin" {
+		return &org, true
+	}
+
+	role := organizationRoleOf(db, org.ID, claims.UserID)
+	if role == "" {
+		// jangan bocorkan keberadaan organisasi lain
+		utils.ErrorResponse(c, http.StatusNotFound, "Organisasi tidak ditemukan", nil)
+		return nil, false
+	}
+	if minRole == config.OrgRoleAdmin && role != config.OrgRoleAdmin {
+		utils.ErrorResponse(c, http.StatusForbidden, "Hanya admin organisasi yang boleh melakukan aksi ini", nil)
+		return nil, false
+	}
+
+	return &org, true
+}
+
+func countOrganizationAdmins(db *gorm.DB, orgID uint) int64 {
+	var admins int64
+	db.Model(&models.OrganizationMember{}).
+		Where("organization_id = ? AND role = ?", orgID, config.OrgRoleAdmin).
+		Count(&admins)
+	return admins
+}
+
+func validOrganizationRole(role string) bool {
+	return role == config.OrgRoleAdmin || role == config.OrgRoleMember
+}
+
+// CreateOrganization godoc
+// @Summary Buat organisasi / koperasi
+// @Description Pembuat otomatis menjadi admin organisasi
+// @Tags Organization
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body controllers.CreateOrganizationRequest true "Organisasi"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /organizations [post]
+func CreateOrganization(c *gin.Context) {
+	claims := currentClaims(c)
+
+	var input struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
+		return
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "name tidak boleh kosong", nil)
+		return
+	}
+
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	org := models.Organization{Name: name}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&org).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.OrganizationMember{OrganizationID: org.ID, UserID: claims.UserID, Role: config.OrgRoleAdmin}).Error
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal membuat organisasi", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Organisasi berhasil dibuat", org)
+}
+
+// GetMyOrganizations godoc
+// @Summary Daftar organisasi
+// @Description Organisasi tempat user menjadi anggota (Admin: semua organisasi)
+// @Tags Organization
+// @Security Bearer
+// @Produce json
+// @Param page query int false "Halaman"
+// @Param per_page query int false "Jumlah data per halaman"
+// @Success 200 {object} utils.Response
+// @Router /organizations [get]
+func GetMyOrganizations(c *gin.Context) {
+	claims := currentClaims(c)
+	page, perPage := utils.GetPagination(c)
+	offset := utils.GetOffset(page, perPage)
+
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	query := db.Model(&models.Organization{})
+	if claims.Role != "Admin" {
+		query = query.Where("id IN (?)", db.Model(&models.OrganizationMember{}).Select("organization_id").Where("user_id = ?", claims.UserID))
+	}
+
+	var totalRows int64
+	if err := query.Count(&totalRows).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menghitung organisasi", err.Error())
+		return
+	}
+
+	pagination := utils.CalculatePagination(page, perPage, totalRows)
+
+	var orgs []models.Organization
+	if err := query.Order("name ASC").Limit(perPage).Offset(offset).Find(&orgs).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal mengambil organisasi", err.Error())
+		return
+	}
+
+	utils.SuccessResponseWithMeta(c, http.StatusOK, "Data organisasi berhasil diambil", orgs, pagination)
+}
+
+// GetOrganizationByID godoc
+// @Summary Detail organisasi
+// @Description Detail organisasi beserta jumlah anggota dan kebun
+// @Tags Organization
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Organisasi"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /organizations/{id} [get]
+func GetOrganizationByID(c *gin.Context) {
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	org, ok := organizationFromParam(c, db, config.OrgRoleMember)
+	if !ok {
+		return
+	}
+
+	var memberCount, kebunCount int64
+	db.Model(&models.OrganizationMember{}).Where("organization_id = ?", org.ID).Count(&memberCount)
+	db.Model(&models.Kebun{}).Where("organization_id = ?", org.ID).Count(&kebunCount)
+
+	utils.SuccessResponse(c, http.StatusOK, "Detail organisasi", gin.H{
+		"organization": org,
+		"member_count": memberCount,
+		"kebun_count":  kebunCount,
+		"your_role":    organizationRoleOf(db, org.ID, currentClaims(c).UserID),
+	})
+}
+
+// UpdateOrganization godoc
+// @Summary Ubah nama organisasi
+// @Tags Organization
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "ID Organisasi"
+// @Param request body controllers.CreateOrganizationRequest true "Organisasi"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /organizations/{id} [put]
+func UpdateOrganization(c *gin.Context) {
+	var input struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
+		return
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "name tidak boleh kosong", nil)
+		return
+	}
+
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	org, ok := organizationFromParam(c, db, config.OrgRoleAdmin)
+	if !ok {
+		return
+	}
+
+	org.Name = name
+	if err := db.Save(org).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal update organisasi", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Organisasi berhasil diperbarui", org)
+}
+
+// GetOrganizationMembers godoc
+// @Summary Anggota organisasi
+// @Tags Organization
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Organisasi"
+// @Success 200 {object} utils.Response
+// @Router /organizations/{id}/members [get]
+func GetOrganizationMembers(c *gin.Context) {
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	org, ok := organizationFromParam(c, db, config.OrgRoleMember)
+	if !ok {
+		return
+	}
+
+	var members []models.OrganizationMember
+	if err := db.Preload("User").Where("organization_id = ?", org.ID).Order("id ASC").Find(&members).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal mengambil anggota organisasi", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Anggota organisasi berhasil diambil", members)
+}
+
+// AddOrganizationMember godoc
+// @Summary Tambah anggota organisasi
+// @Description Menambahkan user terdaftar (berdasarkan email) ke organisasi sebagai admin / member
+// @Tags Organization
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "ID Organisasi"
+// @Param request body controllers.AddOrganizationMemberRequest true "Anggota"
+// @Success 201 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /organizations/{id}/members [post]
+func AddOrganizationMember(c *gin.Context) {
+	var input struct {
+		Email string `json:"email" binding:"required,email"`
+		Role  string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
+		return
+	}
+	if !validOrganizationRole(input.Role) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "role tidak valid (admin, member)", input.Role)
+		return
+	}
+
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	org, ok := organizationFromParam(c, db, config.OrgRoleAdmin)
+	if !ok {
+		return
+	}
+
+	var user models.User
+	if err := db.Where("LOWER(email) = ?", strings.ToLower(strings.TrimSpace(input.Email))).First(&user).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "User dengan email tersebut belum terdaftar", input.Email)
+		return
+	}
+
+	if organizationRoleOf(db, org.ID, user.ID) != "" {
+		utils.ErrorResponse(c, http.StatusConflict, "User sudah menjadi anggota organisasi", nil)
+		return
+	}
+
+	member := models.OrganizationMember{OrganizationID: org.ID, UserID: user.ID, Role: input.Role}
+	if err := db.Create(&member).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menambah anggota organisasi", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Anggota organisasi berhasil ditambahkan", member)
+}
+
+// UpdateOrganizationMember godoc
+// @Summary Ubah peran anggota organisasi
+// @Tags Organization
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "ID Organisasi"
+// @Param user_id path int true "User ID"
+// @Param request body controllers.UpdateKebunMemberRequest true "Peran baru (admin / member)"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /organizations/{id}/members/{user_id} [put]
+func UpdateOrganizationMember(c *gin.Context) {
+	var input struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
+		return
+	}
+	if !validOrganizationRole(input.Role) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "role tidak valid (admin, member)", input.Role)
+		return
+	}
+
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	org, ok := organizationFromParam(c, db, config.OrgRoleAdmin)
+	if !ok {
+		return
+	}
+
+	var member models.OrganizationMember
+	if err := db.Where("organization_id = ? AND user_id = ?", org.ID, c.Param("user_id")).First(&member).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Anggota tidak ditemukan", nil)
+		return
+	}
+
+	if member.Role == config.OrgRoleAdmin && input.Role != config.OrgRoleAdmin && countOrganizationAdmins(db, org.ID) <= 1 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Organisasi harus punya minimal satu admin", nil)
+		return
+	}
+
+	if err := db.Model(&member).Update("role", input.Role).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal update peran anggota", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Peran anggota berhasil diperbarui", member)
+}
+
+// RemoveOrganizationMember godoc
+// @Summary Keluarkan anggota organisasi
+// @Description Admin organisasi bisa mengeluarkan anggota; anggota juga bisa keluar sendiri
+// @Tags Organization
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Organisasi"
+// @Param user_id path int true "User ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /organizations/{id}/members/{user_id} [delete]
+func RemoveOrganizationMember(c *gin.Context) {
+	claims := currentClaims(c)
+
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	org, ok := organizationFromParam(c, db, config.OrgRoleMember)
+	if !ok {
+		return
+	}
+
+	var member models.OrganizationMember
+	if err := db.Where("organization_id = ? AND user_id = ?", org.ID, c.Param("user_id")).First(&member).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Anggota tidak ditemukan", nil)
+		return
+	}
+
+	if member.UserID != claims.UserID && claims.Role != "Admin" && organizationRoleOf(db, org.ID, claims.UserID) != config.OrgRoleAdmin {
+		utils.ErrorResponse(c, http.StatusForbidden, "Hanya admin organisasi yang boleh mengeluarkan anggota", nil)
+		return
+	}
+
+	if member.Role == config.OrgRoleAdmin && countOrganizationAdmins(db, org.ID) <= 1 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Organisasi harus punya minimal satu admin", nil)
+		return
+	}
+
+	if err := db.Unscoped().Delete(&member).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal mengeluarkan anggota", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Anggota berhasil dikeluarkan", utils.EmptyObj{})
+}
+
+// AssignKebunOrganization godoc
+// @Summary Pindahkan kebun ke organisasi
+// @Description Hanya owner kebun yang juga admin organisasi tujuan. organization_id null melepas kebun dari organisasi.
+// @Tags Organization
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "ID Kebun"
+// @Param request body controllers.AssignKebunOrganizationRequest true "Organisasi tujuan"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /kebun/{id}/organization [put]
+func AssignKebunOrganization(c *gin.Context) {
+	claims := currentClaims(c)
+
+	var input AssignKebunOrganizationRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
+		return
+	}
+
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	kebun, ok := kebunFromParam(c, db)
+	if !ok || !authorizeKebun(c, db, kebun.ID, config.KebunActTransfer) {
+		return
+	}
+
+	if input.OrganizationID != nil {
+		var org models.Organization
+		if err := db.First(&org, *input.OrganizationID).Error; err != nil {
+			utils.ErrorResponse(c, http.StatusNotFound, "Organisasi tidak ditemukan", nil)
+			return
+		}
+	}
+
+	if input.OrganizationID != nil && claims.Role != "Admin" &&
+		organizationRoleOf(db, *input.OrganizationID, claims.UserID) != config.OrgRoleAdmin {
+		utils.ErrorResponse(c, http.StatusForbidden, "Anda harus admin di organisasi tujuan", gin.H{"organization_id": *input.OrganizationID})
+		return
+	}
+
+	if err := db.Model(kebun).Update("organization_id", input.OrganizationID).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal memindahkan kebun", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Organisasi kebun berhasil diperbarui", kebun)
+}
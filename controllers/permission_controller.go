@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"Avocycle/config"
+	"Avocycle/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetMyPermissions godoc
+// @Summary Permission efektif user login
+// @Description Daftar permission (contoh: kebun:write, penyakit:classify) milik role user yang sedang login,
+// @Description dipakai frontend untuk menampilkan / menyembunyikan menu.
+// @Tags Auth
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} map[string]interface{}
+// @Router /me/permissions [get]
+func GetMyPermissions(c *gin.Context) {
+	claims := currentClaims(c)
+
+	utils.SuccessResponse(c, http.StatusOK, "Permission user", gin.H{
+		"user_id":     claims.UserID,
+		"role":        claims.Role,
+		"permissions": config.PermissionsForRole(claims.Role),
+	})
+}
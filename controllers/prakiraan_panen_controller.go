@@ -0,0 +1,257 @@
+package controllers
+
+import (
+	"Avocycle/config"
+	"Avocycle/models"
+	"Avocycle/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// queryRange angka dari query string dengan default & batas, false jika response error sudah dikirim
+func queryRange(c *gin.Context, name string, def, max int) (int, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return def, true
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 || n > max {
+		utils.ErrorResponse(c, http.StatusBadRequest, name+" harus angka 1-"+strconv.Itoa(max), raw)
+		return 0, false
+	}
+	return n, true
+}
+
+// prakiraanScopeFromParam cakupan prakiraan dari :id sesuai level, sekaligus cek akses lihat kebun
+func prakiraanScopeFromParam(c *gin.Context, db *gorm.DB, level string) (config.PrakiraanScope, string, bool) {
+	switch level {
+	case "tanaman":
+		var tanaman models.Tanaman
+		if err := db.Select("id", "kebun_id", "kode_tanaman").First(&tanaman, c.Param("id")).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				utils.ErrorResponse(c, http.StatusNotFound, "Tanaman tidak ditemukan", nil)
+				return config.PrakiraanScope{}, "", false
+			}
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil tanaman", err.Error())
+			return config.PrakiraanScope{}, "", false
+		}
+		if !authorizeKebun(c, db, tanaman.KebunID, config.KebunActView) {
+			return config.PrakiraanScope{}, "", false
+		}
+		return config.PrakiraanScope{KebunID: tanaman.KebunID, TanamanID: &tanaman.ID}, "tanaman " + tanaman.KodeTanaman, true
+	case "blok":
+		blok, ok := blokFromParam(c, db, config.KebunActView)
+		if !ok {
+			return config.PrakiraanScope{}, "", false
+		}
+		return config.PrakiraanScope{KebunID: blok.KebunID, BlokID: &blok.ID}, "blok " + blok.Kode, true
+	default:
+		kebun, ok := kebunFromParam(c, db)
+		if !ok || !authorizeKebun(c, db, kebun.ID, config.KebunActView) {
+			return config.PrakiraanScope{}, "", false
+		}
+		return config.PrakiraanScope{KebunID: kebun.ID}, "kebun " + kebun.NamaKebun, true
+	}
+}
+
+func respondPrakiraanPanen(c *gin.Context, level string) {
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+	minggu, ok := queryRange(c, "minggu", config.PrakiraanMingguDefault, config.PrakiraanMingguMaks)
+	if !ok {
+		return
+	}
+	scope, label, ok := prakiraanScopeFromParam(c, db, level)
+	if !ok {
+		return
+	}
+
+	prakiraan, err := config.HitungPrakiraanPanen(db, scope, minggu)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menghitung prakiraan panen", err.Error())
+		return
+	}
+
+	// rincian satu tingkat di bawah cakupan: pohon untuk blok, blok untuk kebun
+	switch level {
+	case "tanaman":
+		prakiraan.Tanaman = nil
+	case "kebun":
+		prakiraan.RingkasPerBlok()
+		prakiraan.Tanaman = nil
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Prakiraan panen "+label, prakiraan)
+}
+
+func respondBacktestPanen(c *gin.Context, level string) {
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+	minggu, ok := queryRange(c, "minggu", config.PrakiraanMingguDefault, config.PrakiraanMingguMaks)
+	if !ok {
+		return
+	}
+	titik, ok := queryRange(c, "titik", config.BacktestTitikDefault, config.BacktestTitikMaks)
+	if !ok {
+		return
+	}
+	scope, label, ok := prakiraanScopeFromParam(c, db, level)
+	if !ok {
+		return
+	}
+
+	backtest, err := config.BacktestPrakiraanPanen(db, scope, minggu, titik)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menjalankan backtest prakiraan", err.Error())
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "Backtest prakiraan panen "+label, backtest)
+}
+
+// GetTanamanPrakiraanPanen godoc
+// @Summary Prakiraan panen tanaman
+// @Description Perkiraan jumlah buah dan berat (kg) per minggu dari cover yang belum dipanen, bunga / pentil
+// @Description terakhir yang belum di-cover, dan berat buah panen sebelumnya
+// @Tags Prakiraan Panen
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Tanaman"
+// @Param minggu query int false "Jumlah minggu ke depan (default 12, maks 26)"
+// @Success 200 {object} utils.Response{data=config.PrakiraanPanen}
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tanaman/{id}/prakiraan-panen [get]
+func GetTanamanPrakiraanPanen(c *gin.Context) {
+	respondPrakiraanPanen(c, "tanaman")
+}
+
+// GetBlokPrakiraanPanen godoc
+// @Summary Prakiraan panen blok
+// @Description Perkiraan jumlah buah dan berat (kg) per minggu untuk blok, beserta total per pohon
+// @Tags Prakiraan Panen
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Blok"
+// @Param minggu query int false "Jumlah minggu ke depan (default 12, maks 26)"
+// @Success 200 {object} utils.Response{data=config.PrakiraanPanen}
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /blok/{id}/prakiraan-panen [get]
+func GetBlokPrakiraanPanen(c *gin.Context) {
+	respondPrakiraanPanen(c, "blok")
+}
+
+// GetKebunPrakiraanPanen godoc
+// @Summary Prakiraan panen kebun
+// @Description Perkiraan jumlah buah dan berat (kg) per minggu untuk kebun, beserta total per blok
+// @Tags Prakiraan Panen
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Kebun"
+// @Param minggu query int false "Jumlah minggu ke depan (default 12, maks 26)"
+// @Success 200 {object} utils.Response{data=config.PrakiraanPanen}
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /kebun/{id}/prakiraan-panen [get]
+func GetKebunPrakiraanPanen(c *gin.Context) {
+	respondPrakiraanPanen(c, "kebun")
+}
+
+// GetTanamanBacktestPanen godoc
+// @Summary Backtest prakiraan panen tanaman
+// @Description Prakiraan diulang pada beberapa titik di masa lalu hanya dengan data sebelum titik itu,
+// @Description lalu dibandingkan dengan panen yang tercatat
+// @Tags Prakiraan Panen
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Tanaman"
+// @Param minggu query int false "Horizon prakiraan dalam minggu (default 12, maks 26)"
+// @Param titik query int false "Jumlah titik backtest, berjarak 4 minggu (default 4, maks 12)"
+// @Success 200 {object} utils.Response{data=config.BacktestPrakiraan}
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tanaman/{id}/prakiraan-panen/backtest [get]
+func GetTanamanBacktestPanen(c *gin.Context) {
+	respondBacktestPanen(c, "tanaman")
+}
+
+// GetBlokBacktestPanen godoc
+// @Summary Backtest prakiraan panen blok
+// @Description Prakiraan diulang pada beberapa titik di masa lalu hanya dengan data sebelum titik itu,
+// @Description lalu dibandingkan dengan panen yang tercatat
+// @Tags Prakiraan Panen
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Blok"
+// @Param minggu query int false "Horizon prakiraan dalam minggu (default 12, maks 26)"
+// @Param titik query int false "Jumlah titik backtest, berjarak 4 minggu (default 4, maks 12)"
+// @Success 200 {object} utils.Response{data=config.BacktestPrakiraan}
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /blok/{id}/prakiraan-panen/backtest [get]
+func GetBlokBacktestPanen(c *gin.Context) {
+	respondBacktestPanen(c, "blok")
+}
+
+// GetKebunBacktestPanen godoc
+// @Summary Backtest prakiraan panen kebun
+// @Description Prakiraan diulang pada beberapa titik di masa lalu hanya dengan data sebelum titik itu,
+// @Description lalu dibandingkan dengan panen yang tercatat
+// @Tags Prakiraan Panen
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Kebun"
+// @Param minggu query int false "Horizon prakiraan dalam minggu (default 12, maks 26)"
+// @Param titik query int false "Jumlah titik backtest, berjarak 4 minggu (default 4, maks 12)"
+// @Success 200 {object} utils.Response{data=config.BacktestPrakiraan}
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /kebun/{id}/prakiraan-panen/backtest [get]
+func GetKebunBacktestPanen(c *gin.Context) {
+	respondBacktestPanen(c, "kebun")
+}
+
+// GetKebunPrakiraanPanenPembeli godoc
+// @Summary Prakiraan panen kebun untuk pembeli
+// @Description Total perkiraan buah dan berat (kg) per minggu tanpa rincian blok / pohon
+// @Tags Prakiraan Panen
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Kebun"
+// @Param minggu query int false "Jumlah minggu ke depan (default 12, maks 26)"
+// @Success 200 {object} utils.Response{data=config.PrakiraanPanen}
+// @Failure 404 {object} utils.Response
+// @Router /pembeli/kebun/{id}/prakiraan-panen [get]
+func GetKebunPrakiraanPanenPembeli(c *gin.Context) {
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+	minggu, ok := queryRange(c, "minggu", config.PrakiraanMingguDefault, config.PrakiraanMingguMaks)
+	if !ok {
+		return
+	}
+	kebun, ok := kebunFromParam(c, db)
+	if !ok {
+		return
+	}
+
+	prakiraan, err := config.HitungPrakiraanPanen(db, config.PrakiraanScope{KebunID: kebun.ID}, minggu)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menghitung prakiraan panen", err.Error())
+		return
+	}
+	prakiraan.Tanaman = nil
+
+	utils.SuccessResponse(c, http.StatusOK, "Prakiraan panen kebun "+kebun.NamaKebun, prakiraan)
+}
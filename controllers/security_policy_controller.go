@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"Avocycle/config"
+	"Avocycle/middleware"
+	"Avocycle/models"
+	"Avocycle/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateSecurityPolicyRequest body update policy keamanan
+type UpdateSecurityPolicyRequest struct {
+	Require2FAAdmin *bool `json:"require_2fa_admin" example:"true"`
+}
+
+// GetSecurityPolicy godoc
+// @Summary Ambil security policy
+// @Description Pengaturan keamanan global (contoh: wajib 2FA untuk Admin)
+// @Tags Admin Security
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /admin/security-policy [get]
+func GetSecurityPolicy(c *gin.Context) {
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	var policy models.SecurityPolicy
+	db.Order("id ASC").FirstOrCreate(&policy)
+
+	utils.SuccessResponse(c, http.StatusOK, "Security policy", policy)
+}
+
+// UpdateSecurityPolicy godoc
+// @Summary Update security policy
+// @Description Mengubah pengaturan keamanan. Admin yang mengaktifkan wajib 2FA harus sudah mengaktifkan 2FA sendiri supaya tidak terkunci.
+// @Tags Admin Security
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body controllers.UpdateSecurityPolicyRequest true "Policy"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /admin/security-policy [put]
+func UpdateSecurityPolicy(c *gin.Context) {
+	claims := currentClaims(c)
+
+	var input UpdateSecurityPolicyRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
+		return
+	}
+
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	var policy models.SecurityPolicy
+	if err := db.Order("id ASC").FirstOrCreate(&policy).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil security policy", err.Error())
+		return
+	}
+
+	if input.Require2FAAdmin != nil {
+		if *input.Require2FAAdmin {
+			var count int64
+			db.Model(&models.UserTwoFactor{}).Where("user_id = ? AND enabled = ?", claims.UserID, true).Count(&count)
+			if count == 0 {
+				utils.ErrorResponse(c, http.StatusBadRequest, "Aktifkan 2FA di akun Anda sebelum mewajibkan 2FA untuk Admin", nil)
+				return
+			}
+		}
+		policy.Require2FAAdmin = *input.Require2FAAdmin
+	}
+
+	if err := db.Save(&policy).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal update security policy", err.Error())
+		return
+	}
+
+	middleware.InvalidateSecurityPolicy()
+
+	utils.SuccessResponse(c, http.StatusOK, "Security policy diperbarui", policy)
+}
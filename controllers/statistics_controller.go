@@ -12,6 +12,9 @@ import (
 	"Avocycle/utils"
 )
 
+// Semua statistik dibatasi ke kebun organisasi / keanggotaan user (lihat resolveTenant),
+// bisa dipersempit dengan ?organization_id=. Hitungan pohon hanya tanaman berstatus Active.
+
 func CountAllPohon(c *gin.Context) {
 	// connect to db
 	db, err := config.DbConnect()
@@ -20,8 +23,13 @@ func CountAllPohon(c *gin.Context) {
 		return
 	}
 
+	tenant, ok := resolveTenant(c, db)
+	if !ok {
+		return
+	}
+
 	var totalTree int64
-	if err := db.Model(&models.Tanaman{}).Count(&totalTree).Error; err != nil {
+	if err := db.Model(&models.Tanaman{}).Scopes(tenant.ByKebun("kebun_id"), tanamanAktif).Count(&totalTree).Error; err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to count tanaman", err.Error())
 		return
 	}
@@ -42,6 +50,11 @@ func CountTanamanDiseased(c *gin.Context) {
 		return
 	}
 
+	tenant, ok := resolveTenant(c, db)
+	if !ok {
+		return
+	}
+
 	var tanamanSakit int64
 
 	// Subquery: created_at terbaru per tanaman
@@ -54,6 +67,8 @@ func CountTanamanDiseased(c *gin.Context) {
 		Table("(?) AS logs", db.Model(&models.LogPenyakitTanaman{})).
 		Joins("JOIN (?) AS latest ON logs.tanaman_id = latest.tanaman_id AND logs.created_at = latest.latest_created_at", subQuery).
 		Where("logs.kondisi IN ?", []string{"Parah", "Sedang", "Ringan"}).
+		Where("logs.tanaman_id IN (?)", db.Model(&models.Tanaman{}).Select("id").Scopes(tanamanAktif)).
+		Scopes(tenant.ByTanaman("logs.tanaman_id")).
 		Count(&tanamanSakit).
 		Error; err != nil {
 
@@ -78,6 +93,11 @@ func CountSiapPanen(c *gin.Context) {
 		return
 	}
 
+	tenant, ok := resolveTenant(c, db)
+	if !ok {
+		return
+	}
+
 	var siapPanen int64
 	currentTime := time.Now()
 	// Subquery untuk mendapatkan fase buah terbaru per tanaman
@@ -89,6 +109,8 @@ func CountSiapPanen(c *gin.Context) {
 	if err := db.Model(&models.FaseBuah{}).
 		Joins("INNER JOIN (?) as latest ON fase_buahs.tanaman_id = latest.tanaman_id AND fase_buahs.created_at = latest.latest_created_at", subQuery).
 		Where("fase_buahs.estimasi_panen <= ?", currentTime).
+		Where("fase_buahs.tanaman_id IN (?)", db.Model(&models.Tanaman{}).Select("id").Scopes(tanamanAktif)).
+		Scopes(tenant.ByTanaman("fase_buahs.tanaman_id")).
 		Count(&siapPanen).
 		Error; err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to count tanaman siap panen", err.Error())
@@ -110,6 +132,11 @@ func GetWeeklyPanenLast6Weeks(c *gin.Context) {
 		return
 	}
 
+	tenant, ok := resolveTenant(c, db)
+	if !ok {
+		return
+	}
+
 	now := time.Now()
 	endDate := now
 	startDate := now.AddDate(0, 0, -7*5) // 5 minggu ke belakang → total 6 minggu
@@ -129,6 +156,7 @@ func GetWeeklyPanenLast6Weeks(c *gin.Context) {
             SUM(jumlah_panen) AS total_panen
         `).
 		Where("tanggal_panen_aktual IS NOT NULL").
+		Scopes(tenant.ByTanaman("tanaman_id")).
 		Where("tanggal_panen_aktual BETWEEN ? AND ?", startDate, endDate).
 		// GROUP BY kolom 1 dan 2 di SELECT (year & week)
 		Group("1, 2").
@@ -16,13 +16,34 @@ import (
 	"Avocycle/utils"
 )
 
-func isValidVarietas(v string) bool {
-	switch v {
-	case "Var1", "Var2", "Var3":
-		return true
+// resolveVarietas cari varietas dari varietas_id, atau dari nama (kompatibel form lama yang kirim teks)
+func resolveVarietas(db *gorm.DB, idStr, nama string) (*models.Varietas, *string) {
+	var varietas models.Varietas
+	var err error
+	switch {
+	case idStr != "":
+		id, convErr := strconv.Atoi(idStr)
+		if convErr != nil || id <= 0 {
+			msg := "varietas_id tidak valid"
+			return nil, &msg
+		}
+		err = db.First(&varietas, id).Error
+	case strings.TrimSpace(nama) != "":
+		err = db.Where("LOWER(nama) = LOWER(?)", strings.TrimSpace(nama)).First(&varietas).Error
 	default:
-		return false
+		msg := "varietas_id wajib diisi"
+		return nil, &msg
+	}
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			msg := "varietas tidak ditemukan, lihat daftar di /varietas"
+			return nil, &msg
+		}
+		msg := "gagal cek varietas"
+		return nil, &msg
 	}
+	return &varietas, nil
 }
 
 // parse tanggal dan pastikan tidak di masa depan
@@ -55,15 +76,77 @@ func ensureKebunExists(db *gorm.DB, kebunID uint) *string {
 	return nil
 }
 
+// kodeTanamanTaken kode tanaman unik per kebun (tanaman aktif), exceptID untuk update
+func kodeTanamanTaken(db *gorm.DB, kebunID uint, kode string, exceptID uint) bool {
+	var count int64
+	db.Model(&models.Tanaman{}).
+		Where("kebun_id = ? AND LOWER(kode_tanaman) = LOWER(?) AND id <> ?", kebunID, kode, exceptID).
+		Count(&count)
+	return count > 0
+}
+
+// applyTanamanPosisi baca latitude / longitude dan baris / kolom dari form, masing-masing harus berpasangan.
+// Field kosong tidak mengubah posisi. Baris / kolom dibatasi ukuran grid blok jika diisi.
+func applyTanamanPosisi(c *gin.Context, t *models.Tanaman, blok *models.Blok) *string {
+	fail := func(msg string) *string { return &msg }
+
+	latForm, lngForm := strings.TrimSpace(c.PostForm("latitude")), strings.TrimSpace(c.PostForm("longitude"))
+	if latForm != "" || lngForm != "" {
+		lat, errLat := strconv.ParseFloat(latForm, 64)
+		lng, errLng := strconv.ParseFloat(lngForm, 64)
+		if errLat != nil || errLng != nil {
+			return fail("latitude dan longitude harus diisi berpasangan dan berupa angka")
+		}
+		if err := utils.ValidateLatLng(lat, lng); err != nil {
+			return fail(err.Error())
+		}
+		t.Latitude, t.Longitude = &lat, &lng
+	}
+
+	barisForm, kolomForm := strings.TrimSpace(c.PostForm("baris")), strings.TrimSpace(c.PostForm("kolom"))
+	if barisForm != "" || kolomForm != "" {
+		baris, errBaris := strconv.Atoi(barisForm)
+		kolom, errKolom := strconv.Atoi(kolomForm)
+		if errBaris != nil || errKolom != nil || baris < 1 || kolom < 1 {
+			return fail("baris dan kolom harus diisi berpasangan, angka >= 1")
+		}
+		if blok.JumlahBaris != nil && baris > *blok.JumlahBaris {
+			return fail(fmt.Sprintf("baris melebihi jumlah baris blok %s (%d)", blok.Kode, *blok.JumlahBaris))
+		}
+		if blok.JumlahKolom != nil && kolom > *blok.JumlahKolom {
+			return fail(fmt.Sprintf("kolom melebihi jumlah kolom blok %s (%d)", blok.Kode, *blok.JumlahKolom))
+		}
+		t.Baris, t.Kolom = &baris, &kolom
+	}
+	return nil
+}
+
+// posisiTanamanTaken satu posisi baris-kolom hanya untuk satu tanaman berdiri di blok
+// (Removed / Replanted melepas posisinya, sama seperti idx_tanaman_posisi_aktif)
+func posisiTanamanTaken(db *gorm.DB, t *models.Tanaman) bool {
+	if t.BlokID == nil || t.Baris == nil || t.Kolom == nil {
+		return false
+	}
+	var count int64
+	db.Model(&models.Tanaman{}).
+		Where("blok_id = ? AND baris = ? AND kolom = ? AND id <> ?", *t.BlokID, *t.Baris, *t.Kolom, t.ID).
+		Where("status NOT IN ?", []string{config.TanamanStatusRemoved, config.TanamanStatusReplanted}).
+		Count(&count)
+	return count > 0
+}
+
 // --- controller ---
 // @Summary Ambil semua tanaman
-// @Description Mengambil daftar tanaman dengan pagination
+// @Description Mengambil daftar tanaman dengan pagination. Pembeli melihat tanaman semua kebun (read-only).
 // @Tags Tanaman
 // @Produce json
 // @Param page query int false "Halaman"
 // @Param per_page query int false "Jumlah data per halaman"
 // @Success 200 {object} utils.Response
 // @Failure 500 {object} utils.Response
+// @Security Bearer
+// @Param organization_id query int false "Filter satu organisasi"
+// @Param status query string false "Filter status: Active, Dormant, Removed, Replanted (boleh dipisah koma)"
 // @Router /tanaman [get]
 func GetAllTanaman(c *gin.Context) {
 	// get pagination parameters
@@ -71,15 +154,25 @@ func GetAllTanaman(c *gin.Context) {
 	offset := utils.GetOffset(page, perPage)
 
 	// connect to db
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
 	}
 
+	tenant, ok := resolveCatalog(c, db)
+	if !ok {
+		return
+	}
+
+	byStatus, ok := tanamanStatusFilter(c)
+	if !ok {
+		return
+	}
+
 	// count total rows
 	var totalRows int64
-	if err := db.Model(&models.Tanaman{}).Count(&totalRows).Error; err != nil {
+	if err := db.Model(&models.Tanaman{}).Scopes(tenant.ByKebun("kebun_id"), byStatus).Count(&totalRows).Error; err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to count tanaman data", err.Error())
 		return
 	} 
@@ -99,7 +192,7 @@ func GetAllTanaman(c *gin.Context) {
 
 	// get paginated data
 	var tanamanList []models.Tanaman
-	if err := db.Preload("Kebun").
+	if err := db.Scopes(tenant.ByKebun("kebun_id"), byStatus).Preload("Kebun").Preload("Varietas").Preload("Blok").
 		Limit(perPage).
 		Offset(offset).
 		Find(&tanamanList).Error; err != nil {
@@ -124,18 +217,24 @@ func GetAllTanaman(c *gin.Context) {
 // @Param id path int true "ID Tanaman"
 // @Success 200 {object} utils.Response
 // @Failure 404 {object} utils.Response
+// @Security Bearer
 // @Router /tanaman/{id} [get]
 func GetTanamanByID(c *gin.Context) {
 	id := c.Param("id")
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
 	}
 
+	tenant, ok := resolveCatalog(c, db)
+	if !ok {
+		return
+	}
+
 	var tanaman models.Tanaman
-	if err := db.Preload("Kebun").First(&tanaman, id).Error; err != nil {
+	if err := db.Scopes(tenant.ByKebun("kebun_id")).Preload("Kebun").Preload("Varietas").Preload("Blok").First(&tanaman, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utils.ErrorResponse(c, http.StatusNotFound, "Tanaman tidak ditemukan", nil)
 			return
@@ -154,19 +253,27 @@ func GetTanamanByID(c *gin.Context) {
 // @Accept multipart/form-data
 // @Produce json
 // @Param nama_tanaman formData string true "Nama Tanaman"
-// @Param varietas formData string true "Varietas"
+// @Param varietas_id formData int false "ID Varietas (lihat /varietas)"
+// @Param varietas formData string false "Nama varietas, dipakai jika varietas_id kosong"
 // @Param tanggal_tanam formData string true "Tanggal Tanam (YYYY-MM-DD)"
 // @Param kebun_id formData int true "ID Kebun"
-// @Param kode_blok formData string true "Kode Blok"
+// @Param blok_id formData int false "ID Blok (lihat /kebun/{id}/blok)"
+// @Param kode_blok formData string false "Kode blok di kebun tersebut, dipakai jika blok_id kosong"
 // @Param kode_tanaman formData string true "Kode Tanaman"
 // @Param masa_produksi formData int true "Masa Produksi"
-// @Param foto_tanaman formData file false "Foto Tanaman"
+// @Param latitude formData number false "Latitude GPS pohon, berpasangan dengan longitude"
+// @Param longitude formData number false "Longitude GPS pohon"
+// @Param baris formData int false "Baris di grid blok, berpasangan dengan kolom"
+// @Param kolom formData int false "Kolom di grid blok"
+// @Param foto_tanaman formData file false "Foto utama tanaman, juga masuk galeri (foto lain lewat /galeri/tanaman/{id})"
 // @Security 	Bearer
 // @Success 201 {object} utils.Response
 // @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
 // @Router /tanaman [post]
 func CreateTanaman(c *gin.Context) {
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
@@ -175,10 +282,12 @@ func CreateTanaman(c *gin.Context) {
 	// input struct lokal (tanpa DTO terpisah)
 	var input struct {
 		NamaTanaman  string `form:"nama_tanaman" binding:"required"`
-		Varietas     string `form:"varietas" binding:"required"`
+		VarietasID   string `form:"varietas_id"`
+		Varietas     string `form:"varietas"`
 		TanggalTanam string `form:"tanggal_tanam" binding:"required"` // YYYY-MM-DD
 		KebunID      uint   `form:"kebun_id" binding:"required"`
-		KodeBlok 	 string `form:"kode_blok" binding:"required"`
+		BlokID       string `form:"blok_id"`
+		KodeBlok 	 string `form:"kode_blok"`
 		KodeTanaman	 string `form:"kode_tanaman" binding:"required"`
 		FotoTanaman  *multipart.FileHeader `form:"foto_tanaman"`
 		MasaProduksi int	`form:"masa_produksi" binding:"required"`
@@ -197,8 +306,9 @@ func CreateTanaman(c *gin.Context) {
 		return
 	}
 
-	if !isValidVarietas(input.Varietas) {
-		utils.ErrorResponse(c, http.StatusBadRequest, "varietas tidak valid. Hanya boleh Var1 / Var2 / Var3", input.Varietas)
+	varietas, msg := resolveVarietas(db, input.VarietasID, input.Varietas)
+	if msg != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, *msg, gin.H{"varietas_id": input.VarietasID, "varietas": input.Varietas})
 		return
 	}
 
@@ -213,34 +323,81 @@ func CreateTanaman(c *gin.Context) {
 		return
 	}
 
+	if !authorizeKebun(c, db, input.KebunID, config.KebunActTanamanWrite) {
+		return
+	}
+
+	kodeTanaman := strings.TrimSpace(input.KodeTanaman)
+	if kodeTanaman == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "kode_tanaman tidak boleh kosong", nil)
+		return
+	}
+	if kodeTanamanTaken(db, input.KebunID, kodeTanaman, 0) {
+		utils.ErrorResponse(c, http.StatusConflict, "kode_tanaman sudah dipakai di kebun ini", kodeTanaman)
+		return
+	}
+
+	blok, msg := resolveBlok(db, input.KebunID, input.BlokID, input.KodeBlok)
+	if msg != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, *msg, gin.H{"blok_id": input.BlokID, "kode_blok": input.KodeBlok})
+		return
+	}
+
 	// 3) map ke model & simpan
 	tanaman := models.Tanaman{
 		NamaTanaman:  input.NamaTanaman,
-		Varietas:     input.Varietas,
+		VarietasID:   &varietas.ID,
 		TanggalTanam: parsedTanggal,
 		KebunID:      input.KebunID,
-		KodeBlok: 	  input.KodeBlok,
-		KodeTanaman:  input.KodeTanaman,
+		BlokID:       &blok.ID,
+		KodeBlok: 	  blok.Kode,
+		KodeTanaman:  kodeTanaman,
 		MasaProduksi: input.MasaProduksi,
+		Status:       config.TanamanStatusActive,
+	}
+
+	if msg := applyTanamanPosisi(c, &tanaman, blok); msg != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, *msg, nil)
+		return
+	}
+	if posisiTanamanTaken(db, &tanaman) {
+		utils.ErrorResponse(c, http.StatusConflict, "Posisi baris / kolom sudah ditempati tanaman lain", gin.H{"baris": tanaman.Baris, "kolom": tanaman.Kolom})
+		return
 	}
 
 	fileHeader, _ := c.FormFile("foto_tanaman")
-	if fileHeader != nil {
-		url, publicID, uploadErr := utils.AsyncUploadOptionalImage(fileHeader, "tanaman")
-		if uploadErr != nil {
-            utils.ErrorResponse(c, http.StatusBadRequest, "Upload foto gagal", uploadErr.Error())
-            return
-        }
-        tanaman.FotoTanaman = url
-        tanaman.FotoTanamanID = publicID
+	uploaded, uploadErr := utils.UploadImage(fileHeader, "tanaman")
+	if uploadErr != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Upload foto gagal", uploadErr.Error())
+		return
+	}
+	if uploaded != nil {
+		tanaman.FotoTanaman = uploaded.URL
+		tanaman.FotoTanamanID = uploaded.PublicID
+		tanaman.FotoTanamanThumb = uploaded.ThumbURL
 	}
 
-	if err := db.Create(&tanaman).Error; err != nil {
+	// tanaman & entri galeri satu transaksi, supaya tidak ada tanaman dengan foto yang hilang dari galeri
+	var cover *models.Media
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&tanaman).Error; err != nil {
+			return err
+		}
+		var err error
+		cover, err = config.AttachCoverPhoto(tx, "tanaman", tanaman.ID, uploaded, currentUserID(c))
+		return err
+	})
+	if err != nil {
+		utils.DeleteUploadedImage(uploaded)
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menyimpan tanaman", err.Error())
 		return
 	}
+	tanaman.Varietas = varietas
+	tanaman.Blok = blok
+
+	warnings := fotoWarnings(db, kebunLokasi(db, tanaman.KebunID), "foto tanaman", cover)
 
-	utils.SuccessResponse(c, http.StatusCreated, "Tanaman berhasil dibuat", tanaman)
+	utils.SuccessResponseWithWarnings(c, http.StatusCreated, "Tanaman berhasil dibuat", tanaman, warnings)
 }
 
 // PUT /tanaman/:id
@@ -251,19 +408,27 @@ func CreateTanaman(c *gin.Context) {
 // @Produce json
 // @Param id path int true "ID Tanaman"
 // @Param nama_tanaman formData string false "Nama Tanaman"
-// @Param varietas formData string false "Varietas"
+// @Param varietas_id formData int false "ID Varietas"
+// @Param varietas formData string false "Nama varietas, dipakai jika varietas_id kosong"
 // @Param tanggal_tanam formData string false "Tanggal Tanam"
 // @Param kebun_id formData int false "ID Kebun"
-// @Param kode_blok formData string false "Kode Blok"
+// @Param blok_id formData int false "ID Blok"
+// @Param kode_blok formData string false "Kode blok, dipakai jika blok_id kosong. Wajib salah satu jika pindah kebun dan kode lama tidak ada di kebun tujuan"
 // @Param kode_tanaman formData string false "Kode Tanaman"
 // @Param masa_produksi formData int false "Masa Produksi"
-// @Param foto_tanaman formData file false "Foto Tanaman"
+// @Param latitude formData number false "Latitude GPS pohon, berpasangan dengan longitude"
+// @Param longitude formData number false "Longitude GPS pohon"
+// @Param baris formData int false "Baris di grid blok, berpasangan dengan kolom"
+// @Param kolom formData int false "Kolom di grid blok"
+// @Param foto_tanaman formData file false "Foto utama baru, foto lama tetap di galeri"
 // @Security 	Bearer
 // @Success 200 {object} utils.Response
 // @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
 // @Router /tanaman/{id} [put]
 func UpdateTanaman(c *gin.Context) {
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal koneksi database", nil)
 		return
@@ -281,18 +446,24 @@ func UpdateTanaman(c *gin.Context) {
 		return
 	}
 
+	if !authorizeKebun(c, db, tanaman.KebunID, config.KebunActTanamanWrite) {
+		return
+	}
+
 	// ====================== INPUT HANDLING =====================
 	inputNama := strings.TrimSpace(c.PostForm("nama_tanaman"))
 	if inputNama != "" {
 		tanaman.NamaTanaman = inputNama
 	}
 
-	if varietas := c.PostForm("varietas"); varietas != "" {
-		if !isValidVarietas(varietas) {
-			utils.ErrorResponse(c, http.StatusBadRequest, "varietas tidak valid (Var1, Var2, Var3)", varietas)
+	if varietasID, nama := c.PostForm("varietas_id"), c.PostForm("varietas"); varietasID != "" || nama != "" {
+		varietas, msg := resolveVarietas(db, varietasID, nama)
+		if msg != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, *msg, gin.H{"varietas_id": varietasID, "varietas": nama})
 			return
 		}
-		tanaman.Varietas = varietas
+		tanaman.VarietasID = &varietas.ID
+		tanaman.Varietas = nil
 	}
 
 	if tanggal := c.PostForm("tanggal_tanam"); tanggal != "" {
@@ -316,17 +487,62 @@ func UpdateTanaman(c *gin.Context) {
 			return
 		}
 
+		// pindah kebun: harus punya hak juga di kebun tujuan
+		if !authorizeKebun(c, db, uint(idKebun), config.KebunActTanamanWrite) {
+			return
+		}
+
 		tanaman.KebunID = uint(idKebun)
 	}
 
-	if kodeBlok := strings.TrimSpace(c.PostForm("kode_blok")); kodeBlok != "" {
-		tanaman.KodeBlok = kodeBlok
+	// blok harus milik kebun tanaman; pindah kebun tanpa blok baru mencari kode blok yang sama di kebun tujuan
+	var blok *models.Blok
+	blokForm, kodeBlokForm := c.PostForm("blok_id"), c.PostForm("kode_blok")
+	if blokForm != "" || kodeBlokForm != "" || tanaman.BlokID == nil || c.PostForm("kebun_id") != "" {
+		if blokForm == "" && kodeBlokForm == "" {
+			kodeBlokForm = tanaman.KodeBlok
+		}
+		resolved, msg := resolveBlok(db, tanaman.KebunID, blokForm, kodeBlokForm)
+		if msg != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, *msg, gin.H{"blok_id": blokForm, "kode_blok": kodeBlokForm})
+			return
+		}
+		blok = resolved
+		// posisi baris / kolom milik blok lama
+		if tanaman.BlokID == nil || *tanaman.BlokID != blok.ID {
+			tanaman.Baris, tanaman.Kolom = nil, nil
+		}
+		tanaman.BlokID = &blok.ID
+		tanaman.KodeBlok = blok.Kode
+		tanaman.Blok = nil
 	}
 
 	if kodeTanaman := strings.TrimSpace(c.PostForm("kode_tanaman")); kodeTanaman != "" {
 		tanaman.KodeTanaman = kodeTanaman
 	}
 
+	if (c.PostForm("kode_tanaman") != "" || c.PostForm("kebun_id") != "") &&
+		kodeTanamanTaken(db, tanaman.KebunID, tanaman.KodeTanaman, tanaman.ID) {
+		utils.ErrorResponse(c, http.StatusConflict, "kode_tanaman sudah dipakai di kebun ini", tanaman.KodeTanaman)
+		return
+	}
+
+	if blok == nil {
+		blok = &models.Blok{}
+		if err := db.First(blok, *tanaman.BlokID).Error; err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal mengambil blok", err.Error())
+			return
+		}
+	}
+	if msg := applyTanamanPosisi(c, &tanaman, blok); msg != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, *msg, nil)
+		return
+	}
+	if posisiTanamanTaken(db, &tanaman) {
+		utils.ErrorResponse(c, http.StatusConflict, "Posisi baris / kolom sudah ditempati tanaman lain", gin.H{"baris": tanaman.Baris, "kolom": tanaman.Kolom})
+		return
+	}
+
 	if masa := c.PostForm("masa_produksi"); masa != "" {
 		masaInt, err := strconv.Atoi(masa)
 		if err != nil || masaInt <= 0 {
@@ -338,49 +554,61 @@ func UpdateTanaman(c *gin.Context) {
 
 	// ====================== FOTO HANDLING =====================
 	fileHeader, _ := c.FormFile("foto_tanaman")
-	if fileHeader != nil {
-		newURL, newPublicID, uploadErr := utils.AsyncUploadOptionalImage(fileHeader, "tanaman")
-		if uploadErr != nil {
-			utils.ErrorResponse(c, http.StatusBadRequest, "Upload foto gagal", uploadErr.Error())
-			return
-		}
-
-		// Hapus foto lama jika ada
-		if tanaman.FotoTanamanID != "" {
-			_ = utils.DeleteCloudinaryAsset(tanaman.FotoTanamanID)
-		}
-
-		tanaman.FotoTanaman = newURL
-		tanaman.FotoTanamanID = newPublicID
+	uploaded, uploadErr := utils.UploadImage(fileHeader, "tanaman")
+	if uploadErr != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Upload foto gagal", uploadErr.Error())
+		return
+	}
+	if uploaded != nil {
+		// foto lama tidak dihapus, tetap tersimpan di galeri (lihat /galeri/tanaman/{id})
+		tanaman.FotoTanaman = uploaded.URL
+		tanaman.FotoTanamanID = uploaded.PublicID
+		tanaman.FotoTanamanThumb = uploaded.ThumbURL
 	}
 
 	// ====================== SIMPAN =====================
-	if err := db.Save(&tanaman).Error; err != nil {
+	var cover *models.Media
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&tanaman).Error; err != nil {
+			return err
+		}
+		var err error
+		cover, err = config.AttachCoverPhoto(tx, "tanaman", tanaman.ID, uploaded, currentUserID(c))
+		return err
+	})
+	if err != nil {
+		utils.DeleteUploadedImage(uploaded)
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menyimpan perubahan", err.Error())
 		return
 	}
 
 	// ====================== RELOAD RELASI KEBUN =====================
-	if err := db.Preload("Kebun").First(&tanaman, tanaman.ID).Error; err != nil {
+	if err := db.Preload("Kebun").Preload("Varietas").Preload("Blok").First(&tanaman, tanaman.ID).Error; err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal reload tanaman", err.Error())
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Tanaman berhasil diperbarui", tanaman)
+	warnings := fotoWarnings(db, kebunLokasi(db, tanaman.KebunID), "foto tanaman", cover)
+
+	utils.SuccessResponseWithWarnings(c, http.StatusOK, "Tanaman berhasil diperbarui", tanaman, warnings)
 }
 
 // DELETE /tanaman/:id
 // @Summary Hapus tanaman
-// @Description Menghapus tanaman berdasarkan ID
+// @Description Memindahkan tanaman beserta fase, buah, dan log penyakitnya ke trash, bisa di-restore lewat
+// @Description /trash/tanaman/{id}/restore. Ditolak (409) jika masih ada booking aktif.
 // @Tags Tanaman
 // @Produce json
 // @Param id path int true "ID Tanaman"
+// @Param dry_run query bool false "true = hanya tampilkan data yang akan terhapus"
 // @Security 	Bearer
 // @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
 // @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
 // @Router /tanaman/{id} [delete]
 func DeleteTanaman(c *gin.Context) {
-    db, err := config.DbConnect()
+    db, err := requestDB(c)
     if err != nil {
         utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
         return
@@ -398,19 +626,12 @@ func DeleteTanaman(c *gin.Context) {
         return
     }
 
-    if tanaman.FotoTanamanID != "" {
-        if err := utils.DeleteCloudinaryAsset(tanaman.FotoTanamanID); err != nil {
-            utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal hapus foto di Cloudinary", err.Error())
-            return
-        }
-    }
-
-    if err := db.Delete(&tanaman).Error; err != nil {
-        utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal hapus tanaman", err.Error())
+    if !authorizeKebun(c, db, tanaman.KebunID, config.KebunActTanamanDelete) {
         return
     }
 
-    utils.SuccessResponse(c, http.StatusOK, "Tanaman berhasil dihapus", utils.EmptyObj{})
+    // foto di Cloudinary baru dihapus saat purge permanen (lihat config.PurgeTrashed)
+    respondCascadeDelete(c, db, "tanaman", tanaman.ID)
 }
 
 // @Summary Tanaman berdasarkan Kebun
@@ -422,22 +643,29 @@ func DeleteTanaman(c *gin.Context) {
 // @Param per_page query int false "Jumlah per halaman"
 // @Success 200 {object} utils.Response
 // @Failure 400 {object} utils.Response
+// @Security Bearer
+// @Param organization_id query int false "Filter satu organisasi"
 // @Router /tanaman/by-kebun/{id_kebun} [get]
 func GetTanamanByKebunID(c *gin.Context) {
 	idKebun := c.Param("id_kebun")
 
-	db, err := config.DbConnect()
+	db, err := requestDB(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
 		return
 	}
 
+	tenant, ok := resolveTenant(c, db)
+	if !ok {
+		return
+	}
+
 	page, perPage := utils.GetPagination(c)
 	offset := utils.GetOffset(page, perPage)
 
 	// Hitung total rows dengan join relasi ke tabel kebuns
 	var totalRows int64
-	if err := db.Model(&models.Tanaman{}).
+	if err := db.Model(&models.Tanaman{}).Scopes(tenant.ByKebun("kebun_id")).
 		Where("kebun_id = ?", idKebun).
 		Count(&totalRows).Error; err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to count tanaman data", err.Error())
@@ -458,7 +686,7 @@ func GetTanamanByKebunID(c *gin.Context) {
 
 	// Ambil data
 	var tanamanList []models.Tanaman
-	if err := db.Preload("Kebun").
+	if err := db.Scopes(tenant.ByKebun("kebun_id")).Preload("Kebun").Preload("Varietas").Preload("Blok").
 		Where("kebun_id = ?", idKebun).
 		Limit(perPage).
 		Offset(offset).
@@ -0,0 +1,395 @@
+package controllers
+
+import (
+	"Avocycle/config"
+	"Avocycle/models"
+	"Avocycle/utils"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	qrDefaultSize = 256
+	qrMinSize     = 128
+	qrMaxSize     = 1024
+	// batas tanaman per lembar label blok supaya PDF / PNG tidak terlalu besar
+	qrMaxLabels = 500
+)
+
+// TanamanFase fase terakhir tanaman berdasarkan catatan fase terbaru
+type TanamanFase struct {
+	Fase          string     `json:"fase" example:"buah"` // belum-ada, bunga, buah, panen
+	RecordID      uint       `json:"record_id,omitempty"`
+	Tanggal       *time.Time `json:"tanggal,omitempty"`
+	EstimasiPanen *time.Time `json:"estimasi_panen,omitempty"`
+	SiapPanen     bool       `json:"siap_panen"`
+}
+
+// TanamanLookupResponse hasil scan QR
+type TanamanLookupResponse struct {
+	Tanaman     models.Tanaman `json:"tanaman"`
+	FaseSaatIni TanamanFase    `json:"fase_saat_ini"`
+}
+
+func qrSizeParam(c *gin.Context) (int, bool) {
+	raw := c.Query("size")
+	if raw == "" {
+		return qrDefaultSize, true
+	}
+	size, err := strconv.Atoi(raw)
+	if err != nil || size < qrMinSize || size > qrMaxSize {
+		utils.ErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("size harus %d - %d", qrMinSize, qrMaxSize), raw)
+		return 0, false
+	}
+	return size, true
+}
+
+// tanamanLabel isi label satu pohon: judul kode, sub judul nama + blok
+func tanamanLabel(t *models.Tanaman) utils.QRLabel {
+	subtitle := t.NamaTanaman
+	if t.KodeBlok != "" {
+		subtitle += " - Blok " + t.KodeBlok
+	}
+	return utils.QRLabel{
+		Payload:  utils.TanamanQRPayload(t.ID),
+		Title:    t.KodeTanaman,
+		Subtitle: subtitle,
+	}
+}
+
+func tanamanFromParam(c *gin.Context, db *gorm.DB) (*models.Tanaman, bool) {
+	var tanaman models.Tanaman
+	if err := db.First(&tanaman, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "Tanaman tidak ditemukan", nil)
+			return nil, false
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil tanaman", err.Error())
+		return nil, false
+	}
+	if !authorizeKebun(c, db, tanaman.KebunID, config.KebunActView) {
+		return nil, false
+	}
+	return &tanaman, true
+}
+
+func blokLabels(c *gin.Context, db *gorm.DB) (*models.Blok, []utils.QRLabel, bool) {
+	blok, ok := blokFromParam(c, db, config.KebunActView)
+	if !ok {
+		return nil, nil, false
+	}
+
+	var tanaman []models.Tanaman
+	if err := db.Where("blok_id = ?", blok.ID).Order("kode_tanaman ASC").Limit(qrMaxLabels + 1).Find(&tanaman).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal mengambil tanaman", err.Error())
+		return nil, nil, false
+	}
+	if len(tanaman) == 0 {
+		utils.ErrorResponse(c, http.StatusNotFound, "Belum ada tanaman di blok ini", gin.H{"blok_id": blok.ID})
+		return nil, nil, false
+	}
+	if len(tanaman) > qrMaxLabels {
+		utils.ErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Blok berisi lebih dari %d tanaman, pecah blok atau cetak per tanaman", qrMaxLabels), nil)
+		return nil, nil, false
+	}
+
+	labels := make([]utils.QRLabel, len(tanaman))
+	for i := range tanaman {
+		labels[i] = tanamanLabel(&tanaman[i])
+	}
+	return blok, labels, true
+}
+
+func sendFile(c *gin.Context, contentType, filename string, data []byte) {
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", filename))
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// faseSaatIniBulk fase terbaru dari catatan bunga / buah / panen (dilihat dari waktu pencatatan)
+// untuk sekumpulan tanaman. tanamanIDs boleh slice id atau subquery. Tanaman tanpa catatan tidak ada di map.
+func faseSaatIniBulk(db *gorm.DB, tanamanIDs any) (map[uint]TanamanFase, error) {
+	result := map[uint]TanamanFase{}
+	latest := map[uint]time.Time{}
+	set := func(tanamanID uint, createdAt time.Time, fase TanamanFase) {
+		if prev, ok := latest[tanamanID]; ok && createdAt.Before(prev) {
+			return
+		}
+		latest[tanamanID] = createdAt
+		result[tanamanID] = fase
+	}
+
+	// DISTINCT ON: satu record terbaru per tanaman
+	var bunga []models.FaseBunga
+	if err := db.Select("DISTINCT ON (tanaman_id) *").Where("tanaman_id IN (?)", tanamanIDs).
+		Order("tanaman_id, created_at DESC").Find(&bunga).Error; err != nil {
+		return nil, err
+	}
+	for _, b := range bunga {
+		set(b.TanamanID, b.CreatedAt, TanamanFase{Fase: "bunga", RecordID: b.ID, Tanggal: b.TanggalCatat})
+	}
+
+	now := time.Now()
+	var buah []models.FaseBuah
+	if err := db.Select("DISTINCT ON (tanaman_id) *").Where("tanaman_id IN (?)", tanamanIDs).
+		Order("tanaman_id, created_at DESC").Find(&buah).Error; err != nil {
+		return nil, err
+	}
+	for _, b := range buah {
+		set(b.TanamanID, b.CreatedAt, TanamanFase{
+			Fase:          "buah",
+			RecordID:      b.ID,
+			Tanggal:       b.TanggalCatat,
+			EstimasiPanen: b.EstimasiPanen,
+			SiapPanen:     b.EstimasiPanen != nil && !b.EstimasiPanen.After(now),
+		})
+	}
+
+	var panen []models.FasePanen
+	if err := db.Select("DISTINCT ON (tanaman_id) *").Where("tanaman_id IN (?)", tanamanIDs).
+		Order("tanaman_id, created_at DESC").Find(&panen).Error; err != nil {
+		return nil, err
+	}
+	for _, p := range panen {
+		set(p.TanamanID, p.CreatedAt, TanamanFase{Fase: "panen", RecordID: p.ID, Tanggal: p.TanggalPanenAktual})
+	}
+
+	return result, nil
+}
+
+// faseSaatIni fase terbaru satu tanaman, "belum-ada" jika belum pernah dicatat
+func faseSaatIni(db *gorm.DB, tanamanID uint) (TanamanFase, error) {
+	fase, err := faseSaatIniBulk(db, []uint{tanamanID})
+	if err != nil {
+		return TanamanFase{}, err
+	}
+	if f, ok := fase[tanamanID]; ok {
+		return f, nil
+	}
+	return TanamanFase{Fase: "belum-ada"}, nil
+}
+
+// GetTanamanQR godoc
+// @Summary QR code tanaman
+// @Description PNG QR berisi AVC:T:<tanaman_id>, dipakai untuk tag di pohon
+// @Tags Tanaman Label
+// @Security Bearer
+// @Produce png
+// @Param id path int true "ID Tanaman"
+// @Param size query int false "Ukuran sisi dalam pixel (128 - 1024, default 256)"
+// @Success 200 {file} binary
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tanaman/{id}/qr [get]
+func GetTanamanQR(c *gin.Context) {
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	size, ok := qrSizeParam(c)
+	if !ok {
+		return
+	}
+	tanaman, ok := tanamanFromParam(c, db)
+	if !ok {
+		return
+	}
+
+	data, err := utils.QRCodePNG(tanamanLabel(tanaman).Payload, size)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal membuat QR code", err.Error())
+		return
+	}
+	sendFile(c, "image/png", "qr-"+tanaman.KodeTanaman+".png", data)
+}
+
+// GetTanamanLabelPDF godoc
+// @Summary Label PDF tanaman
+// @Description Lembar label A4 berisi satu label QR tanaman
+// @Tags Tanaman Label
+// @Security Bearer
+// @Produce application/pdf
+// @Param id path int true "ID Tanaman"
+// @Success 200 {file} binary
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tanaman/{id}/label.pdf [get]
+func GetTanamanLabelPDF(c *gin.Context) {
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	tanaman, ok := tanamanFromParam(c, db)
+	if !ok {
+		return
+	}
+
+	data, err := utils.QRLabelPDF([]utils.QRLabel{tanamanLabel(tanaman)})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal membuat label PDF", err.Error())
+		return
+	}
+	sendFile(c, "application/pdf", "label-"+tanaman.KodeTanaman+".pdf", data)
+}
+
+// GetBlokQR godoc
+// @Summary QR code seluruh tanaman di blok
+// @Description Satu PNG grid berisi QR semua tanaman di blok, urut kode tanaman
+// @Tags Tanaman Label
+// @Security Bearer
+// @Produce png
+// @Param id path int true "ID Blok"
+// @Param size query int false "Ukuran tiap QR dalam pixel (128 - 1024, default 256)"
+// @Param columns query int false "Jumlah kolom grid (1 - 10, default 4)"
+// @Success 200 {file} binary
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /blok/{id}/qr [get]
+func GetBlokQR(c *gin.Context) {
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	size, ok := qrSizeParam(c)
+	if !ok {
+		return
+	}
+	columns := 4
+	if raw := c.Query("columns"); raw != "" {
+		columns, err = strconv.Atoi(raw)
+		if err != nil || columns < 1 || columns > 10 {
+			utils.ErrorResponse(c, http.StatusBadRequest, "columns harus 1 - 10", raw)
+			return
+		}
+	}
+
+	blok, labels, ok := blokLabels(c, db)
+	if !ok {
+		return
+	}
+
+	data, err := utils.QRSheetPNG(labels, size, columns)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal membuat QR code", err.Error())
+		return
+	}
+	sendFile(c, "image/png", "qr-blok-"+blok.Kode+".png", data)
+}
+
+// GetBlokLabelPDF godoc
+// @Summary Lembar label PDF blok
+// @Description Label QR siap cetak (A4, 3 x 7 per halaman) untuk semua tanaman di blok
+// @Tags Tanaman Label
+// @Security Bearer
+// @Produce application/pdf
+// @Param id path int true "ID Blok"
+// @Success 200 {file} binary
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /blok/{id}/labels.pdf [get]
+func GetBlokLabelPDF(c *gin.Context) {
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	blok, labels, ok := blokLabels(c, db)
+	if !ok {
+		return
+	}
+
+	data, err := utils.QRLabelPDF(labels)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal membuat label PDF", err.Error())
+		return
+	}
+	sendFile(c, "application/pdf", "label-blok-"+blok.Kode+".pdf", data)
+}
+
+// LookupTanaman godoc
+// @Summary Cari tanaman dari hasil scan QR
+// @Description code berisi isi QR (AVC:T:<tanaman_id>, atau AVC:<kebun_id>:<kode_tanaman> untuk label lama).
+// @Description Bisa juga kirim kebun_id + kode_tanaman langsung.
+// @Description Mengembalikan tanaman beserta fase terakhirnya, dipakai sebelum mencatat fase baru.
+// @Tags Tanaman Label
+// @Security Bearer
+// @Produce json
+// @Param code query string false "Isi QR hasil scan"
+// @Param kebun_id query int false "ID Kebun, jika tanpa code"
+// @Param kode_tanaman query string false "Kode tanaman, jika tanpa code"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tanaman/lookup [get]
+func LookupTanaman(c *gin.Context) {
+	var code utils.TanamanQRCode
+	if raw := c.Query("code"); raw != "" {
+		parsed, ok := utils.ParseTanamanQRPayload(raw)
+		if !ok {
+			utils.ErrorResponse(c, http.StatusBadRequest, "QR bukan label tanaman Avocycle", raw)
+			return
+		}
+		code = parsed
+	} else {
+		id, err := strconv.ParseUint(c.Query("kebun_id"), 10, 64)
+		kode := strings.TrimSpace(c.Query("kode_tanaman"))
+		if err != nil || id == 0 || kode == "" {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Isi code, atau kebun_id dan kode_tanaman", nil)
+			return
+		}
+		code = utils.TanamanQRCode{KebunID: uint(id), KodeTanaman: kode}
+	}
+
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	query := db.Preload("Kebun").Preload("Varietas").Preload("Blok")
+	notFound := gin.H{"tanaman_id": code.TanamanID}
+	if code.TanamanID != 0 {
+		query = query.Where("id = ?", code.TanamanID)
+	} else {
+		// label lama: kebun dicek dulu supaya kode tanaman kebun lain tidak bisa ditebak
+		if !authorizeKebun(c, db, code.KebunID, config.KebunActView) {
+			return
+		}
+		query = query.Where("kebun_id = ? AND LOWER(kode_tanaman) = LOWER(?)", code.KebunID, code.KodeTanaman)
+		notFound = gin.H{"kebun_id": code.KebunID, "kode_tanaman": code.KodeTanaman}
+	}
+
+	var tanaman models.Tanaman
+	if err := query.First(&tanaman).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "Tanaman tidak ditemukan", notFound)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil tanaman", err.Error())
+		return
+	}
+	if code.TanamanID != 0 && !authorizeKebun(c, db, tanaman.KebunID, config.KebunActView) {
+		return
+	}
+
+	fase, err := faseSaatIni(db, tanaman.ID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil fase tanaman", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tanaman ditemukan", TanamanLookupResponse{Tanaman: tanaman, FaseSaatIni: fase})
+}
@@ -0,0 +1,365 @@
+package controllers
+
+import (
+	"Avocycle/config"
+	"Avocycle/models"
+	"Avocycle/utils"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// UpdateTanamanStatusRequest body ubah status tanaman
+type UpdateTanamanStatusRequest struct {
+	Status  string `json:"status" binding:"required" example:"Dormant"` // Active, Dormant, Removed
+	Alasan  string `json:"alasan" example:"Tidak berbunga musim ini"`
+	Tanggal string `json:"tanggal" example:"2025-01-31"` // YYYY-MM-DD, default hari ini
+}
+
+// ReplantTanamanRequest body tanam ulang. Field kosong mengikuti tanaman pendahulu.
+type ReplantTanamanRequest struct {
+	KodeTanaman  string `json:"kode_tanaman" example:"A1-001-R2"` // default <kode lama>-R<n>
+	NamaTanaman  string `json:"nama_tanaman"`
+	VarietasID   string `json:"varietas_id"`
+	Varietas     string `json:"varietas"`
+	TanggalTanam string `json:"tanggal_tanam" example:"2025-02-01"` // YYYY-MM-DD, default hari ini
+	MasaProduksi int    `json:"masa_produksi"`
+	Alasan       string `json:"alasan" example:"Mati terkena busuk akar"`
+}
+
+// tanamanStatusFilter scope ?status= (boleh beberapa dipisah koma)
+func tanamanStatusFilter(c *gin.Context) (func(*gorm.DB) *gorm.DB, bool) {
+	raw := strings.TrimSpace(c.Query("status"))
+	if raw == "" {
+		return func(q *gorm.DB) *gorm.DB { return q }, true
+	}
+	statuses := strings.Split(raw, ",")
+	for i, s := range statuses {
+		statuses[i] = strings.TrimSpace(s)
+		switch statuses[i] {
+		case config.TanamanStatusActive, config.TanamanStatusDormant, config.TanamanStatusRemoved, config.TanamanStatusReplanted:
+		default:
+			utils.ErrorResponse(c, http.StatusBadRequest, "status harus Active, Dormant, Removed, atau Replanted", raw)
+			return nil, false
+		}
+	}
+	return func(q *gorm.DB) *gorm.DB { return q.Where("status IN ?", statuses) }, true
+}
+
+// tanamanAktif scope tanaman yang dihitung di statistik (status Active)
+func tanamanAktif(q *gorm.DB) *gorm.DB {
+	return q.Where("status = ?", config.TanamanStatusActive)
+}
+
+// ensureTanamanRecordable tolak pencatatan fase / penyakit untuk tanaman yang sudah dicabut / diganti
+func ensureTanamanRecordable(c *gin.Context, db *gorm.DB, tanamanID uint) bool {
+	var tanaman models.Tanaman
+	if err := db.Select("id", "status").First(&tanaman, tanamanID).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil tanaman", err.Error())
+		return false
+	}
+	if !config.TanamanStatusRecordable(tanaman.Status) {
+		utils.ErrorResponse(c, http.StatusConflict, "Tanaman berstatus "+tanaman.Status+", tidak bisa dicatat lagi", gin.H{"tanaman_id": tanamanID, "status": tanaman.Status})
+		return false
+	}
+	return true
+}
+
+func parseStatusTanggal(raw string) (time.Time, error) {
+	if raw == "" {
+		return time.Now(), nil
+	}
+	t, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("format tanggal harus YYYY-MM-DD")
+	}
+	if t.After(time.Now()) {
+		return time.Time{}, fmt.Errorf("tanggal tidak boleh di masa depan")
+	}
+	return t, nil
+}
+
+// countTanamanBooking booking open yang masih menahan tanaman; fulfilled / cancelled tidak dihitung
+func countTanamanBooking(db *gorm.DB, tanamanID uint) int64 {
+	var count int64
+	db.Model(&models.Booking{}).Scopes(config.BookingOpen).Where("tanaman_id = ?", tanamanID).Count(&count)
+	return count
+}
+
+// nextReplantKode <kode dasar>-R<n> pertama yang belum dipakai di kebun
+func nextReplantKode(db *gorm.DB, t *models.Tanaman) string {
+	base := t.KodeTanaman
+	if i := strings.LastIndex(base, "-R"); i > 0 {
+		if _, err := strconv.Atoi(base[i+2:]); err == nil {
+			base = base[:i]
+		}
+	}
+	for n := 2; ; n++ {
+		suffix := fmt.Sprintf("-R%d", n)
+		kode := base + suffix
+		if len(kode) > 50 {
+			kode = base[:50-len(suffix)] + suffix
+		}
+		if !kodeTanamanTaken(db, t.KebunID, kode, 0) {
+			return kode
+		}
+	}
+}
+
+// UpdateTanamanStatus godoc
+// @Summary Ubah status tanaman
+// @Description Active <-> Dormant, Active / Dormant -> Removed. Tanaman Removed tetap tersimpan beserta riwayatnya,
+// @Description tidak dihitung di statistik, dan tidak bisa dicatat fase baru. Removed ditolak jika masih ada booking.
+// @Description Untuk mengganti pohon mati gunakan POST /tanaman/{id}/replant.
+// @Tags Tanaman
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "ID Tanaman"
+// @Param body body UpdateTanamanStatusRequest true "Status baru"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /tanaman/{id}/status [put]
+func UpdateTanamanStatus(c *gin.Context) {
+	var req UpdateTanamanStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
+		return
+	}
+	tanggal, err := parseStatusTanggal(req.Tanggal)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "tanggal tidak valid", err.Error())
+		return
+	}
+
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	var tanaman models.Tanaman
+	if err := db.First(&tanaman, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "Tanaman tidak ditemukan", nil)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil tanaman", err.Error())
+		return
+	}
+	if !authorizeKebun(c, db, tanaman.KebunID, config.KebunActTanamanWrite) {
+		return
+	}
+
+	if !config.TanamanStatusCanChange(tanaman.Status, req.Status) {
+		utils.ErrorResponse(c, http.StatusConflict, fmt.Sprintf("Status tidak bisa diubah dari %s ke %s", tanaman.Status, req.Status), nil)
+		return
+	}
+	if req.Status == config.TanamanStatusRemoved {
+		if n := countTanamanBooking(db, tanaman.ID); n > 0 {
+			utils.ErrorResponse(c, http.StatusConflict, "Tanaman masih punya booking aktif", gin.H{"jumlah_booking": n})
+			return
+		}
+	}
+
+	tanaman.Status = req.Status
+	tanaman.StatusSejak = &tanggal
+	tanaman.StatusAlasan = strings.TrimSpace(req.Alasan)
+	if err := db.Save(&tanaman).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menyimpan status", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Status tanaman menjadi "+tanaman.Status, tanaman)
+}
+
+// ReplantTanaman godoc
+// @Summary Tanam ulang
+// @Description Menandai tanaman Replanted dan membuat tanaman penerus di kebun, blok, dan posisi yang sama.
+// @Description Penerus menyimpan tanaman_sebelumnya_id. Data yang tidak diisi mengikuti pendahulu.
+// @Tags Tanaman
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "ID Tanaman pendahulu"
+// @Param body body ReplantTanamanRequest true "Data tanaman penerus"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /tanaman/{id}/replant [post]
+func ReplantTanaman(c *gin.Context) {
+	var req ReplantTanamanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
+		return
+	}
+
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	var lama models.Tanaman
+	if err := db.First(&lama, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "Tanaman tidak ditemukan", nil)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil tanaman", err.Error())
+		return
+	}
+	if !authorizeKebun(c, db, lama.KebunID, config.KebunActTanamanWrite) {
+		return
+	}
+	if !config.TanamanStatusReplantable(lama.Status) {
+		utils.ErrorResponse(c, http.StatusConflict, "Tanaman sudah pernah ditanam ulang", gin.H{"status": lama.Status})
+		return
+	}
+	if n := countTanamanBooking(db, lama.ID); n > 0 {
+		utils.ErrorResponse(c, http.StatusConflict, "Tanaman masih punya booking aktif", gin.H{"jumlah_booking": n})
+		return
+	}
+
+	tanggalTanam := time.Now().Truncate(24 * time.Hour)
+	if req.TanggalTanam != "" {
+		if tanggalTanam, err = parseAndValidateTanggalTanam(req.TanggalTanam); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "tanggal_tanam tidak valid", err.Error())
+			return
+		}
+		if tanggalTanam.Before(lama.TanggalTanam) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "tanggal_tanam tidak boleh sebelum tanggal tanam pendahulu", nil)
+			return
+		}
+	}
+
+	baru := models.Tanaman{
+		NamaTanaman:         lama.NamaTanaman,
+		VarietasID:          lama.VarietasID,
+		TanggalTanam:        tanggalTanam,
+		KebunID:             lama.KebunID,
+		BlokID:              lama.BlokID,
+		KodeBlok:            lama.KodeBlok,
+		MasaProduksi:        lama.MasaProduksi,
+		Latitude:            lama.Latitude,
+		Longitude:           lama.Longitude,
+		Baris:               lama.Baris,
+		Kolom:               lama.Kolom,
+		Status:              config.TanamanStatusActive,
+		StatusSejak:         &tanggalTanam,
+		TanamanSebelumnyaID: &lama.ID,
+	}
+	if nama := strings.TrimSpace(req.NamaTanaman); nama != "" {
+		baru.NamaTanaman = nama
+	}
+	if req.VarietasID != "" || req.Varietas != "" {
+		varietas, msg := resolveVarietas(db, req.VarietasID, req.Varietas)
+		if msg != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, *msg, gin.H{"varietas_id": req.VarietasID, "varietas": req.Varietas})
+			return
+		}
+		baru.VarietasID = &varietas.ID
+	}
+	if req.MasaProduksi < 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "masa_produksi harus angka > 0", req.MasaProduksi)
+		return
+	}
+	if req.MasaProduksi > 0 {
+		baru.MasaProduksi = req.MasaProduksi
+	}
+
+	baru.KodeTanaman = strings.TrimSpace(req.KodeTanaman)
+	if baru.KodeTanaman == "" {
+		baru.KodeTanaman = nextReplantKode(db, &lama)
+	} else if kodeTanamanTaken(db, lama.KebunID, baru.KodeTanaman, 0) {
+		utils.ErrorResponse(c, http.StatusConflict, "kode_tanaman sudah dipakai di kebun ini", baru.KodeTanaman)
+		return
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// pendahulu dilepas dari posisi dulu supaya index posisi aktif tidak bentrok
+		lama.Status = config.TanamanStatusReplanted
+		lama.StatusSejak = &tanggalTanam
+		if alasan := strings.TrimSpace(req.Alasan); alasan != "" {
+			lama.StatusAlasan = alasan
+		}
+		if err := tx.Save(&lama).Error; err != nil {
+			return err
+		}
+		return tx.Create(&baru).Error
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal tanam ulang", err.Error())
+		return
+	}
+
+	if err := db.Preload("Kebun").Preload("Varietas").Preload("Blok").Preload("TanamanSebelumnya").First(&baru, baru.ID).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal reload tanaman", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Tanaman berhasil ditanam ulang", baru)
+}
+
+// GetTanamanGenerasi godoc
+// @Summary Riwayat tanam ulang
+// @Description Rantai tanaman di posisi yang sama, dari generasi pertama sampai penerus terakhir
+// @Tags Tanaman
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Tanaman"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tanaman/{id}/generasi [get]
+func GetTanamanGenerasi(c *gin.Context) {
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	tanaman, ok := tanamanFromParam(c, db)
+	if !ok {
+		return
+	}
+
+	// naik ke generasi pertama lalu turun ke penerus terakhir, seen mencegah loop jika data rusak
+	seen := map[uint]bool{tanaman.ID: true}
+	first := *tanaman
+	for first.TanamanSebelumnyaID != nil && !seen[*first.TanamanSebelumnyaID] {
+		var prev models.Tanaman
+		if err := db.First(&prev, *first.TanamanSebelumnyaID).Error; err != nil {
+			break
+		}
+		seen[prev.ID] = true
+		first = prev
+	}
+
+	generasi := []models.Tanaman{first}
+	seen = map[uint]bool{first.ID: true}
+	for {
+		var next models.Tanaman
+		if err := db.Where("tanaman_sebelumnya_id = ?", generasi[len(generasi)-1].ID).Limit(1).Find(&next).Error; err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil penerus", err.Error())
+			return
+		}
+		if next.ID == 0 || seen[next.ID] {
+			break
+		}
+		seen[next.ID] = true
+		generasi = append(generasi, next)
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Riwayat generasi tanaman", generasi)
+}
@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"Avocycle/config"
+	"Avocycle/models"
+	"Avocycle/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// tenantFilter batas data yang boleh dilihat user login: kebun di organisasi tempat dia
+// menjadi anggota + kebun tempat dia menjadi anggota langsung. Admin melihat semua.
+// Query ?organization_id= mempersempit ke satu organisasi.
+// Pemegang kebun:write juga melihat kebun lama tanpa anggota & organisasi (lihat kebunRoleOf).
+type tenantFilter struct {
+	db     *gorm.DB
+	all    bool
+	userID uint
+	orgID  *uint
+	legacy bool
+	// deleted ikut menghitung kebun / tanaman yang sudah di trash (dipakai listing trash)
+	deleted bool
+}
+
+// organizationRoleOf peran user di organisasi, "" jika bukan anggota
+func organizationRoleOf(db *gorm.DB, orgID, userID uint) string {
+	var member models.OrganizationMember
+	if err := db.Where("organization_id = ? AND user_id = ?", orgID, userID).First(&member).Error; err != nil {
+		return ""
+	}
+	return member.Role
+}
+
+// resolveTenant membaca claims dan ?organization_id. Mengembalikan false jika response error sudah dikirim.
+func resolveTenant(c *gin.Context, db *gorm.DB) (*tenantFilter, bool) {
+	claims := currentClaims(c)
+	if claims == nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Token tidak ditemukan", nil)
+		return nil, false
+	}
+
+	t := &tenantFilter{
+		db:     db,
+		all:    claims.Role == "Admin",
+		userID: claims.UserID,
+		legacy: config.HasPermission(claims.Role, config.PermKebunWrite),
+	}
+
+	if raw := c.Query("organization_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || id == 0 {
+			utils.ErrorResponse(c, http.StatusBadRequest, "organization_id tidak valid", raw)
+			return nil, false
+		}
+		orgID := uint(id)
+		if !t.all && organizationRoleOf(db, orgID, claims.UserID) == "" {
+			utils.ErrorResponse(c, http.StatusForbidden, "Anda bukan anggota organisasi ini", gin.H{"organization_id": orgID})
+			return nil, false
+		}
+		t.orgID = &orgID
+	}
+
+	return t, true
+}
+
+// resolveCatalog seperti resolveTenant, tapi pembeli (booking tanpa kebun:write) melihat seluruh
+// kebun & tanaman sebagai katalog read-only. Hanya untuk listing / detail kebun dan tanaman.
+func resolveCatalog(c *gin.Context, db *gorm.DB) (*tenantFilter, bool) {
+	t, ok := resolveTenant(c, db)
+	if !ok {
+		return nil, false
+	}
+	role := currentClaims(c).Role
+	if config.HasPermission(role, config.PermBookingRead) && !config.HasPermission(role, config.PermKebunWrite) {
+		t.all = true
+	}
+	return t, true
+}
+
+// unrestricted true jika tidak perlu filter sama sekali (Admin tanpa organization_id)
+func (t *tenantFilter) unrestricted() bool {
+	return t.all && t.orgID == nil
+}
+
+// withDeleted salinan filter yang juga mencakup kebun / tanaman di trash
+func (t *tenantFilter) withDeleted() *tenantFilter {
+	cp := *t
+	cp.deleted = true
+	return &cp
+}
+
+// kebunIDs subquery id kebun yang boleh dilihat
+func (t *tenantFilter) kebunIDs() *gorm.DB {
+	q := t.db.Model(&models.Kebun{}).Select("id")
+	if t.deleted {
+		q = q.Unscoped()
+	}
+	if t.orgID != nil {
+		return q.Where("organization_id = ?", *t.orgID)
+	}
+	cond := "organization_id IN (?) OR id IN (?)"
+	if t.legacy {
+		cond += " OR (organization_id IS NULL AND NOT EXISTS " +
+			"(SELECT 1 FROM kebun_members km WHERE km.kebun_id = kebuns.id AND km.deleted_at IS NULL))"
+	}
+	return q.Where(cond,
+		t.db.Model(&models.OrganizationMember{}).Select("organization_id").Where("user_id = ?", t.userID),
+		t.db.Model(&models.KebunMember{}).Select("kebun_id").Where("user_id = ?", t.userID),
+	)
+}
+
+// tanamanIDs subquery id tanaman di kebun yang boleh dilihat
+func (t *tenantFilter) tanamanIDs() *gorm.DB {
+	q := t.db.Model(&models.Tanaman{}).Select("id")
+	if t.deleted {
+		q = q.Unscoped()
+	}
+	return q.Where("kebun_id IN (?)", t.kebunIDs())
+}
+
+// ByKebun scope untuk tabel yang punya kolom kebun (contoh: "kebun_id", atau "id" untuk tabel kebun)
+func (t *tenantFilter) ByKebun(column string) func(*gorm.DB) *gorm.DB {
+	return func(q *gorm.DB) *gorm.DB {
+		if t.unrestricted() {
+			return q
+		}
+		return q.Where(column+" IN (?)", t.kebunIDs())
+	}
+}
+
+// ByTanaman scope untuk tabel yang punya kolom tanaman (fase, log penyakit, buah)
+func (t *tenantFilter) ByTanaman(column string) func(*gorm.DB) *gorm.DB {
+	return func(q *gorm.DB) *gorm.DB {
+		if t.unrestricted() {
+			return q
+		}
+		return q.Where(column+" IN (?)", t.tanamanIDs())
+	}
+}
@@ -0,0 +1,294 @@
+package controllers
+
+import (
+	"Avocycle/config"
+	"Avocycle/models"
+	"Avocycle/utils"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// aksi kebun yang dibutuhkan untuk restore / purge, sama dengan aksi hapus tiap entitas
+var trashKebunActions = map[string]config.KebunAction{
+	"kebun":        config.KebunActDelete,
+	"blok":         config.KebunActUpdate,
+	"tanaman":      config.KebunActTanamanDelete,
+	"fase-bunga":   config.KebunActFaseWrite,
+	"fase-berbuah": config.KebunActFaseWrite,
+	"fase-panen":   config.KebunActFaseWrite,
+	"log-penyakit": config.KebunActPenyakitClassify,
+	"buah":         config.KebunActTanamanWrite,
+}
+
+// trashEntityFromParam ambil entitas dari :entity, false jika response error sudah dikirim
+func trashEntityFromParam(c *gin.Context) (*config.TrashEntity, bool) {
+	entity, ok := config.TrashEntityByName(c.Param("entity"))
+	if !ok {
+		names := make([]string, 0, len(config.TrashEntities))
+		for _, e := range config.TrashEntities {
+			names = append(names, e.Name)
+		}
+		utils.ErrorResponse(c, http.StatusNotFound, "Entitas trash tidak dikenal", gin.H{"entity": c.Param("entity"), "tersedia": names})
+		return nil, false
+	}
+	return entity, true
+}
+
+// trashScope batasi listing trash ke data milik tenant user
+func trashScope(c *gin.Context, tenant *tenantFilter, entity *config.TrashEntity) func(*gorm.DB) *gorm.DB {
+	tenant = tenant.withDeleted()
+	switch {
+	case entity.Name == "booking":
+		claims := currentClaims(c)
+		return func(q *gorm.DB) *gorm.DB {
+			if claims.Role == "Admin" {
+				return q
+			}
+			return q.Where("user_id = ?", claims.UserID)
+		}
+	case entity.ParentEntity == "":
+		return tenant.ByKebun("id")
+	case entity.ParentEntity == "kebun":
+		return tenant.ByKebun(entity.ParentColumn)
+	default:
+		return tenant.ByTanaman(entity.ParentColumn)
+	}
+}
+
+// authorizeTrash cek hak restore / purge record di trash, false jika response error sudah dikirim
+func authorizeTrash(c *gin.Context, db *gorm.DB, entity *config.TrashEntity, id uint) bool {
+	if entity.Name == "booking" {
+		claims := currentClaims(c)
+		var booking models.Booking
+		if err := db.Unscoped().Select("id", "user_id").First(&booking, id).Error; err != nil {
+			utils.ErrorResponse(c, http.StatusNotFound, "Booking tidak ada di trash", nil)
+			return false
+		}
+		if claims.Role != "Admin" && booking.UserID != claims.UserID {
+			utils.ErrorResponse(c, http.StatusForbidden, "Booking ini bukan milik Anda", nil)
+			return false
+		}
+		return true
+	}
+
+	var kebunID uint
+	var err error
+	switch entity.ParentEntity {
+	case "":
+		kebunID = id
+	case "kebun":
+		err = db.Unscoped().Model(entity.New()).Select(entity.ParentColumn).Where("id = ?", id).Scan(&kebunID).Error
+	default:
+		err = db.Unscoped().Model(&models.Tanaman{}).Select("kebun_id").
+			Where("id = (?)", db.Unscoped().Model(entity.New()).Select(entity.ParentColumn).Where("id = ?", id)).
+			Scan(&kebunID).Error
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil data "+entity.Label, err.Error())
+		return false
+	}
+	if kebunID == 0 {
+		utils.ErrorResponse(c, http.StatusNotFound, entity.Label+" tidak ada di trash", nil)
+		return false
+	}
+
+	return authorizeKebun(c, db, kebunID, trashKebunActions[entity.Name])
+}
+
+func trashIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ID tidak valid", c.Param("id"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// GetTrash godoc
+// @Summary Daftar data di trash
+// @Description Record yang sudah dihapus (soft delete) dan masih bisa di-restore sebelum purge otomatis.
+// @Description entity: kebun, blok, tanaman, fase-bunga, fase-berbuah, fase-panen, log-penyakit, buah, booking
+// @Tags Trash
+// @Security Bearer
+// @Produce json
+// @Param entity path string true "Jenis entitas"
+// @Param organization_id query int false "Filter satu organisasi"
+// @Param page query int false "Halaman"
+// @Param per_page query int false "Jumlah data per halaman"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /trash/{entity} [get]
+func GetTrash(c *gin.Context) {
+	entity, ok := trashEntityFromParam(c)
+	if !ok {
+		return
+	}
+
+	page, perPage := utils.GetPagination(c)
+	offset := utils.GetOffset(page, perPage)
+
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	tenant, ok := resolveTenant(c, db)
+	if !ok {
+		return
+	}
+
+	query := db.Unscoped().Model(entity.New()).Where("deleted_at IS NOT NULL").Scopes(trashScope(c, tenant, entity))
+
+	var totalRows int64
+	if err := query.Count(&totalRows).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menghitung trash", err.Error())
+		return
+	}
+
+	pagination := utils.CalculatePagination(page, perPage, totalRows)
+
+	records := entity.NewSlice()
+	if err := query.Order("deleted_at DESC").Limit(perPage).Offset(offset).Find(records).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal mengambil trash", err.Error())
+		return
+	}
+
+	utils.SuccessResponseWithMeta(c, http.StatusOK, "Trash "+entity.Label, gin.H{
+		"records":        records,
+		"retention_days": int(config.TrashRetention().Hours() / 24),
+	}, pagination)
+}
+
+// RestoreTrash godoc
+// @Summary Restore data dari trash
+// @Description Mengembalikan record beserta data turunannya yang terhapus bersamaan
+// @Description (contoh: restore kebun ikut mengembalikan tanaman & fase yang terhapus bersama kebun).
+// @Description Gagal 409 jika induknya masih di trash atau nilai uniknya (contoh kode_tanaman) sudah dipakai record aktif.
+// @Tags Trash
+// @Security Bearer
+// @Produce json
+// @Param entity path string true "Jenis entitas"
+// @Param id path int true "ID record"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /trash/{entity}/{id}/restore [post]
+func RestoreTrash(c *gin.Context) {
+	entity, ok := trashEntityFromParam(c)
+	if !ok {
+		return
+	}
+	id, ok := trashIDParam(c)
+	if !ok {
+		return
+	}
+
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	if !authorizeTrash(c, db, entity, id) {
+		return
+	}
+
+	if err := config.RestoreTrashed(db, entity, id); err != nil {
+		switch {
+		case errors.Is(err, config.ErrTrashNotFound):
+			utils.ErrorResponse(c, http.StatusNotFound, entity.Label+" tidak ada di trash", nil)
+		case errors.Is(err, config.ErrTrashParentDeleted):
+			utils.ErrorResponse(c, http.StatusConflict, err.Error(), gin.H{"parent": entity.ParentEntity})
+		case errors.Is(err, config.ErrTrashConflict):
+			utils.ErrorResponse(c, http.StatusConflict, err.Error(), gin.H{"entity": entity.Name, "id": id})
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal restore "+entity.Label, err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, entity.Label+" berhasil di-restore", gin.H{"entity": entity.Name, "id": id})
+}
+
+// PurgeTrash godoc
+// @Summary Hapus permanen data di trash
+// @Description Menghapus permanen record beserta turunan yang ikut di-trash bersamanya dan fotonya di Cloudinary.
+// @Description Tidak bisa dibatalkan. Gagal 409 jika masih ada turunan yang aktif atau di-trash terpisah.
+// @Tags Trash
+// @Security Bearer
+// @Produce json
+// @Param entity path string true "Jenis entitas"
+// @Param id path int true "ID record"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /trash/{entity}/{id} [delete]
+func PurgeTrash(c *gin.Context) {
+	entity, ok := trashEntityFromParam(c)
+	if !ok {
+		return
+	}
+	id, ok := trashIDParam(c)
+	if !ok {
+		return
+	}
+
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	if !authorizeTrash(c, db, entity, id) {
+		return
+	}
+
+	if err := config.PurgeTrashed(db, entity, id); err != nil {
+		switch {
+		case errors.Is(err, config.ErrTrashNotFound):
+			utils.ErrorResponse(c, http.StatusNotFound, entity.Label+" tidak ada di trash", nil)
+		case errors.Is(err, config.ErrTrashChildrenRemain):
+			utils.ErrorResponse(c, http.StatusConflict, err.Error(), gin.H{"entity": entity.Name, "id": id})
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal hapus permanen "+entity.Label, err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, entity.Label+" berhasil dihapus permanen", utils.EmptyObj{})
+}
+
+// respondCascadeDelete hapus record beserta turunannya (lihat config.CascadeSoftDelete).
+// ?dry_run=true hanya mengembalikan dampak penghapusan tanpa mengubah data.
+func respondCascadeDelete(c *gin.Context, db *gorm.DB, entityName string, id uint) {
+	entity, _ := config.TrashEntityByName(entityName)
+
+	if c.Query("dry_run") == "true" {
+		report, err := config.PlanCascadeDelete(db, entity, id)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menghitung dampak hapus "+entity.Label, err.Error())
+			return
+		}
+		utils.SuccessResponse(c, http.StatusOK, "Dry run hapus "+entity.Label, report)
+		return
+	}
+
+	report, err := config.CascadeSoftDelete(db, entity, id)
+	if err != nil {
+		if errors.Is(err, config.ErrCascadeBlocked) {
+			utils.ErrorResponse(c, http.StatusConflict, entity.Label+" tidak bisa dihapus, "+err.Error(), report)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal hapus "+entity.Label, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, entity.Label+" berhasil dihapus", report)
+}
@@ -0,0 +1,499 @@
+package controllers
+
+import (
+	"Avocycle/config"
+	"Avocycle/models"
+	"Avocycle/utils"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	twoFactorIssuer        = "Avocycle"
+	recoveryCodeCount      = 10
+	errTwoFactorCodeFormat = "code atau recovery_code wajib diisi"
+)
+
+// TwoFactorCodeRequest body untuk enable / regenerate recovery codes
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" example:"123456"`
+}
+
+// TwoFactorDisableRequest body untuk mematikan 2FA
+type TwoFactorDisableRequest struct {
+	Password     string `json:"password" example:"secret123"`
+	Code         string `json:"code" example:"123456"`
+	RecoveryCode string `json:"recovery_code" example:"ABCDE-FGHIJ"`
+}
+
+// TwoFactorLoginRequest body langkah kedua login
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" example:"eyJhbGciOi..."`
+	Code           string `json:"code" example:"123456"`
+	RecoveryCode   string `json:"recovery_code" example:"ABCDE-FGHIJ"`
+}
+
+// verifySecondFactor mengecek kode TOTP (dengan anti replay) atau recovery code sekali pakai.
+// Update dilakukan bersyarat di DB supaya aman walau ada beberapa replika.
+func verifySecondFactor(db *gorm.DB, twoFactor *models.UserTwoFactor, code, recoveryCode string) error {
+	if code != "" {
+		step, ok := utils.ValidateTOTP(twoFactor.Secret, code, time.Now())
+		if !ok {
+			return errors.New("kode 2FA salah")
+		}
+
+		res := db.Model(&models.UserTwoFactor{}).
+			Where("id = ? AND last_used_step < ?", twoFactor.ID, step).
+			Update("last_used_step", step)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errors.New("kode 2FA sudah dipakai")
+		}
+		twoFactor.LastUsedStep = step
+		return nil
+	}
+
+	if recoveryCode != "" {
+		now := time.Now()
+		res := db.Model(&models.TwoFactorRecoveryCode{}).
+			Where("user_id = ? AND code_hash = ? AND used_at IS NULL", twoFactor.UserID, utils.HashRecoveryCode(recoveryCode)).
+			Update("used_at", &now)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errors.New("recovery code tidak valid atau sudah dipakai")
+		}
+		return nil
+	}
+
+	return errors.New(errTwoFactorCodeFormat)
+}
+
+// twoFactorChallengeFor challenge token jika user mengaktifkan 2FA, "" jika tidak.
+// Dipakai semua jalur login (password, OAuth) sebelum JWT penuh diterbitkan.
+func twoFactorChallengeFor(db *gorm.DB, user *models.User) (string, error) {
+	var twoFactor models.UserTwoFactor
+	err := db.Where("user_id = ? AND enabled = ?", user.ID, true).First(&twoFactor).Error
+	if err == gorm.ErrRecordNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return utils.GenerateTwoFactorChallenge(user)
+}
+
+// replaceRecoveryCodes menghapus kode lama dan menyimpan hash kode baru
+func replaceRecoveryCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	codes, err := utils.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.TwoFactorRecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	rows := make([]models.TwoFactorRecoveryCode, 0, len(codes))
+	for _, code := range codes {
+		rows = append(rows, models.TwoFactorRecoveryCode{UserID: userID, CodeHash: utils.HashRecoveryCode(code)})
+	}
+	if err := tx.Create(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// GetTwoFactorStatus godoc
+// @Summary Status 2FA
+// @Description Status 2FA user yang sedang login dan sisa recovery code
+// @Tags Two Factor
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /auth/2fa [get]
+func GetTwoFactorStatus(c *gin.Context) {
+	claims := currentClaims(c)
+
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	var twoFactor models.UserTwoFactor
+	enabled := db.Where("user_id = ? AND enabled = ?", claims.UserID, true).First(&twoFactor).Error == nil
+
+	var remaining int64
+	if enabled {
+		db.Model(&models.TwoFactorRecoveryCode{}).
+			Where("user_id = ? AND used_at IS NULL", claims.UserID).
+			Count(&remaining)
+	}
+
+	var policy models.SecurityPolicy
+	db.Order("id ASC").First(&policy)
+
+	utils.SuccessResponse(c, http.StatusOK, "Status 2FA", gin.H{
+		"enabled":                  enabled,
+		"enabled_at":               twoFactor.EnabledAt,
+		"recovery_codes_remaining": remaining,
+		"required":                 claims.Role == "Admin" && policy.Require2FAAdmin,
+		"session_verified":         claims.MFA,
+	})
+}
+
+// SetupTwoFactor godoc
+// @Summary Mulai enrollment 2FA
+// @Description Membuat secret TOTP baru dan provisioning URI (otpauth://) untuk ditampilkan sebagai QR. 2FA belum aktif sampai /auth/2fa/enable berhasil.
+// @Tags Two Factor
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /auth/2fa/setup [post]
+func SetupTwoFactor(c *gin.Context) {
+	claims := currentClaims(c)
+
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	var twoFactor models.UserTwoFactor
+	err = db.Where("user_id = ?", claims.UserID).First(&twoFactor).Error
+	if err == nil && twoFactor.Enabled {
+		utils.ErrorResponse(c, http.StatusConflict, "2FA sudah aktif, nonaktifkan dulu untuk enroll ulang", nil)
+		return
+	} else if err != nil && err != gorm.ErrRecordNotFound {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil data 2FA", err.Error())
+		return
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal membuat secret 2FA", err.Error())
+		return
+	}
+
+	twoFactor.UserID = claims.UserID
+	twoFactor.Secret = secret
+	twoFactor.Enabled = false
+	twoFactor.LastUsedStep = 0
+	if err := db.Save(&twoFactor).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menyimpan secret 2FA", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Scan QR lalu verifikasi kode di /auth/2fa/enable", gin.H{
+		"secret":           secret,
+		"provisioning_uri": utils.TOTPProvisioningURI(secret, claims.Email, twoFactorIssuer),
+	})
+}
+
+// EnableTwoFactor godoc
+// @Summary Aktifkan 2FA
+// @Description Verifikasi kode pertama dari aplikasi authenticator, aktifkan 2FA dan kembalikan recovery codes (hanya ditampilkan sekali)
+// @Tags Two Factor
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body controllers.TwoFactorCodeRequest true "Kode TOTP"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /auth/2fa/enable [post]
+func EnableTwoFactor(c *gin.Context) {
+	claims := currentClaims(c)
+
+	var input struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
+		return
+	}
+
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	var twoFactor models.UserTwoFactor
+	if err := db.Where("user_id = ?", claims.UserID).First(&twoFactor).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Jalankan /auth/2fa/setup terlebih dahulu", nil)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil data 2FA", err.Error())
+		return
+	}
+
+	if twoFactor.Enabled {
+		utils.ErrorResponse(c, http.StatusConflict, "2FA sudah aktif", nil)
+		return
+	}
+
+	if err := verifySecondFactor(db, &twoFactor, input.Code, ""); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Kode 2FA tidak valid", err.Error())
+		return
+	}
+
+	var codes []string
+	err = db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(&twoFactor).Updates(map[string]interface{}{
+			"enabled":    true,
+			"enabled_at": &now,
+		}).Error; err != nil {
+			return err
+		}
+
+		var err error
+		codes, err = replaceRecoveryCodes(tx, claims.UserID)
+		return err
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal mengaktifkan 2FA", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "2FA aktif. Simpan recovery codes di tempat aman", gin.H{
+		"recovery_codes": codes,
+	})
+}
+
+// DisableTwoFactor godoc
+// @Summary Nonaktifkan 2FA
+// @Description Mematikan 2FA. Butuh password (akun lokal) dan kode TOTP atau recovery code. Admin tidak bisa mematikan 2FA jika policy mewajibkan.
+// @Tags Two Factor
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body controllers.TwoFactorDisableRequest true "Konfirmasi"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /auth/2fa/disable [post]
+func DisableTwoFactor(c *gin.Context) {
+	claims := currentClaims(c)
+
+	var input TwoFactorDisableRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
+		return
+	}
+
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	var policy models.SecurityPolicy
+	db.Order("id ASC").First(&policy)
+	if claims.Role == "Admin" && policy.Require2FAAdmin {
+		utils.ErrorResponse(c, http.StatusForbidden, "2FA wajib untuk Admin dan tidak bisa dinonaktifkan", nil)
+		return
+	}
+
+	var user models.User
+	if err := db.First(&user, claims.UserID).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "User tidak ditemukan", nil)
+		return
+	}
+
+	if user.AuthProvider == "Local" {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(input.Password)); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Password salah", nil)
+			return
+		}
+	}
+
+	var twoFactor models.UserTwoFactor
+	if err := db.Where("user_id = ? AND enabled = ?", claims.UserID, true).First(&twoFactor).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "2FA belum aktif", nil)
+		return
+	}
+
+	if err := verifySecondFactor(db, &twoFactor, input.Code, input.RecoveryCode); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Kode 2FA tidak valid", err.Error())
+		return
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("user_id = ?", claims.UserID).Delete(&models.TwoFactorRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&twoFactor).Error
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menonaktifkan 2FA", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "2FA dinonaktifkan", utils.EmptyObj{})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Buat ulang recovery codes
+// @Description Mengganti semua recovery code lama dengan yang baru (butuh kode TOTP)
+// @Tags Two Factor
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body controllers.TwoFactorCodeRequest true "Kode TOTP"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /auth/2fa/recovery-codes [post]
+func RegenerateRecoveryCodes(c *gin.Context) {
+	claims := currentClaims(c)
+
+	var input struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
+		return
+	}
+
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	var twoFactor models.UserTwoFactor
+	if err := db.Where("user_id = ? AND enabled = ?", claims.UserID, true).First(&twoFactor).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "2FA belum aktif", nil)
+		return
+	}
+
+	if err := verifySecondFactor(db, &twoFactor, input.Code, ""); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Kode 2FA tidak valid", err.Error())
+		return
+	}
+
+	var codes []string
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		codes, err = replaceRecoveryCodes(tx, claims.UserID)
+		return err
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal membuat recovery codes", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Recovery codes baru dibuat", gin.H{
+		"recovery_codes": codes,
+	})
+}
+
+// VerifyLoginTwoFactor godoc
+// @Summary Login langkah kedua (2FA)
+// @Description Tukar challenge_token dari /login dengan JWT penuh menggunakan kode TOTP atau recovery code
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body controllers.TwoFactorLoginRequest true "Challenge + kode"
+// @Success 200 {object} map[string]interface{} "Login success with JWT"
+// @Failure 400 {object} map[string]interface{} "Invalid input"
+// @Failure 401 {object} map[string]interface{} "Challenge atau kode salah"
+// @Failure 429 {object} map[string]interface{} "Akun atau IP terkunci sementara"
+// @Router /login/2fa [post]
+func VerifyLoginTwoFactor(c *gin.Context) {
+	var input struct {
+		ChallengeToken string `json:"challenge_token" binding:"required"`
+		Code           string `json:"code"`
+		RecoveryCode   string `json:"recovery_code"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid input data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	challenge, err := utils.ParseTwoFactorChallenge(input.ChallengeToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "Invalid or expired challenge token",
+		})
+		return
+	}
+
+	db, err := config.DbConnect()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to connect to database"})
+		return
+	}
+
+	var user models.User
+	if err := db.First(&user, challenge.UserID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "Invalid or expired challenge token",
+		})
+		return
+	}
+
+	clientIP := c.ClientIP()
+	if remaining, locked := checkLoginLock(db, user.Email, clientIP); locked {
+		respondLoginLocked(c, remaining)
+		return
+	}
+
+	var twoFactor models.UserTwoFactor
+	if err := db.Where("user_id = ? AND enabled = ?", user.ID, true).First(&twoFactor).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "Two-factor authentication is not enabled",
+		})
+		return
+	}
+
+	if err := verifySecondFactor(db, &twoFactor, input.Code, input.RecoveryCode); err != nil {
+		recordLoginFailure(db, user.Email, clientIP, &user.ID)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	token, err := utils.GenerateJWTWithMFA(&user, true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to Generate token"})
+		return
+	}
+
+	resetLoginFailures(db, user.Email)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Login successful",
+		"data": gin.H{
+			"id":            user.ID,
+			"full_name":     user.FullName,
+			"email":         user.Email,
+			"phone":         user.Phone,
+			"role":          user.Role,
+			"auth_provider": user.AuthProvider,
+		},
+		"token": token,
+	})
+}
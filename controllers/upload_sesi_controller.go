@@ -0,0 +1,418 @@
+package controllers
+
+import (
+	"Avocycle/config"
+	"Avocycle/models"
+	"Avocycle/utils"
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateUploadSesiRequest mulai upload foto bertahap ke galeri sebuah record
+type CreateUploadSesiRequest struct {
+	OwnerType    string `json:"owner_type" binding:"required" example:"tanaman"`
+	OwnerID      uint   `json:"owner_id" binding:"required" example:"12"`
+	Filename     string `json:"filename" example:"IMG_0421.jpg"`
+	Ukuran       int64  `json:"ukuran" binding:"required,min=1" example:"4194304"` // byte
+	Caption      string `json:"caption" example:"Buah pertama musim ini"`
+	TakenAt      string `json:"taken_at" example:"2025-01-31T08:30:00+07:00"` // RFC3339 atau YYYY-MM-DD, default EXIF
+	JadikanUtama bool   `json:"jadikan_utama"`                                // foto langsung menjadi foto utama
+}
+
+// UploadSesiResponse posisi upload, client melanjutkan dari Offset
+type UploadSesiResponse struct {
+	ID        string    `json:"id"`
+	OwnerType string    `json:"owner_type"`
+	OwnerID   uint      `json:"owner_id"`
+	Filename  string    `json:"filename"`
+	Ukuran    int64     `json:"ukuran"`
+	Offset    int64     `json:"offset"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func uploadSesiResponse(c *gin.Context, sesi *models.UploadSesi) UploadSesiResponse {
+	c.Header("Upload-Offset", strconv.FormatInt(sesi.Diterima, 10))
+	return UploadSesiResponse{
+		ID:        sesi.ID,
+		OwnerType: sesi.OwnerType,
+		OwnerID:   sesi.OwnerID,
+		Filename:  sesi.Filename,
+		Ukuran:    sesi.Ukuran,
+		Offset:    sesi.Diterima,
+		ExpiresAt: sesi.ExpiresAt,
+	}
+}
+
+// uploadSesiFromParam sesi milik user yang login dan belum kedaluwarsa
+func uploadSesiFromParam(c *gin.Context, db *gorm.DB) (*models.UploadSesi, bool) {
+	userID := currentUserID(c)
+	if userID == nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return nil, false
+	}
+	var sesi models.UploadSesi
+	err := db.Where("id = ? AND user_id = ? AND expires_at >= ?", c.Param("id"), *userID, time.Now()).First(&sesi).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, "Sesi upload tidak ditemukan atau sudah kedaluwarsa", nil)
+			return nil, false
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil sesi upload", err.Error())
+		return nil, false
+	}
+	return &sesi, true
+}
+
+// uploadOffsetParam offset dari header Upload-Offset atau query ?offset
+func uploadOffsetParam(c *gin.Context) (int64, bool) {
+	raw := c.GetHeader("Upload-Offset")
+	if raw == "" {
+		raw = c.Query("offset")
+	}
+	offset, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+	if err != nil || offset < 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Header Upload-Offset (atau ?offset) wajib diisi angka >= 0", raw)
+		return 0, false
+	}
+	return offset, true
+}
+
+// CreateUploadSesi godoc
+// @Summary Mulai upload foto bertahap
+// @Description Untuk koneksi lambat: file dikirim per potongan lewat PATCH /upload/sesi/{id} dan bisa dilanjutkan
+// @Description dari offset terakhir jika koneksi putus. Setelah potongan terakhir diterima, foto diproses dan masuk galeri.
+// @Description Jumlah sesi yang belum selesai per user dibatasi (UPLOAD_SESSION_MAX_PER_USER, default 5).
+// @Tags Galeri
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param body body CreateUploadSesiRequest true "Data sesi upload"
+// @Success 201 {object} utils.Response{data=UploadSesiResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Router /upload/sesi [post]
+func CreateUploadSesi(c *gin.Context) {
+	var req CreateUploadSesiRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
+		return
+	}
+	if req.Ukuran > utils.MaxUploadSize {
+		utils.ErrorResponse(c, http.StatusBadRequest, utils.ErrUploadTooLarge.Error(), req.Ukuran)
+		return
+	}
+	takenAt, err := parseTakenAt(strings.TrimSpace(req.TakenAt))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "taken_at tidak valid", err.Error())
+		return
+	}
+
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+	userID := currentUserID(c)
+	if userID == nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	if !authorizeMediaOwner(c, db, req.OwnerType, req.OwnerID, mediaOwnerAction(req.OwnerType)) {
+		return
+	}
+
+	id, err := utils.RandomToken(24)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal membuat sesi upload", err.Error())
+		return
+	}
+	sesi := models.UploadSesi{
+		ID:           id,
+		UserID:       *userID,
+		OwnerType:    req.OwnerType,
+		OwnerID:      req.OwnerID,
+		Filename:     utils.SanitizeFilename(req.Filename),
+		Caption:      strings.TrimSpace(req.Caption),
+		TakenAt:      takenAt,
+		JadikanUtama: req.JadikanUtama,
+		Ukuran:       req.Ukuran,
+		ExpiresAt:    time.Now().Add(config.UploadSesiTTL()),
+	}
+	// baris user dikunci supaya dua request bersamaan tidak sama-sama lolos batas sesi terbuka
+	maxSesi := config.UploadSesiMaxPerUser()
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var lockedUser []uint
+		if err := tx.Model(&models.User{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", *userID).Pluck("id", &lockedUser).Error; err != nil {
+			return err
+		}
+		var open int64
+		if err := tx.Model(&models.UploadSesi{}).Where("user_id = ? AND expires_at >= ?", *userID, time.Now()).
+			Count(&open).Error; err != nil {
+			return err
+		}
+		if open >= maxSesi {
+			return errUploadSesiLimit
+		}
+		return tx.Create(&sesi).Error
+	})
+	if errors.Is(err, errUploadSesiLimit) {
+		utils.ErrorResponse(c, http.StatusTooManyRequests,
+			"Terlalu banyak upload bertahap yang belum selesai, selesaikan atau tunggu sesi lama kedaluwarsa",
+			gin.H{"maks": maxSesi})
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal membuat sesi upload", err.Error())
+		return
+	}
+
+	c.Header("Location", "/api/v1/upload/sesi/"+sesi.ID)
+	utils.SuccessResponse(c, http.StatusCreated, "Sesi upload dibuat", uploadSesiResponse(c, &sesi))
+}
+
+// GetUploadSesi godoc
+// @Summary Posisi upload bertahap
+// @Description Dipakai setelah koneksi putus untuk mengetahui offset lanjutan (juga di header Upload-Offset)
+// @Tags Galeri
+// @Security Bearer
+// @Produce json
+// @Param id path string true "ID sesi upload"
+// @Success 200 {object} utils.Response{data=UploadSesiResponse}
+// @Failure 404 {object} utils.Response
+// @Router /upload/sesi/{id} [get]
+func GetUploadSesi(c *gin.Context) {
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+	sesi, ok := uploadSesiFromParam(c, db)
+	if !ok {
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "Sesi upload", uploadSesiResponse(c, sesi))
+}
+
+// UploadSesiChunk godoc
+// @Summary Kirim potongan file
+// @Description Body berisi byte mentah potongan file mulai dari offset (header Upload-Offset atau ?offset), harus sama
+// @Description dengan offset yang sudah diterima server. Potongan yang terputus di tengah tetap disimpan sebagian.
+// @Description Potongan terakhir memproses foto dan memasukkannya ke galeri (201); kirim body kosong di offset akhir
+// @Description untuk mengulang pemrosesan yang gagal.
+// @Tags Galeri
+// @Security Bearer
+// @Accept application/offset+octet-stream
+// @Produce json
+// @Param id path string true "ID sesi upload"
+// @Param Upload-Offset header int false "Offset byte potongan ini"
+// @Param offset query int false "Alternatif header Upload-Offset"
+// @Param chunk body string true "Isi potongan file"
+// @Success 200 {object} utils.Response{data=UploadSesiResponse}
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /upload/sesi/{id} [patch]
+func UploadSesiChunk(c *gin.Context) {
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+	sesi, ok := uploadSesiFromParam(c, db)
+	if !ok {
+		return
+	}
+	offset, ok := uploadOffsetParam(c)
+	if !ok {
+		return
+	}
+	if offset != sesi.Diterima {
+		c.Header("Upload-Offset", strconv.FormatInt(sesi.Diterima, 10))
+		utils.ErrorResponse(c, http.StatusConflict, "Offset tidak sesuai, lanjutkan dari offset server", gin.H{"offset": sesi.Diterima})
+		return
+	}
+
+	// baca langsung dari body request, tidak lewat file sementara
+	remaining := sesi.Ukuran - sesi.Diterima
+	data, readErr := io.ReadAll(io.LimitReader(c.Request.Body, remaining+1))
+	if int64(len(data)) > remaining {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Potongan melebihi ukuran file yang didaftarkan", gin.H{"sisa": remaining})
+		return
+	}
+
+	if len(data) > 0 {
+		// simpan yang sudah diterima walaupun koneksi putus di tengah, client lanjut dari offset baru
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var locked models.UploadSesi
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, "id = ?", sesi.ID).Error; err != nil {
+				return err
+			}
+			if locked.Diterima != offset {
+				sesi.Diterima = locked.Diterima
+				return errUploadOffset
+			}
+			if err := tx.Create(&models.UploadChunk{SesiID: sesi.ID, Offset: offset, Data: data}).Error; err != nil {
+				return err
+			}
+			sesi.Diterima = offset + int64(len(data))
+			sesi.ExpiresAt = time.Now().Add(config.UploadSesiTTL())
+			return tx.Model(&locked).Updates(map[string]interface{}{"diterima": sesi.Diterima, "expires_at": sesi.ExpiresAt}).Error
+		})
+		if errors.Is(err, errUploadOffset) {
+			c.Header("Upload-Offset", strconv.FormatInt(sesi.Diterima, 10))
+			utils.ErrorResponse(c, http.StatusConflict, "Offset tidak sesuai, lanjutkan dari offset server", gin.H{"offset": sesi.Diterima})
+			return
+		}
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menyimpan potongan", err.Error())
+			return
+		}
+	}
+	if readErr != nil {
+		c.Header("Upload-Offset", strconv.FormatInt(sesi.Diterima, 10))
+		utils.ErrorResponse(c, http.StatusBadRequest, "Potongan terputus, lanjutkan dari offset terakhir", gin.H{"offset": sesi.Diterima, "error": readErr.Error()})
+		return
+	}
+
+	if sesi.Diterima < sesi.Ukuran {
+		utils.SuccessResponse(c, http.StatusOK, "Potongan diterima", uploadSesiResponse(c, sesi))
+		return
+	}
+	finishUploadSesi(c, db, sesi)
+}
+
+var (
+	errUploadOffset    = errors.New("offset upload berubah")
+	errUploadSesiLimit = errors.New("batas sesi upload terbuka tercapai")
+	errUploadSelesai   = errors.New("sesi upload sudah selesai")
+)
+
+// finishUploadSesi gabungkan potongan, proses + upload foto, simpan ke galeri lalu hapus sesi.
+// Jika gagal karena layanan penyimpanan, sesi tetap ada supaya bisa diulang.
+func finishUploadSesi(c *gin.Context, db *gorm.DB, sesi *models.UploadSesi) {
+	// akses bisa dicabut selama upload berjalan
+	if !authorizeMediaOwner(c, db, sesi.OwnerType, sesi.OwnerID, mediaOwnerAction(sesi.OwnerType)) {
+		return
+	}
+
+	var chunks []models.UploadChunk
+	if err := db.Where("sesi_id = ?", sesi.ID).Order("byte_offset ASC").Find(&chunks).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil potongan upload", err.Error())
+		return
+	}
+	var buf bytes.Buffer
+	buf.Grow(int(sesi.Ukuran))
+	for _, chunk := range chunks {
+		if chunk.Offset != int64(buf.Len()) {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Potongan upload tidak berurutan", gin.H{"offset": chunk.Offset})
+			return
+		}
+		buf.Write(chunk.Data)
+	}
+
+	uploaded, err := utils.UploadImageData(buf.Bytes(), "galeri/"+sesi.OwnerType)
+	if err != nil {
+		if errors.Is(err, utils.ErrNotImage) {
+			// isi file tidak bisa diperbaiki dengan mengulang, sesi dibuang
+			db.Delete(sesi)
+			utils.ErrorResponse(c, http.StatusBadRequest, "File bukan gambar yang valid", err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusBadGateway, "Upload foto gagal, ulangi dengan body kosong", err.Error())
+		return
+	}
+
+	media, err := config.NewMediaFromUpload(db, sesi.OwnerType, sesi.OwnerID, uploaded)
+	if err == nil {
+		media.Caption = sesi.Caption
+		media.UploadedBy = &sesi.UserID
+		if sesi.TakenAt != nil {
+			media.TakenAt = sesi.TakenAt
+		}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			// request lain di offset akhir bisa menyelesaikan sesi yang sama; yang kedua mendapati sesi sudah hilang
+			var locked models.UploadSesi
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, "id = ?", sesi.ID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errUploadSelesai
+				}
+				return err
+			}
+			var pos struct{ Min, Max *int }
+			if err := tx.Model(&models.Media{}).Select("MIN(urutan) AS min, MAX(urutan) AS max").
+				Where("owner_type = ? AND owner_id = ?", sesi.OwnerType, sesi.OwnerID).Scan(&pos).Error; err != nil {
+				return err
+			}
+			switch {
+			case sesi.JadikanUtama && pos.Min != nil:
+				media.Urutan = *pos.Min - 1
+			case !sesi.JadikanUtama && pos.Max != nil:
+				media.Urutan = *pos.Max + 1
+			}
+			if err := tx.Create(media).Error; err != nil {
+				return err
+			}
+			if err := tx.Delete(sesi).Error; err != nil {
+				return err
+			}
+			return config.SyncMediaCover(tx, sesi.OwnerType, sesi.OwnerID)
+		})
+	}
+	if errors.Is(err, errUploadSelesai) {
+		utils.DeleteUploadedImage(uploaded)
+		utils.ErrorResponse(c, http.StatusConflict, "Sesi upload sudah selesai diproses", nil)
+		return
+	}
+	if err != nil {
+		utils.DeleteUploadedImage(uploaded)
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menyimpan foto", err.Error())
+		return
+	}
+
+	var kebun *models.Kebun
+	if kebunID, err := config.MediaOwnerKebunID(db, sesi.OwnerType, sesi.OwnerID); err == nil {
+		kebun = kebunLokasi(db, kebunID)
+	}
+	c.Header("Upload-Offset", strconv.FormatInt(sesi.Ukuran, 10))
+	utils.SuccessResponseWithWarnings(c, http.StatusCreated, "Foto ditambahkan", media, fotoWarnings(db, kebun, "foto "+sesi.Filename, media))
+}
+
+// CancelUploadSesi godoc
+// @Summary Batalkan upload bertahap
+// @Description Sesi dan potongan yang sudah diterima dihapus
+// @Tags Galeri
+// @Security Bearer
+// @Produce json
+// @Param id path string true "ID sesi upload"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /upload/sesi/{id} [delete]
+func CancelUploadSesi(c *gin.Context) {
+	db, err := requestDB(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+	sesi, ok := uploadSesiFromParam(c, db)
+	if !ok {
+		return
+	}
+	if err := db.Delete(sesi).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal membatalkan upload", err.Error())
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "Upload dibatalkan", utils.EmptyObj{})
+}
@@ -0,0 +1,277 @@
+package controllers
+
+import (
+	"Avocycle/config"
+	"Avocycle/models"
+	"Avocycle/utils"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// VarietasRequest body create / update varietas. Untuk update, field kosong (null) tidak diubah.
+type VarietasRequest struct {
+	Nama           *string  `json:"nama" example:"Alligator"`
+	Deskripsi      *string  `json:"deskripsi"`
+	HariCoverPanen *int     `json:"hari_cover_panen" example:"180"`
+	BeratBuahGram  *float64 `json:"berat_buah_gram" example:"450"`
+	KetinggianMin  *int     `json:"ketinggian_min" example:"400"`
+	KetinggianMax  *int     `json:"ketinggian_max" example:"1200"`
+}
+
+// apply isi field varietas dari request, mengembalikan pesan error validasi
+func (r *VarietasRequest) apply(v *models.Varietas) *string {
+	if r.Nama != nil {
+		nama := strings.TrimSpace(*r.Nama)
+		if nama == "" {
+			msg := "nama tidak boleh kosong"
+			return &msg
+		}
+		v.Nama = nama
+	}
+	if r.Deskripsi != nil {
+		v.Deskripsi = *r.Deskripsi
+	}
+	if r.HariCoverPanen != nil {
+		if *r.HariCoverPanen <= 0 {
+			msg := "hari_cover_panen harus > 0"
+			return &msg
+		}
+		v.HariCoverPanen = r.HariCoverPanen
+	}
+	if r.BeratBuahGram != nil {
+		if *r.BeratBuahGram <= 0 {
+			msg := "berat_buah_gram harus > 0"
+			return &msg
+		}
+		v.BeratBuahGram = r.BeratBuahGram
+	}
+	if r.KetinggianMin != nil {
+		v.KetinggianMin = r.KetinggianMin
+	}
+	if r.KetinggianMax != nil {
+		v.KetinggianMax = r.KetinggianMax
+	}
+	if v.KetinggianMin != nil && v.KetinggianMax != nil && *v.KetinggianMin > *v.KetinggianMax {
+		msg := "ketinggian_min tidak boleh lebih besar dari ketinggian_max"
+		return &msg
+	}
+	return nil
+}
+
+// varietasNameTaken cek nama unik (case-insensitive), exceptID untuk update
+func varietasNameTaken(db *gorm.DB, nama string, exceptID uint) bool {
+	var count int64
+	db.Unscoped().Model(&models.Varietas{}).Where("LOWER(nama) = LOWER(?) AND id <> ?", nama, exceptID).Count(&count)
+	return count > 0
+}
+
+// GetAllVarietas godoc
+// @Summary Daftar varietas
+// @Description Data referensi varietas alpukat untuk form tanaman
+// @Tags Varietas
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /varietas [get]
+func GetAllVarietas(c *gin.Context) {
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	var list []models.Varietas
+	if err := db.Order("nama ASC").Find(&list).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil varietas", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Data varietas", list)
+}
+
+// GetVarietasByID godoc
+// @Summary Detail varietas
+// @Tags Varietas
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Varietas"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /varietas/{id} [get]
+func GetVarietasByID(c *gin.Context) {
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	var varietas models.Varietas
+	if err := db.First(&varietas, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "Varietas tidak ditemukan", nil)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil varietas", err.Error())
+		return
+	}
+
+	var tanamanCount int64
+	db.Model(&models.Tanaman{}).Where("varietas_id = ?", varietas.ID).Count(&tanamanCount)
+
+	utils.SuccessResponse(c, http.StatusOK, "Detail varietas", gin.H{
+		"varietas":      varietas,
+		"tanaman_count": tanamanCount,
+	})
+}
+
+// CreateVarietas godoc
+// @Summary Tambah varietas
+// @Tags Varietas
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body controllers.VarietasRequest true "Varietas"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /varietas [post]
+func CreateVarietas(c *gin.Context) {
+	var input VarietasRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
+		return
+	}
+	if input.Nama == nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "nama wajib diisi", nil)
+		return
+	}
+
+	var varietas models.Varietas
+	if msg := input.apply(&varietas); msg != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, *msg, nil)
+		return
+	}
+
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	if varietasNameTaken(db, varietas.Nama, 0) {
+		utils.ErrorResponse(c, http.StatusConflict, "Nama varietas sudah dipakai", varietas.Nama)
+		return
+	}
+
+	if err := db.Create(&varietas).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal menyimpan varietas", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Varietas berhasil dibuat", varietas)
+}
+
+// UpdateVarietas godoc
+// @Summary Ubah varietas
+// @Tags Varietas
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "ID Varietas"
+// @Param request body controllers.VarietasRequest true "Field yang diubah"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /varietas/{id} [put]
+func UpdateVarietas(c *gin.Context) {
+	var input VarietasRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Input tidak valid", err.Error())
+		return
+	}
+
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	var varietas models.Varietas
+	if err := db.First(&varietas, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "Varietas tidak ditemukan", nil)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil varietas", err.Error())
+		return
+	}
+
+	if msg := input.apply(&varietas); msg != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, *msg, nil)
+		return
+	}
+
+	if varietasNameTaken(db, varietas.Nama, varietas.ID) {
+		utils.ErrorResponse(c, http.StatusConflict, "Nama varietas sudah dipakai", varietas.Nama)
+		return
+	}
+
+	if err := db.Save(&varietas).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal update varietas", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Varietas berhasil diperbarui", varietas)
+}
+
+// DeleteVarietas godoc
+// @Summary Hapus varietas
+// @Description Ditolak jika masih dipakai tanaman
+// @Tags Varietas
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID Varietas"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /varietas/{id} [delete]
+func DeleteVarietas(c *gin.Context) {
+	db, err := config.DbConnect()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal konek DB", err.Error())
+		return
+	}
+
+	var varietas models.Varietas
+	if err := db.First(&varietas, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "Varietas tidak ditemukan", nil)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal ambil varietas", err.Error())
+		return
+	}
+
+	// termasuk tanaman di trash, supaya restore tidak menunjuk varietas yang hilang
+	var tanamanCount int64
+	if err := db.Unscoped().Model(&models.Tanaman{}).Where("varietas_id = ?", varietas.ID).Count(&tanamanCount).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal cek pemakaian varietas", err.Error())
+		return
+	}
+	if tanamanCount > 0 {
+		utils.ErrorResponse(c, http.StatusConflict, "Varietas masih dipakai tanaman", gin.H{"tanaman_count": tanamanCount})
+		return
+	}
+
+	// hard delete supaya nama bisa dipakai lagi (unique index)
+	if err := db.Unscoped().Delete(&varietas).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal hapus varietas", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Varietas berhasil dihapus", utils.EmptyObj{})
+}
@@ -15,8 +15,33 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Daftar public key (RS256 / EdDSA) untuk memverifikasi token Avocycle dari service lain.\nKey lama yang sudah dirotasi tetap ada di sini sampai token lamanya expired.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Public key JWT (JWKS)",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.JWKS"
+                        }
+                    }
+                }
+            }
+        },
         "/Log-Penyakit-Tanaman": {
             "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
                 "description": "Mendapatkan daftar semua log penyakit tanaman dengan pagination",
                 "consumes": [
                     "application/json"
@@ -42,6 +67,12 @@ const docTemplate = `{
                         "description": "Items per page",
                         "name": "per_page",
                         "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter satu organisasi",
+                        "name": "organization_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -80,6 +111,11 @@ const docTemplate = `{
         },
         "/Log-Penyakit-Tanaman/Tanaman/{id_tanaman}": {
             "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
                 "description": "Mendapatkan semua log penyakit berdasarkan tanaman_id dengan pagination",
                 "consumes": [
                     "application/json"
@@ -156,6 +192,11 @@ const docTemplate = `{
         },
         "/Log-Penyakit-Tanaman/{id}": {
             "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
                 "description": "Mendapatkan detail log penyakit tanaman berdasarkan ID",
                 "consumes": [
                     "application/json"
@@ -210,96 +251,58 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/google/pembeli": {
-            "get": {
-                "description": "This endpoint will redirect users to Google Sign-in page in browser.\n\n⚠ Cannot be tested directly via Swagger or Postman.\n\nPlease open this URL in a normal browser instead:\n\nhttp://localhost:2005/api/v1/auth/google/pembeli",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Auth Pembeli with Google"
-                ],
-                "summary": "Login via Google OAuth (Pembeli)",
-                "responses": {
-                    "302": {
-                        "description": "Redirect to Google OAuth",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/auth/google/petani": {
+        "/admin/audit-logs": {
             "get": {
-                "description": "This endpoint will redirect users to Google Sign-in page in browser.\n\n⚠ Cannot be tested directly via Swagger or Postman.\n\nPlease open this URL in a normal browser instead:\n\nhttp://localhost:2005/api/v1/auth/google/petani",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Auth Petani with Google"
-                ],
-                "summary": "Login via Google OAuth (Petani)",
-                "responses": {
-                    "302": {
-                        "description": "Redirect to Google OAuth",
-                        "schema": {
-                            "type": "string"
-                        }
+                "security": [
+                    {
+                        "Bearer": []
                     }
-                }
-            }
-        },
-        "/auth/{provider}/callback/pembeli": {
-            "get": {
-                "description": "Handle Google OAuth callback and return JWT token for Pembeli.\n\nSetelah login dengan Google, browser akan menampilkan JSON berikut:\n\n{\n\"action\": \"google auth pembeli\",\n\"data\": {\n. \t\"ID\": 0,\n. \t\"CreatedAt\": \"2025-11-27T23:00:09.5797085-08:00\",\n. \t\"UpdatedAt\": \"2025-11-27T23:00:09.5797085-08:00\",\n. \t\"DeletedAt\": null,\n. \t\"fullname\": \"John Doe\",\n.     \"phone\": \"\",\n. \t\"email\": \"test123@gmail.com\",\n. \t\"password\": \"\",\n. \t\"auth_provider\": \"Google\",\n. \t\"provider_id\": \"110xxxxxxxxxxx\",\n. \t\"role\": \"Pembeli\"\n.\t\t},\n\"jwtToken\": \"eyJhbGciOiJIUzI1NiI....\",\n\"success\": true,\n\"token_google\": {\n. \t\"access_token\": \"ya29.A0ATi6K....\",\n. \t\"token_type\": \"Bearer\",\n. \t\"expiry\": \"2025-11-28T00:00:08.0994068-08:00\",\n. \t\"expires_in\": 3599\n.    }\n}",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Auth Pembeli with Google"
                 ],
-                "summary": "Google OAuth Callback (Pembeli)",
-                "responses": {
-                    "200": {
-                        "description": "Login success",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    }
-                }
-            }
-        },
-        "/auth/{provider}/callback/petani": {
-            "get": {
-                "description": "Handle Google OAuth callback and return JWT token for Petani.\n\nSetelah login dengan Google, browser akan menampilkan JSON berikut:\n\n{\n\"action\": \"google auth petani\",\n\"data\": {\n. \t\"ID\": 0,\n. \t\"CreatedAt\": \"2025-11-27T23:00:09.5797085-08:00\",\n. \t\"UpdatedAt\": \"2025-11-27T23:00:09.5797085-08:00\",\n. \t\"DeletedAt\": null,\n. \t\"fullname\": \"John Doe\",\n.     \"phone\": \"\",\n. \t\"email\": \"test123@gmail.com\",\n. \t\"password\": \"\",\n. \t\"auth_provider\": \"Google\",\n. \t\"provider_id\": \"110xxxxxxxxxxx\",\n. \t\"role\": \"Petani\"\n.\t\t},\n\"jwtToken\": \"eyJhbGciOiJIUzI1NiI....\",\n\"success\": true,\n\"token_google\": {\n. \t\"access_token\": \"ya29.A0ATi6K....\",\n. \t\"token_type\": \"Bearer\",\n. \t\"expiry\": \"2025-11-28T00:00:08.0994068-08:00\",\n. \t\"expires_in\": 3599\n.    }\n}",
+                "description": "Riwayat create / update / delete kebun, tanaman, fase, booking, dan log penyakit\nbeserta actor, IP, dan diff sebelum / sesudah",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth Petani with Google"
-                ],
-                "summary": "Google OAuth Callback (Petani)",
-                "responses": {
-                    "200": {
-                        "description": "Login success",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    }
-                }
-            }
-        },
-        "/kebun": {
-            "get": {
-                "description": "Mengambil daftar kebun dengan pagination (menggunakan meta pagination sesuai utils Pagination)",
-                "tags": [
-                    "Kebun"
+                    "Admin Security"
                 ],
-                "summary": "Ambil semua kebun",
+                "summary": "Audit log perubahan data",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kebun / Tanaman / FaseBunga / FaseBuah / FasePanen / Booking / LogPenyakitTanaman",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID entitas",
+                        "name": "entity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID user pelaku",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "create / update / delete",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mulai tanggal (YYYY-MM-DD atau RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sampai tanggal (YYYY-MM-DD atau RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "Halaman",
@@ -315,64 +318,52 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Data kebun berhasil diambil",
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/admin/login-locks": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Membuat data kebun baru (response mengikuti utils.Response)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Akun dan IP yang sedang terkunci karena terlalu banyak login gagal",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Kebun"
+                    "Admin Security"
                 ],
-                "summary": "Membuat kebun baru",
+                "summary": "Daftar lockout login aktif",
                 "parameters": [
                     {
-                        "description": "Input kebun",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "properties": {
-                                "mdpl": {
-                                    "type": "string"
-                                },
-                                "nama_kebun": {
-                                    "type": "string"
-                                }
-                            }
-                        }
+                        "type": "integer",
+                        "description": "Halaman",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah data per halaman",
+                        "name": "per_page",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/utils.Response"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
@@ -380,17 +371,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/kebun/{id}": {
-            "get": {
-                "description": "Mengambil detail kebun berdasarkan ID",
+        "/admin/login-locks/{id}/unlock": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Menghapus lock dan mereset hitungan gagal berdasarkan ID lock",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Kebun"
+                    "Admin Security"
                 ],
-                "summary": "Ambil detail kebun",
+                "summary": "Buka lock akun / IP",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Kebun",
+                        "description": "ID lock",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -410,86 +409,87 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/admin/media/orphan": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Mengupdate data kebun berdasarkan ID (partial update)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Aset gambar di Cloudinary (di bawah folder CLOUDINARY_FOLDER) yang tidak dirujuk galeri maupun kolom foto record mana pun.\nsiap_dihapus = sudah melewati masa tenggang MEDIA_GC_GRACE_HOURS. Tidak menghapus apa pun.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Kebun"
-                ],
-                "summary": "Update kebun",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID Kebun",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Input update",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.UpdateKebunRequest"
-                        }
-                    }
+                    "Admin Security"
                 ],
+                "summary": "Laporan foto tanpa referensi",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/config.MediaGCReport"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "502": {
+                        "description": "Bad Gateway",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/media/orphan/purge": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Menghapus data kebun berdasarkan ID",
+                "description": "Jalankan garbage collector sekarang: hapus aset tanpa referensi yang sudah melewati masa tenggang.\nAset yang lebih baru tetap dilaporkan tapi tidak dihapus.",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Kebun"
-                ],
-                "summary": "Hapus kebun",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID Kebun",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Admin Security"
                 ],
+                "summary": "Hapus foto tanpa referensi",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/config.MediaGCReport"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "502": {
+                        "description": "Bad Gateway",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
@@ -497,130 +497,82 @@ const docTemplate = `{
                 }
             }
         },
-        "/login": {
-            "post": {
-                "description": "Login user menggunakan email dan password",
-                "consumes": [
-                    "application/json"
+        "/admin/security-events": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
                 ],
+                "description": "Daftar lockout / unlock terbaru, bisa difilter event",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin Security"
                 ],
-                "summary": "Login user",
+                "summary": "Riwayat kejadian keamanan",
                 "parameters": [
                     {
-                        "description": "Login credentials",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.LoginRequest"
-                        }
+                        "type": "string",
+                        "description": "account_locked / ip_locked / account_unlocked / ip_unlocked",
+                        "name": "event",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Halaman",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah data per halaman",
+                        "name": "per_page",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Login success with JWT",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid input",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "401": {
-                        "description": "Wrong credentials",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/utils.Response"
                         }
                     }
                 }
             }
         },
-        "/pembeli/booking": {
+        "/admin/security-policy": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve paginated list of booking (hanya untuk role Pembeli)",
+                "description": "Pengaturan keamanan global (contoh: wajib 2FA untuk Admin)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Booking"
-                ],
-                "summary": "Get all booking with pagination",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Items per page",
-                        "name": "per_page",
-                        "in": "query"
-                    }
+                    "Admin Security"
                 ],
+                "summary": "Ambil security policy",
                 "responses": {
                     "200": {
                         "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/utils.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/models.SwaggerBooking"
-                                            }
-                                        },
-                                        "meta": {
-                                            "$ref": "#/definitions/utils.Pagination"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/utils.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
                     }
                 }
             },
-            "post": {
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Booking action by pembeli",
+                "description": "Mengubah pengaturan keamanan. Admin yang mengaktifkan wajib 2FA harus sudah mengaktifkan 2FA sendiri supaya tidak terkunci.",
                 "consumes": [
                     "application/json"
                 ],
@@ -628,37 +580,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Booking"
+                    "Admin Security"
                 ],
-                "summary": "Create a new booking",
+                "summary": "Update security policy",
                 "parameters": [
                     {
-                        "description": "Booking input",
-                        "name": "booking",
+                        "description": "Policy",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.BookingRequest"
+                            "$ref": "#/definitions/controllers.UpdateSecurityPolicyRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/utils.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.SwaggerBooking"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/utils.Response"
                         }
                     },
                     "400": {
@@ -666,85 +606,43 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/utils.Response"
-                        }
                     }
                 }
             }
         },
-        "/pembeli/booking/user/{user_id}": {
-            "get": {
+        "/admin/users/{id}/unlock": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve user's booking list with pagination",
+                "description": "Mereset lock login akun berdasarkan user ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Booking"
+                    "Admin Security"
                 ],
-                "summary": "Get booking list by user ID",
+                "summary": "Buka lock akun user",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "User ID",
-                        "name": "user_id",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Items per page",
-                        "name": "per_page",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/utils.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/models.SwaggerBooking"
-                                            }
-                                        },
-                                        "meta": {
-                                            "$ref": "#/definitions/utils.Pagination"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
@@ -752,70 +650,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/pembeli/booking/{id}": {
+        "/auth/2fa": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve detail booking by ID (role Pembeli)",
+                "description": "Status 2FA user yang sedang login dan sisa recovery code",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Booking"
-                ],
-                "summary": "Get booking by ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Booking ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Two Factor"
                 ],
+                "summary": "Status 2FA",
                 "responses": {
                     "200": {
                         "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/utils.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.SwaggerBooking"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/utils.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/auth/2fa/disable": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Update booking by ID (role Pembeli)",
+                "description": "Mematikan 2FA. Butuh password (akun lokal) dan kode TOTP atau recovery code. Admin tidak bisa mematikan 2FA jika policy mewajibkan.",
                 "consumes": [
                     "application/json"
                 ],
@@ -823,24 +690,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Booking"
+                    "Two Factor"
                 ],
-                "summary": "Update existing booking",
+                "summary": "Nonaktifkan 2FA",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Booking ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Booking input",
-                        "name": "booking",
+                        "description": "Konfirmasi",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.BookingRequest"
+                            "$ref": "#/definitions/controllers.TwoFactorDisableRequest"
                         }
                     }
                 ],
@@ -848,19 +708,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/utils.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.SwaggerBooking"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/utils.Response"
                         }
                     },
                     "400": {
@@ -869,41 +717,42 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/utils.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/auth/2fa/enable": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete action for booking (role Pembeli)",
+                "description": "Verifikasi kode pertama dari aplikasi authenticator, aktifkan 2FA dan kembalikan recovery codes (hanya ditampilkan sekali)",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Booking"
+                    "Two Factor"
                 ],
-                "summary": "Delete booking by ID",
+                "summary": "Aktifkan 2FA",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Booking ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Kode TOTP",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.TwoFactorCodeRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -913,14 +762,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/utils.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
@@ -928,91 +771,66 @@ const docTemplate = `{
                 }
             }
         },
-        "/petamin/penyakit/{id_tanaman}": {
+        "/auth/2fa/recovery-codes": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Menerima foto tanaman alpukat, mengklasifikasikan penyakit menggunakan Gemini AI, mengunggah foto, dan menyimpan log ke database.",
+                "description": "Mengganti semua recovery code lama dengan yang baru (butuh kode TOTP)",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Petani \u0026 Admin (Deteksi)"
+                    "Two Factor"
                 ],
-                "summary": "Klasifikasi Penyakit Tanaman Alpukat",
+                "summary": "Buat ulang recovery codes",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID Tanaman yang ingin diklasifikasi penyakitnya",
-                        "name": "id_tanaman",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "Foto daun atau bagian tanaman yang sakit (JPG, PNG, dll.)",
-                        "name": "foto_tanaman",
-                        "in": "formData",
-                        "required": true
+                        "description": "Kode TOTP",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.TwoFactorCodeRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Klasifikasi berhasil\" // \u003c-- Menggunakan wrapper dari package controllers",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.SuccessResponseWrapper"
+                            "$ref": "#/definitions/utils.Response"
                         }
                     },
                     "400": {
-                        "description": "ID tanaman tidak valid atau Foto tanaman wajib diunggah\" // \u003c-- Menggunakan wrapper dari package controllers",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.ErrorResponseWrapper"
-                        }
-                    },
-                    "500": {
-                        "description": "Gagal konek DB, Inisialisasi Gemini gagal, Proses klasifikasi atau upload gagal, atau Gagal menyimpan data\" // \u003c-- Menggunakan wrapper dari package controllers",
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/controllers.ErrorResponseWrapper"
+                            "$ref": "#/definitions/utils.Response"
                         }
                     }
                 }
             }
         },
-        "/petani/buah": {
-            "get": {
+        "/auth/2fa/setup": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve paginated list of buah",
+                "description": "Membuat secret TOTP baru dan provisioning URI (otpauth://) untuk ditampilkan sebagai QR. 2FA belum aktif sampai /auth/2fa/enable berhasil.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Buah"
-                ],
-                "summary": "Get all buah with pagination",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Items per page",
-                        "name": "per_page",
-                        "in": "query"
-                    }
+                    "Two Factor"
                 ],
+                "summary": "Mulai enrollment 2FA",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1020,118 +838,86 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.Response"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/utils.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
                     }
                 }
-            },
-            "post": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/auth/google/pembeli": {
+            "get": {
+                "description": "This endpoint will redirect users to Google Sign-in page in browser.\nAlias dari /auth/google?role=pembeli.\n\n⚠ Cannot be tested directly via Swagger or Postman.\n\nPlease open this URL in a normal browser instead:\n\nhttp://localhost:2005/api/v1/auth/google/pembeli",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Buah"
+                    "Auth Pembeli with Google"
                 ],
-                "summary": "Create a new buah",
-                "parameters": [
-                    {
-                        "description": "Buah Data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                "summary": "Login via Google OAuth (Pembeli)",
+                "responses": {
+                    "302": {
+                        "description": "Redirect to Google OAuth",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "nama_buah": {
-                                    "type": "string"
-                                },
-                                "tanaman_id": {
-                                    "type": "integer"
-                                }
-                            }
+                            "type": "string"
                         }
                     }
+                }
+            }
+        },
+        "/auth/google/petani": {
+            "get": {
+                "description": "This endpoint will redirect users to Google Sign-in page in browser.\nAlias dari /auth/google?role=petani.\n\n⚠ Cannot be tested directly via Swagger or Postman.\n\nPlease open this URL in a normal browser instead:\n\nhttp://localhost:2005/api/v1/auth/google/petani",
+                "produces": [
+                    "application/json"
                 ],
+                "tags": [
+                    "Auth Petani with Google"
+                ],
+                "summary": "Login via Google OAuth (Petani)",
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/utils.Response"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/utils.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "302": {
+                        "description": "Redirect to Google OAuth",
                         "schema": {
-                            "$ref": "#/definitions/utils.Response"
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/petani/buah/by-tanaman/{id_kebun}": {
+        "/auth/{provider}": {
             "get": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Retrieve buah based on related tanaman kebun",
+                "description": "Redirect browser ke halaman login provider (google atau provider OIDC yang dikonfigurasi lewat OAUTH_PROVIDERS).\nRole dibawa di parameter state yang ditandatangani, lalu diverifikasi di callback.\n\n⚠ Cannot be tested directly via Swagger or Postman, buka di browser biasa.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Buah"
+                    "Auth OAuth"
                 ],
-                "summary": "Get buah by kebun ID with pagination",
+                "summary": "Login via OAuth / OIDC provider",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Kebun ID",
-                        "name": "id_kebun",
+                        "type": "string",
+                        "description": "Nama provider, contoh: google",
+                        "name": "provider",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Items per page",
-                        "name": "per_page",
-                        "in": "query"
+                        "type": "string",
+                        "description": "petani / pembeli",
+                        "name": "role",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "302": {
+                        "description": "Redirect ke provider",
                         "schema": {
-                            "$ref": "#/definitions/utils.Response"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -1139,55 +925,128 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/utils.Response"
-                        }
                     }
                 }
             }
         },
-        "/petani/buah/{id}": {
+        "/auth/{provider}/callback": {
             "get": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
+                "description": "Callback dari provider. State dan nonce diverifikasi, id_token dicek ke JWKS provider,\nlalu user dibuat (jika baru) dengan role dari state dan browser diarahkan ke FRONTEND_GOOGLE_CALLBACK_URL?token=...\nJika 2FA aktif, redirect membawa two_factor_required=true\u0026challenge_token=... untuk ditukar di /login/2fa.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Buah"
+                    "Auth OAuth"
                 ],
-                "summary": "Get buah by ID",
+                "summary": "OAuth / OIDC callback",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Buah ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Nama provider, contoh: google",
+                        "name": "provider",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "State dari provider",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/utils.Response"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                    "307": {
+                        "description": "Redirect ke frontend dengan token",
                         "schema": {
-                            "$ref": "#/definitions/utils.Response"
+                            "type": "string"
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
+                    }
+                }
+            }
+        },
+        "/auth/{provider}/callback/pembeli": {
+            "get": {
+                "description": "Handle Google OAuth callback and return JWT token for Pembeli.\n\nSetelah login dengan Google, browser akan menampilkan JSON berikut:\n\n{\n\"action\": \"google auth pembeli\",\n\"data\": {\n. \t\"ID\": 0,\n. \t\"CreatedAt\": \"2025-11-27T23:00:09.5797085-08:00\",\n. \t\"UpdatedAt\": \"2025-11-27T23:00:09.5797085-08:00\",\n. \t\"DeletedAt\": null,\n. \t\"fullname\": \"John Doe\",\n.     \"phone\": \"\",\n. \t\"email\": \"test123@gmail.com\",\n. \t\"password\": \"\",\n. \t\"auth_provider\": \"Google\",\n. \t\"provider_id\": \"110xxxxxxxxxxx\",\n. \t\"role\": \"Pembeli\"\n.\t\t},\n\"jwtToken\": \"eyJhbGciOiJIUzI1NiI....\",\n\"success\": true,\n\"token_google\": {\n. \t\"access_token\": \"ya29.A0ATi6K....\",\n. \t\"token_type\": \"Bearer\",\n. \t\"expiry\": \"2025-11-28T00:00:08.0994068-08:00\",\n. \t\"expires_in\": 3599\n.    }\n}",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth Pembeli with Google"
+                ],
+                "summary": "Google OAuth Callback (Pembeli)",
+                "responses": {
+                    "200": {
+                        "description": "Login success",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/{provider}/callback/petani": {
+            "get": {
+                "description": "Handle Google OAuth callback and return JWT token for Petani.\n\nSetelah login dengan Google, browser akan menampilkan JSON berikut:\n\n{\n\"action\": \"google auth petani\",\n\"data\": {\n. \t\"ID\": 0,\n. \t\"CreatedAt\": \"2025-11-27T23:00:09.5797085-08:00\",\n. \t\"UpdatedAt\": \"2025-11-27T23:00:09.5797085-08:00\",\n. \t\"DeletedAt\": null,\n. \t\"fullname\": \"John Doe\",\n.     \"phone\": \"\",\n. \t\"email\": \"test123@gmail.com\",\n. \t\"password\": \"\",\n. \t\"auth_provider\": \"Google\",\n. \t\"provider_id\": \"110xxxxxxxxxxx\",\n. \t\"role\": \"Petani\"\n.\t\t},\n\"jwtToken\": \"eyJhbGciOiJIUzI1NiI....\",\n\"success\": true,\n\"token_google\": {\n. \t\"access_token\": \"ya29.A0ATi6K....\",\n. \t\"token_type\": \"Bearer\",\n. \t\"expiry\": \"2025-11-28T00:00:08.0994068-08:00\",\n. \t\"expires_in\": 3599\n.    }\n}",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth Petani with Google"
+                ],
+                "summary": "Google OAuth Callback (Petani)",
+                "responses": {
+                    "200": {
+                        "description": "Login success",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/blok/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Blok"
+                ],
+                "summary": "Detail blok",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Blok",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
                     }
@@ -1199,6 +1058,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
+                "description": "Ganti kode blok ikut memperbarui kode_blok semua tanaman di blok tersebut",
                 "consumes": [
                     "application/json"
                 ],
@@ -1206,32 +1066,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Buah"
+                    "Blok"
                 ],
-                "summary": "Update buah",
+                "summary": "Ubah blok",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Buah ID",
+                        "description": "ID Blok",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated Buah Data",
+                        "description": "Field yang diubah",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "nama_buah": {
-                                    "type": "string"
-                                },
-                                "tanaman_id": {
-                                    "type": "integer"
-                                }
-                            }
+                            "$ref": "#/definitions/controllers.BlokRequest"
                         }
                     }
                 ],
@@ -1248,14 +1100,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/utils.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
@@ -1268,17 +1114,18 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
+                "description": "Ditolak jika masih ada tanaman (termasuk di trash) di blok ini",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Buah"
+                    "Blok"
                 ],
-                "summary": "Delete buah",
+                "summary": "Hapus blok",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Buah ID",
+                        "description": "ID Blok",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1297,8 +1144,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
@@ -1306,95 +1153,109 @@ const docTemplate = `{
                 }
             }
         },
-        "/petani/fase-berbuah": {
+        "/blok/{id}/labels.pdf": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Mendapatkan semua data fase berbuah dengan pagination",
+                "description": "Label QR siap cetak (A4, 3 x 7 per halaman) untuk semua tanaman di blok",
                 "produces": [
-                    "application/json"
+                    "application/pdf"
                 ],
                 "tags": [
-                    "Fase Berbuah"
+                    "Tanaman Label"
                 ],
-                "summary": "Get all fase berbuah",
+                "summary": "Lembar label PDF blok",
                 "parameters": [
                     {
                         "type": "integer",
-                        "default": 1,
-                        "description": "Halaman",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Jumlah item per halaman",
-                        "name": "per_page",
-                        "in": "query"
+                        "description": "ID Blok",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/blok/{id}/prakiraan-panen": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Menambahkan data fase berbuah baru untuk tanaman",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Perkiraan jumlah buah dan berat (kg) per minggu untuk blok, beserta total per pohon",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Fase Berbuah"
+                    "Prakiraan Panen"
                 ],
-                "summary": "Create fase berbuah",
+                "summary": "Prakiraan panen blok",
                 "parameters": [
                     {
-                        "description": "Fase Buah Data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.CreateFaseBuahInput"
-                        }
+                        "type": "integer",
+                        "description": "ID Blok",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah minggu ke depan (default 12, maks 26)",
+                        "name": "minggu",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/config.PrakiraanPanen"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
@@ -1402,47 +1263,63 @@ const docTemplate = `{
                 }
             }
         },
-        "/petani/fase-berbuah/tanaman/{tanaman_id}": {
+        "/blok/{id}/prakiraan-panen/backtest": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Mendapatkan data fase berbuah berdasarkan tanaman ID dengan pagination",
+                "description": "Prakiraan diulang pada beberapa titik di masa lalu hanya dengan data sebelum titik itu,\nlalu dibandingkan dengan panen yang tercatat",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Fase Berbuah"
+                    "Prakiraan Panen"
                 ],
-                "summary": "Get fase berbuah by tanaman ID",
+                "summary": "Backtest prakiraan panen blok",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Tanaman",
-                        "name": "tanaman_id",
+                        "description": "ID Blok",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "default": 1,
-                        "description": "Halaman",
-                        "name": "page",
+                        "description": "Horizon prakiraan dalam minggu (default 12, maks 26)",
+                        "name": "minggu",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "default": 10,
-                        "description": "Jumlah item per halaman",
-                        "name": "per_page",
+                        "description": "Jumlah titik backtest, berjarak 4 minggu (default 4, maks 12)",
+                        "name": "titik",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/config.BacktestPrakiraan"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
@@ -1456,33 +1333,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/petani/fase-berbuah/{id}": {
+        "/blok/{id}/qr": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Mendapatkan detail fase berbuah berdasarkan ID",
+                "description": "Satu PNG grid berisi QR semua tanaman di blok, urut kode tanaman",
                 "produces": [
-                    "application/json"
+                    "image/png"
                 ],
                 "tags": [
-                    "Fase Berbuah"
+                    "Tanaman Label"
                 ],
-                "summary": "Get fase berbuah by ID",
+                "summary": "QR code seluruh tanaman di blok",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Fase Buah",
+                        "description": "ID Blok",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ukuran tiap QR dalam pixel (128 - 1024, default 256)",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah kolom grid (1 - 10, default 4)",
+                        "name": "columns",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
@@ -1494,40 +1389,30 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/blok/{id}/statistik": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Mengupdate data fase berbuah berdasarkan ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Jumlah tanaman, tanaman sakit (log penyakit terbaru), siap panen (fase buah terbaru),\ndan total hasil panen di satu blok",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Fase Berbuah"
+                    "Blok"
                 ],
-                "summary": "Update fase berbuah",
+                "summary": "Statistik blok",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Fase Buah",
+                        "description": "ID Blok",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Fase Buah Data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.CreateFaseBuahInput"
-                        }
                     }
                 ],
                 "responses": {
@@ -1537,12 +1422,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.Response"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/utils.Response"
-                        }
-                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1550,28 +1429,41 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
+            }
+        },
+        "/blok/{id}/tanaman": {
+            "get": {
+                "security": [
+                    {
                         "Bearer": []
                     }
                 ],
-                "description": "Menghapus fase berbuah berdasarkan ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Fase Berbuah"
+                    "Blok"
                 ],
-                "summary": "Delete fase berbuah",
+                "summary": "Tanaman dalam blok",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Fase Buah",
+                        "description": "ID Blok",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Halaman",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah per halaman",
+                        "name": "per_page",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1590,14 +1482,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/petani/fase-bunga": {
-            "get": {
+        "/booking/{id}/status": {
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Mendapatkan daftar semua fase bunga (dengan pagination)",
+                "description": "Pembeli hanya bisa membatalkan booking miliknya (cancelled). Pengguna dengan permission booking:approve\nyang punya hak tulis tanaman di kebunnya menandai booking fulfilled / cancelled. Booking yang sudah selesai / batal tidak bisa dibuka lagi.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1605,63 +1497,100 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Fase Bunga"
+                    "Booking"
                 ],
-                "summary": "Get all fase bunga",
+                "summary": "Ubah status booking",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Nomor halaman",
-                        "name": "page",
-                        "in": "query"
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Jumlah data per halaman",
-                        "name": "per_page",
-                        "in": "query"
+                        "description": "Status baru",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.BookingStatusRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.SwaggerBooking"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/fase-berbuah": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Menambahkan fase bunga baru untuk tanaman",
+                "description": "Menambahkan data fase berbuah baru untuk tanaman. Bisa juga dikirim sebagai multipart/form-data dengan\nfield yang sama ditambah foto (opsional, masuk galeri); tanggal_catat kosong memakai tanggal EXIF foto.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Fase Bunga"
+                    "Fase Berbuah"
                 ],
-                "summary": "Create fase bunga",
+                "summary": "Create fase berbuah",
                 "parameters": [
                     {
-                        "description": "Fase Bunga Data",
+                        "description": "Fase Buah Data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.CreateFaseBungaInput"
+                            "$ref": "#/definitions/controllers.CreateFaseBuahInput"
                         }
                     }
                 ],
@@ -1687,25 +1616,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/petani/fase-bunga/tanaman/{tanaman_id}": {
-            "get": {
+        "/fase-berbuah/{id}": {
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Mendapatkan semua fase bunga milik tanaman tertentu",
+                "description": "Mengupdate data fase berbuah berdasarkan ID. Estimasi panen dihitung ulang jika cover / tanaman\nberubah, kecuali estimasinya diisi manual; kirim hitung_ulang_estimasi = true untuk menggantinya.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Fase Bunga"
+                    "Fase Berbuah"
                 ],
-                "summary": "Get fase bunga by tanaman ID",
+                "summary": "Update fase berbuah",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Tanaman",
-                        "name": "tanaman_id",
+                        "description": "ID Fase Buah",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Fase Buah Data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UpdateFaseBuahInput"
+                        }
                     }
                 ],
                 "responses": {
@@ -1715,6 +1659,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.Response"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1722,30 +1672,25 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/petani/fase-bunga/{id}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Mendapatkan detail fase bunga berdasarkan ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Menghapus fase berbuah berdasarkan ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Fase Bunga"
+                    "Fase Berbuah"
                 ],
-                "summary": "Get fase bunga by ID",
+                "summary": "Delete fase berbuah",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Fase Bunga",
+                        "description": "ID Fase Buah",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1765,53 +1710,49 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/fase-berbuah/{id}/estimasi": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Mengupdate data fase bunga berdasarkan ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Hitung estimasi panen dengan data terkini: varietas, ketinggian kebun, tanggal cover dan\nriwayat interval cover-panen kebun. Tidak mengubah estimasi yang tersimpan.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Fase Bunga"
+                    "Fase Berbuah"
                 ],
-                "summary": "Update fase bunga",
+                "summary": "Rincian estimasi panen fase berbuah",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Fase Bunga",
+                        "description": "ID Fase Buah",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Fase Bunga Data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.UpdateFaseBungaInput"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.Response"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/utils.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/config.EstimasiPanen"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "404": {
@@ -1821,22 +1762,27 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/fase-berbuah/{id}/revisions": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Menghapus fase bunga berdasarkan ID",
+                "description": "Semua versi record fase buah (create / update / delete) beserta masa berlakunya",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Fase Bunga"
+                    "Fase Buah"
                 ],
-                "summary": "Delete fase bunga",
+                "summary": "Riwayat versi fase buah",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Fase Bunga",
+                        "description": "ID Fase Buah",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1858,113 +1804,144 @@ const docTemplate = `{
                 }
             }
         },
-        "/petani/fase-panen": {
-            "get": {
+        "/fase-bunga": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Mendapatkan semua fase panen dengan pagination",
+                "description": "Menambahkan fase bunga baru untuk tanaman. Bisa juga dikirim sebagai multipart/form-data dengan\nfield yang sama ditambah foto (opsional, masuk galeri); tanggal_catat kosong memakai tanggal EXIF foto.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Fase Panen"
+                    "Fase Bunga"
                 ],
-                "summary": "Get all fase panen",
+                "summary": "Create fase bunga",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Nomor halaman",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Jumlah data per halaman",
-                        "name": "per_page",
-                        "in": "query"
+                        "description": "Fase Bunga Data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateFaseBungaInput"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/fase-bunga/{id}": {
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Menambahkan data fase panen baru",
+                "description": "Mengupdate data fase bunga berdasarkan ID",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Fase Panen"
+                    "Fase Bunga"
                 ],
-                "summary": "Create fase panen",
+                "summary": "Update fase bunga",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Tanggal Panen Aktual (YYYY-MM-DD)",
-                        "name": "tanggal_panen_aktual",
-                        "in": "formData",
+                        "type": "integer",
+                        "description": "ID Fase Bunga",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Jumlah Panen",
-                        "name": "jumlah_panen",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Jumlah Sampel",
-                        "name": "jumlah_sampel",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "number",
-                        "description": "Berat Total (Kg)",
-                        "name": "berat_total",
-                        "in": "formData"
+                        "description": "Fase Bunga Data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UpdateFaseBungaInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "Catatan",
-                        "name": "catatan",
-                        "in": "formData"
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
                     {
-                        "type": "file",
-                        "description": "Foto Panen",
-                        "name": "foto_panen",
-                        "in": "formData"
-                    },
+                        "Bearer": []
+                    }
+                ],
+                "description": "Menghapus fase bunga berdasarkan ID",
+                "tags": [
+                    "Fase Bunga"
+                ],
+                "summary": "Delete fase bunga",
+                "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Tanaman",
-                        "name": "tanaman_id",
-                        "in": "formData",
+                        "description": "ID Fase Bunga",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
@@ -1972,43 +1949,28 @@ const docTemplate = `{
                 }
             }
         },
-        "/petani/fase-panen/tanaman/{tanaman_id}": {
+        "/fase-bunga/{id}/revisions": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Mendapatkan daftar fase panen milik tanaman tertentu",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Semua versi record fase bunga (create / update / delete) beserta masa berlakunya",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Fase Panen"
+                    "Fase Bunga"
                 ],
-                "summary": "Get fase panen by tanaman ID",
+                "summary": "Riwayat versi fase bunga",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Tanaman",
-                        "name": "tanaman_id",
+                        "description": "ID Fase Bunga",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Nomor halaman",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Jumlah data per halaman",
-                        "name": "per_page",
-                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -2027,16 +1989,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/petani/fase-panen/{id}": {
-            "get": {
+        "/fase-panen": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Mendapatkan detail fase panen",
+                "description": "Menambahkan data fase panen baru",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
@@ -2044,31 +2006,63 @@ const docTemplate = `{
                 "tags": [
                     "Fase Panen"
                 ],
-                "summary": "Get fase panen by ID",
+                "summary": "Create fase panen",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tanggal Panen Aktual (YYYY-MM-DD), default tanggal EXIF foto_panen",
+                        "name": "tanggal_panen_aktual",
+                        "in": "formData"
+                    },
                     {
                         "type": "integer",
-                        "description": "ID Fase Panen",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Jumlah Panen",
+                        "name": "jumlah_panen",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah Sampel",
+                        "name": "jumlah_sampel",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Berat Total (Kg)",
+                        "name": "berat_total",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Catatan",
+                        "name": "catatan",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Foto Panen",
+                        "name": "foto_panen",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID Tanaman",
+                        "name": "tanaman_id",
+                        "in": "formData",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/utils.Response"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/fase-panen/{id}": {
             "put": {
                 "security": [
                     {
@@ -2182,137 +2176,98 @@ const docTemplate = `{
                 }
             }
         },
-        "/register/pembeli": {
-            "post": {
-                "description": "Register pembeli menggunakan credential lokal",
-                "consumes": [
-                    "application/json"
+        "/fase-panen/{id}/revisions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
                 ],
+                "description": "Semua versi record fase panen (create / update / delete) beserta masa berlakunya",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Fase Panen"
                 ],
-                "summary": "Register Pembeli",
+                "summary": "Riwayat versi fase panen",
                 "parameters": [
                     {
-                        "description": "Register Pembeli",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.RegisterRequest"
-                        }
+                        "type": "integer",
+                        "description": "ID Fase Panen",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Registered successfully",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid input",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/utils.Response"
                         }
                     },
-                    "409": {
-                        "description": "Email or phone already exists",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/utils.Response"
                         }
                     }
                 }
             }
         },
-        "/register/petani": {
-            "post": {
-                "description": "Register petani menggunakan credential lokal",
-                "consumes": [
-                    "application/json"
+        "/galeri/{owner_type}/{owner_id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
                 ],
+                "description": "Semua foto milik satu record, urut sesuai urutan galeri. Foto pertama = foto utama.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Galeri"
                 ],
-                "summary": "Register Petani",
+                "summary": "Galeri foto record",
                 "parameters": [
                     {
-                        "description": "Register Petani",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.RegisterRequest"
-                        }
+                        "type": "string",
+                        "description": "kebun, tanaman, fase-bunga, fase-berbuah, fase-panen, log-penyakit, buah",
+                        "name": "owner_type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID record",
+                        "name": "owner_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Registered successfully",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/utils.Response"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/utils.Response"
                         }
                     },
-                    "409": {
-                        "description": "Email or phone already exists",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    }
-                }
-            }
-        },
-        "/tanaman": {
-            "get": {
-                "description": "Mengambil daftar tanaman dengan pagination",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Tanaman"
-                ],
-                "summary": "Ambil semua tanaman",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Halaman",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Jumlah data per halaman",
-                        "name": "per_page",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/utils.Response"
+                            "$ref": "#/definitions/utils.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
@@ -2325,7 +2280,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Menambahkan tanaman baru",
+                "description": "Upload satu atau beberapa foto (field foto diulang, maks 10). Foto ditambahkan di akhir galeri.",
                 "consumes": [
                     "multipart/form-data"
                 ],
@@ -2333,63 +2288,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tanaman"
+                    "Galeri"
                 ],
-                "summary": "Tambah tanaman",
+                "summary": "Tambah foto ke galeri",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Nama Tanaman",
-                        "name": "nama_tanaman",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Varietas",
-                        "name": "varietas",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Tanggal Tanam (YYYY-MM-DD)",
-                        "name": "tanggal_tanam",
-                        "in": "formData",
+                        "description": "kebun, tanaman, fase-bunga, fase-berbuah, fase-panen, log-penyakit, buah",
+                        "name": "owner_type",
+                        "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "ID Kebun",
-                        "name": "kebun_id",
-                        "in": "formData",
+                        "description": "ID record",
+                        "name": "owner_id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Kode Blok",
-                        "name": "kode_blok",
+                        "type": "file",
+                        "description": "Foto (boleh lebih dari satu)",
+                        "name": "foto",
                         "in": "formData",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Kode Tanaman",
-                        "name": "kode_tanaman",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Masa Produksi",
-                        "name": "masa_produksi",
-                        "in": "formData",
-                        "required": true
+                        "description": "Keterangan, dipakai untuk semua foto yang diupload",
+                        "name": "caption",
+                        "in": "formData"
                     },
                     {
-                        "type": "file",
-                        "description": "Foto Tanaman",
-                        "name": "foto_tanaman",
+                        "type": "string",
+                        "description": "Waktu foto diambil (RFC3339 atau YYYY-MM-DD), default EXIF foto lalu waktu upload",
+                        "name": "taken_at",
                         "in": "formData"
                     }
                 ],
@@ -2405,39 +2338,63 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
                     }
                 }
             }
         },
-        "/tanaman/by-kebun/{id_kebun}": {
-            "get": {
-                "description": "Ambil data tanaman berdasarkan kebun_id",
+        "/galeri/{owner_type}/{owner_id}/urutan": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "ids berisi semua foto galeri dengan urutan baru. Foto pertama menjadi foto utama record.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tanaman"
+                    "Galeri"
                 ],
-                "summary": "Tanaman berdasarkan Kebun",
+                "summary": "Atur urutan galeri",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID Kebun",
-                        "name": "id_kebun",
+                        "type": "string",
+                        "description": "kebun, tanaman, fase-bunga, fase-berbuah, fase-panen, log-penyakit, buah",
+                        "name": "owner_type",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Halaman",
-                        "name": "page",
-                        "in": "query"
+                        "description": "ID record",
+                        "name": "owner_id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Jumlah per halaman",
-                        "name": "per_page",
-                        "in": "query"
+                        "description": "Urutan id foto",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ReorderMediaRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -2452,32 +2409,9 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
-                    }
-                }
-            }
-        },
-        "/tanaman/{id}": {
-            "get": {
-                "description": "Mendapatkan detail tanaman berdasarkan ID",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Tanaman"
-                ],
-                "summary": "Detail tanaman",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID Tanaman",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
@@ -2489,140 +2423,5745 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/kebun": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Mengubah data tanaman (bisa sebagian)",
-                "consumes": [
-                    "multipart/form-data"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Mengambil daftar kebun dengan pagination (menggunakan meta pagination sesuai utils Pagination).\nPetani melihat kebun organisasi / keanggotaannya, pembeli melihat semua kebun (read-only).",
                 "tags": [
-                    "Tanaman"
+                    "Kebun"
                 ],
-                "summary": "Update tanaman",
+                "summary": "Ambil semua kebun",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Tanaman",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Nama Tanaman",
-                        "name": "nama_tanaman",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Varietas",
-                        "name": "varietas",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Tanggal Tanam",
-                        "name": "tanggal_tanam",
-                        "in": "formData"
+                        "description": "Halaman",
+                        "name": "page",
+                        "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "ID Kebun",
-                        "name": "kebun_id",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Kode Blok",
-                        "name": "kode_blok",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Kode Tanaman",
-                        "name": "kode_tanaman",
-                        "in": "formData"
+                        "description": "Jumlah data per halaman",
+                        "name": "per_page",
+                        "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Masa Produksi",
-                        "name": "masa_produksi",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "Foto Tanaman",
-                        "name": "foto_tanaman",
-                        "in": "formData"
+                        "description": "Filter satu organisasi",
+                        "name": "organization_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Data kebun berhasil diambil",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Menghapus tanaman berdasarkan ID",
+                "description": "Membuat data kebun baru (response mengikuti utils.Response). Pembuat otomatis menjadi owner kebun.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tanaman"
+                    "Kebun"
                 ],
-                "summary": "Hapus tanaman",
+                "summary": "Membuat kebun baru",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID Tanaman",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Input kebun",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateKebunRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/utils.Response"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "controllers.BookingRequest": {
-            "type": "object",
-            "properties": {
+        },
+        "/kebun-invitations/accept": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Token dari pengundang. Email akun yang login harus sama dengan email undangan.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Kebun Member"
+                ],
+                "summary": "Terima undangan kebun",
+                "parameters": [
+                    {
+                        "description": "Token undangan",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.AcceptKebunInvitationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/kebun/nearby": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Mencari kebun dalam radius tertentu dari sebuah titik, urut dari yang terdekat.\nTerbuka untuk semua user login, hanya kebun yang boleh dilihat pengguna (organisasi / keanggotaan,\nkatalog untuk Pembeli) dan hanya data publik kebun yang dikembalikan.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Kebun"
+                ],
+                "summary": "Kebun terdekat",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Latitude titik asal",
+                        "name": "lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude titik asal",
+                        "name": "lng",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Radius dalam km (default 25, maks 200)",
+                        "name": "radius_km",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah maksimal hasil (default 20, maks 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/kebun/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Mengambil detail kebun berdasarkan ID",
+                "tags": [
+                    "Kebun"
+                ],
+                "summary": "Ambil detail kebun",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Kebun",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Mengupdate data kebun berdasarkan ID (partial update). Hanya owner / manager kebun.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Kebun"
+                ],
+                "summary": "Update kebun",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Kebun",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Input update",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UpdateKebunRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Memindahkan kebun beserta tanaman, fase, buah, dan log penyakitnya ke trash dalam satu transaksi.\nDitolak (409) jika masih ada booking aktif. Hanya owner kebun.",
+                "tags": [
+                    "Kebun"
+                ],
+                "summary": "Hapus kebun",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Kebun",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "true = hanya tampilkan data yang akan terhapus",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/kebun/{id}/blok": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Blok tanam di kebun beserta jumlah tanaman per blok",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Blok"
+                ],
+                "summary": "Daftar blok kebun",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Kebun",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Kode blok unik per kebun (disimpan huruf besar). Hanya owner / manager kebun.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Blok"
+                ],
+                "summary": "Tambah blok",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Kebun",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Blok",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.BlokRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/kebun/{id}/geojson": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "FeatureCollection untuk web map. Feature pertama batas kebun (jika ada, properties.jenis = \"kebun\"),\nsisanya satu feature Point per tanaman (properties.jenis = \"tanaman\") berisi fase terakhir,\nkondisi penyakit terbaru, dan status booking. Tanaman tanpa GPS tetap dikirim dengan geometry null\nbeserta baris / kolom di blok, kecuali ?hanya_berkoordinat=true.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Kebun"
+                ],
+                "summary": "Peta tanaman kebun (GeoJSON)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Kebun",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Hanya tanaman di blok ini",
+                        "name": "blok_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter fase: belum-ada, bunga, buah, panen",
+                        "name": "fase",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter status tanaman: Active, Dormant, Removed, Replanted (boleh dipisah koma)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "true = lewati tanaman tanpa latitude / longitude",
+                        "name": "hanya_berkoordinat",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.GeoJSONFeatureCollection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/kebun/{id}/invitations": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Undangan yang belum diterima dan belum kedaluwarsa",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Kebun Member"
+                ],
+                "summary": "Daftar undangan kebun",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Kebun",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Membuat undangan lewat email. Token hanya dikembalikan sekali di response ini untuk dikirim ke calon anggota,\nlalu diterima lewat POST /kebun-invitations/accept. Calon anggota yang login dengan email undangan\njuga bisa menerima lewat POST /me/kebun-invitations/{invitation_id}/accept tanpa token.\nPeran selain viewer butuh akun Petani.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Kebun Member"
+                ],
+                "summary": "Undang anggota kebun",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Kebun",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Undangan",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.InviteKebunMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/kebun/{id}/invitations/{invitation_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Kebun Member"
+                ],
+                "summary": "Batalkan undangan kebun",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Kebun",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID Undangan",
+                        "name": "invitation_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/kebun/{id}/members": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Anggota kebun beserta perannya (owner, manager, worker, viewer)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Kebun Member"
+                ],
+                "summary": "Daftar anggota kebun",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Kebun",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/kebun/{id}/members/{user_id}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Owner bisa memberi peran apa saja, manager hanya worker / viewer. Owner terakhir tidak bisa diturunkan.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Kebun Member"
+                ],
+                "summary": "Ubah peran anggota kebun",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Kebun",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID anggota",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Peran baru",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UpdateKebunMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Owner / manager bisa mengeluarkan anggota dengan peran di bawahnya; anggota juga bisa keluar sendiri.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Kebun Member"
+                ],
+                "summary": "Keluarkan anggota kebun",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Kebun",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID anggota",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/kebun/{id}/organization": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Hanya owner kebun yang juga admin organisasi tujuan. organization_id null melepas kebun dari organisasi.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Pindahkan kebun ke organisasi",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Kebun",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Organisasi tujuan",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.AssignKebunOrganizationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/kebun/{id}/prakiraan-panen": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Perkiraan jumlah buah dan berat (kg) per minggu untuk kebun, beserta total per blok",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prakiraan Panen"
+                ],
+                "summary": "Prakiraan panen kebun",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Kebun",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah minggu ke depan (default 12, maks 26)",
+                        "name": "minggu",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/config.PrakiraanPanen"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/kebun/{id}/prakiraan-panen/backtest": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Prakiraan diulang pada beberapa titik di masa lalu hanya dengan data sebelum titik itu,\nlalu dibandingkan dengan panen yang tercatat",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prakiraan Panen"
+                ],
+                "summary": "Backtest prakiraan panen kebun",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Kebun",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Horizon prakiraan dalam minggu (default 12, maks 26)",
+                        "name": "minggu",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah titik backtest, berjarak 4 minggu (default 4, maks 12)",
+                        "name": "titik",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/config.BacktestPrakiraan"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Login user menggunakan email dan password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Login user",
+                "parameters": [
+                    {
+                        "description": "Login credentials",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login success with JWT, atau two_factor_required + challenge_token jika 2FA aktif",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Wrong credentials",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "429": {
+                        "description": "Akun atau IP terkunci sementara",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/login/2fa": {
+            "post": {
+                "description": "Tukar challenge_token dari /login dengan JWT penuh menggunakan kode TOTP atau recovery code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Login langkah kedua (2FA)",
+                "parameters": [
+                    {
+                        "description": "Challenge + kode",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.TwoFactorLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login success with JWT",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Challenge atau kode salah",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "429": {
+                        "description": "Akun atau IP terkunci sementara",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/me/kebun-invitations": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Undangan aktif yang ditujukan ke email user login",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Kebun Member"
+                ],
+                "summary": "Undangan kebun untuk saya",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/kebun-invitations/{invitation_id}/accept": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Menerima undangan dari GET /me/kebun-invitations berdasarkan ID. Cukup login dengan akun\nyang emailnya sama dengan email undangan, token tidak perlu diteruskan oleh pengundang.\nEmail akun lokal tidak diverifikasi, jadi akun yang dibuat setelah undangan tetap wajib pakai token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Kebun Member"
+                ],
+                "summary": "Terima undangan kebun tanpa token",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Undangan",
+                        "name": "invitation_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/permissions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Daftar permission (contoh: kebun:write, penyakit:classify) milik role user yang sedang login,\ndipakai frontend untuk menampilkan / menyembunyikan menu.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Permission efektif user login",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/media/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Galeri"
+                ],
+                "summary": "Ubah keterangan foto",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Foto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Keterangan foto",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UpdateMediaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Menghapus foto dari galeri dan Cloudinary. Jika foto utama yang dihapus, foto berikutnya menjadi foto utama.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Galeri"
+                ],
+                "summary": "Hapus satu foto",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Foto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Organisasi tempat user menjadi anggota (Admin: semua organisasi)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Daftar organisasi",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Halaman",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah data per halaman",
+                        "name": "per_page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Pembuat otomatis menjadi admin organisasi",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Buat organisasi / koperasi",
+                "parameters": [
+                    {
+                        "description": "Organisasi",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateOrganizationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Detail organisasi beserta jumlah anggota dan kebun",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Detail organisasi",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Organisasi",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Ubah nama organisasi",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Organisasi",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Organisasi",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateOrganizationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{id}/members": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Anggota organisasi",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Organisasi",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Menambahkan user terdaftar (berdasarkan email) ke organisasi sebagai admin / member",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Tambah anggota organisasi",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Organisasi",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Anggota",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.AddOrganizationMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{id}/members/{user_id}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Ubah peran anggota organisasi",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Organisasi",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Peran baru (admin / member)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UpdateKebunMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Admin organisasi bisa mengeluarkan anggota; anggota juga bisa keluar sendiri",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Keluarkan anggota organisasi",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Organisasi",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/pembeli/booking": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve paginated list of booking (hanya untuk role Pembeli)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Booking"
+                ],
+                "summary": "Get all booking with pagination",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter status: open, fulfilled, cancelled",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.SwaggerBooking"
+                                            }
+                                        },
+                                        "meta": {
+                                            "$ref": "#/definitions/utils.Pagination"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Booking action by pembeli. Tanaman harus berstatus Active dan belum punya booking open.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Booking"
+                ],
+                "summary": "Create a new booking",
+                "parameters": [
+                    {
+                        "description": "Booking input",
+                        "name": "booking",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.BookingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.SwaggerBooking"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/pembeli/booking/user/{user_id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve user's booking list with pagination. Pembeli hanya boleh melihat user_id miliknya sendiri.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Booking"
+                ],
+                "summary": "Get booking list by user ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "per_page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.SwaggerBooking"
+                                            }
+                                        },
+                                        "meta": {
+                                            "$ref": "#/definitions/utils.Pagination"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/pembeli/booking/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve detail booking by ID (role Pembeli, hanya booking milik sendiri)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Booking"
+                ],
+                "summary": "Get booking by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.SwaggerBooking"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update booking by ID (role Pembeli). Hanya booking open yang bisa diubah, status lewat\nPUT /pembeli/booking/{id}/status.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Booking"
+                ],
+                "summary": "Update existing booking",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Booking input",
+                        "name": "booking",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.BookingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.SwaggerBooking"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete action for booking (role Pembeli)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Booking"
+                ],
+                "summary": "Delete booking by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/pembeli/booking/{id}/status": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Pembeli hanya bisa membatalkan booking miliknya (cancelled). Pengguna dengan permission booking:approve\nyang punya hak tulis tanaman di kebunnya menandai booking fulfilled / cancelled. Booking yang sudah selesai / batal tidak bisa dibuka lagi.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Booking"
+                ],
+                "summary": "Ubah status booking",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Status baru",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.BookingStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.SwaggerBooking"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/pembeli/kebun/{id}/prakiraan-panen": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Total perkiraan buah dan berat (kg) per minggu tanpa rincian blok / pohon",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prakiraan Panen"
+                ],
+                "summary": "Prakiraan panen kebun untuk pembeli",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Kebun",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah minggu ke depan (default 12, maks 26)",
+                        "name": "minggu",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/config.PrakiraanPanen"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/petamin/penyakit/{id_tanaman}": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Menerima foto tanaman alpukat, mengklasifikasikan penyakit menggunakan Gemini AI, mengunggah foto, dan menyimpan log ke database. Foto yang identik dengan foto klasifikasi sebelumnya memakai diagnosis lama tanpa memanggil Gemini (dari_cache = true).",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Petani \u0026 Admin (Deteksi)"
+                ],
+                "summary": "Klasifikasi Penyakit Tanaman Alpukat",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Tanaman yang ingin diklasifikasi penyakitnya",
+                        "name": "id_tanaman",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Foto daun atau bagian tanaman yang sakit (JPG, PNG, dll.)",
+                        "name": "foto_tanaman",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Klasifikasi berhasil\" // \u003c-- Menggunakan wrapper dari package controllers",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.SuccessResponseWrapper"
+                        }
+                    },
+                    "400": {
+                        "description": "ID tanaman tidak valid atau Foto tanaman wajib diunggah\" // \u003c-- Menggunakan wrapper dari package controllers",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponseWrapper"
+                        }
+                    },
+                    "500": {
+                        "description": "Gagal konek DB, Inisialisasi Gemini gagal, Proses klasifikasi atau upload gagal, atau Gagal menyimpan data\" // \u003c-- Menggunakan wrapper dari package controllers",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponseWrapper"
+                        }
+                    }
+                }
+            }
+        },
+        "/petani/buah": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve paginated list of buah",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Buah"
+                ],
+                "summary": "Get all buah with pagination",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter satu organisasi",
+                        "name": "organization_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Buah"
+                ],
+                "summary": "Create a new buah",
+                "parameters": [
+                    {
+                        "description": "Buah Data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "nama_buah": {
+                                    "type": "string"
+                                },
+                                "tanaman_id": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/petani/buah/by-tanaman/{id_kebun}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve buah based on related tanaman kebun",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Buah"
+                ],
+                "summary": "Get buah by kebun ID with pagination",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Kebun ID",
+                        "name": "id_kebun",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "per_page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/petani/buah/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Buah"
+                ],
+                "summary": "Get buah by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Buah ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Buah"
+                ],
+                "summary": "Update buah",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Buah ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Buah Data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "nama_buah": {
+                                    "type": "string"
+                                },
+                                "tanaman_id": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Buah"
+                ],
+                "summary": "Delete buah",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Buah ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/petani/fase-berbuah": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Mendapatkan semua data fase berbuah dengan pagination",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fase Berbuah"
+                ],
+                "summary": "Get all fase berbuah",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Halaman",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Jumlah item per halaman",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter satu organisasi",
+                        "name": "organization_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Menambahkan data fase berbuah baru untuk tanaman. Bisa juga dikirim sebagai multipart/form-data dengan\nfield yang sama ditambah foto (opsional, masuk galeri); tanggal_catat kosong memakai tanggal EXIF foto.",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fase Berbuah"
+                ],
+                "summary": "Create fase berbuah",
+                "parameters": [
+                    {
+                        "description": "Fase Buah Data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateFaseBuahInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/petani/fase-berbuah/tanaman/{tanaman_id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Mendapatkan data fase berbuah berdasarkan tanaman ID dengan pagination",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fase Berbuah"
+                ],
+                "summary": "Get fase berbuah by tanaman ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Tanaman",
+                        "name": "tanaman_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Halaman",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Jumlah item per halaman",
+                        "name": "per_page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/petani/fase-berbuah/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Mendapatkan detail fase berbuah berdasarkan ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fase Berbuah"
+                ],
+                "summary": "Get fase berbuah by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Fase Buah",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Mengupdate data fase berbuah berdasarkan ID. Estimasi panen dihitung ulang jika cover / tanaman\nberubah, kecuali estimasinya diisi manual; kirim hitung_ulang_estimasi = true untuk menggantinya.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fase Berbuah"
+                ],
+                "summary": "Update fase berbuah",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Fase Buah",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fase Buah Data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UpdateFaseBuahInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Menghapus fase berbuah berdasarkan ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fase Berbuah"
+                ],
+                "summary": "Delete fase berbuah",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Fase Buah",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/petani/fase-bunga": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Mendapatkan daftar semua fase bunga (dengan pagination)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fase Bunga"
+                ],
+                "summary": "Get all fase bunga",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Nomor halaman",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah data per halaman",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter satu organisasi",
+                        "name": "organization_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Menambahkan fase bunga baru untuk tanaman. Bisa juga dikirim sebagai multipart/form-data dengan\nfield yang sama ditambah foto (opsional, masuk galeri); tanggal_catat kosong memakai tanggal EXIF foto.",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fase Bunga"
+                ],
+                "summary": "Create fase bunga",
+                "parameters": [
+                    {
+                        "description": "Fase Bunga Data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateFaseBungaInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/petani/fase-bunga/tanaman/{tanaman_id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Mendapatkan semua fase bunga milik tanaman tertentu",
+                "tags": [
+                    "Fase Bunga"
+                ],
+                "summary": "Get fase bunga by tanaman ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Tanaman",
+                        "name": "tanaman_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/petani/fase-bunga/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Mendapatkan detail fase bunga berdasarkan ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fase Bunga"
+                ],
+                "summary": "Get fase bunga by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Fase Bunga",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Mengupdate data fase bunga berdasarkan ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fase Bunga"
+                ],
+                "summary": "Update fase bunga",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Fase Bunga",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fase Bunga Data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UpdateFaseBungaInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Menghapus fase bunga berdasarkan ID",
+                "tags": [
+                    "Fase Bunga"
+                ],
+                "summary": "Delete fase bunga",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Fase Bunga",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/petani/fase-panen": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Mendapatkan semua fase panen dengan pagination",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fase Panen"
+                ],
+                "summary": "Get all fase panen",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Nomor halaman",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah data per halaman",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter satu organisasi",
+                        "name": "organization_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Menambahkan data fase panen baru",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fase Panen"
+                ],
+                "summary": "Create fase panen",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tanggal Panen Aktual (YYYY-MM-DD), default tanggal EXIF foto_panen",
+                        "name": "tanggal_panen_aktual",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah Panen",
+                        "name": "jumlah_panen",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah Sampel",
+                        "name": "jumlah_sampel",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Berat Total (Kg)",
+                        "name": "berat_total",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Catatan",
+                        "name": "catatan",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Foto Panen",
+                        "name": "foto_panen",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID Tanaman",
+                        "name": "tanaman_id",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/petani/fase-panen/tanaman/{tanaman_id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Mendapatkan daftar fase panen milik tanaman tertentu",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fase Panen"
+                ],
+                "summary": "Get fase panen by tanaman ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Tanaman",
+                        "name": "tanaman_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nomor halaman",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah data per halaman",
+                        "name": "per_page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/petani/fase-panen/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Mendapatkan detail fase panen",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fase Panen"
+                ],
+                "summary": "Get fase panen by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Fase Panen",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Mengupdate data fase panen",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fase Panen"
+                ],
+                "summary": "Update fase panen",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Fase Panen",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "YYYY-MM-DD",
+                        "name": "tanggal_panen_aktual",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah Panen",
+                        "name": "jumlah_panen",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah Sampel",
+                        "name": "jumlah_sampel",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Berat Total (Kg)",
+                        "name": "berat_total",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Catatan",
+                        "name": "catatan",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Foto Panen",
+                        "name": "foto_panen",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID Tanaman",
+                        "name": "tanaman_id",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Menghapus fase panen berdasarkan ID",
+                "tags": [
+                    "Fase Panen"
+                ],
+                "summary": "Delete fase panen",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Fase Panen",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/pembeli": {
+            "post": {
+                "description": "Register pembeli menggunakan credential lokal",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Register Pembeli",
+                "parameters": [
+                    {
+                        "description": "Register Pembeli",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Registered successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Email or phone already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/register/petani": {
+            "post": {
+                "description": "Register petani menggunakan credential lokal",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Register Petani",
+                "parameters": [
+                    {
+                        "description": "Register Petani",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Registered successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Email or phone already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tanaman": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Mengambil daftar tanaman dengan pagination. Pembeli melihat tanaman semua kebun (read-only).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tanaman"
+                ],
+                "summary": "Ambil semua tanaman",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Halaman",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah data per halaman",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter satu organisasi",
+                        "name": "organization_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter status: Active, Dormant, Removed, Replanted (boleh dipisah koma)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Menambahkan tanaman baru",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tanaman"
+                ],
+                "summary": "Tambah tanaman",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nama Tanaman",
+                        "name": "nama_tanaman",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID Varietas (lihat /varietas)",
+                        "name": "varietas_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nama varietas, dipakai jika varietas_id kosong",
+                        "name": "varietas",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal Tanam (YYYY-MM-DD)",
+                        "name": "tanggal_tanam",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID Kebun",
+                        "name": "kebun_id",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID Blok (lihat /kebun/{id}/blok)",
+                        "name": "blok_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Kode blok di kebun tersebut, dipakai jika blok_id kosong",
+                        "name": "kode_blok",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Kode Tanaman",
+                        "name": "kode_tanaman",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Masa Produksi",
+                        "name": "masa_produksi",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude GPS pohon, berpasangan dengan longitude",
+                        "name": "latitude",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude GPS pohon",
+                        "name": "longitude",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Baris di grid blok, berpasangan dengan kolom",
+                        "name": "baris",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Kolom di grid blok",
+                        "name": "kolom",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Foto utama tanaman, juga masuk galeri (foto lain lewat /galeri/tanaman/{id})",
+                        "name": "foto_tanaman",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/tanaman/by-kebun/{id_kebun}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Ambil data tanaman berdasarkan kebun_id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tanaman"
+                ],
+                "summary": "Tanaman berdasarkan Kebun",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Kebun",
+                        "name": "id_kebun",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Halaman",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah per halaman",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter satu organisasi",
+                        "name": "organization_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/tanaman/lookup": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "code berisi isi QR (AVC:T:\u003ctanaman_id\u003e, atau AVC:\u003ckebun_id\u003e:\u003ckode_tanaman\u003e untuk label lama).\nBisa juga kirim kebun_id + kode_tanaman langsung.\nMengembalikan tanaman beserta fase terakhirnya, dipakai sebelum mencatat fase baru.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tanaman Label"
+                ],
+                "summary": "Cari tanaman dari hasil scan QR",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Isi QR hasil scan",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID Kebun, jika tanpa code",
+                        "name": "kebun_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Kode tanaman, jika tanpa code",
+                        "name": "kode_tanaman",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/tanaman/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Mendapatkan detail tanaman berdasarkan ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tanaman"
+                ],
+                "summary": "Detail tanaman",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Tanaman",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Mengubah data tanaman (bisa sebagian)",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tanaman"
+                ],
+                "summary": "Update tanaman",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Tanaman",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nama Tanaman",
+                        "name": "nama_tanaman",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID Varietas",
+                        "name": "varietas_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nama varietas, dipakai jika varietas_id kosong",
+                        "name": "varietas",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal Tanam",
+                        "name": "tanggal_tanam",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID Kebun",
+                        "name": "kebun_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID Blok",
+                        "name": "blok_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Kode blok, dipakai jika blok_id kosong. Wajib salah satu jika pindah kebun dan kode lama tidak ada di kebun tujuan",
+                        "name": "kode_blok",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Kode Tanaman",
+                        "name": "kode_tanaman",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Masa Produksi",
+                        "name": "masa_produksi",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude GPS pohon, berpasangan dengan longitude",
+                        "name": "latitude",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude GPS pohon",
+                        "name": "longitude",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Baris di grid blok, berpasangan dengan kolom",
+                        "name": "baris",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Kolom di grid blok",
+                        "name": "kolom",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Foto utama baru, foto lama tetap di galeri",
+                        "name": "foto_tanaman",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Memindahkan tanaman beserta fase, buah, dan log penyakitnya ke trash, bisa di-restore lewat\n/trash/tanaman/{id}/restore. Ditolak (409) jika masih ada booking aktif.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tanaman"
+                ],
+                "summary": "Hapus tanaman",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Tanaman",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "true = hanya tampilkan data yang akan terhapus",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/tanaman/{id}/booking": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Riwayat booking tanaman untuk anggota kebun, booking open paling atas",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Booking"
+                ],
+                "summary": "Booking sebuah tanaman",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Tanaman",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.SwaggerBooking"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/tanaman/{id}/fase-history": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Fase bunga, buah, dan panen sebuah tanaman sesuai kondisi data pada waktu as_of\n(sebelum koreksi / penghapusan setelah waktu tersebut)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tanaman"
+                ],
+                "summary": "Data fase tanaman per tanggal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Tanaman",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal (YYYY-MM-DD, dihitung akhir hari) atau RFC3339",
+                        "name": "as_of",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/tanaman/{id}/generasi": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Rantai tanaman di posisi yang sama, dari generasi pertama sampai penerus terakhir",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tanaman"
+                ],
+                "summary": "Riwayat tanam ulang",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Tanaman",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/tanaman/{id}/label.pdf": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Lembar label A4 berisi satu label QR tanaman",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Tanaman Label"
+                ],
+                "summary": "Label PDF tanaman",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Tanaman",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/tanaman/{id}/prakiraan-panen": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Perkiraan jumlah buah dan berat (kg) per minggu dari cover yang belum dipanen, bunga / pentil\nterakhir yang belum di-cover, dan berat buah panen sebelumnya",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prakiraan Panen"
+                ],
+                "summary": "Prakiraan panen tanaman",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Tanaman",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah minggu ke depan (default 12, maks 26)",
+                        "name": "minggu",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/config.PrakiraanPanen"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/tanaman/{id}/prakiraan-panen/backtest": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Prakiraan diulang pada beberapa titik di masa lalu hanya dengan data sebelum titik itu,\nlalu dibandingkan dengan panen yang tercatat",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prakiraan Panen"
+                ],
+                "summary": "Backtest prakiraan panen tanaman",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Tanaman",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Horizon prakiraan dalam minggu (default 12, maks 26)",
+                        "name": "minggu",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah titik backtest, berjarak 4 minggu (default 4, maks 12)",
+                        "name": "titik",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/config.BacktestPrakiraan"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/tanaman/{id}/qr": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "PNG QR berisi AVC:T:\u003ctanaman_id\u003e, dipakai untuk tag di pohon",
+                "produces": [
+                    "image/png"
+                ],
+                "tags": [
+                    "Tanaman Label"
+                ],
+                "summary": "QR code tanaman",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Tanaman",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ukuran sisi dalam pixel (128 - 1024, default 256)",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/tanaman/{id}/replant": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Menandai tanaman Replanted dan membuat tanaman penerus di kebun, blok, dan posisi yang sama.\nPenerus menyimpan tanaman_sebelumnya_id. Data yang tidak diisi mengikuti pendahulu.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tanaman"
+                ],
+                "summary": "Tanam ulang",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Tanaman pendahulu",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data tanaman penerus",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ReplantTanamanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/tanaman/{id}/status": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Active \u003c-\u003e Dormant, Active / Dormant -\u003e Removed. Tanaman Removed tetap tersimpan beserta riwayatnya,\ntidak dihitung di statistik, dan tidak bisa dicatat fase baru. Removed ditolak jika masih ada booking.\nUntuk mengganti pohon mati gunakan POST /tanaman/{id}/replant.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tanaman"
+                ],
+                "summary": "Ubah status tanaman",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Tanaman",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Status baru",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UpdateTanamanStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/{entity}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Record yang sudah dihapus (soft delete) dan masih bisa di-restore sebelum purge otomatis.\nentity: kebun, blok, tanaman, fase-bunga, fase-berbuah, fase-panen, log-penyakit, buah, booking",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Daftar data di trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Jenis entitas",
+                        "name": "entity",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter satu organisasi",
+                        "name": "organization_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Halaman",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah data per halaman",
+                        "name": "per_page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/{entity}/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Menghapus permanen record beserta turunan yang ikut di-trash bersamanya dan fotonya di Cloudinary.\nTidak bisa dibatalkan. Gagal 409 jika masih ada turunan yang aktif atau di-trash terpisah.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Hapus permanen data di trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Jenis entitas",
+                        "name": "entity",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID record",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/{entity}/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Mengembalikan record beserta data turunannya yang terhapus bersamaan\n(contoh: restore kebun ikut mengembalikan tanaman \u0026 fase yang terhapus bersama kebun).\nGagal 409 jika induknya masih di trash atau nilai uniknya (contoh kode_tanaman) sudah dipakai record aktif.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Restore data dari trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Jenis entitas",
+                        "name": "entity",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID record",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/upload/sesi": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Untuk koneksi lambat: file dikirim per potongan lewat PATCH /upload/sesi/{id} dan bisa dilanjutkan\ndari offset terakhir jika koneksi putus. Setelah potongan terakhir diterima, foto diproses dan masuk galeri.\nJumlah sesi yang belum selesai per user dibatasi (UPLOAD_SESSION_MAX_PER_USER, default 5).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Galeri"
+                ],
+                "summary": "Mulai upload foto bertahap",
+                "parameters": [
+                    {
+                        "description": "Data sesi upload",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateUploadSesiRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controllers.UploadSesiResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/upload/sesi/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Dipakai setelah koneksi putus untuk mengetahui offset lanjutan (juga di header Upload-Offset)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Galeri"
+                ],
+                "summary": "Posisi upload bertahap",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID sesi upload",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controllers.UploadSesiResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Sesi dan potongan yang sudah diterima dihapus",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Galeri"
+                ],
+                "summary": "Batalkan upload bertahap",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID sesi upload",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Body berisi byte mentah potongan file mulai dari offset (header Upload-Offset atau ?offset), harus sama\ndengan offset yang sudah diterima server. Potongan yang terputus di tengah tetap disimpan sebagian.\nPotongan terakhir memproses foto dan memasukkannya ke galeri (201); kirim body kosong di offset akhir\nuntuk mengulang pemrosesan yang gagal.",
+                "consumes": [
+                    "application/offset+octet-stream"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Galeri"
+                ],
+                "summary": "Kirim potongan file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID sesi upload",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset byte potongan ini",
+                        "name": "Upload-Offset",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Alternatif header Upload-Offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Isi potongan file",
+                        "name": "chunk",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controllers.UploadSesiResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/varietas": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Data referensi varietas alpukat untuk form tanaman",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Varietas"
+                ],
+                "summary": "Daftar varietas",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Varietas"
+                ],
+                "summary": "Tambah varietas",
+                "parameters": [
+                    {
+                        "description": "Varietas",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.VarietasRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/varietas/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Varietas"
+                ],
+                "summary": "Detail varietas",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Varietas",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Varietas"
+                ],
+                "summary": "Ubah varietas",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Varietas",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Field yang diubah",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.VarietasRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Ditolak jika masih dipakai tanaman",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Varietas"
+                ],
+                "summary": "Hapus varietas",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Varietas",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "config.BacktestMinggu": {
+            "type": "object",
+            "properties": {
+                "aktual_buah": {
+                    "type": "number"
+                },
+                "aktual_kg": {
+                    "type": "number"
+                },
+                "minggu_mulai": {
+                    "type": "string"
+                },
+                "prediksi_buah": {
+                    "type": "number"
+                },
+                "prediksi_kg": {
+                    "type": "number"
+                }
+            }
+        },
+        "config.BacktestPrakiraan": {
+            "type": "object",
+            "properties": {
+                "bias_kg_persen": {
+                    "description": "positif = prakiraan cenderung lebih tinggi",
+                    "type": "number"
+                },
+                "minggu": {
+                    "type": "integer"
+                },
+                "titik": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.BacktestTitik"
+                    }
+                },
+                "wape_kg_persen": {
+                    "description": "gabungan semua titik",
+                    "type": "number"
+                }
+            }
+        },
+        "config.BacktestTitik": {
+            "type": "object",
+            "properties": {
+                "aktual_buah": {
+                    "type": "number"
+                },
+                "aktual_kg": {
+                    "type": "number"
+                },
+                "galat_kg_persen": {
+                    "description": "(prediksi - aktual) / aktual, null jika belum ada panen",
+                    "type": "number"
+                },
+                "mingguan": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.BacktestMinggu"
+                    }
+                },
+                "mulai": {
+                    "type": "string"
+                },
+                "prediksi_buah": {
+                    "type": "number"
+                },
+                "prediksi_kg": {
+                    "type": "number"
+                },
+                "wape_kg_persen": {
+                    "description": "galat absolut mingguan / total aktual",
+                    "type": "number"
+                }
+            }
+        },
+        "config.EstimasiPanen": {
+            "type": "object",
+            "properties": {
+                "akhir": {
+                    "type": "string"
+                },
+                "awal": {
+                    "type": "string"
+                },
+                "hari_cover_panen": {
+                    "type": "integer"
+                },
+                "keyakinan": {
+                    "description": "0-1",
+                    "type": "number"
+                },
+                "koreksi_ketinggian_hari": {
+                    "description": "hari tambahan (negatif = lebih cepat) dari ketinggian kebun",
+                    "type": "integer"
+                },
+                "sampel": {
+                    "description": "jumlah interval riwayat yang dipakai",
+                    "type": "integer"
+                },
+                "sumber": {
+                    "type": "string"
+                },
+                "tanggal": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.MediaGCReport": {
+            "type": "object",
+            "properties": {
+                "dihapus": {
+                    "type": "integer"
+                },
+                "dirujuk": {
+                    "type": "integer"
+                },
+                "gagal_hapus": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "orphan": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.OrphanAsset"
+                    }
+                },
+                "orphan_bytes": {
+                    "type": "integer"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "total_aset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "config.OrphanAsset": {
+            "type": "object",
+            "properties": {
+                "bytes": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "public_id": {
+                    "type": "string"
+                },
+                "siap_dihapus": {
+                    "description": "sudah melewati masa tenggang",
+                    "type": "boolean"
+                }
+            }
+        },
+        "config.PrakiraanAsumsi": {
+            "type": "object",
+            "properties": {
+                "berat_buah_kg": {
+                    "description": "rata-rata kebun, pohon dengan riwayat memakai beratnya sendiri",
+                    "type": "number"
+                },
+                "rasio_bunga_pentil": {
+                    "description": "pentil per bunga",
+                    "type": "number"
+                },
+                "rasio_panen_cover": {
+                    "description": "buah dipanen per buah di-cover",
+                    "type": "number"
+                },
+                "rasio_pentil_cover": {
+                    "description": "buah di-cover per pentil",
+                    "type": "number"
+                }
+            }
+        },
+        "config.PrakiraanBlok": {
+            "type": "object",
+            "properties": {
+                "berat_kg": {
+                    "type": "number"
+                },
+                "blok_id": {
+                    "type": "integer"
+                },
+                "jumlah_buah": {
+                    "type": "number"
+                },
+                "jumlah_tanaman": {
+                    "type": "integer"
+                },
+                "kode_blok": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.PrakiraanMinggu": {
+            "type": "object",
+            "properties": {
+                "berat_kg": {
+                    "type": "number"
+                },
+                "dari_bunga": {
+                    "description": "buah dari bunga / pentil yang belum di-cover",
+                    "type": "number"
+                },
+                "dari_cover": {
+                    "description": "buah dari cover yang sudah tercatat",
+                    "type": "number"
+                },
+                "jumlah_buah": {
+                    "type": "number"
+                },
+                "minggu_mulai": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.PrakiraanPanen": {
+            "type": "object",
+            "properties": {
+                "asumsi": {
+                    "$ref": "#/definitions/config.PrakiraanAsumsi"
+                },
+                "berat_kg": {
+                    "type": "number"
+                },
+                "blok": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.PrakiraanBlok"
+                    }
+                },
+                "jumlah_buah": {
+                    "type": "number"
+                },
+                "minggu": {
+                    "type": "integer"
+                },
+                "mingguan": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.PrakiraanMinggu"
+                    }
+                },
+                "mulai": {
+                    "type": "string"
+                },
+                "tanaman": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.PrakiraanTanaman"
+                    }
+                }
+            }
+        },
+        "config.PrakiraanTanaman": {
+            "type": "object",
+            "properties": {
+                "berat_buah_kg": {
+                    "type": "number"
+                },
+                "berat_kg": {
+                    "type": "number"
+                },
+                "blok_id": {
+                    "type": "integer"
+                },
+                "jumlah_buah": {
+                    "type": "number"
+                },
+                "kode_blok": {
+                    "type": "string"
+                },
+                "kode_tanaman": {
+                    "type": "string"
+                },
+                "tanaman_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.AcceptKebunInvitationRequest": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "q1w2e3r4..."
+                }
+            }
+        },
+        "controllers.AddOrganizationMemberRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "petani@example.com"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "member"
+                }
+            }
+        },
+        "controllers.AssignKebunOrganizationRequest": {
+            "type": "object",
+            "properties": {
+                "organization_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "controllers.BlokRequest": {
+            "type": "object",
+            "properties": {
+                "jumlah_baris": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "jumlah_kolom": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "kode": {
+                    "type": "string",
+                    "example": "A1"
+                },
+                "luas_hektar": {
+                    "type": "number",
+                    "example": 0.75
+                },
+                "nama": {
+                    "type": "string",
+                    "example": "Blok lereng timur"
+                },
+                "zona_irigasi": {
+                    "type": "string",
+                    "example": "Zona 1"
+                }
+            }
+        },
+        "controllers.BookingRequest": {
+            "type": "object",
+            "properties": {
                 "tanaman_id": {
                     "type": "integer",
                     "example": 10
@@ -2633,6 +8172,15 @@ const docTemplate = `{
                 }
             }
         },
+        "controllers.BookingStatusRequest": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "fulfilled"
+                }
+            }
+        },
         "controllers.ClassifyPenyakitData": {
             "type": "object",
             "properties": {
@@ -2691,37 +8239,142 @@ const docTemplate = `{
                 },
                 "warna_label": {
                     "type": "string",
-                    "example": "Hijau"
+                    "example": "Hijau"
+                }
+            }
+        },
+        "controllers.CreateFaseBungaInput": {
+            "type": "object",
+            "properties": {
+                "bunga_pecah": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "jumlah_bunga": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "minggu_ke": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "pentil_muncul": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "tanaman_id": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "tanggal_catat": {
+                    "description": "format ISO-8601",
+                    "type": "string",
+                    "example": "2025-12-20"
+                }
+            }
+        },
+        "controllers.CreateKebunRequest": {
+            "type": "object",
+            "required": [
+                "nama_kebun"
+            ],
+            "properties": {
+                "alamat": {
+                    "type": "string"
+                },
+                "batas": {
+                    "description": "GeoJSON Polygon, \"null\" untuk menghapus",
+                    "type": "object"
+                },
+                "desa": {
+                    "type": "string"
+                },
+                "kabupaten": {
+                    "type": "string"
+                },
+                "kecamatan": {
+                    "type": "string"
+                },
+                "ketinggian": {
+                    "type": "number",
+                    "example": 1200
+                },
+                "kode_pos": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number",
+                    "example": -7.2575
+                },
+                "longitude": {
+                    "type": "number",
+                    "example": 112.7521
+                },
+                "luas_hektar": {
+                    "type": "number",
+                    "example": 2.5
+                },
+                "mdpl": {
+                    "type": "string"
+                },
+                "nama_kebun": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "provinsi": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.CreateOrganizationRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Koperasi Alpukat Sumberjaya"
                 }
             }
         },
-        "controllers.CreateFaseBungaInput": {
+        "controllers.CreateUploadSesiRequest": {
             "type": "object",
+            "required": [
+                "owner_id",
+                "owner_type",
+                "ukuran"
+            ],
             "properties": {
-                "bunga_pecah": {
-                    "type": "integer",
-                    "example": 2
+                "caption": {
+                    "type": "string",
+                    "example": "Buah pertama musim ini"
                 },
-                "jumlah_bunga": {
-                    "type": "integer",
-                    "example": 10
+                "filename": {
+                    "type": "string",
+                    "example": "IMG_0421.jpg"
                 },
-                "minggu_ke": {
-                    "type": "integer",
-                    "example": 3
+                "jadikan_utama": {
+                    "description": "foto langsung menjadi foto utama",
+                    "type": "boolean"
                 },
-                "pentil_muncul": {
+                "owner_id": {
                     "type": "integer",
-                    "example": 1
+                    "example": 12
                 },
-                "tanaman_id": {
-                    "type": "integer",
-                    "example": 5
+                "owner_type": {
+                    "type": "string",
+                    "example": "tanaman"
                 },
-                "tanggal_catat": {
-                    "description": "format ISO-8601",
+                "taken_at": {
+                    "description": "RFC3339 atau YYYY-MM-DD, default EXIF",
                     "type": "string",
-                    "example": "2025-12-20"
+                    "example": "2025-01-31T08:30:00+07:00"
+                },
+                "ukuran": {
+                    "description": "byte",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 4194304
                 }
             }
         },
@@ -2745,6 +8398,19 @@ const docTemplate = `{
                 }
             }
         },
+        "controllers.InviteKebunMemberRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "pekerja@example.com"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "worker"
+                }
+            }
+        },
         "controllers.LogPenyakitTanamanCustom": {
             "type": "object",
             "properties": {
@@ -2820,6 +8486,52 @@ const docTemplate = `{
                 }
             }
         },
+        "controllers.ReorderMediaRequest": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "controllers.ReplantTanamanRequest": {
+            "type": "object",
+            "properties": {
+                "alasan": {
+                    "type": "string",
+                    "example": "Mati terkena busuk akar"
+                },
+                "kode_tanaman": {
+                    "description": "default \u003ckode lama\u003e-R\u003cn\u003e",
+                    "type": "string",
+                    "example": "A1-001-R2"
+                },
+                "masa_produksi": {
+                    "type": "integer"
+                },
+                "nama_tanaman": {
+                    "type": "string"
+                },
+                "tanggal_tanam": {
+                    "description": "YYYY-MM-DD, default hari ini",
+                    "type": "string",
+                    "example": "2025-02-01"
+                },
+                "varietas": {
+                    "type": "string"
+                },
+                "varietas_id": {
+                    "type": "string"
+                }
+            }
+        },
         "controllers.SuccessResponseWrapper": {
             "type": "object",
             "properties": {
@@ -2873,6 +8585,87 @@ const docTemplate = `{
                 }
             }
         },
+        "controllers.TwoFactorCodeRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "controllers.TwoFactorDisableRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "secret123"
+                },
+                "recovery_code": {
+                    "type": "string",
+                    "example": "ABCDE-FGHIJ"
+                }
+            }
+        },
+        "controllers.TwoFactorLoginRequest": {
+            "type": "object",
+            "properties": {
+                "challenge_token": {
+                    "type": "string",
+                    "example": "eyJhbGciOi..."
+                },
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "recovery_code": {
+                    "type": "string",
+                    "example": "ABCDE-FGHIJ"
+                }
+            }
+        },
+        "controllers.UpdateFaseBuahInput": {
+            "type": "object",
+            "properties": {
+                "estimasi_panen": {
+                    "description": "estimasi manual",
+                    "type": "string",
+                    "example": "2026-04-20"
+                },
+                "hitung_ulang_estimasi": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "jumlah_cover": {
+                    "type": "integer",
+                    "example": 15
+                },
+                "minggu_ke": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "tanaman_id": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "tanggal_catat": {
+                    "type": "string",
+                    "example": "2025-11-29"
+                },
+                "tanggal_cover": {
+                    "type": "string",
+                    "example": "2025-11-30"
+                },
+                "warna_label": {
+                    "type": "string",
+                    "example": "Hijau"
+                }
+            }
+        },
         "controllers.UpdateFaseBungaInput": {
             "type": "object",
             "properties": {
@@ -2902,14 +8695,160 @@ const docTemplate = `{
                 }
             }
         },
+        "controllers.UpdateKebunMemberRequest": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "example": "manager"
+                }
+            }
+        },
         "controllers.UpdateKebunRequest": {
             "type": "object",
             "properties": {
+                "alamat": {
+                    "type": "string"
+                },
+                "batas": {
+                    "description": "GeoJSON Polygon, \"null\" untuk menghapus",
+                    "type": "object"
+                },
+                "desa": {
+                    "type": "string"
+                },
+                "kabupaten": {
+                    "type": "string"
+                },
+                "kecamatan": {
+                    "type": "string"
+                },
+                "ketinggian": {
+                    "type": "number",
+                    "example": 1200
+                },
+                "kode_pos": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number",
+                    "example": -7.2575
+                },
+                "longitude": {
+                    "type": "number",
+                    "example": 112.7521
+                },
+                "luas_hektar": {
+                    "type": "number",
+                    "example": 2.5
+                },
                 "mdpl": {
                     "type": "string"
                 },
                 "nama_kebun": {
                     "type": "string"
+                },
+                "provinsi": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.UpdateMediaRequest": {
+            "type": "object",
+            "properties": {
+                "caption": {
+                    "type": "string",
+                    "example": "Daun menguning di sisi timur"
+                },
+                "taken_at": {
+                    "description": "RFC3339 atau YYYY-MM-DD",
+                    "type": "string",
+                    "example": "2025-01-31T08:30:00+07:00"
+                }
+            }
+        },
+        "controllers.UpdateSecurityPolicyRequest": {
+            "type": "object",
+            "properties": {
+                "require_2fa_admin": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "controllers.UpdateTanamanStatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "alasan": {
+                    "type": "string",
+                    "example": "Tidak berbunga musim ini"
+                },
+                "status": {
+                    "description": "Active, Dormant, Removed",
+                    "type": "string",
+                    "example": "Dormant"
+                },
+                "tanggal": {
+                    "description": "YYYY-MM-DD, default hari ini",
+                    "type": "string",
+                    "example": "2025-01-31"
+                }
+            }
+        },
+        "controllers.UploadSesiResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "owner_id": {
+                    "type": "integer"
+                },
+                "owner_type": {
+                    "type": "string"
+                },
+                "ukuran": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.VarietasRequest": {
+            "type": "object",
+            "properties": {
+                "berat_buah_gram": {
+                    "type": "number",
+                    "example": 450
+                },
+                "deskripsi": {
+                    "type": "string"
+                },
+                "hari_cover_panen": {
+                    "type": "integer",
+                    "example": 180
+                },
+                "ketinggian_max": {
+                    "type": "integer",
+                    "example": 1200
+                },
+                "ketinggian_min": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "nama": {
+                    "type": "string",
+                    "example": "Alligator"
                 }
             }
         },
@@ -2925,6 +8864,10 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
+                "status": {
+                    "type": "string",
+                    "example": "open"
+                },
                 "tanaman": {
                     "$ref": "#/definitions/models.SwaggerTanaman"
                 },
@@ -3020,6 +8963,77 @@ const docTemplate = `{
                 }
             }
         },
+        "utils.GeoJSONFeature": {
+            "type": "object",
+            "properties": {
+                "geometry": {},
+                "id": {},
+                "properties": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "utils.GeoJSONFeatureCollection": {
+            "type": "object",
+            "properties": {
+                "features": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.GeoJSONFeature"
+                    }
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "utils.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "type": "string"
+                },
+                "e": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "n": {
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                },
+                "y": {
+                    "type": "string"
+                }
+            }
+        },
+        "utils.JWKS": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.JWK"
+                    }
+                }
+            }
+        },
         "utils.Pagination": {
             "type": "object",
             "properties": {
@@ -3054,6 +9068,12 @@ const docTemplate = `{
                 "meta": {},
                 "success": {
                     "type": "boolean"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         }
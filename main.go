@@ -4,6 +4,7 @@ import (
     _ "Avocycle/docs"
 	"Avocycle/config"
 	"Avocycle/routes"
+	"Avocycle/utils"
 
 	"github.com/joho/godotenv"
 	// "fmt"
@@ -24,16 +25,31 @@ import (
 func main() {
 	// ini untuk seeder nanti
 
-	// initialize gocial
-	config.InitGocial()
-
 	godotenv.Load()
+
+	// load JWT signing keys, server tidak boleh jalan tanpa key
+	if err := utils.InitJWTKeys(); err != nil {
+		panic("Failed to load JWT signing keys: " + err.Error())
+	}
+
+	// initialize oauth / oidc providers (butuh env sudah ter-load)
+	config.InitOAuthProviders()
+
+	// direktori file sementara upload multipart (TMPDIR, jika diisi)
+	if err := config.InitUploadTempDir(); err != nil {
+		panic("Failed to prepare upload temp dir: " + err.Error())
+	}
 	// connect to postgres
 	postsql, err := config.DbConnect()
 	if err != nil {
 		panic("Failed to get database connection: " + err.Error())
 	}
 
+	// migrasi skema & data lama cukup sekali saat startup, bukan per request
+	if err := config.Migrate(postsql); err != nil {
+		panic("Failed to migrate database: " + err.Error())
+	}
+
 	// Get underlying sql.DB to close the connection
     sqlDB, err := postsql.DB()
     if err != nil {
@@ -42,6 +58,15 @@ func main() {
 
 	defer sqlDB.Close()
 
+	// purge otomatis data trash yang melewati masa retensi
+	config.StartTrashPurger()
+
+	// sesi upload bertahap kedaluwarsa dan file sementara yang tertinggal
+	config.StartUploadCleaner()
+
+	// hapus aset Cloudinary yang tidak dirujuk record mana pun
+	config.StartMediaGC()
+
 	router := routes.InitRoutes()
 
 	router.Run(":2005")
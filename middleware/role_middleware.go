@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"Avocycle/config"
 	"Avocycle/utils"
 	"net/http"
 	"strings"
@@ -8,45 +9,118 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// RoleMiddleware memastikan token valid dan role termasuk allowedRoles.
+// Route baru sebaiknya memakai RequirePermission supaya aturan akses terpusat di config.
 func RoleMiddleware(allowedRoles ...string) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		// get the token from header
-		authToken := ctx.GetHeader("Authorization")
+		claims, ok := authenticate(ctx, func(role string) bool { return containsRole(allowedRoles, role) })
+		if !ok || !enforceAdminTwoFactor(ctx, claims) {
+			return
+		}
 
-		// check the "Bearer " string
-		if authToken == "" || !strings.HasPrefix(authToken, "Bearer ") {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error" : "Missing or invalid authorization header"})
-			return 
+		// continue to the next
+		ctx.Next()
+	}
+}
+
+// RequirePermission memastikan token valid dan role-nya punya permission di config.rolePermissions
+func RequirePermission(perm config.Permission) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		claims, ok := authenticate(ctx, func(role string) bool { return config.HasPermission(role, perm) })
+		if !ok || !enforceAdminTwoFactor(ctx, claims) {
+			return
 		}
 
-		// trim the prefix
-		tokenString := strings.TrimPrefix(authToken, "Bearer ")
+		ctx.Next()
+	}
+}
 
-		// get the data from token
-		claims, err := utils.ValidateJWT(tokenString)
-		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+// AuthMiddleware hanya memastikan token valid, untuk endpoint yang boleh diakses semua role
+func AuthMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		claims, ok := authenticate(ctx, func(string) bool { return true })
+		if !ok || !enforceAdminTwoFactor(ctx, claims) {
 			return
 		}
 
-		// Check if user role is in allowed roles
-        hasPermission := false
-        for _, allowedRole := range allowedRoles {
-            if claims.Role == allowedRole {
-                hasPermission = true
-                break
-            }
-        }
-
-        if !hasPermission {
-            ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
-                "success": false,
-                "error":   "Forbidden - insufficient role permission",
-            })
-            return
-        }
+		ctx.Next()
+	}
+}
+
+// TwoFactorSetupMiddleware sama seperti RequirePermission tanpa kewajiban 2FA,
+// dipakai untuk endpoint enrollment supaya Admin bisa mengaktifkan 2FA.
+func TwoFactorSetupMiddleware(perm config.Permission) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if _, ok := authenticate(ctx, func(role string) bool { return config.HasPermission(role, perm) }); !ok {
+			return
+		}
 
-		// continue to the next
 		ctx.Next()
 	}
-}
\ No newline at end of file
+}
+
+// enforceAdminTwoFactor menolak Admin yang wajib 2FA (lihat SecurityPolicy) jika token belum lewat 2FA
+func enforceAdminTwoFactor(ctx *gin.Context, claims *utils.Claims) bool {
+	if claims.Role == "Admin" && !claims.MFA && requireTwoFactorForAdmin() {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Two-factor authentication required - enroll 2FA and login again",
+		})
+		return false
+	}
+	return true
+}
+
+func containsRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// authenticate memvalidasi bearer token, cek akses role, lalu menyimpan claims di context
+func authenticate(ctx *gin.Context, allowed func(role string) bool) (*utils.Claims, bool) {
+	// get the token from header
+	authToken := ctx.GetHeader("Authorization")
+
+	// check the "Bearer " string
+	if authToken == "" || !strings.HasPrefix(authToken, "Bearer ") {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid authorization header"})
+		return nil, false
+	}
+
+	// trim the prefix
+	tokenString := strings.TrimPrefix(authToken, "Bearer ")
+
+	// get the data from token
+	claims, err := utils.ValidateJWT(tokenString)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	if !allowed(claims.Role) {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Forbidden - insufficient role permission",
+		})
+		return nil, false
+	}
+
+	// simpan claims supaya controller bisa tahu siapa yang request
+	ctx.Set("claims", claims)
+	ctx.Set("user_id", claims.UserID)
+	ctx.Set("role", claims.Role)
+
+	// actor untuk audit log, dibaca callback GORM lewat db.WithContext(request context)
+	userID := claims.UserID
+	ctx.Request = ctx.Request.WithContext(utils.WithAuditActor(ctx.Request.Context(), utils.AuditActor{
+		UserID: &userID,
+		Role:   claims.Role,
+		IP:     ctx.ClientIP(),
+	}))
+
+	return claims, true
+}
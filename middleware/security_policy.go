@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"Avocycle/config"
+	"Avocycle/models"
+	"sync"
+	"time"
+)
+
+const securityPolicyTTL = 30 * time.Second
+
+var (
+	cachedPolicy     models.SecurityPolicy
+	cachedPolicyAt   time.Time
+	cachedPolicyLock sync.Mutex
+)
+
+// requireTwoFactorForAdmin membaca SecurityPolicy dengan cache singkat supaya tidak query tiap request
+func requireTwoFactorForAdmin() bool {
+	cachedPolicyLock.Lock()
+	defer cachedPolicyLock.Unlock()
+
+	if time.Since(cachedPolicyAt) < securityPolicyTTL {
+		return cachedPolicy.Require2FAAdmin
+	}
+
+	db, err := config.DbConnect()
+	if err != nil {
+		// DB bermasalah: pakai nilai terakhir yang diketahui
+		return cachedPolicy.Require2FAAdmin
+	}
+
+	var policy models.SecurityPolicy
+	if err := db.Order("id ASC").First(&policy).Error; err != nil {
+		policy = models.SecurityPolicy{}
+	}
+
+	cachedPolicy = policy
+	cachedPolicyAt = time.Now()
+	return cachedPolicy.Require2FAAdmin
+}
+
+// InvalidateSecurityPolicy dipanggil setelah Admin mengubah policy
+func InvalidateSecurityPolicy() {
+	cachedPolicyLock.Lock()
+	cachedPolicyAt = time.Time{}
+	cachedPolicyLock.Unlock()
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"encoding/json"
+
+	"gorm.io/gorm"
+)
+
+// AuditLog catatan setiap create / update / delete pada entitas yang diaudit.
+// Ditulis otomatis oleh callback GORM (config/audit.go), bukan oleh controller.
+type AuditLog struct {
+	gorm.Model
+	ActorID   *uint           `gorm:"index" json:"actor_id"`
+	ActorRole string          `gorm:"type:varchar(20)" json:"actor_role"`
+	Action    string          `gorm:"type:varchar(10);check:action IN ('create','update','delete');not null;index" json:"action"`
+	Entity    string          `gorm:"type:varchar(50);not null;index:idx_audit_entity" json:"entity"`
+	EntityID  uint            `gorm:"index:idx_audit_entity" json:"entity_id"`
+	Before    json.RawMessage `gorm:"type:jsonb" json:"before,omitempty" swaggertype:"object"`
+	After     json.RawMessage `gorm:"type:jsonb" json:"after,omitempty" swaggertype:"object"`
+	Changes   json.RawMessage `gorm:"type:jsonb" json:"changes,omitempty" swaggertype:"object"`
+	IP        string          `gorm:"type:varchar(45)" json:"ip"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// Blok blok tanam di dalam kebun. Kode unik per kebun (disimpan huruf besar),
+// tata letak baris x kolom dipakai untuk penomoran posisi pohon.
+type Blok struct {
+	gorm.Model
+	KebunID     uint     `gorm:"not null;uniqueIndex:idx_blok_kode" json:"kebun_id"`
+	Kebun       Kebun    `gorm:"foreignKey:KebunID;references:ID" json:"-"`
+	Kode        string   `gorm:"type:varchar(25);not null;uniqueIndex:idx_blok_kode" json:"kode"`
+	Nama        string   `gorm:"type:varchar(100)" json:"nama,omitempty"`
+	LuasHektar  *float64 `gorm:"type:decimal(10,4)" json:"luas_hektar"`
+	JumlahBaris *int     `json:"jumlah_baris"`
+	JumlahKolom *int     `json:"jumlah_kolom"`
+	ZonaIrigasi string   `gorm:"type:varchar(50);index" json:"zona_irigasi,omitempty"`
+}
@@ -10,4 +10,6 @@ type Booking struct {
 	User      User    `gorm:"foreignKey:UserID;references:ID" json:"user"`
 	TanamanID uint    `gorm:"not null;index" json:"tanaman_id"`
 	Tanaman   Tanaman `gorm:"foreignKey:TanamanID;references:ID" json:"tanaman"`
-}
\ No newline at end of file
+	// Status open / fulfilled / cancelled (lihat config.BookingStatus*), hanya open yang menahan tanaman
+	Status string `gorm:"type:varchar(10);check:status IN ('open','fulfilled','cancelled');not null;default:'open';index" json:"status"`
+}
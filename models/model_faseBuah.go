@@ -14,6 +14,11 @@ type FaseBuah struct {
 	JumlahCover   	int       	`gorm:"not null" json:"jumlah_cover"`
 	WarnaLabel    	string    	`gorm:"size:50" json:"warna_label,omitempty"`
 	EstimasiPanen 	*time.Time 	`gorm:"not null;index" json:"estimasi_panen"`
+	EstimasiPanenAwal	*time.Time	`json:"estimasi_panen_awal,omitempty"`  // rentang estimasi
+	EstimasiPanenAkhir	*time.Time	`json:"estimasi_panen_akhir,omitempty"`
+	EstimasiKeyakinan	*float64	`gorm:"type:decimal(3,2)" json:"estimasi_keyakinan,omitempty"` // 0-1
+	EstimasiSumber	string		`gorm:"type:varchar(30)" json:"estimasi_sumber,omitempty"`      // lihat config.EstimasiSumber*
+	EstimasiManual	bool		`gorm:"not null;default:false" json:"estimasi_manual"`           // diisi pengguna, tidak dihitung ulang
 	TanamanID 		uint    	`gorm:"not null;index" json:"tanaman_id"`
 	Tanaman   		Tanaman 	`gorm:"foreignKey:TanamanID;references:ID" json:"tanaman"`
 }
\ No newline at end of file
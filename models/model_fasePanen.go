@@ -15,6 +15,7 @@ type FasePanen struct {
 	Catatan            	string     	`gorm:"type:text" json:"catatan,omitempty"`              // Catatan panen
 	FotoPanen          	string     	`gorm:"type:text" json:"foto_panen,omitempty"`           // Foto hasil panen (optional)
 	FotoPanenID			string		`gorm:"type:varchar(255)" json:"foto_panen_id,omitempty"`
+	FotoPanenThumb		string		`gorm:"type:text" json:"foto_panen_thumb,omitempty"`
 	TanamanID 			uint    	`gorm:"not null;index" json:"tanaman_id"`
 	Tanaman   			Tanaman 	`gorm:"foreignKey:TanamanID;references:ID" json:"tanaman"`
 }
\ No newline at end of file
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FaseRevision versi sebuah record fase (bunga / buah / panen). Setiap create / update / delete
+// menutup versi aktif (ValidTo) dan membuat versi baru, sehingga data fase bisa dilihat "per tanggal".
+// Versi delete punya ValidFrom = ValidTo, jadi tidak pernah muncul di query as-of.
+type FaseRevision struct {
+	gorm.Model
+	Entity    string          `gorm:"type:varchar(20);not null;uniqueIndex:idx_fase_revision" json:"entity"`
+	RecordID  uint            `gorm:"not null;uniqueIndex:idx_fase_revision" json:"record_id"`
+	Revision  int             `gorm:"not null;uniqueIndex:idx_fase_revision" json:"revision"`
+	Action    string          `gorm:"type:varchar(10);check:action IN ('create','update','delete');not null" json:"action"`
+	TanamanID uint            `gorm:"not null;index" json:"tanaman_id"`
+	Data      json.RawMessage `gorm:"type:jsonb;not null" json:"data" swaggertype:"object"`
+	ValidFrom time.Time       `gorm:"not null;index" json:"valid_from"`
+	ValidTo   *time.Time      `gorm:"index" json:"valid_to"`
+	ActorID   *uint           `gorm:"index" json:"actor_id"`
+}
@@ -1,11 +1,26 @@
 package models
 
 import (
+	"encoding/json"
+
 	"gorm.io/gorm"
 )
 
 type Kebun struct {
 	gorm.Model
 	NamaKebun string `gorm:"type:varchar(100);not null" json:"nama_kebun"`
-	MDPL string `gorm:"type:varchar(100);not null" json:"mdpl"`
-}
\ No newline at end of file
+	MDPL string `gorm:"type:varchar(100);not null" json:"mdpl"` // teks lama, pakai Ketinggian untuk perhitungan
+	Ketinggian *float64 `gorm:"type:decimal(7,1)" json:"ketinggian"` // mdpl numerik
+	Latitude *float64 `gorm:"type:decimal(9,6);index:idx_kebun_location" json:"latitude"`
+	Longitude *float64 `gorm:"type:decimal(9,6);index:idx_kebun_location" json:"longitude"`
+	Batas json.RawMessage `gorm:"type:jsonb" json:"batas,omitempty" swaggertype:"object"` // GeoJSON Polygon
+	LuasHektar *float64 `gorm:"type:decimal(10,4)" json:"luas_hektar"`
+	Alamat string `gorm:"type:text" json:"alamat,omitempty"`
+	Desa string `gorm:"type:varchar(100)" json:"desa,omitempty"`
+	Kecamatan string `gorm:"type:varchar(100)" json:"kecamatan,omitempty"`
+	Kabupaten string `gorm:"type:varchar(100);index" json:"kabupaten,omitempty"`
+	Provinsi string `gorm:"type:varchar(100)" json:"provinsi,omitempty"`
+	KodePos string `gorm:"type:varchar(10)" json:"kode_pos,omitempty"`
+	OrganizationID *uint `gorm:"index" json:"organization_id"`
+	Organization *Organization `gorm:"foreignKey:OrganizationID;references:ID" json:"organization,omitempty"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// KebunMember keanggotaan user di sebuah kebun beserta perannya (owner, manager, worker, viewer).
+// Hak tiap peran diatur di config.KebunRoleCan.
+type KebunMember struct {
+	gorm.Model
+	KebunID uint   `gorm:"not null;uniqueIndex:idx_kebun_member" json:"kebun_id"`
+	Kebun   Kebun  `gorm:"foreignKey:KebunID;references:ID" json:"-"`
+	UserID  uint   `gorm:"not null;uniqueIndex:idx_kebun_member;index" json:"user_id"`
+	User    User   `gorm:"foreignKey:UserID;references:ID" json:"user"`
+	Role    string `gorm:"type:varchar(10);check:role IN ('owner','manager','worker','viewer');not null" json:"role"`
+}
+
+// KebunInvitation undangan bergabung ke kebun lewat email. Token hanya dikirim sekali,
+// yang disimpan hash-nya.
+type KebunInvitation struct {
+	gorm.Model
+	KebunID     uint       `gorm:"not null;index" json:"kebun_id"`
+	Kebun       Kebun      `gorm:"foreignKey:KebunID;references:ID" json:"kebun"`
+	Email       string     `gorm:"type:varchar(100);not null;index" json:"email"`
+	Role        string     `gorm:"type:varchar(10);check:role IN ('owner','manager','worker','viewer');not null" json:"role"`
+	TokenHash   string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	InvitedByID uint       `gorm:"not null" json:"invited_by_id"`
+	ExpiresAt   time.Time  `gorm:"not null" json:"expires_at"`
+	AcceptedAt  *time.Time `json:"accepted_at,omitempty"`
+}
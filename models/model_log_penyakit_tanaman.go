@@ -10,6 +10,7 @@ type LogPenyakitTanaman struct {
 	Catatan    string          `gorm:"type:text" json:"catatan"`
 	Foto       string          `gorm:"type:varchar(255)" json:"foto,omitempty"`
 	FotoLogPenyakitID string   `gorm:"type:varchar(255)" json:"foto_log_penyakit_id,omitempty"`
+	FotoThumb  string          `gorm:"type:text" json:"foto_thumb,omitempty"`
 	SaranPerawatan string	   `gorm:"type:text" json:"saran_perawatan"`
 	TanamanID  uint            `gorm:"not null;index" json:"tanaman_id"`
 	Tanaman    Tanaman         `gorm:"foreignKey:TanamanID;references:ID" json:"tanaman"`
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LoginThrottle menghitung login gagal per akun ("account:<email>", email tak terdaftar
+// "account:<email>|<ip>") atau per IP ("ip:<addr>").
+// Disimpan di Postgres supaya lockout berlaku di semua replika.
+type LoginThrottle struct {
+	gorm.Model
+	Key          string     `gorm:"type:varchar(255);not null;uniqueIndex" json:"key"`
+	Scope        string     `gorm:"type:varchar(10);check:scope IN ('account','ip');not null" json:"scope"`
+	FailedCount  int        `gorm:"not null;default:0" json:"failed_count"`
+	LastFailedAt *time.Time `json:"last_failed_at,omitempty"`
+	LockedUntil  *time.Time `gorm:"index" json:"locked_until,omitempty"`
+}
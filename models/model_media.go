@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Media foto galeri yang bisa ditempel ke record mana pun. OwnerType memakai nama entitas trash
+// ("tanaman", "fase-panen", "log-penyakit", ...). Kolom foto tunggal lama (FotoTanaman, FotoPanen,
+// Foto) tetap diisi dengan foto urutan pertama sebagai foto utama.
+type Media struct {
+	gorm.Model
+	OwnerType      string     `gorm:"type:varchar(30);not null;index:idx_media_owner" json:"owner_type"`
+	OwnerID        uint       `gorm:"not null;index:idx_media_owner" json:"owner_id"`
+	KebunID        *uint      `gorm:"index" json:"kebun_id,omitempty"` // kebun pemilik record, batas pencarian foto mirip
+	URL            string     `gorm:"type:text;not null" json:"url"`
+	PublicID       string     `gorm:"type:varchar(255);index" json:"public_id,omitempty"`
+	ThumbURL       string     `gorm:"type:text" json:"thumb_url,omitempty"`
+	ThumbPublicID  string     `gorm:"type:varchar(255)" json:"thumb_public_id,omitempty"`
+	Width          int        `json:"width,omitempty"`
+	Height         int        `json:"height,omitempty"`
+	Caption        string     `gorm:"type:text" json:"caption,omitempty"`
+	TakenAt        *time.Time `gorm:"index" json:"taken_at"`             // waktu foto diambil di lapangan (default dari EXIF)
+	Latitude       *float64   `gorm:"type:decimal(9,6)" json:"latitude"` // lokasi foto dari EXIF GPS
+	Longitude      *float64   `gorm:"type:decimal(9,6)" json:"longitude"`
+	Urutan         int        `gorm:"not null;default:0" json:"urutan"`
+	UploadedBy     *uint      `json:"uploaded_by"`
+	PHash          *int64     `gorm:"column:phash" json:"-"`                   // perceptual hash (pola bit uint64)
+	ContentHash    string     `gorm:"type:varchar(64);index" json:"-"`         // SHA-256 file asli
+	DuplikatDariID *uint      `gorm:"index" json:"duplikat_dari_id,omitempty"` // foto mirip milik tanaman lain
+}
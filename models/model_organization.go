@@ -0,0 +1,23 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// Organization koperasi / kelompok tani yang menaungi banyak kebun milik anggotanya.
+// Semua list data dibatasi ke organisasi user (lihat controllers.resolveTenant).
+type Organization struct {
+	gorm.Model
+	Name string `gorm:"type:varchar(150);not null" json:"name"`
+}
+
+// OrganizationMember keanggotaan user di organisasi. Role admin mengelola anggota,
+// kebun, dan statistik organisasi; member hanya bisa melihat data organisasi.
+type OrganizationMember struct {
+	gorm.Model
+	OrganizationID uint         `gorm:"not null;uniqueIndex:idx_organization_member" json:"organization_id"`
+	Organization   Organization `gorm:"foreignKey:OrganizationID;references:ID" json:"-"`
+	UserID         uint         `gorm:"not null;uniqueIndex:idx_organization_member;index" json:"user_id"`
+	User           User         `gorm:"foreignKey:UserID;references:ID" json:"user"`
+	Role           string       `gorm:"type:varchar(10);check:role IN ('admin','member');not null" json:"role"`
+}
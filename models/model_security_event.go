@@ -0,0 +1,16 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// SecurityEvent catatan kejadian keamanan (lockout, unlock) untuk ditinjau Admin
+type SecurityEvent struct {
+	gorm.Model
+	Event   string `gorm:"type:varchar(50);not null;index" json:"event"`
+	UserID  *uint  `gorm:"index" json:"user_id,omitempty"`
+	Email   string `gorm:"type:varchar(100)" json:"email,omitempty"`
+	IP      string `gorm:"type:varchar(64)" json:"ip,omitempty"`
+	ActorID *uint  `json:"actor_id,omitempty"` // Admin yang melakukan aksi (unlock)
+	Detail  string `gorm:"type:text" json:"detail,omitempty"`
+}
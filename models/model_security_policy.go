@@ -0,0 +1,11 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// SecurityPolicy pengaturan keamanan global yang diatur Admin (hanya satu baris)
+type SecurityPolicy struct {
+	gorm.Model
+	Require2FAAdmin bool `gorm:"column:require_2fa_admin;not null;default:false" json:"require_2fa_admin"`
+}
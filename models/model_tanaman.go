@@ -8,13 +8,26 @@ import (
 type Tanaman struct {
 	gorm.Model
 	NamaTanaman  string    `gorm:"type:varchar(100);not null" json:"nama_tanaman"`
-	Varietas     string    `gorm:"type:varchar(50);check:varietas IN ('Var1', 'Var2', 'Var3')" json:"varietas"`
+	VarietasID   *uint     `gorm:"index" json:"varietas_id"`
+	Varietas     *Varietas `gorm:"foreignKey:VarietasID;references:ID" json:"varietas,omitempty"`
 	TanggalTanam time.Time `gorm:"type:date;not null" json:"tanggal_tanam"`
 	KebunID      uint      `gorm:"not null;index" json:"kebun_id"`
 	Kebun        Kebun     `gorm:"foreignKey:KebunID;references:ID" json:"kebun"`
-	KodeBlok	 string    `gorm:"type:varchar(25);not null" json:"kode_blok"`
-	KodeTanaman  string    `gorm:"type:varchar(50);not null" json:"kode_tanaman"`
+	BlokID       *uint     `gorm:"index;uniqueIndex:idx_tanaman_posisi_aktif,where:deleted_at IS NULL AND status <> 'Removed' AND status <> 'Replanted'" json:"blok_id"`
+	Blok         *Blok     `gorm:"foreignKey:BlokID;references:ID" json:"blok,omitempty"`
+	KodeBlok	 string    `gorm:"type:varchar(25);not null" json:"kode_blok"` // salinan Blok.Kode, jangan diisi langsung
+	KodeTanaman  string    `gorm:"type:varchar(50);not null" json:"kode_tanaman"` // unik per kebun tanpa beda besar/kecil huruf (config.migrateKodeTanamanIndex)
+	Latitude     *float64  `gorm:"type:decimal(9,6)" json:"latitude"`
+	Longitude    *float64  `gorm:"type:decimal(9,6)" json:"longitude"`
+	Baris        *int      `gorm:"uniqueIndex:idx_tanaman_posisi_aktif,where:deleted_at IS NULL AND status <> 'Removed' AND status <> 'Replanted'" json:"baris"` // posisi di grid blok, satu pohon berdiri per baris-kolom
+	Kolom        *int      `gorm:"uniqueIndex:idx_tanaman_posisi_aktif,where:deleted_at IS NULL AND status <> 'Removed' AND status <> 'Replanted'" json:"kolom"`
 	FotoTanaman  string    `gorm:"type:text" json:"foto_tanaman,omitempty"`
 	MasaProduksi int	   `gorm:"not null" json:"masa_produksi"`
 	FotoTanamanID string   `gorm:"type:varchar(255)" json:"foto_tanaman_id,omitempty"`
+	FotoTanamanThumb string `gorm:"type:text" json:"foto_tanaman_thumb,omitempty"`
+	Status       string    `gorm:"type:varchar(20);not null;default:'Active';index;check:status IN ('Active','Dormant','Removed','Replanted')" json:"status"`
+	StatusSejak  *time.Time `json:"status_sejak,omitempty"`
+	StatusAlasan string    `gorm:"type:text" json:"status_alasan,omitempty"`
+	TanamanSebelumnyaID *uint    `gorm:"index" json:"tanaman_sebelumnya_id"` // pendahulu jika hasil tanam ulang
+	TanamanSebelumnya   *Tanaman `gorm:"foreignKey:TanamanSebelumnyaID;references:ID" json:"tanaman_sebelumnya,omitempty"`
 }
\ No newline at end of file
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserTwoFactor menyimpan secret TOTP per user. Enabled baru true setelah kode pertama diverifikasi.
+type UserTwoFactor struct {
+	gorm.Model
+	UserID       uint       `gorm:"not null;uniqueIndex" json:"user_id"`
+	User         User       `gorm:"foreignKey:UserID;references:ID" json:"-"`
+	Secret       string     `gorm:"type:varchar(64);not null" json:"-"`
+	Enabled      bool       `gorm:"not null;default:false" json:"enabled"`
+	EnabledAt    *time.Time `json:"enabled_at,omitempty"`
+	LastUsedStep int64      `gorm:"not null;default:0" json:"-"` // cegah kode yang sama dipakai dua kali
+}
+
+// TwoFactorRecoveryCode kode cadangan sekali pakai, disimpan dalam bentuk hash
+type TwoFactorRecoveryCode struct {
+	gorm.Model
+	UserID   uint       `gorm:"not null;index" json:"user_id"`
+	CodeHash string     `gorm:"type:varchar(64);not null;index" json:"-"`
+	UsedAt   *time.Time `json:"used_at,omitempty"`
+}
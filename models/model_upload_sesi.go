@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// UploadSesi upload foto bertahap (resumable) untuk koneksi lambat: client kirim potongan file
+// berurutan, bisa dilanjutkan dari offset terakhir setelah koneksi putus. Potongan disimpan di
+// Postgres (bukan disk lokal) supaya bisa dilanjutkan di replika mana pun.
+type UploadSesi struct {
+	ID           string        `gorm:"primaryKey;type:varchar(64)" json:"id"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+	UserID       uint          `gorm:"not null;index" json:"user_id"`
+	OwnerType    string        `gorm:"type:varchar(30);not null" json:"owner_type"`
+	OwnerID      uint          `gorm:"not null" json:"owner_id"`
+	Filename     string        `gorm:"type:varchar(100)" json:"filename"`
+	Caption      string        `gorm:"type:text" json:"caption,omitempty"`
+	TakenAt      *time.Time    `json:"taken_at,omitempty"`
+	JadikanUtama bool          `gorm:"not null;default:false" json:"jadikan_utama"`
+	Ukuran       int64         `gorm:"not null" json:"ukuran"`
+	Diterima     int64         `gorm:"not null;default:0" json:"diterima"`
+	ExpiresAt    time.Time     `gorm:"not null;index" json:"expires_at"`
+	Chunks       []UploadChunk `gorm:"foreignKey:SesiID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// UploadChunk satu potongan file, Offset = posisi byte pertama di file utuh
+type UploadChunk struct {
+	ID     uint   `gorm:"primaryKey"`
+	SesiID string `gorm:"type:varchar(64);not null;index" json:"sesi_id"`
+	Offset int64  `gorm:"column:byte_offset;not null" json:"offset"`
+	Data   []byte `gorm:"type:bytea;not null" json:"-"`
+}
@@ -10,7 +10,7 @@ type User struct {
     Phone        string `gorm:"type:varchar(50);not null" json:"phone"`
     Email        string `gorm:"type:varchar(100);not null;uniqueIndex" json:"email"`
     PasswordHash string `gorm:"size:255" json:"password"`
-    AuthProvider string `gorm:"type:varchar(20);check:auth_provider IN ('Google', 'Local');" json:"auth_provider"`
+    AuthProvider string `gorm:"type:varchar(50)" json:"auth_provider"` // Local, Google, atau nama provider OIDC (OAUTH_PROVIDERS)
     ProviderID   string `gorm:"size:255" json:"provider_id"`
     Role         string `gorm:"type:varchar(20);check:role IN ('Admin', 'Petani', 'Pembeli');" json:"role"`
 }
\ No newline at end of file
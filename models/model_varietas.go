@@ -0,0 +1,17 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// Varietas data referensi varietas alpukat (Alligator, Mentega, Hass, ...), dikelola Admin.
+// Angka agronomis opsional, dipakai untuk estimasi panen.
+type Varietas struct {
+	gorm.Model
+	Nama           string   `gorm:"type:varchar(50);not null;uniqueIndex" json:"nama"`
+	Deskripsi      string   `gorm:"type:text" json:"deskripsi,omitempty"`
+	HariCoverPanen *int     `json:"hari_cover_panen,omitempty"`                          // rata-rata hari dari cover sampai panen
+	BeratBuahGram  *float64 `gorm:"type:decimal(10,2)" json:"berat_buah_gram,omitempty"` // perkiraan berat per buah
+	KetinggianMin  *int     `json:"ketinggian_min,omitempty"`                            // mdpl
+	KetinggianMax  *int     `json:"ketinggian_max,omitempty"`                            // mdpl
+}
@@ -49,4 +49,6 @@ type SwaggerBooking struct {
 
     TanamanID uint            `json:"tanaman_id"`
     Tanaman   SwaggerTanaman  `json:"tanaman"`
+
+    Status    string          `json:"status" example:"open"`
 }
\ No newline at end of file
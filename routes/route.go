@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"Avocycle/config"
 	"Avocycle/controllers"
 	"Avocycle/middleware"
 	"time"
@@ -14,13 +15,14 @@ import (
 
 func InitRoutes() *gin.Engine {
 	r := gin.Default()
+	r.MaxMultipartMemory = config.UploadMaxMemory()
 
 	// ========== FINAL CORS CONFIG ==========
 	r.Use(cors.New(cors.Config{
 		AllowOriginFunc:  func(origin string) bool { return true },
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "Upload-Offset"},
+		ExposeHeaders:    []string{"Content-Length", "Upload-Offset"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -28,6 +30,9 @@ func InitRoutes() *gin.Engine {
 	// swagger
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// public key untuk verifikasi JWT oleh service lain
+	r.GET("/.well-known/jwks.json", controllers.GetJWKS)
+
 	// API v1
 	api := r.Group("/api/v1")
 	{
@@ -35,101 +40,226 @@ func InitRoutes() *gin.Engine {
 		api.POST("register/petani", controllers.ManualRegisterPetani)
 		api.POST("register/pembeli", controllers.ManualRegisterPembeli)
 		api.POST("login", controllers.ManualLogin)
+		api.POST("login/2fa", controllers.VerifyLoginTwoFactor)
+
+		// Two-factor authentication (Admin & Petani), tanpa kewajiban 2FA supaya bisa enroll
+		twoFactor := api.Group("/auth/2fa")
+		twoFactor.Use(middleware.TwoFactorSetupMiddleware(config.PermTwoFactorManage))
+		{
+			twoFactor.GET("", controllers.GetTwoFactorStatus)
+			twoFactor.POST("/setup", controllers.SetupTwoFactor)
+			twoFactor.POST("/enable", controllers.EnableTwoFactor)
+			twoFactor.POST("/disable", controllers.DisableTwoFactor)
+			twoFactor.POST("/recovery-codes", controllers.RegenerateRecoveryCodes)
+		}
+
+		// permission efektif user login (untuk menu frontend)
+		api.GET("/me/permissions", middleware.AuthMiddleware(), controllers.GetMyPermissions)
 
-		// Google OAuth Pembeli
+		// OAuth / OIDC (google + provider dari OAUTH_PROVIDERS), role dibawa di state
+		api.GET("auth/:provider", controllers.RedirectHandler)
+		api.GET("auth/:provider/callback", controllers.CallbackHandler)
+
+		// Google OAuth Pembeli (alias lama)
 		api.GET("auth/google/pembeli", controllers.RedirectHandlerPembeli)
 		api.GET("auth/:provider/callback/pembeli", controllers.CallbackHandlerPembeli)
 		api.POST("auth/google/complete/pembeli", controllers.CompleteGooglePembeli)
 
-		// Google OAuth Petani
+		// Google OAuth Petani (alias lama)
 		api.GET("auth/google/petani", controllers.RedirectHandlerPetani)
 		api.GET("auth/:provider/callback/petani", controllers.CallbackHandlerPetani)
 		api.POST("auth/google/complete/petani", controllers.CompleteGooglePetani)
 
+		// Organisasi / koperasi, semua list data dibatasi ke organisasi user
+		api.POST("/organizations", middleware.RequirePermission(config.PermOrgCreate), controllers.CreateOrganization)
+		api.GET("/organizations", middleware.AuthMiddleware(), controllers.GetMyOrganizations)
+		api.GET("/organizations/:id", middleware.AuthMiddleware(), controllers.GetOrganizationByID)
+		api.PUT("/organizations/:id", middleware.AuthMiddleware(), controllers.UpdateOrganization)
+		api.GET("/organizations/:id/members", middleware.AuthMiddleware(), controllers.GetOrganizationMembers)
+		api.POST("/organizations/:id/members", middleware.AuthMiddleware(), controllers.AddOrganizationMember)
+		api.PUT("/organizations/:id/members/:user_id", middleware.AuthMiddleware(), controllers.UpdateOrganizationMember)
+		api.DELETE("/organizations/:id/members/:user_id", middleware.AuthMiddleware(), controllers.RemoveOrganizationMember)
+		api.PUT("/kebun/:id/organization", middleware.AuthMiddleware(), controllers.AssignKebunOrganization)
+
 		// CRUD Kebun
-		api.POST("/kebun", middleware.RoleMiddleware("Petani", "Admin"), controllers.CreateKebun)
-		api.GET("/kebun", controllers.GetAllKebun)
-		api.GET("/kebun/:id", controllers.GetKebunByID)
-		api.PUT("/kebun/:id", middleware.RoleMiddleware("Petani", "Admin"), controllers.UpdateKebun)
-		api.DELETE("/kebun/:id", middleware.RoleMiddleware("Petani", "Admin"), controllers.DeleteKebun)
+		api.POST("/kebun", middleware.RequirePermission(config.PermKebunWrite), controllers.CreateKebun)
+		api.GET("/kebun", middleware.AuthMiddleware(), controllers.GetAllKebun)
+		api.GET("/kebun/nearby", middleware.AuthMiddleware(), controllers.GetNearbyKebun)
+		api.GET("/kebun/:id", middleware.AuthMiddleware(), controllers.GetKebunByID)
+		api.PUT("/kebun/:id", middleware.RequirePermission(config.PermKebunWrite), controllers.UpdateKebun)
+		api.DELETE("/kebun/:id", middleware.RequirePermission(config.PermKebunWrite), controllers.DeleteKebun)
+
+		// Anggota & undangan kebun, hak dicek per peran di kebun (owner, manager, worker, viewer)
+		api.GET("/kebun/:id/members", middleware.AuthMiddleware(), controllers.GetKebunMembers)
+		api.PUT("/kebun/:id/members/:user_id", middleware.AuthMiddleware(), controllers.UpdateKebunMember)
+		api.DELETE("/kebun/:id/members/:user_id", middleware.AuthMiddleware(), controllers.RemoveKebunMember)
+		api.POST("/kebun/:id/invitations", middleware.AuthMiddleware(), controllers.InviteKebunMember)
+		api.GET("/kebun/:id/invitations", middleware.AuthMiddleware(), controllers.GetKebunInvitations)
+		api.DELETE("/kebun/:id/invitations/:invitation_id", middleware.AuthMiddleware(), controllers.RevokeKebunInvitation)
+		api.GET("/me/kebun-invitations", middleware.AuthMiddleware(), controllers.GetMyKebunInvitations)
+		api.POST("/me/kebun-invitations/:invitation_id/accept", middleware.AuthMiddleware(), controllers.AcceptMyKebunInvitation)
+		api.POST("/kebun-invitations/accept", middleware.AuthMiddleware(), controllers.AcceptKebunInvitation)
+
+		// Blok tanam per kebun
+		api.GET("/kebun/:id/geojson", middleware.AuthMiddleware(), controllers.GetKebunGeoJSON)
+		api.GET("/kebun/:id/blok", middleware.AuthMiddleware(), controllers.GetKebunBlok)
+		api.GET("/kebun/:id/prakiraan-panen", middleware.AuthMiddleware(), controllers.GetKebunPrakiraanPanen)
+		api.GET("/kebun/:id/prakiraan-panen/backtest", middleware.AuthMiddleware(), controllers.GetKebunBacktestPanen)
+		api.POST("/kebun/:id/blok", middleware.RequirePermission(config.PermKebunWrite), controllers.CreateBlok)
+		api.GET("/blok/:id", middleware.AuthMiddleware(), controllers.GetBlokByID)
+		api.PUT("/blok/:id", middleware.RequirePermission(config.PermKebunWrite), controllers.UpdateBlok)
+		api.DELETE("/blok/:id", middleware.RequirePermission(config.PermKebunWrite), controllers.DeleteBlok)
+		api.GET("/blok/:id/tanaman", middleware.AuthMiddleware(), controllers.GetBlokTanaman)
+		api.GET("/blok/:id/statistik", middleware.AuthMiddleware(), controllers.GetBlokStatistik)
+		api.GET("/blok/:id/prakiraan-panen", middleware.AuthMiddleware(), controllers.GetBlokPrakiraanPanen)
+		api.GET("/blok/:id/prakiraan-panen/backtest", middleware.AuthMiddleware(), controllers.GetBlokBacktestPanen)
+		api.GET("/blok/:id/qr", middleware.AuthMiddleware(), controllers.GetBlokQR)
+		api.GET("/blok/:id/labels.pdf", middleware.AuthMiddleware(), controllers.GetBlokLabelPDF)
+
+		// Varietas (data referensi, kelola khusus Admin)
+		api.GET("/varietas", middleware.AuthMiddleware(), controllers.GetAllVarietas)
+		api.GET("/varietas/:id", middleware.AuthMiddleware(), controllers.GetVarietasByID)
+		api.POST("/varietas", middleware.RequirePermission(config.PermVarietasManage), controllers.CreateVarietas)
+		api.PUT("/varietas/:id", middleware.RequirePermission(config.PermVarietasManage), controllers.UpdateVarietas)
+		api.DELETE("/varietas/:id", middleware.RequirePermission(config.PermVarietasManage), controllers.DeleteVarietas)
 
 		// CRUD Tanaman
-		api.POST("/tanaman", middleware.RoleMiddleware("Petani", "Admin"), controllers.CreateTanaman)
-		api.GET("/tanaman", controllers.GetAllTanaman)
-		api.GET("/tanaman/:id", controllers.GetTanamanByID)
-		api.GET("/tanaman/by-kebun/:id_kebun", controllers.GetTanamanByKebunID)
-		api.PUT("/tanaman/:id", middleware.RoleMiddleware("Petani", "Admin"), controllers.UpdateTanaman)
-		api.DELETE("/tanaman/:id", middleware.RoleMiddleware("Petani", "Admin"), controllers.DeleteTanaman)
+		api.POST("/tanaman", middleware.RequirePermission(config.PermTanamanWrite), controllers.CreateTanaman)
+		api.GET("/tanaman", middleware.AuthMiddleware(), controllers.GetAllTanaman)
+		api.GET("/tanaman/lookup", middleware.AuthMiddleware(), controllers.LookupTanaman)
+		api.GET("/tanaman/:id", middleware.AuthMiddleware(), controllers.GetTanamanByID)
+		api.GET("/tanaman/:id/qr", middleware.AuthMiddleware(), controllers.GetTanamanQR)
+		api.GET("/tanaman/:id/label.pdf", middleware.AuthMiddleware(), controllers.GetTanamanLabelPDF)
+		api.GET("/tanaman/:id/fase-history", middleware.AuthMiddleware(), controllers.GetTanamanFaseAsOf)
+		api.GET("/tanaman/:id/generasi", middleware.AuthMiddleware(), controllers.GetTanamanGenerasi)
+		api.GET("/tanaman/:id/prakiraan-panen", middleware.AuthMiddleware(), controllers.GetTanamanPrakiraanPanen)
+		api.GET("/tanaman/:id/prakiraan-panen/backtest", middleware.AuthMiddleware(), controllers.GetTanamanBacktestPanen)
+		api.PUT("/tanaman/:id/status", middleware.RequirePermission(config.PermTanamanWrite), controllers.UpdateTanamanStatus)
+		api.POST("/tanaman/:id/replant", middleware.RequirePermission(config.PermTanamanWrite), controllers.ReplantTanaman)
+		api.GET("/tanaman/:id/booking", middleware.AuthMiddleware(), controllers.GetTanamanBooking)
+		api.PUT("/booking/:id/status", middleware.RequirePermission(config.PermBookingApprove), controllers.UpdateBookingStatus)
+		api.GET("/tanaman/by-kebun/:id_kebun", middleware.AuthMiddleware(), controllers.GetTanamanByKebunID)
+		api.PUT("/tanaman/:id", middleware.RequirePermission(config.PermTanamanWrite), controllers.UpdateTanaman)
+		api.DELETE("/tanaman/:id", middleware.RequirePermission(config.PermTanamanWrite), controllers.DeleteTanaman)
+
+		// galeri foto per record, akses mengikuti peran di kebun pemilik record
+		api.GET("/galeri/:owner_type/:owner_id", middleware.AuthMiddleware(), controllers.GetGaleri)
+		api.POST("/galeri/:owner_type/:owner_id", middleware.AuthMiddleware(), controllers.UploadGaleri)
+		api.PUT("/galeri/:owner_type/:owner_id/urutan", middleware.AuthMiddleware(), controllers.ReorderGaleri)
+		api.PUT("/media/:id", middleware.AuthMiddleware(), controllers.UpdateMedia)
+		api.DELETE("/media/:id", middleware.AuthMiddleware(), controllers.DeleteMedia)
+
+		// upload foto bertahap (resumable) ke galeri untuk koneksi lambat
+		api.POST("/upload/sesi", middleware.AuthMiddleware(), controllers.CreateUploadSesi)
+		api.GET("/upload/sesi/:id", middleware.AuthMiddleware(), controllers.GetUploadSesi)
+		api.PATCH("/upload/sesi/:id", middleware.AuthMiddleware(), controllers.UploadSesiChunk)
+		api.DELETE("/upload/sesi/:id", middleware.AuthMiddleware(), controllers.CancelUploadSesi)
+
+		// Trash: data yang di-soft delete, restore & hapus permanen (akses dicek per kebun)
+		api.GET("/trash/:entity", middleware.AuthMiddleware(), controllers.GetTrash)
+		api.POST("/trash/:entity/:id/restore", middleware.AuthMiddleware(), controllers.RestoreTrash)
+		api.DELETE("/trash/:entity/:id", middleware.AuthMiddleware(), controllers.PurgeTrash)
 
 		// Log Penyakit
-		api.GET("/Log-Penyakit-Tanaman", controllers.GetAllLogPenyakit)
-		api.GET("/Log-Penyakit-Tanaman/:id", controllers.GetLogPenyakitById)
-		api.GET("/Log-Penyakit-Tanaman/Tanaman/:id_tanaman", controllers.GetLogPenyakitByTanamanId)
+		api.GET("/Log-Penyakit-Tanaman", middleware.AuthMiddleware(), controllers.GetAllLogPenyakit)
+		api.GET("/Log-Penyakit-Tanaman/:id", middleware.AuthMiddleware(), controllers.GetLogPenyakitById)
+		api.GET("/Log-Penyakit-Tanaman/Tanaman/:id_tanaman", middleware.AuthMiddleware(), controllers.GetLogPenyakitByTanamanId)
 
 		// Fase Bunga
-		api.GET("/fase-bunga", controllers.GetAllFaseBunga)
-		api.GET("/fase-bunga/:id", controllers.GetFaseBungaByID)
-		api.GET("/fase-bunga/tanaman/:tanaman_id", controllers.GetFaseBungaByTanaman)
+		api.GET("/fase-bunga", middleware.AuthMiddleware(), controllers.GetAllFaseBunga)
+		api.GET("/fase-bunga/:id", middleware.AuthMiddleware(), controllers.GetFaseBungaByID)
+		api.GET("/fase-bunga/:id/revisions", middleware.AuthMiddleware(), controllers.GetFaseBungaRevisions)
+		api.GET("/fase-bunga/tanaman/:tanaman_id", middleware.AuthMiddleware(), controllers.GetFaseBungaByTanaman)
+		api.POST("/fase-bunga", middleware.RequirePermission(config.PermFaseWrite), controllers.CreateFaseBunga)
+		api.PUT("/fase-bunga/:id", middleware.RequirePermission(config.PermFaseWrite), controllers.UpdateFaseBunga)
+		api.DELETE("/fase-bunga/:id", middleware.RequirePermission(config.PermFaseWrite), controllers.DeleteFaseBunga)
 
 		// Fase Berbuah
-		api.GET("/fase-berbuah", controllers.GetAllFaseBuah)
-		api.GET("/fase-berbuah/:id", controllers.GetFaseBuahByID)
-		api.GET("/fase-berbuah/tanaman/:tanaman_id", controllers.GetFaseBuahByTanaman)
+		api.GET("/fase-berbuah", middleware.AuthMiddleware(), controllers.GetAllFaseBuah)
+		api.GET("/fase-berbuah/:id", middleware.AuthMiddleware(), controllers.GetFaseBuahByID)
+		api.GET("/fase-berbuah/:id/revisions", middleware.AuthMiddleware(), controllers.GetFaseBuahRevisions)
+		api.GET("/fase-berbuah/:id/estimasi", middleware.AuthMiddleware(), controllers.GetFaseBuahEstimasi)
+		api.GET("/fase-berbuah/tanaman/:tanaman_id", middleware.AuthMiddleware(), controllers.GetFaseBuahByTanaman)
+		api.POST("/fase-berbuah", middleware.RequirePermission(config.PermFaseWrite), controllers.CreateFaseBuah)
+		api.PUT("/fase-berbuah/:id", middleware.RequirePermission(config.PermFaseWrite), controllers.UpdateFaseBuah)
+		api.DELETE("/fase-berbuah/:id", middleware.RequirePermission(config.PermFaseWrite), controllers.DeleteFaseBuah)
 
 		// Fase Panen
-		api.GET("/fase-panen", controllers.GetAllFasePanen)
-		api.GET("/fase-panen/:id", controllers.GetFasePanenByID)
-		api.GET("/fase-panen/tanaman/:tanaman_id", controllers.GetFasePanenByTanaman)
+		api.GET("/fase-panen", middleware.AuthMiddleware(), controllers.GetAllFasePanen)
+		api.GET("/fase-panen/:id", middleware.AuthMiddleware(), controllers.GetFasePanenByID)
+		api.GET("/fase-panen/:id/revisions", middleware.AuthMiddleware(), controllers.GetFasePanenRevisions)
+		api.GET("/fase-panen/tanaman/:tanaman_id", middleware.AuthMiddleware(), controllers.GetFasePanenByTanaman)
+		api.POST("/fase-panen", middleware.RequirePermission(config.PermFaseWrite), controllers.CreateFasePanen)
+		api.PUT("/fase-panen/:id", middleware.RequirePermission(config.PermFaseWrite), controllers.UpdateFasePanen)
+		api.DELETE("/fase-panen/:id", middleware.RequirePermission(config.PermFaseWrite), controllers.DeleteFasePanen)
 
-		// Petani Protected Routes
+		// Petani Protected Routes (prefix lama, akses ditentukan permission)
 		petaniRoutes := api.Group("/petani")
-		petaniRoutes.Use(middleware.RoleMiddleware("Petani"))
 		{
 			// Buah
-			petaniRoutes.POST("/buah", controllers.CreateBuah)
-			petaniRoutes.GET("/buah", controllers.GetAllBuah)
-			petaniRoutes.GET("/buah/:id", controllers.GetBuahByID)
-			petaniRoutes.GET("/buah/by-tanaman/:id_kebun", controllers.GetBuahByKebun)
-			petaniRoutes.PUT("/buah/:id", controllers.UpdateBuah)
-			petaniRoutes.DELETE("/buah/:id", controllers.DeleteBuah)
+			petaniRoutes.POST("/buah", middleware.RequirePermission(config.PermBuahWrite), controllers.CreateBuah)
+			petaniRoutes.GET("/buah", middleware.RequirePermission(config.PermBuahRead), controllers.GetAllBuah)
+			petaniRoutes.GET("/buah/:id", middleware.RequirePermission(config.PermBuahRead), controllers.GetBuahByID)
+			petaniRoutes.GET("/buah/by-tanaman/:id_kebun", middleware.RequirePermission(config.PermBuahRead), controllers.GetBuahByKebun)
+			petaniRoutes.PUT("/buah/:id", middleware.RequirePermission(config.PermBuahWrite), controllers.UpdateBuah)
+			petaniRoutes.DELETE("/buah/:id", middleware.RequirePermission(config.PermBuahWrite), controllers.DeleteBuah)
 
 			// Statistik
-			petaniRoutes.GET("/count-all-tanaman", controllers.CountAllPohon)
-			petaniRoutes.GET("/count-tanaman-sakit", controllers.CountTanamanDiseased)
-			petaniRoutes.GET("/count-tanaman-siap-panen", controllers.CountSiapPanen)
-			petaniRoutes.GET("/count-tanaman-tiap-minggu", controllers.GetWeeklyPanenLast6Weeks)
-
-			// CRUD Fase Tanaman
-			petaniRoutes.POST("/fase-bunga", controllers.CreateFaseBunga)
-			petaniRoutes.PUT("/fase-bunga/:id", controllers.UpdateFaseBunga)
-			petaniRoutes.DELETE("/fase-bunga/:id", controllers.DeleteFaseBunga)
-
-			petaniRoutes.POST("/fase-berbuah", controllers.CreateFaseBuah)
-			petaniRoutes.PUT("/fase-berbuah/:id", controllers.UpdateFaseBuah)
-			petaniRoutes.DELETE("/fase-berbuah/:id", controllers.DeleteFaseBuah)
-
-			petaniRoutes.POST("/fase-panen", controllers.CreateFasePanen)
-			petaniRoutes.PUT("/fase-panen/:id", controllers.UpdateFasePanen)
-			petaniRoutes.DELETE("/fase-panen/:id", controllers.DeleteFasePanen)
+			statistik := petaniRoutes.Group("")
+			statistik.Use(middleware.RequirePermission(config.PermStatistikRead))
+			statistik.GET("/count-all-tanaman", controllers.CountAllPohon)
+			statistik.GET("/count-tanaman-sakit", controllers.CountTanamanDiseased)
+			statistik.GET("/count-tanaman-siap-panen", controllers.CountSiapPanen)
+			statistik.GET("/count-tanaman-tiap-minggu", controllers.GetWeeklyPanenLast6Weeks)
+
+			// CRUD Fase Tanaman (alias lama dari /fase-*)
+			fase := petaniRoutes.Group("")
+			fase.Use(middleware.RequirePermission(config.PermFaseWrite))
+			fase.POST("/fase-bunga", controllers.CreateFaseBunga)
+			fase.PUT("/fase-bunga/:id", controllers.UpdateFaseBunga)
+			fase.DELETE("/fase-bunga/:id", controllers.DeleteFaseBunga)
+
+			fase.POST("/fase-berbuah", controllers.CreateFaseBuah)
+			fase.PUT("/fase-berbuah/:id", controllers.UpdateFaseBuah)
+			fase.DELETE("/fase-berbuah/:id", controllers.DeleteFaseBuah)
+
+			fase.POST("/fase-panen", controllers.CreateFasePanen)
+			fase.PUT("/fase-panen/:id", controllers.UpdateFasePanen)
+			fase.DELETE("/fase-panen/:id", controllers.DeleteFasePanen)
 		}
 
 		// petani+admin routes
 		petaniAdmin := api.Group("/petamin")
-		petaniAdmin.Use(middleware.RoleMiddleware("Petani", "Admin"))
 		{
-			petaniAdmin.POST("/penyakit/:id_tanaman", controllers.ClassifyPenyakit)
+			petaniAdmin.POST("/penyakit/:id_tanaman", middleware.RequirePermission(config.PermPenyakitClassify), controllers.ClassifyPenyakit)
+		}
+
+		// admin routes
+		adminRoutes := api.Group("/admin")
+		adminRoutes.Use(middleware.RequirePermission(config.PermSecurityManage))
+		{
+			adminRoutes.GET("/security-policy", controllers.GetSecurityPolicy)
+			adminRoutes.PUT("/security-policy", controllers.UpdateSecurityPolicy)
+			adminRoutes.GET("/security-events", controllers.GetSecurityEvents)
+			adminRoutes.GET("/audit-logs", controllers.GetAuditLogs)
+			adminRoutes.GET("/login-locks", controllers.GetLoginLocks)
+			adminRoutes.POST("/login-locks/:id/unlock", controllers.UnlockLoginThrottle)
+			adminRoutes.POST("/users/:id/unlock", controllers.UnlockUserLogin)
+			adminRoutes.GET("/media/orphan", controllers.GetOrphanMedia)
+			adminRoutes.POST("/media/orphan/purge", controllers.PurgeOrphanMedia)
 		}
 
 		// pembeli routes
 		pembeliRoutes := api.Group("/pembeli")
-		pembeliRoutes.Use(middleware.RoleMiddleware("Pembeli"))
 		{
-			pembeliRoutes.POST("/booking", controllers.CreateBooking)
-			pembeliRoutes.GET("/booking", controllers.GetAllBooking)
-			pembeliRoutes.GET("/booking/:id", controllers.GetBookingByID)
-			pembeliRoutes.PUT("/booking/:id", controllers.UpdateBooking)
-			pembeliRoutes.DELETE("/booking/:id", controllers.DeleteBooking)
-			pembeliRoutes.GET("/booking/user/:user_id", controllers.GetBookingByUserID)
+			pembeliRoutes.POST("/booking", middleware.RequirePermission(config.PermBookingWrite), controllers.CreateBooking)
+			pembeliRoutes.GET("/booking", middleware.RequirePermission(config.PermBookingRead), controllers.GetAllBooking)
+			pembeliRoutes.GET("/booking/:id", middleware.RequirePermission(config.PermBookingRead), controllers.GetBookingByID)
+			pembeliRoutes.PUT("/booking/:id", middleware.RequirePermission(config.PermBookingWrite), controllers.UpdateBooking)
+			pembeliRoutes.DELETE("/booking/:id", middleware.RequirePermission(config.PermBookingWrite), controllers.DeleteBooking)
+			pembeliRoutes.PUT("/booking/:id/status", middleware.RequirePermission(config.PermBookingWrite), controllers.UpdateBookingStatus)
+			pembeliRoutes.GET("/booking/user/:user_id", middleware.RequirePermission(config.PermBookingRead), controllers.GetBookingByUserID)
+			pembeliRoutes.GET("/kebun/:id/prakiraan-panen", middleware.RequirePermission(config.PermBookingRead), controllers.GetKebunPrakiraanPanenPembeli)
 		}
 	}
 
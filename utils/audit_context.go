@@ -0,0 +1,26 @@
+package utils
+
+import "context"
+
+type auditActorKey struct{}
+
+// AuditActor siapa yang melakukan perubahan data, dibawa lewat context request sampai ke callback GORM
+type AuditActor struct {
+	UserID *uint
+	Role   string
+	IP     string
+}
+
+// WithAuditActor menyimpan actor di context
+func WithAuditActor(ctx context.Context, actor AuditActor) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+// AuditActorFrom mengambil actor dari context, kosong jika tidak ada (contoh: seeder / job)
+func AuditActorFrom(ctx context.Context) AuditActor {
+	if ctx == nil {
+		return AuditActor{}
+	}
+	actor, _ := ctx.Value(auditActorKey{}).(AuditActor)
+	return actor
+}
@@ -1,102 +1,200 @@
 package utils
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"os"
-	"path/filepath"
-    "strings" // Pastikan package strings diimpor
+	"path"
+	"regexp"
+	"strings"
 	"time"
 
     "github.com/cloudinary/cloudinary-go/v2"
+    "github.com/cloudinary/cloudinary-go/v2/api"
+    "github.com/cloudinary/cloudinary-go/v2/api/admin"
     "github.com/cloudinary/cloudinary-go/v2/api/uploader"
+    "golang.org/x/sync/errgroup"
 )
 
 // mantap
 const uploadTimeout = 30 * time.Second
-const maxUploadSize = 10 << 20 // 10 MB
-
-var allowedExts = map[string]bool{
-    ".jpg": true, ".jpeg": true, ".png": true, ".gif": true, ".webp": true,
+// MaxUploadSize ukuran file gambar maksimal, upload biasa maupun upload bertahap
+const MaxUploadSize = 10 << 20 // 10 MB
+
+// UploadedImage hasil UploadImage: foto utama dan thumbnail, masing-masing aset Cloudinary sendiri
+type UploadedImage struct {
+    URL           string
+    PublicID      string
+    ThumbURL      string
+    ThumbPublicID string
+    Width         int
+    Height        int
+    Exif          *ExifData // metadata asli (waktu & lokasi foto), tidak ikut terupload
+    PHash         uint64    // perceptual hash, lihat PerceptualHash
+    ContentHash   string    // SHA-256 file asli
 }
 
-func AsyncUploadOptionalImage(file *multipart.FileHeader, folder string) (string, string, error) {
+var ErrUploadTooLarge = fmt.Errorf("ukuran file maksimal %d MB", MaxUploadSize/(1<<20))
+
+// UploadImage validasi isi file (magic bytes), perbaiki orientasi EXIF, buang metadata, perkecil
+// ke MaxImageDimension, lalu upload bersama thumbnail. nil jika file nil.
+// File dibaca langsung dari multipart ke memori, tidak ada file sementara di working directory.
+func UploadImage(file *multipart.FileHeader, folder string) (*UploadedImage, error) {
     if file == nil {
-        return "", "", nil
+        return nil, nil
     }
-    if file.Size > maxUploadSize {
-        return "", "", fmt.Errorf("ukuran file maksimal %d MB", maxUploadSize/(1<<20))
-    }
-    ext := strings.ToLower(filepath.Ext(file.Filename))
-    if !allowedExts[ext] {
-        return "", "", fmt.Errorf("format file tidak didukung")
+    if file.Size > MaxUploadSize {
+        return nil, ErrUploadTooLarge
     }
 
-    tmpPath := fmt.Sprintf("./tmp_%d_%s", time.Now().UnixNano(), file.Filename)
-    if err := saveMultipart(file, tmpPath); err != nil {
-        return "", "", err
+    src, err := file.Open()
+    if err != nil {
+        return nil, err
+    }
+    defer src.Close()
+    data, err := io.ReadAll(io.LimitReader(src, MaxUploadSize+1))
+    if err != nil {
+        return nil, err
     }
+    return UploadImageData(data, folder)
+}
 
-    resultCh := make(chan *uploader.UploadResult, 1)
-    errCh := make(chan error, 1)
+// UploadImageData seperti UploadImage untuk isi file yang sudah di memori (hasil upload bertahap)
+func UploadImageData(data []byte, folder string) (*UploadedImage, error) {
+    if len(data) > MaxUploadSize {
+        return nil, ErrUploadTooLarge
+    }
 
-    go func() {
-        defer func() {
-            os.Remove(tmpPath)
-            close(resultCh)
-            close(errCh)
-        }()
+    full, thumb, phash, err := PrepareImage(data)
+    if err != nil {
+        return nil, err
+    }
 
-        cld, err := cloudinary.NewFromURL(os.Getenv("CLOUDINARY_URL"))
-        if err != nil {
-            errCh <- err
-            return
-        }
+    cld, err := cloudinary.NewFromURL(os.Getenv("CLOUDINARY_URL"))
+    if err != nil {
+        return nil, err
+    }
 
-        ctx, cancel := context.WithTimeout(context.Background(), uploadTimeout)
-        defer cancel()
+    ctx, cancel := context.WithTimeout(context.Background(), uploadTimeout)
+    defer cancel()
 
-        res, err := cld.Upload.Upload(ctx, tmpPath, uploader.UploadParams{
-            Folder:         fmt.Sprintf("avocycle/%s", folder),
-            UniqueFilename: &[]bool{true}[0],
-            Overwrite:      &[]bool{false}[0],
-        })
-        if err != nil {
-            errCh <- err
-            return
+    var fullRes, thumbRes *uploader.UploadResult
+    g, gctx := errgroup.WithContext(ctx)
+    g.Go(func() error {
+        var err error
+        fullRes, err = uploadVariant(gctx, cld, full, path.Join(CloudinaryFolder(), folder))
+        return err
+    })
+    g.Go(func() error {
+        var err error
+        thumbRes, err = uploadVariant(gctx, cld, thumb, path.Join(CloudinaryFolder(), folder, "thumb"))
+        return err
+    })
+    if err := g.Wait(); err != nil {
+        // jangan tinggalkan aset setengah jadi
+        if fullRes != nil {
+            _ = DeleteCloudinaryAsset(fullRes.PublicID)
         }
-        resultCh <- res
-    }()
-
-    select {
-    case res := <-resultCh:
-        if res == nil {
-            return "", "", nil
+        if thumbRes != nil {
+            _ = DeleteCloudinaryAsset(thumbRes.PublicID)
         }
-        return res.SecureURL, res.PublicID, nil
-    case err := <-errCh:
-        return "", "", err
-    case <-time.After(uploadTimeout):
-        return "", "", fmt.Errorf("upload timeout")
+        if errors.Is(err, context.DeadlineExceeded) {
+            return nil, fmt.Errorf("upload timeout")
+        }
+        return nil, err
     }
+
+    return &UploadedImage{
+        URL:           fullRes.SecureURL,
+        PublicID:      fullRes.PublicID,
+        ThumbURL:      thumbRes.SecureURL,
+        ThumbPublicID: thumbRes.PublicID,
+        Width:         full.Width,
+        Height:        full.Height,
+        Exif:          ReadExif(data),
+        PHash:         phash,
+        ContentHash:   ContentHash(data),
+    }, nil
 }
 
-func saveMultipart(file *multipart.FileHeader, dst string) error {
-    src, err := file.Open()
+func uploadVariant(ctx context.Context, cld *cloudinary.Cloudinary, img *ImageVariant, folder string) (*uploader.UploadResult, error) {
+    res, err := cld.Upload.Upload(ctx, bytes.NewReader(img.Data), uploader.UploadParams{
+        Folder:         folder,
+        UniqueFilename: &[]bool{true}[0],
+        Overwrite:      &[]bool{false}[0],
+    })
     if err != nil {
-        return err
+        return nil, err
     }
-    defer src.Close()
+    if res.Error.Message != "" {
+        return nil, errors.New(res.Error.Message)
+    }
+    return res, nil
+}
+
+// DeleteUploadedImage hapus foto + thumbnail hasil UploadImage, dipakai saat penyimpanan ke DB gagal.
+// Error diabaikan; aset yang tertinggal dibersihkan garbage collector media.
+func DeleteUploadedImage(img *UploadedImage) {
+    if img == nil {
+        return
+    }
+    _ = DeleteCloudinaryAsset(img.PublicID)
+    _ = DeleteCloudinaryAsset(img.ThumbPublicID)
+}
 
-    out, err := os.Create(dst)
+// CloudinaryFolder folder induk semua aset aplikasi di Cloudinary (CLOUDINARY_FOLDER, default "avocycle").
+// Dipakai saat upload maupun oleh media GC, bedakan per environment jika satu akun Cloudinary dipakai bersama.
+func CloudinaryFolder() string {
+    if folder := strings.Trim(strings.TrimSpace(os.Getenv("CLOUDINARY_FOLDER")), "/"); folder != "" {
+        return folder
+    }
+    return "avocycle"
+}
+
+// StoredAsset aset gambar yang ada di Cloudinary
+type StoredAsset struct {
+    PublicID  string    `json:"public_id"`
+    CreatedAt time.Time `json:"created_at"`
+    Bytes     int       `json:"bytes"`
+}
+
+// ListCloudinaryAssets semua aset gambar dengan public id berawalan prefix (mis. "avocycle/")
+func ListCloudinaryAssets(prefix string) ([]StoredAsset, error) {
+    cld, err := cloudinary.NewFromURL(os.Getenv("CLOUDINARY_URL"))
     if err != nil {
-        return err
+        return nil, fmt.Errorf("cloudinary init failed: %w", err)
     }
-    defer out.Close()
 
-    _, err = out.ReadFrom(src)
-    return err
+    var assets []StoredAsset
+    cursor := ""
+    for {
+        ctx, cancel := context.WithTimeout(context.Background(), uploadTimeout)
+        res, err := cld.Admin.Assets(ctx, admin.AssetsParams{
+            AssetType:    api.Image,
+            DeliveryType: "upload",
+            Prefix:       prefix,
+            MaxResults:   500,
+            NextCursor:   cursor,
+        })
+        cancel()
+        if err != nil {
+            return nil, err
+        }
+        if res.Error.Message != "" {
+            return nil, errors.New(res.Error.Message)
+        }
+        for _, a := range res.Assets {
+            assets = append(assets, StoredAsset{PublicID: a.PublicID, CreatedAt: a.CreatedAt, Bytes: a.Bytes})
+        }
+        if res.NextCursor == "" {
+            return assets, nil
+        }
+        cursor = res.NextCursor
+    }
 }
 
 func DeleteCloudinaryAsset(publicID string) error {
@@ -117,4 +215,22 @@ func DeleteCloudinaryAsset(publicID string) error {
         Invalidate: &[]bool{true}[0],
     })
     return err
-}
\ No newline at end of file
+}
+
+// unsafeFilenameChars karakter yang diganti "_" oleh SanitizeFilename
+var unsafeFilenameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// SanitizeFilename nama file dari client yang aman untuk ditampilkan / disimpan: tanpa path,
+// hanya huruf, angka, titik, strip dan underscore, maksimal 100 karakter.
+func SanitizeFilename(name string) string {
+    name = strings.ReplaceAll(name, "\\", "/")
+    name = path.Base(name)
+    name = strings.Trim(unsafeFilenameChars.ReplaceAllString(name, "_"), "._")
+    if len(name) > 100 {
+        name = name[len(name)-100:]
+    }
+    if name == "" {
+        return "foto"
+    }
+    return name
+}
@@ -0,0 +1,282 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"mime/multipart"
+	"strings"
+	"time"
+)
+
+// ExifData metadata EXIF yang dipakai aplikasi. Nilai nol / nil jika tag tidak ada.
+type ExifData struct {
+	Orientation int        // 1-8, lihat applyOrientation
+	TakenAt     *time.Time // DateTimeOriginal
+	Latitude    *float64   // GPS, WGS84
+	Longitude   *float64
+}
+
+// HasLocation true jika foto membawa koordinat GPS
+func (e *ExifData) HasLocation() bool {
+	return e != nil && e.Latitude != nil && e.Longitude != nil
+}
+
+const (
+	exifTagOrientation        = 0x0112
+	exifTagExifIFD            = 0x8769
+	exifTagGPSIFD             = 0x8825
+	exifTagDateTimeOriginal   = 0x9003
+	exifTagOffsetTimeOriginal = 0x9011
+	gpsTagLatitudeRef         = 0x0001
+	gpsTagLatitude            = 0x0002
+	gpsTagLongitudeRef        = 0x0003
+	gpsTagLongitude           = 0x0004
+
+	// segmen APP1 maksimal 64 KB dan selalu di awal file
+	exifHeadSize = 128 << 10
+)
+
+// exifIFDEntry satu entry IFD TIFF, value berisi 4 byte mentah (nilai langsung atau offset)
+type exifIFDEntry struct {
+	Tag   uint16
+	Type  uint16
+	Count uint32
+	Value []byte
+}
+
+type exifReader struct {
+	tiff  []byte
+	order binary.ByteOrder
+}
+
+// ReadExif baca EXIF dari JPEG (segmen APP1). nil jika bukan JPEG atau tidak ada EXIF.
+// Parser minimal: cukup untuk tag yang dipakai, data rusak diabaikan tanpa panic.
+func ReadExif(data []byte) *ExifData {
+	tiff := jpegExifSegment(data)
+	if tiff == nil {
+		return nil
+	}
+	r, ifd0, ok := newExifReader(tiff)
+	if !ok {
+		return nil
+	}
+
+	exif := &ExifData{}
+	for _, e := range r.readIFD(ifd0) {
+		switch e.Tag {
+		case exifTagOrientation:
+			if v, ok := r.uint(e); ok {
+				exif.Orientation = int(v)
+			}
+		case exifTagExifIFD:
+			if v, ok := r.uint(e); ok {
+				exif.TakenAt = r.takenAt(r.readIFD(v))
+			}
+		case exifTagGPSIFD:
+			if v, ok := r.uint(e); ok {
+				exif.Latitude, exif.Longitude = r.gpsLocation(r.readIFD(v))
+			}
+		}
+	}
+	return exif
+}
+
+// ReadExifFile EXIF dari file upload tanpa membaca seluruh isi file. nil jika tidak ada.
+func ReadExifFile(file *multipart.FileHeader) *ExifData {
+	if file == nil {
+		return nil
+	}
+	src, err := file.Open()
+	if err != nil {
+		return nil
+	}
+	defer src.Close()
+	head, err := io.ReadAll(io.LimitReader(src, exifHeadSize))
+	if err != nil {
+		return nil
+	}
+	return ReadExif(head)
+}
+
+// takenAt DateTimeOriginal ("2006:01:02 15:04:05"). Tanpa OffsetTimeOriginal dianggap zona waktu server.
+// Waktu di masa depan (jam kamera salah) diabaikan.
+func (r *exifReader) takenAt(entries []exifIFDEntry) *time.Time {
+	var raw, offset string
+	for _, e := range entries {
+		switch e.Tag {
+		case exifTagDateTimeOriginal:
+			raw = r.ascii(e)
+		case exifTagOffsetTimeOriginal:
+			offset = r.ascii(e)
+		}
+	}
+	if raw == "" {
+		return nil
+	}
+	var t time.Time
+	var err error
+	if offset != "" {
+		t, err = time.Parse("2006:01:02 15:04:05-07:00", raw+offset)
+	} else {
+		t, err = time.ParseInLocation("2006:01:02 15:04:05", raw, time.Local)
+	}
+	if err != nil || t.Year() < 1990 || t.After(time.Now().Add(time.Minute)) {
+		return nil
+	}
+	return &t
+}
+
+// gpsLocation koordinat desimal dari GPSLatitude / GPSLongitude (derajat, menit, detik) + Ref N/S E/W
+func (r *exifReader) gpsLocation(entries []exifIFDEntry) (*float64, *float64) {
+	var latRef, lngRef string
+	var lat, lng []float64
+	for _, e := range entries {
+		switch e.Tag {
+		case gpsTagLatitudeRef:
+			latRef = r.ascii(e)
+		case gpsTagLatitude:
+			lat = r.rationals(e)
+		case gpsTagLongitudeRef:
+			lngRef = r.ascii(e)
+		case gpsTagLongitude:
+			lng = r.rationals(e)
+		}
+	}
+	if len(lat) != 3 || len(lng) != 3 {
+		return nil, nil
+	}
+	latV := lat[0] + lat[1]/60 + lat[2]/3600
+	lngV := lng[0] + lng[1]/60 + lng[2]/3600
+	if latRef == "S" {
+		latV = -latV
+	}
+	if lngRef == "W" {
+		lngV = -lngV
+	}
+	// 0,0 biasanya GPS belum fix, bukan lokasi sebenarnya
+	if (latV == 0 && lngV == 0) || ValidateLatLng(latV, lngV) != nil {
+		return nil, nil
+	}
+	return &latV, &lngV
+}
+
+// jpegExifSegment isi TIFF dari segmen APP1 "Exif\0\0"
+func jpegExifSegment(data []byte) []byte {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil
+	}
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return nil
+		}
+		marker := data[pos+1]
+		// SOS / EOI: metadata sudah lewat
+		if marker == 0xDA || marker == 0xD9 {
+			return nil
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if length < 2 || pos+2+length > len(data) {
+			return nil
+		}
+		segment := data[pos+4 : pos+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return segment[6:]
+		}
+		pos += 2 + length
+	}
+	return nil
+}
+
+func newExifReader(tiff []byte) (*exifReader, uint32, bool) {
+	if len(tiff) < 8 {
+		return nil, 0, false
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil, 0, false
+	}
+	if order.Uint16(tiff[2:]) != 42 {
+		return nil, 0, false
+	}
+	return &exifReader{tiff: tiff, order: order}, order.Uint32(tiff[4:]), true
+}
+
+func (r *exifReader) readIFD(offset uint32) []exifIFDEntry {
+	if int(offset)+2 > len(r.tiff) {
+		return nil
+	}
+	n := int(r.order.Uint16(r.tiff[offset:]))
+	start := int(offset) + 2
+	if start+n*12 > len(r.tiff) {
+		return nil
+	}
+	entries := make([]exifIFDEntry, 0, n)
+	for i := 0; i < n; i++ {
+		b := r.tiff[start+i*12:]
+		entries = append(entries, exifIFDEntry{
+			Tag:   r.order.Uint16(b),
+			Type:  r.order.Uint16(b[2:]),
+			Count: r.order.Uint32(b[4:]),
+			Value: b[8:12],
+		})
+	}
+	return entries
+}
+
+// data isi entry: langsung di Value jika <= 4 byte, selain itu di offset
+func (r *exifReader) data(e exifIFDEntry, size int) []byte {
+	if size <= 4 {
+		return e.Value[:size]
+	}
+	offset := int(r.order.Uint32(e.Value))
+	if offset < 0 || offset+size > len(r.tiff) {
+		return nil
+	}
+	return r.tiff[offset : offset+size]
+}
+
+// ascii nilai ASCII tanpa NUL penutup
+func (r *exifReader) ascii(e exifIFDEntry) string {
+	if e.Type != 2 || e.Count == 0 || e.Count > 256 {
+		return ""
+	}
+	return strings.TrimRight(string(r.data(e, int(e.Count))), "\x00 ")
+}
+
+// rationals nilai RATIONAL (pasangan LONG pembilang / penyebut)
+func (r *exifReader) rationals(e exifIFDEntry) []float64 {
+	if e.Type != 5 || e.Count == 0 || e.Count > 16 {
+		return nil
+	}
+	b := r.data(e, int(e.Count)*8)
+	if b == nil {
+		return nil
+	}
+	vals := make([]float64, e.Count)
+	for i := range vals {
+		num, den := r.order.Uint32(b[i*8:]), r.order.Uint32(b[i*8+4:])
+		if den == 0 {
+			return nil
+		}
+		vals[i] = float64(num) / float64(den)
+	}
+	return vals
+}
+
+// uint nilai SHORT / LONG tunggal
+func (r *exifReader) uint(e exifIFDEntry) (uint32, bool) {
+	switch e.Type {
+	case 3: // SHORT
+		return uint32(r.order.Uint16(e.Value)), true
+	case 4: // LONG
+		return r.order.Uint32(e.Value), true
+	}
+	return 0, false
+}
@@ -0,0 +1,168 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+)
+
+// exifFixture JPEG minimal (SOI, APP1 Exif, EOI) dengan Orientation, DateTimeOriginal dan GPS.
+// Layout TIFF: IFD0 @8 (3 entry), Exif IFD @50, string tanggal @68, GPS IFD @88, rational @142 & @166.
+func exifFixture(order binary.ByteOrder, orientation uint16, takenAt string, lat, lng [3]uint32, latRef, lngRef string) []byte {
+	tiff := make([]byte, 190)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+
+	entry := func(at int, tag, typ uint16, count uint32, value []byte) {
+		order.PutUint16(tiff[at:], tag)
+		order.PutUint16(tiff[at+2:], typ)
+		order.PutUint32(tiff[at+4:], count)
+		copy(tiff[at+8:at+12], value)
+	}
+	short := func(v uint16) []byte { b := make([]byte, 4); order.PutUint16(b, v); return b }
+	long := func(v uint32) []byte { b := make([]byte, 4); order.PutUint32(b, v); return b }
+	rationals := func(at int, v [3]uint32) {
+		for i, n := range v {
+			order.PutUint32(tiff[at+i*8:], n)
+			order.PutUint32(tiff[at+i*8+4:], 1)
+		}
+	}
+
+	order.PutUint16(tiff[8:], 3)
+	entry(10, exifTagOrientation, 3, 1, short(orientation))
+	entry(22, exifTagExifIFD, 4, 1, long(50))
+	entry(34, exifTagGPSIFD, 4, 1, long(88))
+
+	order.PutUint16(tiff[50:], 1)
+	entry(52, exifTagDateTimeOriginal, 2, 20, long(68))
+	copy(tiff[68:88], takenAt+"\x00")
+
+	order.PutUint16(tiff[88:], 4)
+	entry(90, gpsTagLatitudeRef, 2, 2, []byte(latRef+"\x00"))
+	entry(102, gpsTagLatitude, 5, 3, long(142))
+	entry(114, gpsTagLongitudeRef, 2, 2, []byte(lngRef+"\x00"))
+	entry(126, gpsTagLongitude, 5, 3, long(166))
+	rationals(142, lat)
+	rationals(166, lng)
+
+	return wrapExifJPEG(tiff)
+}
+
+func wrapExifJPEG(tiff []byte) []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{0xFF, 0xD8, 0xFF, 0xE1})
+	binary.Write(&buf, binary.BigEndian, uint16(2+6+len(tiff)))
+	buf.WriteString("Exif\x00\x00")
+	buf.Write(tiff)
+	buf.Write([]byte{0xFF, 0xD9})
+	return buf.Bytes()
+}
+
+func TestReadExif(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		data := exifFixture(order, 6, "2024:03:05 07:30:00", [3]uint32{7, 15, 0}, [3]uint32{112, 45, 0}, "S", "E")
+		exif := ReadExif(data)
+		if exif == nil {
+			t.Fatalf("%v: ReadExif = nil", order)
+		}
+		if exif.Orientation != 6 {
+			t.Errorf("%v: Orientation = %d, want 6", order, exif.Orientation)
+		}
+		want := time.Date(2024, 3, 5, 7, 30, 0, 0, time.Local)
+		if exif.TakenAt == nil || !exif.TakenAt.Equal(want) {
+			t.Errorf("%v: TakenAt = %v, want %v", order, exif.TakenAt, want)
+		}
+		if !exif.HasLocation() || math.Abs(*exif.Latitude+7.25) > 1e-9 || math.Abs(*exif.Longitude-112.75) > 1e-9 {
+			t.Errorf("%v: lokasi = %v, %v, want -7.25, 112.75", order, exif.Latitude, exif.Longitude)
+		}
+	}
+}
+
+func TestReadExifIgnoresInvalidValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		takenAt  string
+		lat, lng [3]uint32
+		wantTime bool
+		wantGPS  bool
+	}{
+		{"gps belum fix (0,0)", "2024:03:05 07:30:00", [3]uint32{}, [3]uint32{}, true, false},
+		{"latitude di luar rentang", "2024:03:05 07:30:00", [3]uint32{95, 0, 0}, [3]uint32{110, 0, 0}, true, false},
+		{"tanggal di masa depan", "2999:01:01 00:00:00", [3]uint32{7, 0, 0}, [3]uint32{110, 0, 0}, false, true},
+		{"tanggal sebelum 1990", "1980:01:01 00:00:00", [3]uint32{7, 0, 0}, [3]uint32{110, 0, 0}, false, true},
+		{"format tanggal rusak", "kemarin sore", [3]uint32{7, 0, 0}, [3]uint32{110, 0, 0}, false, true},
+	}
+	for _, tt := range tests {
+		exif := ReadExif(exifFixture(binary.LittleEndian, 1, tt.takenAt, tt.lat, tt.lng, "S", "E"))
+		if exif == nil {
+			t.Fatalf("%s: ReadExif = nil", tt.name)
+		}
+		if (exif.TakenAt != nil) != tt.wantTime {
+			t.Errorf("%s: TakenAt = %v", tt.name, exif.TakenAt)
+		}
+		if exif.HasLocation() != tt.wantGPS {
+			t.Errorf("%s: HasLocation = %v", tt.name, exif.HasLocation())
+		}
+	}
+}
+
+// Data rusak / terpotong tidak boleh membuat panic; hasilnya nil atau sebagian tag saja.
+func TestReadExifMalformed(t *testing.T) {
+	valid := exifFixture(binary.LittleEndian, 6, "2024:03:05 07:30:00", [3]uint32{7, 15, 0}, [3]uint32{112, 45, 0}, "S", "E")
+	const tiffStart = 4 + 2 + 6 // APP1 marker, panjang, "Exif\0\0"
+
+	patch := func(at int, b ...byte) []byte {
+		data := bytes.Clone(valid)
+		copy(data[tiffStart+at:], b)
+		return data
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"kosong", nil},
+		{"bukan jpeg", []byte("GIF89a....")},
+		{"hanya SOI", []byte{0xFF, 0xD8}},
+		{"panjang segmen melebihi file", []byte{0xFF, 0xD8, 0xFF, 0xE1, 0xFF, 0xFF, 'E', 'x'}},
+		{"panjang segmen < 2", []byte{0xFF, 0xD8, 0xFF, 0xE1, 0x00, 0x01}},
+		{"byte order tidak dikenal", patch(0, 'X', 'X')},
+		{"magic bukan 42", patch(2, 43, 0)},
+		{"offset IFD0 di luar data", patch(4, 0xFF, 0xFF, 0xFF, 0xFF)},
+		{"jumlah entry IFD0 terlalu besar", patch(8, 0xFF, 0xFF)},
+		{"offset Exif IFD di luar data", patch(22+8, 0xFF, 0xFF, 0xFF, 0x7F)},
+		{"offset string tanggal di luar data", patch(52+8, 0xF0, 0xFF, 0xFF, 0xFF)},
+		{"count string tanggal raksasa", patch(52+4, 0xFF, 0xFF, 0xFF, 0xFF)},
+		{"count rational raksasa", patch(102+4, 0xFF, 0xFF, 0xFF, 0xFF)},
+		{"penyebut rational nol", patch(142+4, 0, 0, 0, 0)},
+		{"tipe orientation salah", patch(10+2, 9, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("panic: %v", r)
+				}
+			}()
+			ReadExif(tt.data)
+		})
+	}
+
+	for n := 0; n < len(valid); n++ {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("terpotong %d byte: panic: %v", n, r)
+				}
+			}()
+			ReadExif(valid[:n])
+		}()
+	}
+}
@@ -0,0 +1,232 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const earthRadiusKm = 6371.0
+
+// ValidateLatLng cek rentang koordinat WGS84
+func ValidateLatLng(lat, lng float64) error {
+	if math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return errors.New("latitude harus di antara -90 dan 90")
+	}
+	if math.IsNaN(lng) || lng < -180 || lng > 180 {
+		return errors.New("longitude harus di antara -180 dan 180")
+	}
+	return nil
+}
+
+// HaversineKm jarak dua titik di permukaan bumi dalam kilometer
+func HaversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLng := (lng2 - lng1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// GeoJSONPolygon geometry GeoJSON bertipe Polygon, koordinat [lng, lat]
+type GeoJSONPolygon struct {
+	Type        string         `json:"type"`
+	Coordinates [][][2]float64 `json:"coordinates"`
+}
+
+// GeoJSONPoint geometry titik, koordinat [lng, lat]
+type GeoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// NewGeoJSONPoint titik dari lat / lng
+func NewGeoJSONPoint(lat, lng float64) *GeoJSONPoint {
+	return &GeoJSONPoint{Type: "Point", Coordinates: [2]float64{lng, lat}}
+}
+
+// GeoJSONFeature satu feature. Geometry nil ditulis null (feature tanpa lokasi masih sah di GeoJSON).
+type GeoJSONFeature struct {
+	Type       string         `json:"type"`
+	ID         any            `json:"id,omitempty"`
+	Geometry   any            `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+// GeoJSONFeatureCollection kumpulan feature untuk peta
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+// NewGeoJSONFeatureCollection collection kosong, features selalu array (bukan null)
+func NewGeoJSONFeatureCollection() *GeoJSONFeatureCollection {
+	return &GeoJSONFeatureCollection{Type: "FeatureCollection", Features: []GeoJSONFeature{}}
+}
+
+// ParseGeoJSONPolygon validasi batas kebun: Polygon, ring tertutup, minimal 4 titik, koordinat valid
+func ParseGeoJSONPolygon(raw []byte) (*GeoJSONPolygon, error) {
+	var poly GeoJSONPolygon
+	if err := json.Unmarshal(raw, &poly); err != nil {
+		return nil, fmt.Errorf("batas bukan GeoJSON Polygon: %w", err)
+	}
+	if poly.Type != "Polygon" {
+		return nil, errors.New("type batas harus Polygon")
+	}
+	if len(poly.Coordinates) == 0 {
+		return nil, errors.New("koordinat batas kosong")
+	}
+	for i, ring := range poly.Coordinates {
+		if len(ring) < 4 {
+			return nil, fmt.Errorf("ring %d minimal 4 titik", i)
+		}
+		if ring[0] != ring[len(ring)-1] {
+			return nil, fmt.Errorf("ring %d harus tertutup (titik awal = titik akhir)", i)
+		}
+		for _, p := range ring {
+			if err := ValidateLatLng(p[1], p[0]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &poly, nil
+}
+
+// AreaHectares luas polygon (ring luar dikurangi lubang) dengan proyeksi equirectangular
+// di sekitar centroid, cukup akurat untuk ukuran kebun
+func (p *GeoJSONPolygon) AreaHectares() float64 {
+	area := 0.0
+	for i, ring := range p.Coordinates {
+		a := ringAreaM2(ring)
+		if i == 0 {
+			area += a
+		} else {
+			area -= a
+		}
+	}
+	return math.Max(area, 0) / 10000
+}
+
+// Centroid rata-rata titik ring luar (tanpa titik penutup)
+func (p *GeoJSONPolygon) Centroid() (lat, lng float64) {
+	ring := p.Coordinates[0]
+	n := len(ring) - 1
+	for _, pt := range ring[:n] {
+		lng += pt[0]
+		lat += pt[1]
+	}
+	return lat / float64(n), lng / float64(n)
+}
+
+// Contains titik di dalam polygon (di dalam ring luar, di luar semua lubang)
+func (p *GeoJSONPolygon) Contains(lat, lng float64) bool {
+	for i, ring := range p.Coordinates {
+		if ringContains(ring, lat, lng) != (i == 0) {
+			return false
+		}
+	}
+	return len(p.Coordinates) > 0
+}
+
+// ringContains ray casting, koordinat [lng, lat]
+func ringContains(ring [][2]float64, lat, lng float64) bool {
+	in := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a[1] > lat) != (b[1] > lat) &&
+			lng < (b[0]-a[0])*(lat-a[1])/(b[1]-a[1])+a[0] {
+			in = !in
+		}
+	}
+	return in
+}
+
+func ringAreaM2(ring [][2]float64) float64 {
+	rad := math.Pi / 180
+	var lat0 float64
+	for _, pt := range ring {
+		lat0 += pt[1]
+	}
+	lat0 /= float64(len(ring))
+	kx := earthRadiusKm * 1000 * rad * math.Cos(lat0*rad)
+	ky := earthRadiusKm * 1000 * rad
+
+	sum := 0.0
+	for i := 0; i < len(ring)-1; i++ {
+		x1, y1 := ring[i][0]*kx, ring[i][1]*ky
+		x2, y2 := ring[i+1][0]*kx, ring[i+1][1]*ky
+		sum += x1*y2 - x2*y1
+	}
+	return math.Abs(sum) / 2
+}
+
+// rentang ketinggian kebun yang masuk akal (mdpl), di luar ini dianggap salah ketik
+const (
+	KetinggianMin  = -500
+	KetinggianMaks = 6000
+)
+
+// ValidKetinggian cek ketinggian (mdpl) di dalam rentang KetinggianMin - KetinggianMaks
+func ValidKetinggian(v float64) bool {
+	return v >= KetinggianMin && v <= KetinggianMaks
+}
+
+var (
+	mdplNumberRe     = regexp.MustCompile(`\d[\d.,]*`)
+	thousandsDotsRe  = regexp.MustCompile(`^\d{1,3}(\.\d{3})+$`)
+	thousandsCommaRe = regexp.MustCompile(`^\d{1,3}(,\d{3})+$`)
+)
+
+// ParseMDPL ambil angka ketinggian dari teks bebas seperti "1.200 mdpl", "1,200 m" atau "850m".
+// Titik / koma diikuti tepat 3 digit dianggap pemisah ribuan (ketinggian pecahan tidak lazim);
+// jika titik dan koma sama-sama ada, yang terakhir pemisah desimal.
+func ParseMDPL(text string) (float64, bool) {
+	token := strings.TrimRight(mdplNumberRe.FindString(text), ".,")
+	if token == "" {
+		return 0, false
+	}
+	switch {
+	case thousandsDotsRe.MatchString(token):
+		token = strings.ReplaceAll(token, ".", "")
+	case thousandsCommaRe.MatchString(token):
+		token = strings.ReplaceAll(token, ",", "")
+	case strings.LastIndex(token, ",") > strings.LastIndex(token, "."):
+		// 1.200,5 atau 12,5
+		token = strings.ReplaceAll(token, ".", "")
+		token = strings.ReplaceAll(token, ",", ".")
+	default:
+		// 1,200.5 atau 12.5
+		token = strings.ReplaceAll(token, ",", "")
+	}
+	v, err := strconv.ParseFloat(token, 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+// zona waktu Indonesia, tanpa daylight saving jadi cukup offset tetap
+var (
+	ZonaWIB  = time.FixedZone("WIB", 7*3600)
+	ZonaWITA = time.FixedZone("WITA", 8*3600)
+	ZonaWIT  = time.FixedZone("WIT", 9*3600)
+)
+
+// ZonaWaktuKebun zona waktu lokal dari bujur kebun: perkiraan batas WIB / WITA (~114,5° BT, Bali &
+// Kalimantan Selatan ke timur) dan WITA / WIT (~126,5° BT, Maluku & Papua). Tanpa koordinat dianggap WIB.
+func ZonaWaktuKebun(longitude *float64) *time.Location {
+	switch {
+	case longitude == nil || *longitude < 114.5:
+		return ZonaWIB
+	case *longitude < 126.5:
+		return ZonaWITA
+	default:
+		return ZonaWIT
+	}
+}
@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseMDPL(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+		ok   bool
+	}{
+		{"850m", 850, true},
+		{"1.200 mdpl", 1200, true},
+		{"1,200 mdpl", 1200, true},
+		{"1.200,5 mdpl", 1200.5, true},
+		{"1,200.5 m", 1200.5, true},
+		{"12,5", 12.5, true},
+		{"12.5", 12.5, true},
+		{"1,20", 1.2, true},
+		{"± 700 - 900 mdpl", 700, true},
+		{"dataran tinggi", 0, false},
+		{"", 0, false},
+		{"1.2.3", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseMDPL(tt.in)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseMDPL(%q) = %v, %v; want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestGeoJSONPolygonContains(t *testing.T) {
+	// kebun 0..10 x 0..10 dengan dua lubang (kolam 2..4, bangunan 6..8), koordinat [lng, lat]
+	withHoles := []byte(`{"type":"Polygon","coordinates":[
+		[[0,0],[10,0],[10,10],[0,10],[0,0]],
+		[[2,2],[4,2],[4,4],[2,4],[2,2]],
+		[[6,6],[8,6],[8,8],[6,8],[6,6]]]}`)
+	// bentuk U: cekungan 3..7 di atas lat 3 bukan bagian kebun
+	concave := []byte(`{"type":"Polygon","coordinates":[
+		[[0,0],[10,0],[10,10],[7,10],[7,3],[3,3],[3,10],[0,10],[0,0]]]}`)
+
+	tests := []struct {
+		name     string
+		poly     []byte
+		lat, lng float64
+		want     bool
+	}{
+		{"di dalam, di luar lubang", withHoles, 5, 5, true},
+		{"di dalam lubang pertama", withHoles, 3, 3, false},
+		{"di dalam lubang kedua", withHoles, 7, 7, false},
+		{"di antara dua lubang", withHoles, 5, 3, true},
+		{"dekat tepi luar", withHoles, 9.9, 0.1, true},
+		{"di luar ring luar", withHoles, 11, 5, false},
+		{"di luar, sejajar lubang", withHoles, 3, -1, false},
+		{"kaki kiri bentuk U", concave, 8, 1.5, true},
+		{"dasar bentuk U", concave, 1.5, 5, true},
+		{"di cekungan bentuk U", concave, 8, 5, false},
+	}
+	for _, tt := range tests {
+		poly, err := ParseGeoJSONPolygon(tt.poly)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := poly.Contains(tt.lat, tt.lng); got != tt.want {
+			t.Errorf("%s: Contains(%v, %v) = %v, want %v", tt.name, tt.lat, tt.lng, got, tt.want)
+		}
+	}
+}
+
+func TestGeoJSONPolygonAreaMinusHoles(t *testing.T) {
+	poly, err := ParseGeoJSONPolygon([]byte(`{"type":"Polygon","coordinates":[
+		[[110,-7],[110.01,-7],[110.01,-6.99],[110,-6.99],[110,-7]],
+		[[110.0025,-6.9975],[110.0075,-6.9975],[110.0075,-6.9925],[110.0025,-6.9925],[110.0025,-6.9975]]]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 0,01° x 0,01° di lat -7: 1,112 km x 1,104 km = ~122,7 ha, lubang seperempatnya
+	side := 2 * math.Pi * earthRadiusKm / 360 * 0.01
+	want := side * side * math.Cos(7*math.Pi/180) * 100 * 0.75
+	if got := poly.AreaHectares(); math.Abs(got-want) > want*0.01 {
+		t.Errorf("AreaHectares = %.2f, want ~%.2f", got, want)
+	}
+}
+
+func TestZonaWaktuKebun(t *testing.T) {
+	lng := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name      string
+		longitude *float64
+		want      string
+	}{
+		{"tanpa koordinat", nil, "WIB"},
+		{"Jakarta", lng(106.8), "WIB"},
+		{"Malang", lng(112.6), "WIB"},
+		{"Denpasar", lng(115.2), "WITA"},
+		{"Makassar", lng(119.4), "WITA"},
+		{"Manado", lng(124.8), "WITA"},
+		{"Ambon", lng(128.2), "WIT"},
+		{"Jayapura", lng(140.7), "WIT"},
+	}
+	for _, tt := range tests {
+		if got := ZonaWaktuKebun(tt.longitude).String(); got != tt.want {
+			t.Errorf("%s: ZonaWaktuKebun = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidKetinggian(t *testing.T) {
+	for v, want := range map[float64]bool{-500: true, 0: true, 1200: true, 6000: true, -501: false, 6001: false, 15000: false} {
+		if got := ValidKetinggian(v); got != want {
+			t.Errorf("ValidKetinggian(%v) = %v, want %v", v, got, want)
+		}
+	}
+}
@@ -3,23 +3,26 @@ package utils
 import (
 	"Avocycle/models"
 	"errors"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtSecretKey = []byte(os.Getenv("JWT_SECRET"))
-
 type Claims struct {
 	UserID       uint   `json:"user_id"`
     Email        string `json:"email"`
     Role         string `json:"role"`
     AuthProvider string `json:"auth_provider"`
+    MFA          bool   `json:"mfa,omitempty"` // true jika login sudah lewat verifikasi 2FA
     jwt.RegisteredClaims
 }
 
 func GenerateJWT(user *models.User) (string, error) {
+	return GenerateJWTWithMFA(user, false)
+}
+
+// GenerateJWTWithMFA sama seperti GenerateJWT, dengan penanda bahwa user sudah lolos 2FA
+func GenerateJWTWithMFA(user *models.User, mfa bool) (string, error) {
 	expirationTime := time.Now().Add(24*time.Hour)
 
 	claims := &Claims{
@@ -27,6 +30,7 @@ func GenerateJWT(user *models.User) (string, error) {
 		Email: user.Email,
 		Role: user.Role,
 		AuthProvider: user.AuthProvider,
+		MFA: mfa,
 		RegisteredClaims: jwt.RegisteredClaims{
             ExpiresAt: jwt.NewNumericDate(expirationTime),
             IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -36,9 +40,7 @@ func GenerateJWT(user *models.User) (string, error) {
         },
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	tokenString, err := token.SignedString(jwtSecretKey)
+	tokenString, err := signToken(claims)
 	if err != nil {
 		return "", err
 	}
@@ -48,19 +50,13 @@ func GenerateJWT(user *models.User) (string, error) {
 
 // ValidateJWT validates and parses JWT token
 func ValidateJWT(tokenString string) (*Claims, error) {
-    // Check if JWT secret is set
+    // Check if token is present
     if len(tokenString) == 0 {
-        return nil, errors.New("JWT secret key is not set")
+        return nil, errors.New("token is empty")
     }
 
-    // Parse token
-    token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-        // Validate signing method
-        if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-            return nil, errors.New("invalid signing method")
-        }
-        return jwtSecretKey, nil
-    })
+    // Parse token (signature dicek dengan key sesuai kid)
+    token, err := parseToken(tokenString, &Claims{}, jwt.WithIssuer("Avocycle"))
 
     if err != nil {
         return nil, err
@@ -95,6 +91,7 @@ func RefreshJWT(oldTokenString string) (string, error) {
         Email:        claims.Email,
         Role:         claims.Role,
         AuthProvider: claims.AuthProvider,
+        MFA:          claims.MFA,
         RegisteredClaims: jwt.RegisteredClaims{
             ExpiresAt: jwt.NewNumericDate(newExpirationTime),
             IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -104,8 +101,7 @@ func RefreshJWT(oldTokenString string) (string, error) {
         },
     }
 
-    token := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaims)
-    return token.SignedString(jwtSecretKey)
+    return signToken(newClaims)
 }
 
 // ==== Temp token khusus flow Google ====
@@ -137,18 +133,12 @@ func GenerateTempGoogleToken(provider, providerID, email, fullName string) (stri
         },
     }
 
-    token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-    return token.SignedString(jwtSecretKey)
+    return signToken(claims)
 }
 
 // ParseTempGoogleToken mem-parse tempToken dari FE
 func ParseTempGoogleToken(tokenString string) (*TempGoogleClaims, error) {
-    token, err := jwt.ParseWithClaims(tokenString, &TempGoogleClaims{}, func(token *jwt.Token) (interface{}, error) {
-        if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-            return nil, errors.New("invalid signing method")
-        }
-        return jwtSecretKey, nil
-    })
+    token, err := parseToken(tokenString, &TempGoogleClaims{}, jwt.WithIssuer("Avocycle-Google-Temp"))
 
     if err != nil {
         return nil, err
@@ -160,3 +150,85 @@ func ParseTempGoogleToken(tokenString string) (*TempGoogleClaims, error) {
 
     return nil, errors.New("invalid temp token")
 }
+
+// ==== State token untuk flow OAuth/OIDC ====
+
+// OAuthStateClaims dibawa lewat parameter state, berisi role yang dipilih dan nonce id_token
+type OAuthStateClaims struct {
+    Provider string `json:"provider"`
+    Role     string `json:"role"`
+    Nonce    string `json:"nonce"`
+    jwt.RegisteredClaims
+}
+
+// GenerateOAuthState membuat state bertanda tangan (10 menit). stateID juga disimpan di cookie
+// browser sehingga callback bisa memastikan state berasal dari sesi yang sama.
+func GenerateOAuthState(provider, role, nonce, stateID string) (string, error) {
+    claims := &OAuthStateClaims{
+        Provider: provider,
+        Role:     role,
+        Nonce:    nonce,
+        RegisteredClaims: jwt.RegisteredClaims{
+            ID:        stateID,
+            ExpiresAt: jwt.NewNumericDate(time.Now().Add(10 * time.Minute)),
+            IssuedAt:  jwt.NewNumericDate(time.Now()),
+            NotBefore: jwt.NewNumericDate(time.Now()),
+            Issuer:    "Avocycle-OAuth-State",
+        },
+    }
+
+    return signToken(claims)
+}
+
+// ParseOAuthState memverifikasi signature dan masa berlaku state dari callback provider
+func ParseOAuthState(tokenString string) (*OAuthStateClaims, error) {
+    token, err := parseToken(tokenString, &OAuthStateClaims{}, jwt.WithIssuer("Avocycle-OAuth-State"))
+
+    if err != nil {
+        return nil, err
+    }
+
+    if claims, ok := token.Claims.(*OAuthStateClaims); ok && token.Valid {
+        return claims, nil
+    }
+
+    return nil, errors.New("invalid oauth state")
+}
+
+// ==== Challenge token untuk login 2 langkah (2FA) ====
+
+// TwoFactorChallengeClaims diberikan setelah password benar, ditukar dengan JWT penuh di /login/2fa
+type TwoFactorChallengeClaims struct {
+    UserID uint `json:"user_id"`
+    jwt.RegisteredClaims
+}
+
+// GenerateTwoFactorChallenge membuat challenge token jangka pendek (5 menit)
+func GenerateTwoFactorChallenge(user *models.User) (string, error) {
+    claims := &TwoFactorChallengeClaims{
+        UserID: user.ID,
+        RegisteredClaims: jwt.RegisteredClaims{
+            ExpiresAt: jwt.NewNumericDate(time.Now().Add(5 * time.Minute)),
+            IssuedAt:  jwt.NewNumericDate(time.Now()),
+            NotBefore: jwt.NewNumericDate(time.Now()),
+            Issuer:    "Avocycle-2FA-Challenge",
+            Subject:   user.Email,
+        },
+    }
+
+    return signToken(claims)
+}
+
+// ParseTwoFactorChallenge memverifikasi challenge token dari FE
+func ParseTwoFactorChallenge(tokenString string) (*TwoFactorChallengeClaims, error) {
+    token, err := parseToken(tokenString, &TwoFactorChallengeClaims{}, jwt.WithIssuer("Avocycle-2FA-Challenge"))
+    if err != nil {
+        return nil, err
+    }
+
+    if claims, ok := token.Claims.(*TwoFactorChallengeClaims); ok && token.Valid {
+        return claims, nil
+    }
+
+    return nil, errors.New("invalid 2fa challenge")
+}
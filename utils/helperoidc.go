@@ -0,0 +1,228 @@
+package utils
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const jwksCacheTTL = time.Hour
+
+// JWK representasi satu public key dalam JSON Web Key Set (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKS kumpulan JWK, format response /.well-known/jwks.json
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// PublicKey mengubah JWK menjadi crypto.PublicKey (RSA, EC P-256/P-384 atau Ed25519)
+func (k JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("jwk %s: n tidak valid", k.Kid)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("jwk %s: e tidak valid", k.Kid)
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("jwk %s: curve %s tidak didukung", k.Kid, k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("jwk %s: x tidak valid", k.Kid)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("jwk %s: y tidak valid", k.Kid)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("jwk %s: curve %s tidak didukung", k.Kid, k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("jwk %s: x tidak valid", k.Kid)
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("jwk %s: kty %s tidak didukung", k.Kid, k.Kty)
+	}
+}
+
+type jwksCacheEntry struct {
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+var (
+	jwksCache   = map[string]*jwksCacheEntry{}
+	jwksCacheMu sync.Mutex
+)
+
+// fetchJWKS mengambil JWKS dari URL provider, di-cache selama jwksCacheTTL.
+// forceRefresh dipakai ketika kid tidak ditemukan (provider baru rotasi key).
+func fetchJWKS(ctx context.Context, jwksURL string, forceRefresh bool) (map[string]crypto.PublicKey, error) {
+	jwksCacheMu.Lock()
+	defer jwksCacheMu.Unlock()
+
+	if entry, ok := jwksCache[jwksURL]; ok && !forceRefresh && time.Since(entry.fetchedAt) < jwksCacheTTL {
+		return entry.keys, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("gagal ambil jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("gagal ambil jwks: status %d", resp.StatusCode)
+	}
+
+	var set JWKS
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("jwks tidak valid: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pub, err := k.PublicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+
+	jwksCache[jwksURL] = &jwksCacheEntry{keys: keys, fetchedAt: time.Now()}
+	return keys, nil
+}
+
+// IDTokenClaims klaim standar OIDC yang kita pakai dari id_token
+type IDTokenClaims struct {
+	Email         string      `json:"email"`
+	EmailVerified interface{} `json:"email_verified"` // bool, beberapa provider mengirim string "true"
+	Name          string      `json:"name"`
+	Nonce         string      `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+// ParseEmailVerified membaca klaim email_verified. present = false jika provider tidak mengirimnya.
+func ParseEmailVerified(v interface{}) (verified bool, present bool) {
+	switch val := v.(type) {
+	case bool:
+		return val, true
+	case string:
+		return strings.EqualFold(val, "true"), true
+	default:
+		return false, false
+	}
+}
+
+// VerifyIDToken memverifikasi signature id_token terhadap JWKS provider,
+// lalu issuer, audience (client id), masa berlaku dan nonce.
+func VerifyIDToken(ctx context.Context, rawIDToken, jwksURL, issuer, clientID, nonce string) (*IDTokenClaims, error) {
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+
+		keys, err := fetchJWKS(ctx, jwksURL, false)
+		if err != nil {
+			return nil, err
+		}
+		if key, ok := keys[kid]; ok {
+			return key, nil
+		}
+
+		// kid belum dikenal, kemungkinan provider baru rotasi key
+		keys, err = fetchJWKS(ctx, jwksURL, true)
+		if err != nil {
+			return nil, err
+		}
+		if key, ok := keys[kid]; ok {
+			return key, nil
+		}
+		return nil, fmt.Errorf("kid %q tidak ditemukan di jwks", kid)
+	}
+
+	token, err := jwt.ParseWithClaims(rawIDToken, &IDTokenClaims{}, keyFunc,
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "EdDSA"}),
+		jwt.WithIssuer(issuer),
+		jwt.WithAudience(clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(30*time.Second),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*IDTokenClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid id token")
+	}
+
+	if nonce == "" || claims.Nonce != nonce {
+		return nil, errors.New("nonce id token tidak cocok")
+	}
+
+	return claims, nil
+}
+
+// RandomToken membuat string acak URL-safe (dipakai untuk state id dan nonce)
+func RandomToken(nBytes int) (string, error) {
+	b := make([]byte, nBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,251 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+
+	"github.com/gabriel-vasile/mimetype"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+const (
+	// MaxImageDimension sisi terpanjang foto yang disimpan
+	MaxImageDimension = 2048
+	// ThumbnailDimension sisi terpanjang thumbnail
+	ThumbnailDimension = 320
+	// ClassifierImageDimension sisi terpanjang foto yang dikirim ke Gemini
+	ClassifierImageDimension = 1024
+	// batas piksel sebelum decode, mencegah decompression bomb. 36 MP cukup untuk kamera HP
+	// (mode 48/50 MP default menyimpan 12 MP) dan menjaga memori decode di bawah ~150 MB per upload.
+	maxImagePixels = 36_000_000
+	jpegQuality    = 85
+)
+
+var ErrNotImage = errors.New("file bukan gambar yang didukung (jpeg, png, webp, gif)")
+
+// ImageVariant gambar hasil olahan, siap diupload. Metadata (EXIF, GPS) tidak ikut karena di-encode ulang.
+type ImageVariant struct {
+	Data   []byte
+	MIME   string
+	Width  int
+	Height int
+}
+
+var imageDecoders = map[string]func([]byte) (image.Image, error){
+	"image/jpeg": func(b []byte) (image.Image, error) { return jpeg.Decode(bytes.NewReader(b)) },
+	"image/png":  func(b []byte) (image.Image, error) { return png.Decode(bytes.NewReader(b)) },
+	"image/gif":  func(b []byte) (image.Image, error) { return gif.Decode(bytes.NewReader(b)) },
+	"image/webp": func(b []byte) (image.Image, error) { return webp.Decode(bytes.NewReader(b)) },
+}
+
+var imageConfigDecoders = map[string]func([]byte) (image.Config, error){
+	"image/jpeg": func(b []byte) (image.Config, error) { return jpeg.DecodeConfig(bytes.NewReader(b)) },
+	"image/png":  func(b []byte) (image.Config, error) { return png.DecodeConfig(bytes.NewReader(b)) },
+	"image/gif":  func(b []byte) (image.Config, error) { return gif.DecodeConfig(bytes.NewReader(b)) },
+	"image/webp": func(b []byte) (image.Config, error) { return webp.DecodeConfig(bytes.NewReader(b)) },
+}
+
+// SniffImageMIME tentukan jenis gambar dari isi file (magic bytes), bukan ekstensi / header client
+func SniffImageMIME(data []byte) (string, error) {
+	mime := mimetype.Detect(data).String()
+	if _, ok := imageDecoders[mime]; !ok {
+		return "", ErrNotImage
+	}
+	return mime, nil
+}
+
+// LoadImage sniff + decode, diperkecil ke MaxImageDimension lalu diputar sesuai EXIF Orientation.
+// Diperkecil dulu supaya putar / balik tidak dikerjakan pada foto resolusi penuh.
+func LoadImage(data []byte) (image.Image, string, error) {
+	mime, err := SniffImageMIME(data)
+	if err != nil {
+		return nil, "", err
+	}
+	cfg, err := imageConfigDecoders[mime](data)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrNotImage, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxImagePixels {
+		return nil, "", fmt.Errorf("resolusi gambar %dx%d tidak didukung", cfg.Width, cfg.Height)
+	}
+	img, err := imageDecoders[mime](data)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrNotImage, err)
+	}
+	img = ResizeToFit(img, MaxImageDimension)
+	if exif := ReadExif(data); exif != nil {
+		img = applyOrientation(img, exif.Orientation)
+	}
+	return img, mime, nil
+}
+
+// ResizeToFit perkecil supaya sisi terpanjang <= maxDim, gambar kecil tidak diperbesar
+func ResizeToFit(img image.Image, maxDim int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxDim && h <= maxDim {
+		return img
+	}
+	if w >= h {
+		h = max(1, h*maxDim/w)
+		w = maxDim
+	} else {
+		w = max(1, w*maxDim/h)
+		h = maxDim
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+// EncodeImage PNG untuk gambar transparan / sumber PNG & GIF, selain itu JPEG
+func EncodeImage(img image.Image, sourceMIME string) (*ImageVariant, error) {
+	if sourceMIME == "image/png" || sourceMIME == "image/gif" || !isOpaque(img) {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+		return newImageVariant(buf.Bytes(), "image/png", img), nil
+	}
+	return EncodeJPEG(img)
+}
+
+// EncodeJPEG selalu JPEG, bagian transparan diberi latar putih
+func EncodeJPEG(img image.Image) (*ImageVariant, error) {
+	if !isOpaque(img) {
+		flat := image.NewRGBA(img.Bounds())
+		draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(flat, flat.Bounds(), img, img.Bounds().Min, draw.Over)
+		img = flat
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, err
+	}
+	return newImageVariant(buf.Bytes(), "image/jpeg", img), nil
+}
+
+// PrepareImage foto utama + thumbnail + perceptual hash dari file upload mentah
+func PrepareImage(data []byte) (full, thumb *ImageVariant, phash uint64, err error) {
+	img, mime, err := LoadImage(data)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if full, err = EncodeImage(ResizeToFit(img, MaxImageDimension), mime); err != nil {
+		return nil, nil, 0, err
+	}
+	small := ResizeToFit(img, ThumbnailDimension)
+	if thumb, err = EncodeImage(small, mime); err != nil {
+		return nil, nil, 0, err
+	}
+	return full, thumb, PerceptualHash(small), nil
+}
+
+// PerceptualHash dHash 64 bit: gambar diperkecil ke 9x8 grayscale, tiap bit = piksel lebih terang dari
+// tetangga kanannya. Foto yang sama setelah dikompres ulang / diperkecil menghasilkan hash yang (hampir) sama.
+func PerceptualHash(img image.Image) uint64 {
+	small := image.NewGray(image.Rect(0, 0, 9, 8))
+	draw.ApproxBiLinear.Scale(small, small.Bounds(), img, img.Bounds(), draw.Src, nil)
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			if small.GrayAt(x, y).Y > small.GrayAt(x+1, y).Y {
+				hash |= 1 << (y*8 + x)
+			}
+		}
+	}
+	return hash
+}
+
+// ContentHash SHA-256 isi file mentah, untuk mengenali upload yang byte-per-byte sama
+func ContentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// PrepareClassifierImage JPEG kecil untuk klasifikasi penyakit
+func PrepareClassifierImage(data []byte) (*ImageVariant, error) {
+	img, _, err := LoadImage(data)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeJPEG(ResizeToFit(img, ClassifierImageDimension))
+}
+
+func newImageVariant(data []byte, mime string, img image.Image) *ImageVariant {
+	b := img.Bounds()
+	return &ImageVariant{Data: data, MIME: mime, Width: b.Dx(), Height: b.Dy()}
+}
+
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return false
+}
+
+// applyOrientation putar / balik gambar sesuai tag EXIF Orientation (1 = normal).
+// Piksel disalin langsung antar buffer RGBA, bukan lewat At / Set per piksel.
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+	src := toRGBA(img)
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	// 5-8: lebar dan tinggi tertukar
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		row := src.Pix[y*src.Stride : y*src.Stride+w*4]
+		for x := 0; x < w; x++ {
+			dx, dy := orientedPoint(orientation, x, y, w, h)
+			i := dy*dst.Stride + dx*4
+			copy(dst.Pix[i:i+4], row[x*4:x*4+4])
+		}
+	}
+	return dst
+}
+
+// orientedPoint posisi piksel (x, y) gambar w x h setelah diputar / dibalik sesuai orientation
+func orientedPoint(orientation, x, y, w, h int) (int, int) {
+	switch orientation {
+	case 2: // cermin horizontal
+		return w - 1 - x, y
+	case 3: // putar 180
+		return w - 1 - x, h - 1 - y
+	case 4: // cermin vertikal
+		return x, h - 1 - y
+	case 5: // transpose
+		return y, x
+	case 6: // putar 90 searah jarum jam
+		return h - 1 - y, x
+	case 7: // transverse
+		return h - 1 - y, w - 1 - x
+	case 8: // putar 90 berlawanan jarum jam
+		return y, w - 1 - x
+	}
+	return x, y
+}
+
+// toRGBA gambar sebagai *image.RGBA berawal di (0, 0); hasil decode JPEG (YCbCr) dikonversi sekali
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) {
+		return rgba
+	}
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+	return rgba
+}
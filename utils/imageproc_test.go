@@ -0,0 +1,238 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"math/bits"
+	"testing"
+
+	"golang.org/x/image/draw"
+)
+
+// orientationLabels label piksel gambar uji 2x3:
+//
+//	a b
+//	c d
+//	e f
+var orientationLabels = "abcdef"
+
+func orientationColor(label byte) color.RGBA {
+	v := uint8(20 + 40*int(label-'a'))
+	return color.RGBA{R: v, G: 255 - v, B: v / 2, A: 255}
+}
+
+// gridOf label tiap piksel, baris per baris
+func gridOf(t *testing.T, img image.Image) []string {
+	t.Helper()
+	b := img.Bounds()
+	rows := make([]string, b.Dy())
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			got := color.RGBAModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.RGBA)
+			label := byte('?')
+			for i := 0; i < len(orientationLabels); i++ {
+				if orientationColor(orientationLabels[i]) == got {
+					label = orientationLabels[i]
+				}
+			}
+			rows[y] += string(label)
+		}
+	}
+	return rows
+}
+
+func TestApplyOrientation(t *testing.T) {
+	rgba := image.NewRGBA(image.Rect(0, 0, 2, 3))
+	// sumber bukan RGBA dan tidak berawal di (0, 0), lewat jalur konversi
+	nrgba := image.NewNRGBA(image.Rect(5, 7, 7, 10))
+	for i := 0; i < len(orientationLabels); i++ {
+		x, y := i%2, i/2
+		rgba.Set(x, y, orientationColor(orientationLabels[i]))
+		nrgba.Set(5+x, 7+y, orientationColor(orientationLabels[i]))
+	}
+
+	tests := []struct {
+		orientation int
+		want        []string
+	}{
+		{1, []string{"ab", "cd", "ef"}},
+		{2, []string{"ba", "dc", "fe"}},
+		{3, []string{"fe", "dc", "ba"}},
+		{4, []string{"ef", "cd", "ab"}},
+		{5, []string{"ace", "bdf"}},
+		{6, []string{"eca", "fdb"}},
+		{7, []string{"fdb", "eca"}},
+		{8, []string{"bdf", "ace"}},
+		{0, []string{"ab", "cd", "ef"}},
+		{9, []string{"ab", "cd", "ef"}},
+	}
+	for _, src := range []image.Image{rgba, nrgba} {
+		for _, tt := range tests {
+			got := gridOf(t, applyOrientation(src, tt.orientation))
+			if len(got) != len(tt.want) {
+				t.Errorf("%T orientation %d = %v, want %v", src, tt.orientation, got, tt.want)
+				continue
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("%T orientation %d = %v, want %v", src, tt.orientation, got, tt.want)
+					break
+				}
+			}
+		}
+	}
+}
+
+// withExifOrientation sisipkan segmen APP1 berisi Orientation saja tepat setelah SOI
+func withExifOrientation(jpg []byte, orientation uint16) []byte {
+	tiff := make([]byte, 26)
+	copy(tiff, "II")
+	binary.LittleEndian.PutUint16(tiff[2:], 42)
+	binary.LittleEndian.PutUint32(tiff[4:], 8)
+	binary.LittleEndian.PutUint16(tiff[8:], 1)
+	binary.LittleEndian.PutUint16(tiff[10:], exifTagOrientation)
+	binary.LittleEndian.PutUint16(tiff[12:], 3)
+	binary.LittleEndian.PutUint32(tiff[14:], 1)
+	binary.LittleEndian.PutUint16(tiff[18:], orientation)
+
+	app1 := wrapExifJPEG(tiff)
+	app1 = app1[2 : len(app1)-2] // tanpa SOI / EOI
+	return append(append([]byte{0xFF, 0xD8}, app1...), jpg[2:]...)
+}
+
+func encodeTestJPEG(t *testing.T, img image.Image, quality int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestLoadImageOrientationAndDownscale(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 3000, 1000))
+	jpg := encodeTestJPEG(t, src, 80)
+
+	tests := []struct {
+		orientation uint16
+		w, h        int
+	}{
+		{1, MaxImageDimension, MaxImageDimension / 3},
+		{3, MaxImageDimension, MaxImageDimension / 3},
+		{6, MaxImageDimension / 3, MaxImageDimension},
+		{8, MaxImageDimension / 3, MaxImageDimension},
+	}
+	for _, tt := range tests {
+		img, mime, err := LoadImage(withExifOrientation(jpg, tt.orientation))
+		if err != nil {
+			t.Fatalf("orientation %d: %v", tt.orientation, err)
+		}
+		if mime != "image/jpeg" {
+			t.Errorf("orientation %d: mime = %s", tt.orientation, mime)
+		}
+		if b := img.Bounds(); b.Dx() != tt.w || b.Dy() != tt.h {
+			t.Errorf("orientation %d: ukuran = %dx%d, want %dx%d", tt.orientation, b.Dx(), b.Dy(), tt.w, tt.h)
+		}
+	}
+}
+
+func TestLoadImageRejectsHugeResolution(t *testing.T) {
+	// header JPEG dengan dimensi di atas maxImagePixels, tanpa perlu membuat gambar sebesar itu
+	jpg := encodeTestJPEG(t, image.NewGray(image.Rect(0, 0, 16, 16)), 80)
+	sof := bytes.Index(jpg, []byte{0xFF, 0xC0})
+	if sof < 0 {
+		t.Fatal("SOF0 tidak ditemukan")
+	}
+	binary.BigEndian.PutUint16(jpg[sof+5:], 8000) // tinggi
+	binary.BigEndian.PutUint16(jpg[sof+7:], 8000) // lebar
+
+	if _, _, err := LoadImage(jpg); err == nil {
+		t.Fatal("LoadImage 8000x8000 harus ditolak")
+	}
+}
+
+// fieldPhoto gambar uji mirip foto: bidang-bidang berkontras (daun, batang, langit) ditambah gradien dan
+// tekstur halus, seed berbeda = foto berbeda
+func fieldPhoto(w, h int, seed uint32) *image.RGBA {
+	next := func() float64 {
+		seed = seed*1664525 + 1013904223
+		return float64(seed>>8) / (1 << 24)
+	}
+	const cells = 6
+	var base [cells][cells]float64
+	for i := range base {
+		for j := range base[i] {
+			base[i][j] = 30 + 190*next()
+		}
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			fx, fy := float64(x)/float64(w), float64(y)/float64(h)
+			v := base[int(fy*cells)][int(fx*cells)] + 20*fx - 15*fy + 6*math.Sin(float64(x*y)/50)
+			v = math.Max(0, math.Min(255, v))
+			img.Set(x, y, color.RGBA{R: uint8(v * 0.8), G: uint8(v), B: uint8(v * 0.6), A: 255})
+		}
+	}
+	return img
+}
+
+func phashOf(t *testing.T, data []byte) uint64 {
+	t.Helper()
+	_, _, phash, err := PrepareImage(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return phash
+}
+
+// Foto yang sama setelah dikompres ulang / diperkecil harus tetap dianggap duplikat
+// (selisih <= config.MediaDuplicateMaxDistance = 5 bit), foto lain tidak.
+func TestPerceptualHashStableUnderRecompression(t *testing.T) {
+	const maxDistance = 5
+	src := fieldPhoto(800, 600, 1)
+	base := phashOf(t, encodeTestJPEG(t, src, 95))
+
+	half := image.NewRGBA(image.Rect(0, 0, 400, 300))
+	draw.CatmullRom.Scale(half, half.Bounds(), src, src.Bounds(), draw.Src, nil)
+	var pngBuf bytes.Buffer
+	if err := png.Encode(&pngBuf, src); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"jpeg kualitas 60", encodeTestJPEG(t, src, 60)},
+		{"jpeg kualitas 30", encodeTestJPEG(t, src, 30)},
+		{"diperkecil 50%", encodeTestJPEG(t, half, 80)},
+		{"png", pngBuf.Bytes()},
+		{"kompres ulang dua kali", encodeTestJPEG(t, decodeTestJPEG(t, encodeTestJPEG(t, src, 50)), 50)},
+	}
+	for _, tt := range tests {
+		if d := bits.OnesCount64(base ^ phashOf(t, tt.data)); d > maxDistance {
+			t.Errorf("%s: selisih %d bit, want <= %d", tt.name, d, maxDistance)
+		}
+	}
+
+	other := phashOf(t, encodeTestJPEG(t, fieldPhoto(800, 600, 2), 95))
+	if d := bits.OnesCount64(base ^ other); d <= maxDistance {
+		t.Errorf("foto berbeda: selisih %d bit, harus > %d", d, maxDistance)
+	}
+}
+
+func decodeTestJPEG(t *testing.T, data []byte) image.Image {
+	t.Helper()
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
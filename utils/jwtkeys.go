@@ -0,0 +1,235 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signingKey satu key JWT. private nil berarti key lama yang hanya dipakai verifikasi
+// (token yang sudah terbit tetap valid sampai expired setelah rotasi).
+type signingKey struct {
+	kid     string
+	private crypto.Signer
+	public  crypto.PublicKey
+	method  jwt.SigningMethod
+}
+
+type jwtKeyring struct {
+	active *signingKey
+	keys   map[string]*signingKey
+}
+
+var (
+	keyring   *jwtKeyring
+	keyringMu sync.RWMutex
+)
+
+// InitJWTKeys memuat key RS256 / EdDSA untuk sign & verifikasi JWT. Wajib dipanggil
+// setelah env ter-load; error berarti server tidak boleh jalan.
+//
+//   - JWT_KEYS_DIR     : folder berisi <kid>.pem (private key, PKCS#1/PKCS#8) dan
+//     <kid>.pub.pem (public key saja, untuk key yang sudah dipensiunkan)
+//   - JWT_PRIVATE_KEY  : alternatif satu private key PEM langsung di env, kid dari JWT_KEY_ID
+//   - JWT_ACTIVE_KID   : kid yang dipakai sign token baru (default: kid private terakhir secara urutan nama)
+//
+// Rotasi: taruh key baru di folder, set JWT_ACTIVE_KID ke kid baru, restart. Key lama tetap
+// ada di JWKS dan tetap memverifikasi token lama; hapus (atau sisakan .pub.pem) setelah token lama expired.
+func InitJWTKeys() error {
+	keys := map[string]*signingKey{}
+
+	if dir := os.Getenv("JWT_KEYS_DIR"); dir != "" {
+		files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+		if err != nil {
+			return fmt.Errorf("gagal baca JWT_KEYS_DIR: %w", err)
+		}
+		for _, file := range files {
+			raw, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("gagal baca key %s: %w", file, err)
+			}
+			kid := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(file), ".pem"), ".pub")
+			key, err := parseSigningKey(kid, raw)
+			if err != nil {
+				return err
+			}
+			if existing, ok := keys[kid]; ok && existing.private != nil {
+				continue // private key menang atas public key dengan kid sama
+			}
+			keys[kid] = key
+		}
+	}
+
+	if inline := os.Getenv("JWT_PRIVATE_KEY"); inline != "" {
+		kid := os.Getenv("JWT_KEY_ID")
+		if kid == "" {
+			kid = "primary"
+		}
+		key, err := parseSigningKey(kid, []byte(strings.ReplaceAll(inline, `\n`, "\n")))
+		if err != nil {
+			return err
+		}
+		keys[kid] = key
+	}
+
+	if len(keys) == 0 {
+		return errors.New("tidak ada JWT signing key, set JWT_KEYS_DIR atau JWT_PRIVATE_KEY")
+	}
+
+	activeKid := os.Getenv("JWT_ACTIVE_KID")
+	if activeKid == "" {
+		kids := make([]string, 0, len(keys))
+		for kid, key := range keys {
+			if key.private != nil {
+				kids = append(kids, kid)
+			}
+		}
+		sort.Strings(kids)
+		if len(kids) > 0 {
+			activeKid = kids[len(kids)-1]
+		}
+	}
+
+	active, ok := keys[activeKid]
+	if !ok || active.private == nil {
+		return fmt.Errorf("JWT_ACTIVE_KID %q tidak punya private key", activeKid)
+	}
+
+	keyringMu.Lock()
+	keyring = &jwtKeyring{active: active, keys: keys}
+	keyringMu.Unlock()
+	return nil
+}
+
+func parseSigningKey(kid string, raw []byte) (*signingKey, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, fmt.Errorf("key %s bukan PEM", kid)
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("key %s: tipe PEM %s tidak didukung", kid, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("key %s tidak valid: %w", kid, err)
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("key %s: RSA minimal 2048 bit", kid)
+		}
+		return &signingKey{kid: kid, private: k, public: &k.PublicKey, method: jwt.SigningMethodRS256}, nil
+	case *rsa.PublicKey:
+		return &signingKey{kid: kid, public: k, method: jwt.SigningMethodRS256}, nil
+	case ed25519.PrivateKey:
+		return &signingKey{kid: kid, private: k, public: k.Public(), method: jwt.SigningMethodEdDSA}, nil
+	case ed25519.PublicKey:
+		return &signingKey{kid: kid, public: k, method: jwt.SigningMethodEdDSA}, nil
+	default:
+		return nil, fmt.Errorf("key %s: hanya RSA dan Ed25519 yang didukung", kid)
+	}
+}
+
+// signToken menandatangani claims dengan key aktif dan menaruh kid di header
+func signToken(claims jwt.Claims) (string, error) {
+	keyringMu.RLock()
+	kr := keyring
+	keyringMu.RUnlock()
+
+	if kr == nil {
+		return "", errors.New("JWT keys belum diinisialisasi")
+	}
+
+	token := jwt.NewWithClaims(kr.active.method, claims)
+	token.Header["kid"] = kr.active.kid
+	return token.SignedString(kr.active.private)
+}
+
+// parseToken memverifikasi token dengan key sesuai kid di header
+func parseToken(tokenString string, claims jwt.Claims, opts ...jwt.ParserOption) (*jwt.Token, error) {
+	keyringMu.RLock()
+	kr := keyring
+	keyringMu.RUnlock()
+
+	if kr == nil {
+		return nil, errors.New("JWT keys belum diinisialisasi")
+	}
+
+	opts = append(opts, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}))
+	return jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := kr.keys[kid]
+		if !ok {
+			return nil, errors.New("unknown signing key")
+		}
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, errors.New("invalid signing method")
+		}
+		return key.public, nil
+	}, opts...)
+}
+
+// PublicJWKS mengembalikan semua public key (aktif + pensiun) untuk /.well-known/jwks.json
+func PublicJWKS() JWKS {
+	keyringMu.RLock()
+	kr := keyring
+	keyringMu.RUnlock()
+
+	set := JWKS{Keys: []JWK{}}
+	if kr == nil {
+		return set
+	}
+
+	kids := make([]string, 0, len(kr.keys))
+	for kid := range kr.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	for _, kid := range kids {
+		key := kr.keys[kid]
+		jwk := JWK{Kid: kid, Use: "sig", Alg: key.method.Alg()}
+
+		switch pub := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			continue
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
@@ -0,0 +1,184 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strconv"
+	"strings"
+
+	"codeberg.org/go-pdf/fpdf"
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// prefix isi QR label pohon: AVC:T:<tanaman_id>.
+// Label lama masih berisi AVC:<kebun_id>:<kode_tanaman> dan tetap bisa dibaca.
+const (
+	tanamanQRPrefix   = "AVC:"
+	tanamanQRIDPrefix = tanamanQRPrefix + "T:"
+)
+
+// TanamanQRCode hasil baca QR label pohon. TanamanID terisi untuk label baru,
+// KebunID + KodeTanaman untuk label lama.
+type TanamanQRCode struct {
+	TanamanID   uint
+	KebunID     uint
+	KodeTanaman string
+}
+
+// TanamanQRPayload isi QR untuk satu pohon. Memakai ID karena kode tanaman bisa diubah,
+// label yang sudah terpasang di pohon tetap berlaku.
+func TanamanQRPayload(tanamanID uint) string {
+	return tanamanQRIDPrefix + strconv.FormatUint(uint64(tanamanID), 10)
+}
+
+// ParseTanamanQRPayload kebalikan TanamanQRPayload, termasuk format label lama
+func ParseTanamanQRPayload(payload string) (TanamanQRCode, bool) {
+	payload = strings.TrimSpace(payload)
+	if rest, ok := strings.CutPrefix(payload, tanamanQRIDPrefix); ok {
+		id, err := strconv.ParseUint(rest, 10, 64)
+		if err != nil || id == 0 {
+			return TanamanQRCode{}, false
+		}
+		return TanamanQRCode{TanamanID: uint(id)}, true
+	}
+
+	rest, ok := strings.CutPrefix(payload, tanamanQRPrefix)
+	if !ok {
+		return TanamanQRCode{}, false
+	}
+	idStr, kode, ok := strings.Cut(rest, ":")
+	if !ok || kode == "" {
+		return TanamanQRCode{}, false
+	}
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil || id == 0 {
+		return TanamanQRCode{}, false
+	}
+	return TanamanQRCode{KebunID: uint(id), KodeTanaman: kode}, true
+}
+
+// QRLabel satu label: QR + teks di bawah / sampingnya
+type QRLabel struct {
+	Payload  string
+	Title    string
+	Subtitle string
+}
+
+// QRCodePNG satu QR code sebagai PNG persegi
+func QRCodePNG(payload string, size int) ([]byte, error) {
+	return qrcode.Encode(payload, qrcode.Medium, size)
+}
+
+// QRSheetPNG beberapa QR dalam satu gambar grid, judul label ditulis di bawah tiap QR
+func QRSheetPNG(labels []QRLabel, cellSize, columns int) ([]byte, error) {
+	if len(labels) == 0 {
+		return nil, fmt.Errorf("tidak ada label")
+	}
+	if columns <= 0 {
+		columns = 4
+	}
+	if columns > len(labels) {
+		columns = len(labels)
+	}
+	rows := (len(labels) + columns - 1) / columns
+	textHeight := 2 * basicfont.Face7x13.Metrics().Height.Ceil()
+	cellHeight := cellSize + textHeight
+
+	sheet := image.NewRGBA(image.Rect(0, 0, columns*cellSize, rows*cellHeight))
+	draw.Draw(sheet, sheet.Bounds(), image.White, image.Point{}, draw.Src)
+
+	for i, label := range labels {
+		qr, err := qrcode.New(label.Payload, qrcode.Medium)
+		if err != nil {
+			return nil, err
+		}
+		x := (i % columns) * cellSize
+		y := (i / columns) * cellHeight
+		draw.Draw(sheet, image.Rect(x, y, x+cellSize, y+cellSize), qr.Image(cellSize), image.Point{}, draw.Src)
+
+		drawCenteredText(sheet, label.Title, x, y+cellSize+basicfont.Face7x13.Metrics().Ascent.Ceil(), cellSize)
+		drawCenteredText(sheet, label.Subtitle, x, y+cellSize+textHeight-basicfont.Face7x13.Metrics().Descent.Ceil(), cellSize)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, sheet); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func drawCenteredText(img draw.Image, text string, x, baseline, width int) {
+	if text == "" {
+		return
+	}
+	d := &font.Drawer{Dst: img, Src: image.NewUniform(color.Black), Face: basicfont.Face7x13}
+	// potong teks yang lebih lebar dari sel
+	for len(text) > 1 && d.MeasureString(text).Ceil() > width {
+		text = text[:len(text)-1]
+	}
+	offset := (width - d.MeasureString(text).Ceil()) / 2
+	d.Dot = fixed.P(x+offset, baseline)
+	d.DrawString(text)
+}
+
+// ukuran label mengikuti lembar label A4 3 x 7 (63.5 x 38.1 mm)
+const (
+	labelCols    = 3
+	labelRows    = 7
+	labelWidth   = 63.5
+	labelHeight  = 38.1
+	labelMarginX = 7.2
+	labelMarginY = 15.15
+	labelQRSize  = 30.0
+)
+
+// QRLabelPDF lembar label siap cetak (A4), satu pohon per label: QR di kiri, kode & keterangan di kanan
+func QRLabelPDF(labels []QRLabel) ([]byte, error) {
+	if len(labels) == 0 {
+		return nil, fmt.Errorf("tidak ada label")
+	}
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetAutoPageBreak(false, 0)
+	perPage := labelCols * labelRows
+
+	for i, label := range labels {
+		if i%perPage == 0 {
+			pdf.AddPage()
+		}
+		pos := i % perPage
+		x := labelMarginX + float64(pos%labelCols)*labelWidth
+		y := labelMarginY + float64(pos/labelCols)*labelHeight
+
+		qrPNG, err := QRCodePNG(label.Payload, 256)
+		if err != nil {
+			return nil, err
+		}
+		name := fmt.Sprintf("qr-%d", i)
+		opts := fpdf.ImageOptions{ImageType: "PNG"}
+		pdf.RegisterImageOptionsReader(name, opts, bytes.NewReader(qrPNG))
+		pdf.ImageOptions(name, x+2, y+(labelHeight-labelQRSize)/2, labelQRSize, labelQRSize, false, opts, 0, "")
+
+		textX := x + labelQRSize + 4
+		textW := labelWidth - labelQRSize - 6
+		pdf.SetXY(textX, y+8)
+		pdf.SetFont("Helvetica", "B", 11)
+		pdf.MultiCell(textW, 5, label.Title, "", "L", false)
+		pdf.SetX(textX)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.MultiCell(textW, 4, label.Subtitle, "", "L", false)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -9,6 +9,7 @@ type Response struct {
     Error   any    `json:"error,omitempty"`
     Data    any    `json:"data,omitempty"`
     Meta    any    `json:"meta,omitempty"`
+    Warnings []string `json:"warnings,omitempty"`
 }
 
 // EmptyObj untuk response tanpa data
@@ -44,6 +45,16 @@ func SuccessResponseWithMeta(c *gin.Context, statusCode int, message string, dat
     })
 }
 
+// SuccessResponseWithWarnings sends success response with non-fatal warnings (data tetap tersimpan)
+func SuccessResponseWithWarnings(c *gin.Context, statusCode int, message string, data interface{}, warnings []string) {
+    c.JSON(statusCode, Response{
+        Success:  true,
+        Message:  message,
+        Data:     data,
+        Warnings: warnings,
+    })
+}
+
 // ErrorResponse sends error response
 func ErrorResponse(c *gin.Context, statusCode int, message string, detail interface{}) {
     c.JSON(statusCode, Response{